@@ -16,6 +16,7 @@ func main() {
 
 	root.AddCommand(newGenerateCmd())
 	root.AddCommand(newValidateCmd())
+	root.AddCommand(newDiffCmd())
 
 	if err := root.Execute(); err != nil {
 		log.Println(err)
@@ -33,6 +34,8 @@ func newGenerateCmd() *cobra.Command {
 	var name string
 	var includeTags []string
 	var excludeTags []string
+	var noCache bool
+	var verbosity int
 
 	cmd := &cobra.Command{
 		Use:   "generate",
@@ -41,6 +44,8 @@ func newGenerateCmd() *cobra.Command {
 			return cli.RunGenerate(cli.RunGenerateParams{
 				ConfigPath:   configPath,
 				SingleClient: singleClient,
+				NoCache:      noCache,
+				Verbosity:    verbosity,
 				Fallback: cli.FallbackParams{
 					Spec:        input,
 					Type:        typ,
@@ -56,6 +61,8 @@ func newGenerateCmd() *cobra.Command {
 
 	cmd.Flags().StringVarP(&configPath, "config", "c", "", "Path to sdkgen.yaml config")
 	cmd.Flags().StringVar(&singleClient, "client", "", "Generate only the named client from config")
+	cmd.Flags().BoolVar(&noCache, "no-cache", false, "Bypass the spec cache configured in the config file")
+	cmd.Flags().CountVarP(&verbosity, "verbose", "v", "Log each generation phase to stderr; -vv also logs filtered operations, unused models and written files")
 	// Fallback single-client flags
 	cmd.Flags().StringVar(&input, "input", "", "OpenAPI spec file or URL (yaml/json)")
 	cmd.Flags().StringVar(&typ, "type", "", "Client type (e.g., typescript)")
@@ -70,14 +77,44 @@ func newGenerateCmd() *cobra.Command {
 
 func newValidateCmd() *cobra.Command {
 	var input string
+	var cache bool
+	var cacheDir string
+	var lint bool
+	var format string
 	cmd := &cobra.Command{
 		Use:   "validate",
 		Short: "Validate an OpenAPI spec",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return cli.RunValidate(input)
+			return cli.RunValidate(cli.RunValidateParams{Input: input, Cache: cache, CacheDir: cacheDir, Lint: lint, Format: format})
 		},
 	}
 	cmd.Flags().StringVar(&input, "input", "", "OpenAPI spec file or URL (yaml/json)")
+	cmd.Flags().BoolVar(&cache, "cache", false, "Reuse cached parse/validation results for unchanged specs")
+	cmd.Flags().StringVar(&cacheDir, "cache-dir", "", "Spec cache directory (defaults to the user cache dir)")
+	cmd.Flags().BoolVar(&lint, "lint", false, "Also report issues that affect the generated SDKs (missing or duplicate operationIds, untyped schemas, ...)")
+	cmd.Flags().StringVar(&format, "format", "text", "Results format: text, json or sarif (json and sarif also report validation errors as issues)")
+	cmd.Flags().StringVar(&format, "output-format", "text", "Alias of --format")
 	_ = cmd.MarkFlagRequired("input")
 	return cmd
 }
+
+func newDiffCmd() *cobra.Command {
+	var oldSpec string
+	var newSpec string
+	var format string
+	var failOnBreaking bool
+	cmd := &cobra.Command{
+		Use:   "diff",
+		Short: "Report added, removed and changed operations and models between two specs",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return cli.RunDiff(cli.RunDiffParams{Old: oldSpec, New: newSpec, Format: format, FailOnBreaking: failOnBreaking})
+		},
+	}
+	cmd.Flags().StringVar(&oldSpec, "old", "", "Previous OpenAPI spec file or URL (yaml/json)")
+	cmd.Flags().StringVar(&newSpec, "new", "", "New OpenAPI spec file or URL (yaml/json)")
+	cmd.Flags().StringVar(&format, "format", "text", "Report format: text or json")
+	cmd.Flags().BoolVar(&failOnBreaking, "fail-on-breaking", false, "Exit with a non-zero status when breaking changes are found")
+	_ = cmd.MarkFlagRequired("old")
+	_ = cmd.MarkFlagRequired("new")
+	return cmd
+}
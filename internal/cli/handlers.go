@@ -1,15 +1,27 @@
 package cli
 
 import (
+	"fmt"
+	"log/slog"
+	"os"
+	"regexp"
+	"strconv"
+
 	"github.com/blimu-dev/sdk-gen/pkg/generator"
+	"github.com/blimu-dev/sdk-gen/pkg/lint"
 	"github.com/blimu-dev/sdk-gen/pkg/openapi"
+	"github.com/getkin/kin-openapi/openapi3"
 )
 
 // RunGenerateParams contains parameters for the generate command
 type RunGenerateParams struct {
 	ConfigPath   string
 	SingleClient string
-	Fallback     FallbackParams
+	NoCache      bool
+	// Verbosity is 0 to log warnings only, 1 to log each generation phase and 2 to also log filtered
+	// operations, unused models and written files
+	Verbosity int
+	Fallback  FallbackParams
 }
 
 // FallbackParams contains fallback parameters when no config is provided
@@ -28,6 +40,7 @@ func RunGenerate(p RunGenerateParams) error {
 	opts := generator.GenerateSDKOptions{
 		ConfigPath:   p.ConfigPath,
 		SingleClient: p.SingleClient,
+		NoCache:      p.NoCache,
 		Spec:         p.Fallback.Spec,
 		Type:         p.Fallback.Type,
 		OutDir:       p.Fallback.OutDir,
@@ -35,12 +48,146 @@ func RunGenerate(p RunGenerateParams) error {
 		Name:         p.Fallback.Name,
 		IncludeTags:  p.Fallback.IncludeTags,
 		ExcludeTags:  p.Fallback.ExcludeTags,
+		Logger:       newLogger(p.Verbosity),
 	}
 
 	return generator.GenerateSDK(opts)
 }
 
-// RunValidate runs the validate command using the public API
-func RunValidate(input string) error {
-	return openapi.ValidateDocument(input)
+// newLogger returns a logger writing to stderr at the level of a verbosity; warnings are
+// logged even when quiet
+func newLogger(verbosity int) *slog.Logger {
+	level := slog.LevelWarn
+	if verbosity == 1 {
+		level = slog.LevelInfo
+	} else if verbosity > 1 {
+		level = slog.LevelDebug
+	}
+	return slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: level}))
+}
+
+// RunValidateParams contains parameters for the validate command
+type RunValidateParams struct {
+	Input string
+	// Cache enables the on-disk spec cache
+	Cache    bool
+	CacheDir string
+	// Lint additionally reports issues that affect the generated SDKs
+	Lint bool
+	// Format of the results: "text" (default), "json" or "sarif". In text a spec failing
+	// validation is the command's error; json and sarif report it as an invalid-spec issue.
+	Format string
+}
+
+// yamlErrorLine matches the line number of a YAML syntax error
+var yamlErrorLine = regexp.MustCompile(`yaml: line (\d+):`)
+
+// RunValidate runs the validate command using the public API. It fails when an error-level
+// issue is found.
+func RunValidate(p RunValidateParams) error {
+	if p.Format != "" && p.Format != "text" && p.Format != "json" && p.Format != "sarif" {
+		return fmt.Errorf("unsupported report format %q (expected text, json or sarif)", p.Format)
+	}
+	text := p.Format == "" || p.Format == "text"
+
+	var err error
+	if p.Cache {
+		err = openapi.ValidateDocumentCached(p.Input, openapi.CacheOptions{Dir: p.CacheDir})
+	} else {
+		err = openapi.ValidateDocument(p.Input)
+	}
+
+	var report lint.Report
+	switch {
+	case err != nil && text:
+		return err
+	case err != nil:
+		issue := lint.Issue{Severity: lint.SeverityError, Code: lint.CodeInvalidSpec, File: p.Input, Message: err.Error()}
+		if m := yamlErrorLine.FindStringSubmatch(err.Error()); m != nil {
+			issue.Line, _ = strconv.Atoi(m[1])
+		}
+		report.Issues = []lint.Issue{issue}
+	case p.Lint:
+		if report, err = lintSpec(p); err != nil {
+			return err
+		}
+	case text:
+		return nil
+	}
+
+	switch p.Format {
+	case "json":
+		err = report.WriteJSON(os.Stdout)
+	case "sarif":
+		err = report.WriteSARIF(os.Stdout)
+	default:
+		err = report.WriteText(os.Stdout)
+	}
+	if err != nil {
+		return err
+	}
+	if report.HasErrors() {
+		return fmt.Errorf("validate found %d error(s)", report.Count(lint.SeverityError))
+	}
+	return nil
+}
+
+// lintSpec lints the validated spec, locating the issues in its source when it can be read
+func lintSpec(p RunValidateParams) (lint.Report, error) {
+	var doc *openapi3.T
+	var err error
+	if p.Cache {
+		doc, err = openapi.LoadDocumentCached(p.Input, openapi.CacheOptions{Dir: p.CacheDir})
+	} else {
+		doc, err = openapi.LoadDocument(p.Input)
+	}
+	if err != nil {
+		return lint.Report{}, err
+	}
+	in, err := generator.BuildIR(doc)
+	if err != nil {
+		return lint.Report{}, err
+	}
+
+	report := lint.Lint(in)
+	if raw, err := openapi.ReadSpec(p.Input, openapi.CacheOptions{}); err == nil {
+		if lines, err := openapi.SourceLines(raw); err == nil {
+			report = report.Locate(p.Input, lines)
+		}
+	}
+	return report, nil
+}
+
+// RunDiffParams contains parameters for the diff command
+type RunDiffParams struct {
+	Old string
+	New string
+	// Format of the report: "text" (default) or "json"
+	Format string
+	// FailOnBreaking returns an error when breaking changes are found
+	FailOnBreaking bool
+}
+
+// RunDiff runs the diff command using the public API
+func RunDiff(p RunDiffParams) error {
+	if p.Format != "" && p.Format != "text" && p.Format != "json" {
+		return fmt.Errorf("unsupported report format %q (expected text or json)", p.Format)
+	}
+
+	report, err := generator.DiffSpecs(p.Old, p.New)
+	if err != nil {
+		return err
+	}
+	if p.Format == "json" {
+		err = report.WriteJSON(os.Stdout)
+	} else {
+		err = report.WriteText(os.Stdout)
+	}
+	if err != nil {
+		return err
+	}
+	if p.FailOnBreaking && report.HasBreaking() {
+		return fmt.Errorf("found %d breaking change(s)", report.BreakingCount())
+	}
+	return nil
 }
@@ -6,16 +6,102 @@ import (
 	"net/url"
 	"os"
 	"path/filepath"
+	"strconv"
 	"strings"
 
+	"github.com/blimu-dev/sdk-gen/pkg/utils"
 	"gopkg.in/yaml.v3"
 )
 
 // Config represents the complete configuration for SDK generation
 type Config struct {
-	Spec    string   `yaml:"spec"`
-	Name    string   `yaml:"name"`
-	Clients []Client `yaml:"clients"`
+	Spec string `yaml:"spec"`
+	// Specs lists several OpenAPI documents whose paths and components are merged into one
+	// before the IR is built. Used instead of Spec.
+	Specs   []SpecSource `yaml:"specs"`
+	Name    string       `yaml:"name"`
+	Clients []Client     `yaml:"clients"`
+	// Concurrency is the maximum number of clients generated in parallel.
+	// Zero uses the number of CPUs; SDKGEN_CONCURRENCY overrides it.
+	Concurrency int `yaml:"concurrency"`
+	// SpecCache configures the on-disk cache of parsed specs
+	SpecCache SpecCache `yaml:"specCache"`
+	// SpecHeaders are sent when fetching HTTP(S) specs, only to the spec's own host
+	// (e.g. Authorization for a spec behind auth)
+	SpecHeaders map[string]string `yaml:"specHeaders"`
+	// UntaggedTag is the service operations without tags are grouped into (defaults to "misc")
+	UntaggedTag string `yaml:"untaggedTag"`
+	// UntaggedBehavior decides what happens to operations without tags: "bucket" (default)
+	// groups them under UntaggedTag, "skip" leaves them out and "error" fails generation
+	UntaggedBehavior string `yaml:"untaggedBehavior"`
+	// UseSchemaTitleAsName generates inline object and enum schemas that have a title as models
+	// named after it (PascalCased, numbered on collision) instead of as inline types
+	UseSchemaTitleAsName bool `yaml:"useSchemaTitleAsName"`
+	// PreserveSpecOrder orders services by the spec's top-level tags and operations by their
+	// position in the spec instead of alphabetically. Anything not listed is appended in
+	// alphabetical order.
+	PreserveSpecOrder bool `yaml:"preserveSpecOrder"`
+}
+
+// DefaultUntaggedTag is the service untagged operations are grouped into by default
+const DefaultUntaggedTag = "misc"
+
+// Values of Config.UntaggedBehavior
+const (
+	UntaggedBucket = "bucket"
+	UntaggedSkip   = "skip"
+	UntaggedError  = "error"
+)
+
+// Values of Client.GoContextMode
+const (
+	GoContextOnly = "contextOnly"
+	GoContextBoth = "both"
+	GoNoContext   = "noContext"
+)
+
+// Values of Client.QuoteStyle
+const (
+	QuoteSingle = "single"
+	QuoteDouble = "double"
+)
+
+// Values of Client.FileNaming
+const (
+	FileNamingSnake  = "snake"
+	FileNamingKebab  = "kebab"
+	FileNamingCamel  = "camel"
+	FileNamingPascal = "pascal"
+)
+
+// IndentTab is the Client.Indent value for indenting with tabs
+const IndentTab = "tab"
+
+// SpecSource is one entry of Config.Specs. It can be written as a plain path/URL string
+// or as a mapping with a path and an optional prefix.
+type SpecSource struct {
+	// Path is the OpenAPI file path or HTTP(S) URL
+	Path string `yaml:"path"`
+	// Prefix is prepended to every component schema name from this spec to avoid collisions
+	Prefix string `yaml:"prefix"`
+}
+
+// UnmarshalYAML accepts either a scalar path or a {path, prefix} mapping
+func (s *SpecSource) UnmarshalYAML(value *yaml.Node) error {
+	if value.Kind == yaml.ScalarNode {
+		s.Path = value.Value
+		return nil
+	}
+	type plain SpecSource
+	return value.Decode((*plain)(s))
+}
+
+// SpecCache configures caching of parsed (and validated) OpenAPI documents between runs
+type SpecCache struct {
+	// Enabled turns on the cache
+	Enabled bool `yaml:"enabled"`
+	// Dir is the cache directory (defaults to the user cache dir + "/sdk-gen")
+	Dir string `yaml:"dir"`
 }
 
 // Client represents configuration for a single client SDK
@@ -27,6 +113,11 @@ type Client struct {
 	Name        string   `yaml:"name"`
 	IncludeTags []string `yaml:"includeTags"`
 	ExcludeTags []string `yaml:"excludeTags"`
+	// IncludeOperations and ExcludeOperations are regex patterns matched against operationIds
+	// ("METHOD /path" for operations without one). They apply on top of the tag filters, and an
+	// operation matching an exclude pattern is left out even if an include pattern matches it.
+	IncludeOperations []string `yaml:"includeOperations"`
+	ExcludeOperations []string `yaml:"excludeOperations"`
 	// IncludeQueryKeys toggles generation of __queryKeys helper methods in services
 	IncludeQueryKeys bool `yaml:"includeQueryKeys"`
 	// OperationIDParser is an optional executable script to transform operationId to a method name.
@@ -42,13 +133,127 @@ type Client struct {
 	PostCommand []string `yaml:"postCommand"`
 	// DefaultBaseURL is the default base URL that will be used if no base URL is provided when creating a client
 	DefaultBaseURL string `yaml:"defaultBaseURL"`
+	// Environments maps environment names to base URLs, offered by the generated client as
+	// presets (e.g. Client.forEnvironment("staging") in TypeScript). When empty, the spec's
+	// absolute servers are used, named after their descriptions (TypeScript, Python and Go)
+	Environments map[string]string `yaml:"environments"`
+	// DefaultHeaders are attached to every request made by the generated client.
+	// Headers configured at runtime or passed per call take precedence.
+	DefaultHeaders map[string]string `yaml:"defaultHeaders"`
+	// DateAsNativeType maps string schemas with format "date"/"date-time" to native date types
+	// (TypeScript Date, Python datetime/date, Go time.Time) instead of plain strings
+	DateAsNativeType bool `yaml:"dateAsNativeType"`
+	// EmitOperationMetadata generates src/meta.ts with an operationId -> { method, path, tag } map
+	// and a union type of all operation ids in TypeScript, and operations.go with an OperationID
+	// constant and an OperationInfo per operation in Go
+	EmitOperationMetadata bool `yaml:"emitOperationMetadata"`
+	// EmitZod generates src/schemas.zod.ts with a Zod schema per model, named like its
+	// TypeScript type, and adds zod as a dependency (TypeScript only)
+	EmitZod bool `yaml:"emitZod"`
+	// EmitSourceMaps enables declaration maps in tsconfig.json and publishes src/ alongside
+	// dist/ so editors can jump from the SDK's types to its TypeScript source (TypeScript only)
+	EmitSourceMaps bool `yaml:"emitSourceMaps"`
+	// BundleSingleFile merges the client, services and models into a single src/index.ts
+	// without internal imports, for runtimes like Deno; models are exported by name instead of
+	// under the Schema namespace. Not supported with emitZod (TypeScript only)
+	BundleSingleFile bool `yaml:"bundleSingleFile"`
+	// Indent is the indentation of the generated TypeScript sources and .prettierrc.json: a
+	// number of spaces or "tab". Defaults to 2 spaces (TypeScript only)
+	Indent string `yaml:"indent"`
+	// QuoteStyle converts the string literals of the generated TypeScript sources to "single" or
+	// "double" quotes where that needs no extra escaping. When empty, literals keep the quotes
+	// the templates write (TypeScript only)
+	QuoteStyle string `yaml:"quoteStyle"`
+	// FileNaming names the generated service files "snake", "kebab", "camel" or "pascal" case
+	// in every generator. When empty, each generator keeps its own convention. Python module
+	// names cannot contain hyphens, so kebab is rejected for python clients.
+	FileNaming string `yaml:"fileNaming"`
+	// ResponseWithHeaders makes operations that declare response headers return a wrapper with
+	// the parsed body and typed headers instead of the body alone (TypeScript only)
+	ResponseWithHeaders bool `yaml:"responseWithHeaders"`
+	// SingleOptionsArg makes every service method take one options object
+	// ({ pathParams, query, body, init }) typed by a generated per-operation interface instead
+	// of positional arguments (TypeScript only)
+	SingleOptionsArg bool `yaml:"singleOptionsArg"`
+	// EmitRequestBuilders generates a <method>__request builder per operation returning the
+	// method, URL, headers and serialized body of its request without sending it, for callers
+	// with their own HTTP layer (TypeScript only)
+	EmitRequestBuilders bool `yaml:"emitRequestBuilders"`
+	// PackagePerTag generates one package per service tag into <outDir>/<tag> instead of a
+	// single package, each with a client for the tag's operations and the models they use
+	// (TypeScript, Python and Go)
+	PackagePerTag bool `yaml:"packagePerTag"`
+	// RequireRequestBodies treats every request body with content as required, even when the
+	// spec leaves requestBody.required at its false default. Bodies the spec requires stay
+	// required either way.
+	RequireRequestBodies bool `yaml:"requireRequestBodies"`
+	// RawResponse makes operations return the parsed body together with the underlying HTTP
+	// response, for status codes, headers and redirects: { data, response } in TypeScript, a
+	// RawResponse in Python and an extra *http.Response result in Go
+	RawResponse bool `yaml:"rawResponse"`
+	// AsyncClient additionally generates an httpx.AsyncClient based client with async service
+	// methods alongside the synchronous one (Python only)
+	AsyncClient bool `yaml:"asyncClient"`
+	// GoFilePerOperation splits every Go service into one file declaring the service and one
+	// file per operation, named <tag>_<method>.go (Go only)
+	GoFilePerOperation bool `yaml:"goFilePerOperation"`
+	// GoContextMode decides which method variants Go services get per operation: "contextOnly"
+	// (default) takes a context.Context, "noContext" uses context.Background(), and "both"
+	// generates a <Method>WithContext variant next to a <Method> that calls it (Go only)
+	GoContextMode string `yaml:"goContextMode"`
+	// GoServiceInterfaces generates interfaces.go with a <Service>Interface per service listing
+	// its methods, implemented by the service struct, so consumers can mock services (Go only)
+	GoServiceInterfaces bool `yaml:"goServiceInterfaces"`
+	// Version is the version of the generated package, written to package.json, pyproject.toml
+	// (and __version__) and build.gradle.kts; defaults to 0.1.0. SDKGEN_VERSION overrides it.
+	Version string `yaml:"version"`
+	// EmitClient set to false generates a types-only package: the models and a package entry
+	// point re-exporting them, without the HTTP client and services (TypeScript and Python
+	// only). Defaults to true.
+	EmitClient *bool `yaml:"emitClient"`
+	// ForPublishing additionally generates an .npmignore so only compiled output is published
+	// (TypeScript only)
+	ForPublishing bool `yaml:"forPublishing"`
+	// ModelNamePrefix and ModelNameSuffix are added to every generated model name and to every
+	// reference to it (e.g. prefix "Api" turns User into ApiUser)
+	ModelNamePrefix string `yaml:"modelNamePrefix"`
+	ModelNameSuffix string `yaml:"modelNameSuffix"`
 	// ExcludeFiles is a list of file paths (relative to outDir) that should not be generated
 	// Example: ["package.json", "src/client.ts"]
 	ExcludeFiles []string `yaml:"exclude"`
+	// FileHeader is prepended to every generated source file as a comment block, using the
+	// comment syntax of the file's language (e.g. a license or SPDX header)
+	FileHeader string `yaml:"fileHeader"`
+	// FileHeaderFile is a path to a file whose contents are used as FileHeader
+	FileHeaderFile string `yaml:"fileHeaderFile"`
+	// PreservePropertyNames names model properties exactly like their JSON keys where the
+	// language allows it (Python, Kotlin and Swift; TypeScript always uses the JSON keys and Go
+	// always exports fields with json tags). When false, properties get idiomatic names
+	// (snake_case in Python, camelCase in Kotlin and Swift) mapped to the JSON keys with
+	// aliases, @SerialName or CodingKeys.
+	PreservePropertyNames bool `yaml:"preservePropertyNames"`
+	// TypeMappings replace the generated type of every schema with a given type and format by a
+	// native type (Go, TypeScript and Python). x-go-type, x-ts-type and x-python-type still win.
+	TypeMappings []TypeMapping `yaml:"typeMappings"`
 	// TypeAugmentationOptions are options specific to type augmentation generators
 	TypeAugmentationOptions TypeAugmentationOptions `yaml:"typeAugmentation"`
 }
 
+// TypeMapping maps schemas of an OpenAPI type and format to a native type of the client's
+// language, e.g. string/uuid to uuid.UUID
+type TypeMapping struct {
+	// Type is the schema type: string, integer, number or boolean
+	Type string `yaml:"type"`
+	// Format is the schema format (e.g. uuid, email, decimal). A mapping without a format
+	// applies to schemas of the type that no format-specific mapping matches.
+	Format string `yaml:"format"`
+	// Native is the type emitted instead of the inferred one (e.g. uuid.UUID)
+	Native string `yaml:"native"`
+	// Import is what the native type needs: a Go package path, a Python module, or a
+	// TypeScript import statement (e.g. import type { DateTime } from "luxon")
+	Import string `yaml:"import"`
+}
+
 // TypeAugmentationOptions contains options for type augmentation generators
 type TypeAugmentationOptions struct {
 	// ModuleName is the module name to augment (e.g., "@blimu/backend")
@@ -71,6 +276,41 @@ func (c *Client) GetPostCommand() []string {
 	return c.PostCommand
 }
 
+// ShouldEmitClient reports whether the HTTP client and services are generated; false for
+// types-only packages
+func (c *Client) ShouldEmitClient() bool {
+	return c.EmitClient == nil || *c.EmitClient
+}
+
+// FileName returns the base name of a generated file made of words in the FileNaming style,
+// or def when FileNaming is unset
+func (c *Client) FileName(def string, words ...string) string {
+	name := strings.Join(words, " ")
+	switch c.FileNaming {
+	case FileNamingSnake:
+		return utils.ToSnakeCase(name)
+	case FileNamingKebab:
+		return utils.ToKebabCase(name)
+	case FileNamingCamel:
+		return utils.ToCamelCase(name)
+	case FileNamingPascal:
+		return utils.ToPascalCase(name)
+	}
+	return def
+}
+
+// DefaultVersion is the version of generated packages when Client.Version is unset
+const DefaultVersion = "0.1.0"
+
+// PackageVersion returns the version of the generated package: Version without the "v"
+// prefix of git tags (v1.2.3 becomes 1.2.3), or DefaultVersion
+func (c *Client) PackageVersion() string {
+	if v := strings.TrimPrefix(c.Version, "v"); v != "" {
+		return v
+	}
+	return DefaultVersion
+}
+
 // ShouldExcludeFile checks if a file path should be excluded based on the ExcludeFiles list.
 // targetPath should be an absolute path, and the comparison is done relative to OutDir.
 func (c *Client) ShouldExcludeFile(targetPath string) bool {
@@ -111,7 +351,23 @@ func (c *Client) ShouldExcludeFile(targetPath string) bool {
 	return false
 }
 
-// Load loads configuration from a YAML file
+// Environment variables that override the config file in Load
+const (
+	// EnvSpec replaces spec (and specs) with a single spec path or URL
+	EnvSpec = "SDKGEN_SPEC"
+	// EnvOutDir is the directory relative client outDirs are resolved against, instead of
+	// the working directory
+	EnvOutDir = "SDKGEN_OUT_DIR"
+	// EnvSpecToken is sent as a bearer token when fetching HTTP(S) specs, replacing any
+	// Authorization entry of specHeaders
+	EnvSpecToken = "SDKGEN_SPEC_TOKEN"
+	// EnvVersion replaces the version of every client, e.g. with the git tag being released
+	EnvVersion = "SDKGEN_VERSION"
+)
+
+// Load loads configuration from a YAML file, then applies the SDKGEN_SPEC, SDKGEN_OUT_DIR,
+// SDKGEN_SPEC_TOKEN and SDKGEN_VERSION environment variables, which take precedence over the
+// file
 func Load(path string) (*Config, error) {
 	data, err := os.ReadFile(path)
 	if err != nil {
@@ -121,8 +377,23 @@ func Load(path string) (*Config, error) {
 	if err := yaml.Unmarshal(data, &cfg); err != nil {
 		return nil, err
 	}
-	if cfg.Spec == "" {
-		return nil, errors.New("config.spec is required")
+	applyEnv(&cfg)
+	if cfg.Spec == "" && len(cfg.Specs) == 0 {
+		return nil, errors.New("config.spec or config.specs is required")
+	}
+	if cfg.Spec != "" && len(cfg.Specs) > 0 {
+		return nil, errors.New("config.spec and config.specs are mutually exclusive")
+	}
+	switch cfg.UntaggedBehavior {
+	case "", UntaggedBucket, UntaggedSkip, UntaggedError:
+	default:
+		return nil, fmt.Errorf("invalid untaggedBehavior %q (expected bucket, skip or error)", cfg.UntaggedBehavior)
+	}
+	for i := range cfg.Specs {
+		if cfg.Specs[i].Path == "" {
+			return nil, fmt.Errorf("specs[%d] missing path", i)
+		}
+		cfg.Specs[i].Path = resolveSpecLocation(cfg.Specs[i].Path)
 	}
 	for i := range cfg.Clients {
 		c := &cfg.Clients[i]
@@ -130,16 +401,98 @@ func Load(path string) (*Config, error) {
 			return nil, fmt.Errorf("clients[%d] missing required fields (type, outDir, packageName, name)", i)
 		}
 		if !filepath.IsAbs(c.OutDir) {
+			if base := os.Getenv(EnvOutDir); base != "" {
+				c.OutDir = filepath.Join(base, c.OutDir)
+			}
 			abs, _ := filepath.Abs(c.OutDir)
 			c.OutDir = abs
 		}
+		if c.FileHeaderFile != "" {
+			if c.FileHeader != "" {
+				return nil, fmt.Errorf("clients[%d]: fileHeader and fileHeaderFile are mutually exclusive", i)
+			}
+			header, err := os.ReadFile(c.FileHeaderFile)
+			if err != nil {
+				return nil, fmt.Errorf("clients[%d]: reading fileHeaderFile: %w", i, err)
+			}
+			c.FileHeader = string(header)
+		}
+		switch c.GoContextMode {
+		case "", GoContextOnly, GoContextBoth, GoNoContext:
+		default:
+			return nil, fmt.Errorf("clients[%d]: invalid goContextMode %q (expected contextOnly, both or noContext)", i, c.GoContextMode)
+		}
+		if c.Indent != "" && c.Indent != IndentTab {
+			if n, err := strconv.Atoi(c.Indent); err != nil || n < 1 || n > 8 {
+				return nil, fmt.Errorf("clients[%d]: invalid indent %q (expected 1 to 8 spaces or tab)", i, c.Indent)
+			}
+		}
+		for name, url := range c.Environments {
+			if name == "" || url == "" {
+				return nil, fmt.Errorf("clients[%d].environments: names and base URLs must not be empty", i)
+			}
+		}
+		if c.PackagePerTag && c.Type != "typescript" && c.Type != "python" && c.Type != "go" {
+			return nil, fmt.Errorf("clients[%d]: packagePerTag is only supported by typescript, python and go clients", i)
+		}
+		switch c.QuoteStyle {
+		case "", QuoteSingle, QuoteDouble:
+		default:
+			return nil, fmt.Errorf("clients[%d]: invalid quoteStyle %q (expected single or double)", i, c.QuoteStyle)
+		}
+		switch c.FileNaming {
+		case "", FileNamingSnake, FileNamingCamel, FileNamingPascal:
+		case FileNamingKebab:
+			if c.Type == "python" {
+				return nil, fmt.Errorf("clients[%d]: fileNaming kebab is not supported by python clients", i)
+			}
+		default:
+			return nil, fmt.Errorf("clients[%d]: invalid fileNaming %q (expected snake, kebab, camel or pascal)", i, c.FileNaming)
+		}
+		for j, m := range c.TypeMappings {
+			if m.Type == "" || m.Native == "" {
+				return nil, fmt.Errorf("clients[%d].typeMappings[%d] missing required fields (type, native)", i, j)
+			}
+		}
 	}
-	// Do not absolutize when spec is an HTTP(S) URL
-	if u, err := url.Parse(cfg.Spec); err == nil && (u.Scheme == "http" || u.Scheme == "https") {
-		// keep as-is
-	} else if !filepath.IsAbs(cfg.Spec) {
-		abs, _ := filepath.Abs(cfg.Spec)
-		cfg.Spec = abs
+	if cfg.Spec != "" {
+		cfg.Spec = resolveSpecLocation(cfg.Spec)
 	}
 	return &cfg, nil
 }
+
+// applyEnv overlays the spec, spec token and version environment variables on cfg.
+// SDKGEN_OUT_DIR is applied while resolving client outDirs.
+func applyEnv(cfg *Config) {
+	if spec := os.Getenv(EnvSpec); spec != "" {
+		cfg.Spec = spec
+		cfg.Specs = nil
+	}
+	if token := os.Getenv(EnvSpecToken); token != "" {
+		headers := make(map[string]string, len(cfg.SpecHeaders)+1)
+		for k, v := range cfg.SpecHeaders {
+			if !strings.EqualFold(k, "Authorization") {
+				headers[k] = v
+			}
+		}
+		headers["Authorization"] = "Bearer " + token
+		cfg.SpecHeaders = headers
+	}
+	if version := os.Getenv(EnvVersion); version != "" {
+		for i := range cfg.Clients {
+			cfg.Clients[i].Version = version
+		}
+	}
+}
+
+// resolveSpecLocation makes a spec file path absolute; HTTP(S) URLs are kept as-is
+func resolveSpecLocation(spec string) string {
+	if u, err := url.Parse(spec); err == nil && (u.Scheme == "http" || u.Scheme == "https") {
+		return spec
+	}
+	if !filepath.IsAbs(spec) {
+		abs, _ := filepath.Abs(spec)
+		return abs
+	}
+	return spec
+}
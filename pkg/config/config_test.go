@@ -0,0 +1,136 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const envTestConfig = `spec: openapi.yaml
+specHeaders:
+  authorization: Basic old
+  X-Api-Version: "2"
+clients:
+  - type: typescript
+    outDir: sdk/ts
+    packageName: sdk
+    name: Client
+`
+
+func TestLoadEnvOverrides(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "sdkgen.yaml")
+	if err := os.WriteFile(path, []byte(envTestConfig), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if filepath.Base(cfg.Spec) != "openapi.yaml" || cfg.SpecHeaders["authorization"] != "Basic old" {
+		t.Errorf("unexpected config without overrides: spec=%q headers=%v", cfg.Spec, cfg.SpecHeaders)
+	}
+	if v := cfg.Clients[0].PackageVersion(); v != DefaultVersion {
+		t.Errorf("version = %q, expected the default %q", v, DefaultVersion)
+	}
+
+	outDir := filepath.Join(dir, "out")
+	t.Setenv(EnvSpec, "https://example.com/openapi.json")
+	t.Setenv(EnvOutDir, outDir)
+	t.Setenv(EnvSpecToken, "secret")
+	t.Setenv(EnvVersion, "v1.2.3")
+	cfg, err = Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Spec != "https://example.com/openapi.json" {
+		t.Errorf("spec = %q, expected the SDKGEN_SPEC URL", cfg.Spec)
+	}
+	if expected := filepath.Join(outDir, "sdk", "ts"); cfg.Clients[0].OutDir != expected {
+		t.Errorf("outDir = %q, expected %q", cfg.Clients[0].OutDir, expected)
+	}
+	if v := cfg.Clients[0].PackageVersion(); v != "1.2.3" {
+		t.Errorf("version = %q, expected 1.2.3 from the SDKGEN_VERSION tag", v)
+	}
+	expectedHeaders := map[string]string{"Authorization": "Bearer secret", "X-Api-Version": "2"}
+	if len(cfg.SpecHeaders) != len(expectedHeaders) {
+		t.Errorf("specHeaders = %v, expected %v", cfg.SpecHeaders, expectedHeaders)
+	}
+	for k, v := range expectedHeaders {
+		if cfg.SpecHeaders[k] != v {
+			t.Errorf("specHeaders[%s] = %q, expected %q", k, cfg.SpecHeaders[k], v)
+		}
+	}
+}
+
+func TestLoadCodeStyle(t *testing.T) {
+	tests := []struct {
+		options string
+		valid   bool
+	}{
+		{"indent: 4\n    quoteStyle: single", true},
+		{"indent: tab\n    quoteStyle: double", true},
+		{"indent: 0", false},
+		{"indent: spaces", false},
+		{"quoteStyle: backtick", false},
+	}
+
+	dir := t.TempDir()
+	for _, test := range tests {
+		path := filepath.Join(dir, "sdkgen.yaml")
+		if err := os.WriteFile(path, []byte(envTestConfig+"    "+test.options+"\n"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := Load(path); (err == nil) != test.valid {
+			t.Errorf("%q: Load() error = %v, expected valid %v", test.options, err, test.valid)
+		}
+	}
+}
+
+func TestLoadFileNaming(t *testing.T) {
+	tests := []struct {
+		clientType string
+		fileNaming string
+		valid      bool
+	}{
+		{"typescript", "kebab", true},
+		{"typescript", "pascal", true},
+		{"python", "camel", true},
+		{"python", "kebab", false},
+		{"go", "screaming", false},
+	}
+
+	dir := t.TempDir()
+	for _, test := range tests {
+		config := strings.Replace(envTestConfig, "type: typescript", "type: "+test.clientType, 1)
+		path := filepath.Join(dir, "sdkgen.yaml")
+		if err := os.WriteFile(path, []byte(config+"    fileNaming: "+test.fileNaming+"\n"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := Load(path); (err == nil) != test.valid {
+			t.Errorf("%s %q: Load() error = %v, expected valid %v", test.clientType, test.fileNaming, err, test.valid)
+		}
+	}
+}
+
+func TestClientFileName(t *testing.T) {
+	tests := []struct {
+		fileNaming string
+		expected   string
+	}{
+		{"", "default"},
+		{FileNamingSnake, "user_accounts_service"},
+		{FileNamingKebab, "user-accounts-service"},
+		{FileNamingCamel, "userAccountsService"},
+		{FileNamingPascal, "UserAccountsService"},
+	}
+
+	for _, test := range tests {
+		client := Client{FileNaming: test.fileNaming}
+		if got := client.FileName("default", "UserAccounts", "service"); got != test.expected {
+			t.Errorf("FileName() with %q = %q, expected %q", test.fileNaming, got, test.expected)
+		}
+	}
+}
@@ -0,0 +1,384 @@
+// Package diff compares two OpenAPI specs through their IR and reports added, removed and
+// changed operations and models, classifying each change as breaking or not for SDK users.
+// It is meant for release notes and for catching breaking changes in CI.
+package diff
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/blimu-dev/sdk-gen/pkg/ir"
+)
+
+// Kinds of change reported by Compare
+const (
+	KindAdded   = "added"
+	KindRemoved = "removed"
+	KindChanged = "changed"
+)
+
+// Change is a single difference between the old and the new spec
+type Change struct {
+	// Breaking marks changes that can break code written against the old SDK
+	Breaking bool   `json:"breaking"`
+	Kind     string `json:"kind"`
+	// Location points at the operation ("GET /users"), model ("User") or field ("User.email")
+	Location string `json:"location"`
+	Message  string `json:"message"`
+}
+
+// Report holds the changes between two specs, breaking changes first
+type Report struct {
+	Changes []Change `json:"changes"`
+}
+
+// BreakingCount returns the number of breaking changes
+func (r Report) BreakingCount() int {
+	n := 0
+	for _, c := range r.Changes {
+		if c.Breaking {
+			n++
+		}
+	}
+	return n
+}
+
+// HasBreaking reports whether the report contains any breaking change
+func (r Report) HasBreaking() bool {
+	return r.BreakingCount() > 0
+}
+
+// WriteText writes the changes grouped into breaking and non-breaking sections, followed by
+// a summary
+func (r Report) WriteText(w io.Writer) error {
+	breaking := r.BreakingCount()
+	sections := []struct {
+		title    string
+		breaking bool
+		count    int
+	}{
+		{"Breaking changes", true, breaking},
+		{"Non-breaking changes", false, len(r.Changes) - breaking},
+	}
+	for _, section := range sections {
+		if section.count == 0 {
+			continue
+		}
+		if _, err := fmt.Fprintf(w, "%s:\n", section.title); err != nil {
+			return err
+		}
+		for _, c := range r.Changes {
+			if c.Breaking != section.breaking {
+				continue
+			}
+			if _, err := fmt.Fprintf(w, "  %-8s %s: %s\n", c.Kind, c.Location, c.Message); err != nil {
+				return err
+			}
+		}
+		if _, err := fmt.Fprintln(w); err != nil {
+			return err
+		}
+	}
+	_, err := fmt.Fprintf(w, "%d breaking, %d non-breaking change(s)\n", breaking, len(r.Changes)-breaking)
+	return err
+}
+
+// WriteJSON writes the report as an indented JSON document
+func (r Report) WriteJSON(w io.Writer) error {
+	if r.Changes == nil {
+		r.Changes = []Change{}
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(r)
+}
+
+// Compare reports the differences between two IRs (as built by generator.BuildIR)
+func Compare(oldIR, newIR ir.IR) Report {
+	d := &differ{}
+	d.operations(oldIR.Services, newIR.Services)
+	d.models(oldIR.ModelDefs, newIR.ModelDefs)
+
+	sort.SliceStable(d.changes, func(i, j int) bool {
+		a, b := d.changes[i], d.changes[j]
+		if a.Breaking != b.Breaking {
+			return a.Breaking
+		}
+		if a.Location != b.Location {
+			return a.Location < b.Location
+		}
+		return a.Message < b.Message
+	})
+	return Report{Changes: d.changes}
+}
+
+type differ struct {
+	changes []Change
+}
+
+func (d *differ) report(breaking bool, kind, location, format string, args ...any) {
+	d.changes = append(d.changes, Change{Breaking: breaking, Kind: kind, Location: location, Message: fmt.Sprintf(format, args...)})
+}
+
+// operationsByKey indexes operations by "METHOD /path", which stays stable across renames
+func operationsByKey(services []ir.IRService) map[string]ir.IROperation {
+	out := map[string]ir.IROperation{}
+	for _, service := range services {
+		for _, op := range service.Operations {
+			out[op.Method+" "+op.Path] = op
+		}
+	}
+	return out
+}
+
+func (d *differ) operations(oldServices, newServices []ir.IRService) {
+	oldOps, newOps := operationsByKey(oldServices), operationsByKey(newServices)
+	for key, oldOp := range oldOps {
+		newOp, ok := newOps[key]
+		if !ok {
+			d.report(true, KindRemoved, key, "operation removed")
+			continue
+		}
+		d.operation(key, oldOp, newOp)
+	}
+	for key := range newOps {
+		if _, ok := oldOps[key]; !ok {
+			d.report(false, KindAdded, key, "operation added")
+		}
+	}
+}
+
+func (d *differ) operation(key string, oldOp, newOp ir.IROperation) {
+	if oldOp.OperationID != newOp.OperationID {
+		// The operationId names the generated method
+		d.report(true, KindChanged, key, "operationId changed from %q to %q", oldOp.OperationID, newOp.OperationID)
+	}
+	if !oldOp.Deprecated && newOp.Deprecated {
+		d.report(false, KindChanged, key, "operation deprecated")
+	}
+	d.params(key+" path parameter", oldOp.PathParams, newOp.PathParams)
+	d.params(key+" query parameter", oldOp.QueryParams, newOp.QueryParams)
+
+	switch oldBody, newBody := oldOp.RequestBody, newOp.RequestBody; {
+	case oldBody == nil && newBody != nil:
+		d.report(newBody.Required, KindAdded, key, "request body added")
+	case oldBody != nil && newBody == nil:
+		d.report(true, KindRemoved, key, "request body removed")
+	case oldBody != nil:
+		if oldBody.ContentType != newBody.ContentType {
+			d.report(true, KindChanged, key, "request body content type changed from %s to %s", oldBody.ContentType, newBody.ContentType)
+		}
+		if before, after := describe(oldBody.Schema), describe(newBody.Schema); before != after {
+			d.report(true, KindChanged, key, "request body type changed from %s to %s", before, after)
+		}
+		if !oldBody.Required && newBody.Required {
+			d.report(true, KindChanged, key, "request body is now required")
+		}
+	}
+
+	if before, after := describeResponse(oldOp.Response), describeResponse(newOp.Response); before != after {
+		d.report(true, KindChanged, key, "response type changed from %s to %s", before, after)
+	}
+}
+
+func (d *differ) params(location string, oldParams, newParams []ir.IRParam) {
+	byName := func(params []ir.IRParam) map[string]ir.IRParam {
+		out := make(map[string]ir.IRParam, len(params))
+		for _, p := range params {
+			out[p.Name] = p
+		}
+		return out
+	}
+	oldByName, newByName := byName(oldParams), byName(newParams)
+	for name, oldParam := range oldByName {
+		newParam, ok := newByName[name]
+		if !ok {
+			d.report(true, KindRemoved, location+" "+name, "parameter removed")
+			continue
+		}
+		if before, after := describe(oldParam.Schema), describe(newParam.Schema); before != after {
+			d.report(true, KindChanged, location+" "+name, "type changed from %s to %s", before, after)
+		}
+		if !oldParam.Required && newParam.Required {
+			d.report(true, KindChanged, location+" "+name, "parameter is now required")
+		}
+	}
+	for name, newParam := range newByName {
+		if _, ok := oldByName[name]; !ok {
+			if newParam.Required {
+				d.report(true, KindAdded, location+" "+name, "required parameter added")
+			} else {
+				d.report(false, KindAdded, location+" "+name, "optional parameter added")
+			}
+		}
+	}
+}
+
+func (d *differ) models(oldDefs, newDefs []ir.IRModelDef) {
+	byName := func(defs []ir.IRModelDef) map[string]ir.IRSchema {
+		out := make(map[string]ir.IRSchema, len(defs))
+		for _, md := range defs {
+			out[md.Name] = md.Schema
+		}
+		return out
+	}
+	oldModels, newModels := byName(oldDefs), byName(newDefs)
+	for name, oldSchema := range oldModels {
+		newSchema, ok := newModels[name]
+		if !ok {
+			d.report(true, KindRemoved, name, "model removed")
+			continue
+		}
+		d.model(name, oldSchema, newSchema)
+	}
+	for name := range newModels {
+		if _, ok := oldModels[name]; !ok {
+			d.report(false, KindAdded, name, "model added")
+		}
+	}
+}
+
+func (d *differ) model(name string, oldSchema, newSchema ir.IRSchema) {
+	switch {
+	case oldSchema.Kind == ir.IRKindObject && newSchema.Kind == ir.IRKindObject:
+		d.fields(name, oldSchema.Properties, newSchema.Properties)
+		switch {
+		case !oldSchema.Nullable && newSchema.Nullable:
+			d.report(true, KindChanged, name, "model is now nullable")
+		case oldSchema.Nullable && !newSchema.Nullable:
+			d.report(true, KindChanged, name, "model is no longer nullable")
+		}
+	case oldSchema.Kind == ir.IRKindEnum && newSchema.Kind == ir.IRKindEnum:
+		for _, v := range oldSchema.EnumValues {
+			if !slices.Contains(newSchema.EnumValues, v) {
+				d.report(true, KindRemoved, name, "enum value %q removed", v)
+			}
+		}
+		for _, v := range newSchema.EnumValues {
+			if !slices.Contains(oldSchema.EnumValues, v) {
+				d.report(false, KindAdded, name, "enum value %q added", v)
+			}
+		}
+	default:
+		if before, after := describe(oldSchema), describe(newSchema); before != after {
+			d.report(true, KindChanged, name, "type changed from %s to %s", before, after)
+		}
+	}
+}
+
+func (d *differ) fields(model string, oldFields, newFields []ir.IRField) {
+	byName := func(fields []ir.IRField) map[string]ir.IRField {
+		out := make(map[string]ir.IRField, len(fields))
+		for _, f := range fields {
+			out[f.Name] = f
+		}
+		return out
+	}
+	oldByName, newByName := byName(oldFields), byName(newFields)
+	for name, oldField := range oldByName {
+		location := model + "." + name
+		newField, ok := newByName[name]
+		if !ok {
+			d.report(true, KindRemoved, location, "field removed")
+			continue
+		}
+		if before, after := describeField(oldField), describeField(newField); before != after {
+			d.report(true, KindChanged, location, "type changed from %s to %s", before, after)
+		}
+		switch {
+		case !oldField.Required && newField.Required:
+			d.report(true, KindChanged, location, "field is now required")
+		case oldField.Required && !newField.Required:
+			d.report(false, KindChanged, location, "field is no longer required")
+		}
+		if !oldField.Annotations.Deprecated && newField.Annotations.Deprecated {
+			d.report(false, KindChanged, location, "field deprecated")
+		}
+	}
+	for name, newField := range newByName {
+		if _, ok := oldByName[name]; !ok {
+			if newField.Required {
+				d.report(true, KindAdded, model+"."+name, "required field added")
+			} else {
+				d.report(false, KindAdded, model+"."+name, "optional field added")
+			}
+		}
+	}
+}
+
+func describeField(f ir.IRField) string {
+	if f.Type == nil {
+		return "unknown"
+	}
+	return describe(*f.Type)
+}
+
+func describeResponse(r ir.IRResponse) string {
+	if r.TypeTS == "void" || r.TypeTS == "unknown" {
+		return r.TypeTS
+	}
+	return describe(r.Schema)
+}
+
+// describe renders a schema as a short language-neutral type such as "array<User>" or
+// "string(date-time) | null"; two schemas with the same description generate the same types
+func describe(s ir.IRSchema) string {
+	var t string
+	switch s.Kind {
+	case ir.IRKindString:
+		t = "string"
+		if s.Format != "" {
+			t += "(" + s.Format + ")"
+		}
+	case ir.IRKindRef:
+		t = s.Ref
+	case ir.IRKindArray:
+		item := "unknown"
+		if s.Items != nil {
+			item = describe(*s.Items)
+		}
+		t = "array<" + item + ">"
+	case ir.IRKindObject:
+		if len(s.Properties) == 0 && s.AdditionalProperties != nil {
+			t = "map<" + describe(*s.AdditionalProperties) + ">"
+			break
+		}
+		parts := make([]string, 0, len(s.Properties))
+		for _, f := range s.Properties {
+			opt := ""
+			if !f.Required {
+				opt = "?"
+			}
+			parts = append(parts, f.Name+opt+": "+describeField(f))
+		}
+		t = "{" + strings.Join(parts, ", ") + "}"
+	case ir.IRKindEnum:
+		t = "enum(" + strings.Join(s.EnumValues, ", ") + ")"
+	case ir.IRKindOneOf:
+		t = "oneOf<" + describeList(s.OneOf) + ">"
+	case ir.IRKindAnyOf:
+		t = "anyOf<" + describeList(s.AnyOf) + ">"
+	case ir.IRKindAllOf:
+		t = "allOf<" + describeList(s.AllOf) + ">"
+	case "":
+		t = string(ir.IRKindUnknown)
+	default:
+		t = string(s.Kind)
+	}
+	if s.Nullable && s.Kind != ir.IRKindNull {
+		t += " | null"
+	}
+	return t
+}
+
+func describeList(list []*ir.IRSchema) string {
+	parts := make([]string, 0, len(list))
+	for _, s := range list {
+		parts = append(parts, describe(*s))
+	}
+	return strings.Join(parts, ", ")
+}
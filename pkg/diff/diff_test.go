@@ -0,0 +1,142 @@
+package diff
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/blimu-dev/sdk-gen/pkg/ir"
+)
+
+func TestCompare(t *testing.T) {
+	str := &ir.IRSchema{Kind: ir.IRKindString}
+	integer := &ir.IRSchema{Kind: ir.IRKindInteger}
+	oldIR := ir.IR{
+		Services: []ir.IRService{{Tag: "users", Operations: []ir.IROperation{
+			{OperationID: "listUsers", Method: "GET", Path: "/users", QueryParams: []ir.IRParam{{Name: "limit", Schema: *integer}}},
+			{OperationID: "getUser", Method: "GET", Path: "/users/{id}", PathParams: []ir.IRParam{{Name: "id", Schema: *str, Required: true}},
+				Response: ir.IRResponse{Schema: ir.IRSchema{Kind: ir.IRKindRef, Ref: "User"}}},
+			{OperationID: "deleteUser", Method: "DELETE", Path: "/users/{id}", Response: ir.IRResponse{TypeTS: "void"}},
+		}}},
+		ModelDefs: []ir.IRModelDef{
+			{Name: "User", Schema: ir.IRSchema{Kind: ir.IRKindObject, Properties: []ir.IRField{
+				{Name: "id", Type: str, Required: true},
+				{Name: "age", Type: integer},
+				{Name: "email", Type: str},
+				{Name: "nickname", Type: str},
+			}}},
+			{Name: "Status", Schema: ir.IRSchema{Kind: ir.IRKindEnum, EnumValues: []string{"active", "banned"}}},
+			{Name: "Legacy", Schema: ir.IRSchema{Kind: ir.IRKindObject}},
+		},
+	}
+	newIR := ir.IR{
+		Services: []ir.IRService{{Tag: "users", Operations: []ir.IROperation{
+			{OperationID: "listUsers", Method: "GET", Path: "/users", QueryParams: []ir.IRParam{{Name: "limit", Schema: *integer}, {Name: "cursor", Schema: *str}}},
+			{OperationID: "getUser", Method: "GET", Path: "/users/{id}", PathParams: []ir.IRParam{{Name: "id", Schema: *integer, Required: true}},
+				Response: ir.IRResponse{Schema: ir.IRSchema{Kind: ir.IRKindRef, Ref: "User"}}},
+			{OperationID: "createUser", Method: "POST", Path: "/users", RequestBody: &ir.IRRequestBody{ContentType: "application/json", Required: true, Schema: ir.IRSchema{Kind: ir.IRKindRef, Ref: "User"}}},
+		}}},
+		ModelDefs: []ir.IRModelDef{
+			{Name: "User", Schema: ir.IRSchema{Kind: ir.IRKindObject, Properties: []ir.IRField{
+				{Name: "id", Type: str, Required: true},
+				{Name: "age", Type: str},
+				{Name: "email", Type: str, Required: true},
+				{Name: "role", Type: str},
+			}}},
+			{Name: "Status", Schema: ir.IRSchema{Kind: ir.IRKindEnum, EnumValues: []string{"active", "suspended"}}},
+			{Name: "Team", Schema: ir.IRSchema{Kind: ir.IRKindObject}},
+		},
+	}
+
+	report := Compare(oldIR, newIR)
+	got := make([]string, len(report.Changes))
+	for i, c := range report.Changes {
+		breaking := "non-breaking"
+		if c.Breaking {
+			breaking = "breaking"
+		}
+		got[i] = breaking + " " + c.Kind + " " + c.Location + ": " + c.Message
+	}
+	expected := []string{
+		"breaking removed DELETE /users/{id}: operation removed",
+		"breaking changed GET /users/{id} path parameter id: type changed from string to integer",
+		"breaking removed Legacy: model removed",
+		`breaking removed Status: enum value "banned" removed`,
+		"breaking changed User.age: type changed from integer to string",
+		"breaking changed User.email: field is now required",
+		"breaking removed User.nickname: field removed",
+		"non-breaking added GET /users query parameter cursor: optional parameter added",
+		"non-breaking added POST /users: operation added",
+		`non-breaking added Status: enum value "suspended" added`,
+		"non-breaking added Team: model added",
+		"non-breaking added User.role: optional field added",
+	}
+	if strings.Join(got, "\n") != strings.Join(expected, "\n") {
+		t.Errorf("Compare() changes:\n%s\n\nexpected:\n%s", strings.Join(got, "\n"), strings.Join(expected, "\n"))
+	}
+	if !report.HasBreaking() || report.BreakingCount() != 7 {
+		t.Errorf("expected 7 breaking changes, got %d", report.BreakingCount())
+	}
+}
+
+func TestCompareRequiredAdditions(t *testing.T) {
+	str := ir.IRSchema{Kind: ir.IRKindString}
+	op := func(query []ir.IRParam, body *ir.IRRequestBody) ir.IR {
+		return ir.IR{Services: []ir.IRService{{Tag: "users", Operations: []ir.IROperation{
+			{OperationID: "updateUser", Method: "PUT", Path: "/users", QueryParams: query, RequestBody: body},
+		}}}}
+	}
+
+	tests := []struct {
+		name     string
+		old, new ir.IR
+		breaking bool
+		message  string
+	}{
+		{"required parameter", op(nil, nil), op([]ir.IRParam{{Name: "force", Schema: str, Required: true}}, nil), true, "required parameter added"},
+		{"parameter now required", op([]ir.IRParam{{Name: "force", Schema: str}}, nil), op([]ir.IRParam{{Name: "force", Schema: str, Required: true}}, nil), true, "parameter is now required"},
+		{"required body", op(nil, nil), op(nil, &ir.IRRequestBody{Required: true, Schema: str}), true, "request body added"},
+		{"optional body", op(nil, nil), op(nil, &ir.IRRequestBody{Schema: str}), false, "request body added"},
+		{"body now required", op(nil, &ir.IRRequestBody{Schema: str}), op(nil, &ir.IRRequestBody{Required: true, Schema: str}), true, "request body is now required"},
+	}
+
+	for _, test := range tests {
+		report := Compare(test.old, test.new)
+		if len(report.Changes) != 1 {
+			t.Errorf("%s: expected 1 change, got %+v", test.name, report.Changes)
+			continue
+		}
+		if c := report.Changes[0]; c.Breaking != test.breaking || c.Message != test.message {
+			t.Errorf("%s: got %+v, expected breaking=%v message=%q", test.name, c, test.breaking, test.message)
+		}
+	}
+}
+
+func TestReportWriteText(t *testing.T) {
+	report := Report{Changes: []Change{
+		{Breaking: true, Kind: KindRemoved, Location: "User.email", Message: "field removed"},
+		{Kind: KindAdded, Location: "POST /users", Message: "operation added"},
+	}}
+	var buf bytes.Buffer
+	if err := report.WriteText(&buf); err != nil {
+		t.Fatal(err)
+	}
+	expected := "Breaking changes:\n" +
+		"  removed  User.email: field removed\n" +
+		"\n" +
+		"Non-breaking changes:\n" +
+		"  added    POST /users: operation added\n" +
+		"\n" +
+		"1 breaking, 1 non-breaking change(s)\n"
+	if buf.String() != expected {
+		t.Errorf("WriteText() =\n%s\nexpected:\n%s", buf.String(), expected)
+	}
+
+	buf.Reset()
+	if err := (Report{}).WriteText(&buf); err != nil {
+		t.Fatal(err)
+	}
+	if buf.String() != "0 breaking, 0 non-breaking change(s)\n" {
+		t.Errorf("unexpected empty report: %q", buf.String())
+	}
+}
@@ -1,19 +1,24 @@
 package generator
 
 import (
+	"log/slog"
 	"path/filepath"
 
 	"github.com/blimu-dev/sdk-gen/pkg/config"
+	"github.com/blimu-dev/sdk-gen/pkg/diff"
+	"github.com/blimu-dev/sdk-gen/pkg/lint"
 	"github.com/blimu-dev/sdk-gen/pkg/openapi"
 )
 
 // GenerateSDK is a convenience function for generating SDKs with minimal configuration
 func GenerateSDK(opts GenerateSDKOptions) error {
 	service := NewService()
+	service.SetLogger(opts.Logger)
 
 	genOpts := GenerateOptions{
 		ConfigPath:   opts.ConfigPath,
 		SingleClient: opts.SingleClient,
+		NoCache:      opts.NoCache,
 		Fallback: FallbackOptions{
 			Spec:        opts.Spec,
 			Type:        opts.Type,
@@ -22,6 +27,7 @@ func GenerateSDK(opts GenerateSDKOptions) error {
 			Name:        opts.Name,
 			IncludeTags: opts.IncludeTags,
 			ExcludeTags: opts.ExcludeTags,
+			SpecHeaders: opts.SpecHeaders,
 		},
 	}
 
@@ -36,6 +42,9 @@ type GenerateSDKOptions struct {
 	// SingleClient generates only the named client from config (optional)
 	SingleClient string
 
+	// NoCache bypasses the spec cache configured in the config file (optional)
+	NoCache bool
+
 	// Fallback options when no config file is provided
 	Spec        string   // OpenAPI spec file or URL
 	Type        string   // Generator type (e.g., "typescript")
@@ -44,6 +53,12 @@ type GenerateSDKOptions struct {
 	Name        string   // Client class name
 	IncludeTags []string // Regex patterns for tags to include
 	ExcludeTags []string // Regex patterns for tags to exclude
+
+	// SpecHeaders are sent when fetching an HTTP(S) spec, e.g. Authorization (optional)
+	SpecHeaders map[string]string
+
+	// Logger receives progress reports, see Service.SetLogger (optional, quiet when nil)
+	Logger *slog.Logger
 }
 
 // GenerateTypeScriptSDK is a convenience function specifically for TypeScript SDK generation
@@ -117,3 +132,26 @@ func GenerateFromConfig(configPath string, singleClient ...string) error {
 func ValidateSpec(specPath string) error {
 	return openapi.ValidateDocument(specPath)
 }
+
+// LintSpec loads an OpenAPI specification and reports issues that affect the generated SDKs
+func LintSpec(specPath string) (lint.Report, error) {
+	in, err := BuildIRFromSpec(specPath)
+	if err != nil {
+		return lint.Report{}, err
+	}
+	return lint.Lint(in), nil
+}
+
+// DiffSpecs loads two OpenAPI specifications and reports the operations and models that were
+// added, removed or changed between them
+func DiffSpecs(oldSpecPath, newSpecPath string) (diff.Report, error) {
+	oldIR, err := BuildIRFromSpec(oldSpecPath)
+	if err != nil {
+		return diff.Report{}, err
+	}
+	newIR, err := BuildIRFromSpec(newSpecPath)
+	if err != nil {
+		return diff.Report{}, err
+	}
+	return diff.Compare(oldIR, newIR), nil
+}
@@ -0,0 +1,105 @@
+package generator
+
+import (
+	"bytes"
+	"go/format"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/blimu-dev/sdk-gen/pkg/config"
+)
+
+// TestGenerateDeterministic generates the TypeScript, Go and Python SDKs for a fixture spec
+// several times and requires byte-identical output, so map iteration order can never leak
+// into generated files. Go sources must also be gofmt-clean.
+func TestGenerateDeterministic(t *testing.T) {
+	spec, err := filepath.Abs("testdata/determinism.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	types := []string{"typescript", "go", "python"}
+
+	var golden map[string][]byte
+	for run := 0; run < 3; run++ {
+		root := t.TempDir()
+		cfg := &config.Config{Spec: spec, UntaggedTag: config.DefaultUntaggedTag, UntaggedBehavior: config.UntaggedBucket}
+		for _, typ := range types {
+			cfg.Clients = append(cfg.Clients, config.Client{
+				Type:        typ,
+				OutDir:      filepath.Join(root, typ),
+				PackageName: "store",
+				ModuleName:  "example.com/store",
+				Name:        "Store",
+			})
+		}
+		if err := NewService().GenerateFromConfig(cfg, ""); err != nil {
+			t.Fatalf("run %d: %v", run, err)
+		}
+
+		files := readTree(t, root)
+		if golden == nil {
+			golden = files
+			checkGofmt(t, files)
+			continue
+		}
+		for name, want := range golden {
+			got, ok := files[name]
+			if !ok {
+				t.Errorf("run %d: %s was not generated", run, name)
+				continue
+			}
+			if !bytes.Equal(got, want) {
+				t.Errorf("run %d: %s differs from the first run", run, name)
+			}
+		}
+		for name := range files {
+			if _, ok := golden[name]; !ok {
+				t.Errorf("run %d: unexpected file %s", run, name)
+			}
+		}
+	}
+}
+
+// readTree returns the contents of every file under root keyed by slash-separated relative path
+func readTree(t *testing.T, root string) map[string][]byte {
+	t.Helper()
+	files := map[string][]byte{}
+	err := filepath.WalkDir(root, func(path string, d os.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(root, path)
+		if err != nil {
+			return err
+		}
+		files[filepath.ToSlash(rel)] = data
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return files
+}
+
+func checkGofmt(t *testing.T, files map[string][]byte) {
+	t.Helper()
+	for name, data := range files {
+		if !strings.HasSuffix(name, ".go") {
+			continue
+		}
+		formatted, err := format.Source(data)
+		if err != nil {
+			t.Errorf("%s does not parse: %v", name, err)
+			continue
+		}
+		if !bytes.Equal(formatted, data) {
+			t.Errorf("%s is not gofmt-clean", name)
+		}
+	}
+}
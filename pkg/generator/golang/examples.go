@@ -0,0 +1,448 @@
+package golang
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/blimu-dev/sdk-gen/pkg/config"
+	"github.com/blimu-dev/sdk-gen/pkg/ir"
+)
+
+// goExample is one Example function of example_test.go, calling a representative operation
+// of a service
+type goExample struct {
+	// Name is the example function name, e.g. ExampleUsersService_ListUsers
+	Name      string
+	Operation ir.IROperation
+	// Call is the method call without the client, e.g. Users.ListUsers(ctx, "42")
+	Call string
+}
+
+// exampleTests holds what example_test.go needs: one example per service and the imports
+// their arguments use besides context, fmt and the SDK itself
+type exampleTests struct {
+	Examples []goExample
+	Imports  []string
+	// Client is the variable holding the client, "client" unless the package has that name
+	Client string
+}
+
+// buildExamples picks a representative operation of every service and renders a call to it
+// with arguments taken from the documented examples and defaults
+func buildExamples(client config.Client, in ir.IR, methodName func(ir.IROperation) string) exampleTests {
+	pkg := sanitizePackageName(client.PackageName)
+	r := exampleRenderer{pkg: pkg, opts: newTypeOptions(client), defs: modelDefsByName(in.ModelDefs), imports: map[string]bool{}}
+	withContext := contextMode(client) != config.GoNoContext
+
+	var out exampleTests
+	for _, service := range in.Services {
+		if len(service.Operations) == 0 {
+			continue
+		}
+		op := exampleOperation(service.Operations)
+		method := methodName(op)
+		var args []string
+		if withContext {
+			method = contextMethodName(client, method)
+			args = append(args, "ctx")
+		}
+		for _, p := range orderPathParams(op) {
+			value := paramValue(p)
+			// Undocumented string parameters show their name, e.g. "userId"
+			if value == nil && p.Schema.Kind == ir.IRKindString {
+				value = p.Name
+			}
+			args = append(args, r.literal(p.Schema, value))
+		}
+		if len(op.QueryParams) > 0 {
+			args = append(args, r.query(op, methodName(op)))
+		}
+		if op.RequestBody != nil {
+			var body any
+			if len(op.RequestBody.Examples) > 0 {
+				body = op.RequestBody.Examples[0]
+			}
+			args = append(args, r.literal(op.RequestBody.Schema, body))
+		}
+		if op.Response.Stream {
+			r.imports["io"] = true
+		}
+		out.Examples = append(out.Examples, goExample{
+			Name:      "Example" + toPascalCase(service.Tag) + "Service_" + method,
+			Operation: op,
+			Call:      serviceAccessor(service.Tag) + "." + method + "(" + strings.Join(args, ", ") + ")",
+		})
+	}
+	out.Imports = sortedImports(r.imports, nil)
+	out.Client = "client"
+	if pkg == "client" {
+		out.Client = "c"
+	}
+	return out
+}
+
+// exampleOperation prefers a GET listing a collection, then a POST creating something, then
+// any GET, among operations without path parameters; otherwise the first operation
+func exampleOperation(ops []ir.IROperation) ir.IROperation {
+	rank := func(op ir.IROperation) int {
+		if len(op.PathParams) > 0 {
+			return 3
+		}
+		switch strings.ToUpper(op.Method) {
+		case "GET":
+			if op.Response.Schema.Kind == ir.IRKindArray {
+				return 0
+			}
+			return 2
+		case "POST":
+			return 1
+		}
+		return 3
+	}
+	best := ops[0]
+	for _, op := range ops[1:] {
+		if rank(op) < rank(best) {
+			best = op
+		}
+	}
+	return best
+}
+
+// serviceAccessor returns the client field path of a service: Users, or Admin.Users for a
+// namespaced tag like admin.users
+func serviceAccessor(tag string) string {
+	if ns, name, ok := strings.Cut(tag, "."); ok {
+		return toPascalCase(ns) + "." + toPascalCase(strings.Split(name, ".")[0])
+	}
+	return toPascalCase(tag)
+}
+
+// paramValue returns the first example of a parameter, else its default
+func paramValue(p ir.IRParam) any {
+	if len(p.Examples) > 0 {
+		return p.Examples[0]
+	}
+	return p.Default
+}
+
+// exampleRenderer turns example values into Go expressions of the types the SDK declares,
+// qualified with the SDK package name since examples live in the external test package
+type exampleRenderer struct {
+	pkg     string
+	opts    typeOptions
+	defs    map[string]ir.IRModelDef
+	imports map[string]bool
+}
+
+// query renders the query struct of an operation with its required parameters set
+func (r exampleRenderer) query(op ir.IROperation, methodName string) string {
+	var fields []string
+	for _, p := range op.QueryParams {
+		if p.Required {
+			// Query fields are never nullable, see the queryGoType template func
+			schema := p.Schema
+			schema.Nullable = false
+			fields = append(fields, toPascalCase(p.Name)+": "+r.literal(schema, paramValue(p)))
+		}
+	}
+	return "&" + r.pkg + "." + queryTypeName(op, methodName) + "{" + strings.Join(fields, ", ") + "}"
+}
+
+// literal renders value as an expression of the Go type of s. A nil value is replaced by a
+// placeholder: the schema's own example or default when it is a model, an empty string,
+// zero or an empty collection otherwise.
+func (r exampleRenderer) literal(s ir.IRSchema, value any) string {
+	return r.render(s, value, map[string]bool{})
+}
+
+func (r exampleRenderer) render(s ir.IRSchema, value any, seen map[string]bool) string {
+	if override := s.TypeOverrides["go"]; override != "" {
+		if s.Nullable {
+			return "nil"
+		}
+		// The type is not known to the generator, so only its zero value can be written
+		return "*new(" + r.typeName(s) + ")"
+	}
+	if s.Nullable {
+		if value == nil {
+			return "nil"
+		}
+		base := s
+		base.Nullable = false
+		lit := r.render(base, value, seen)
+		// Only composite literals can have their address taken
+		if strings.HasSuffix(lit, "}") {
+			return "&" + lit
+		}
+		return "nil"
+	}
+
+	switch s.Kind {
+	case ir.IRKindString:
+		text, _ := value.(string)
+		switch schemaToGoType(s, r.opts) {
+		case "[]byte":
+			return "[]byte(" + strconv.Quote(text) + ")"
+		case "time.Time":
+			return r.timeLiteral(text, time.RFC3339)
+		case "Date":
+			return r.pkg + ".Date{Time: " + r.timeLiteral(text, "2006-01-02") + "}"
+		}
+		if value != nil && text == "" {
+			text = fmt.Sprint(value)
+		}
+		return strconv.Quote(text)
+	case ir.IRKindInteger:
+		switch v := value.(type) {
+		case float64:
+			return strconv.FormatInt(int64(v), 10)
+		case int, int64, uint64:
+			return fmt.Sprint(v)
+		}
+		return "0"
+	case ir.IRKindNumber:
+		switch v := value.(type) {
+		case float64:
+			return strconv.FormatFloat(v, 'g', -1, 64)
+		case int, int64, uint64:
+			return fmt.Sprint(v)
+		}
+		return "0"
+	case ir.IRKindBoolean:
+		if v, ok := value.(bool); ok {
+			return strconv.FormatBool(v)
+		}
+		return "false"
+	case ir.IRKindEnum:
+		// Inline enums are values of their base type; unknown values fall back to the first member
+		literals := s.EnumLiterals()
+		if len(literals) == 0 {
+			return r.render(ir.IRSchema{Kind: s.EnumBase}, nil, seen)
+		}
+		i := 0
+		if value != nil {
+			i = max(slices.Index(s.EnumValues, ir.FormatEnumValue(value)), 0)
+		}
+		if goEnumBaseType(s) == "string" {
+			return strconv.Quote(s.EnumValues[i])
+		}
+		return literals[i]
+	case ir.IRKindArray:
+		t := r.typeName(s)
+		items, _ := value.([]any)
+		if s.Items == nil {
+			return t + "{" + r.rawList(items) + "}"
+		}
+		parts := make([]string, 0, len(items))
+		for _, item := range items {
+			parts = append(parts, r.render(*s.Items, item, seen))
+		}
+		return t + "{" + strings.Join(parts, ", ") + "}"
+	case ir.IRKindRef:
+		return r.model(s.Ref, value, seen)
+	case ir.IRKindObject:
+		obj, _ := value.(map[string]any)
+		if mapValue := goMapValue(s); mapValue != nil {
+			return r.typeName(s) + "{" + r.typedMap(*mapValue, obj, seen) + "}"
+		}
+		if obj == nil {
+			obj = map[string]any{}
+			for _, f := range s.Properties {
+				if v, ok := annotatedValue(f.Annotations); ok {
+					obj[f.Name] = v
+				}
+			}
+		}
+		return "map[string]interface{}{" + r.rawMap(obj) + "}"
+	}
+	return r.raw(value)
+}
+
+// typeName returns the Go type of s as seen from the test package: models, Date and type
+// overrides naming an SDK type are qualified with the package name
+func (r exampleRenderer) typeName(s ir.IRSchema) string {
+	var t string
+	switch override := s.TypeOverrides["go"]; {
+	case override != "":
+		if imp := s.TypeImports["go"]; imp != "" {
+			r.imports[imp] = true
+		}
+		t = override
+		if !strings.ContainsAny(t, ".[]*") && unicode.IsUpper([]rune(t)[0]) {
+			t = r.pkg + "." + t
+		}
+	case s.Kind == ir.IRKindRef && s.Ref != "":
+		t = r.pkg + "." + toPascalCase(s.Ref)
+	case s.Kind == ir.IRKindArray && s.Items != nil:
+		t = "[]" + r.typeName(*s.Items)
+	case goMapValue(s) != nil:
+		t = "map[string]" + r.typeName(*goMapValue(s))
+	default:
+		base := s
+		base.Nullable = false
+		t = schemaToGoType(base, r.opts)
+		if t == "Date" {
+			t = r.pkg + ".Date"
+		} else if t == "time.Time" {
+			r.imports["time"] = true
+		}
+	}
+	if s.Nullable {
+		t = "*" + t
+	}
+	return t
+}
+
+// model renders a struct literal of a model, setting the fields present in value. Without a
+// value the model's example or default is used, else its required fields get placeholders.
+func (r exampleRenderer) model(name string, value any, seen map[string]bool) string {
+	t := r.pkg + "." + toPascalCase(name)
+	md, ok := r.defs[name]
+	if ok && md.Schema.Kind == ir.IRKindEnum {
+		return r.enumMember(name, md, value)
+	}
+	// Placeholders stop at recursive models; example values always end
+	if !ok || value == nil && seen[name] {
+		return t + "{}"
+	}
+	if !seen[name] {
+		seen[name] = true
+		defer delete(seen, name)
+	}
+
+	if value == nil {
+		value, _ = annotatedValue(md.Annotations)
+	}
+	obj, hasValue := value.(map[string]any)
+	if mapValue := goMapValue(md.Schema); mapValue != nil {
+		return t + "{" + r.typedMap(*mapValue, obj, seen) + "}"
+	}
+	body := goStructBody(r.defs, md.Schema)
+	var fields []string
+	for _, embed := range body.Embeds {
+		fields = append(fields, toPascalCase(embed)+": "+r.model(embed, value, seen))
+	}
+	for _, f := range body.Fields {
+		if f.Type == nil {
+			continue
+		}
+		v, set := obj[f.Name]
+		if !hasValue {
+			v, set = annotatedValue(f.Annotations)
+			set = set || f.Required
+		}
+		if set {
+			fields = append(fields, toPascalCase(f.Name)+": "+r.render(*f.Type, v, seen))
+		}
+	}
+	return t + "{" + strings.Join(fields, ", ") + "}"
+}
+
+// timeLiteral renders a time.Date call for text parsed with layout, or for 2024-01-01
+func (r exampleRenderer) timeLiteral(text, layout string) string {
+	r.imports["time"] = true
+	t, err := time.Parse(layout, text)
+	if err != nil {
+		t = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	}
+	t = t.UTC()
+	return fmt.Sprintf("time.Date(%d, %d, %d, %d, %d, %d, 0, time.UTC)", t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second())
+}
+
+// raw renders a decoded JSON value as an interface{} expression
+func (r exampleRenderer) raw(value any) string {
+	switch v := value.(type) {
+	case nil:
+		return "nil"
+	case string:
+		return strconv.Quote(v)
+	case bool:
+		return strconv.FormatBool(v)
+	case float64:
+		return strconv.FormatFloat(v, 'g', -1, 64)
+	case int, int64, uint64:
+		return fmt.Sprint(v)
+	case []any:
+		return "[]interface{}{" + r.rawList(v) + "}"
+	case map[string]any:
+		return "map[string]interface{}{" + r.rawMap(v) + "}"
+	}
+	return strconv.Quote(fmt.Sprint(value))
+}
+
+func (r exampleRenderer) rawList(items []any) string {
+	parts := make([]string, 0, len(items))
+	for _, item := range items {
+		parts = append(parts, r.raw(item))
+	}
+	return strings.Join(parts, ", ")
+}
+
+func (r exampleRenderer) rawMap(obj map[string]any) string {
+	keys := make([]string, 0, len(obj))
+	for k := range obj {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	parts := make([]string, 0, len(keys))
+	for _, k := range keys {
+		parts = append(parts, strconv.Quote(k)+": "+r.raw(obj[k]))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// typedMap renders the entries of obj as the keyed elements of a map[string]T literal, with T
+// the Go type of value
+func (r exampleRenderer) typedMap(value ir.IRSchema, obj map[string]any, seen map[string]bool) string {
+	keys := make([]string, 0, len(obj))
+	for k := range obj {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	parts := make([]string, 0, len(keys))
+	for _, k := range keys {
+		parts = append(parts, strconv.Quote(k)+": "+r.render(value, obj[k], seen))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// annotatedValue returns the first example, else the default, of a model or property
+func annotatedValue(a ir.IRAnnotations) (any, bool) {
+	if len(a.Examples) > 0 {
+		return a.Examples[0], true
+	}
+	if a.Default != nil {
+		return a.Default, true
+	}
+	return nil, false
+}
+
+// enumMember renders a value of a named enum as its generated constant, falling back to the
+// first member for unknown values. Enums without constants convert the literal instead.
+func (r exampleRenderer) enumMember(name string, md ir.IRModelDef, value any) string {
+	if value == nil {
+		value, _ = annotatedValue(md.Annotations)
+	}
+	literals := md.Schema.EnumLiterals()
+	index := 0
+	for i, v := range literals {
+		if value != nil && ir.FormatEnumValue(value) == v {
+			index = i
+			break
+		}
+	}
+	consts := goEnumConsts(name, md.Schema)
+	if index < len(consts) {
+		return r.pkg + "." + consts[index].Name
+	}
+	literal := "false"
+	if index < len(literals) {
+		literal = literals[index]
+	}
+	return r.pkg + "." + toPascalCase(name) + "(" + literal + ")"
+}
@@ -1,8 +1,11 @@
 package golang
 
 import (
+	"bytes"
 	"embed"
 	"fmt"
+	"go/format"
+	"log/slog"
 	"os"
 	"path/filepath"
 	"regexp"
@@ -13,13 +16,19 @@ import (
 
 	"github.com/blimu-dev/sdk-gen/pkg/config"
 	"github.com/blimu-dev/sdk-gen/pkg/ir"
+	"github.com/blimu-dev/sdk-gen/pkg/utils"
 )
 
 //go:embed templates/*
 var templatesFS embed.FS
 
 // GoGenerator implements the Generator interface for Go
-type GoGenerator struct{}
+type GoGenerator struct {
+	// funcs are the template functions set with SetTemplateFuncs
+	funcs template.FuncMap
+	// logger receives warnings, see SetLogger
+	logger *slog.Logger
+}
 
 // NewGoGenerator creates a new Go generator
 func NewGoGenerator() *GoGenerator {
@@ -31,6 +40,18 @@ func (g *GoGenerator) GetType() string {
 	return "go"
 }
 
+// SetTemplateFuncs adds funcs to the functions available to the templates. A function named
+// like a built-in helper replaces it.
+func (g *GoGenerator) SetTemplateFuncs(funcs template.FuncMap) {
+	g.funcs = funcs
+}
+
+// SetLogger sets the logger warnings found while generating, such as renamed methods, are
+// reported to
+func (g *GoGenerator) SetLogger(logger *slog.Logger) {
+	g.logger = logger
+}
+
 // Generate creates a Go SDK from the given configuration and IR
 func (g *GoGenerator) Generate(client config.Client, in ir.IR) error {
 	// Create directory structure
@@ -38,29 +59,69 @@ func (g *GoGenerator) Generate(client config.Client, in ir.IR) error {
 		return err
 	}
 
+	typeOpts := newTypeOptions(client)
+	methodNames, warnings := utils.NewMethodNames(in.Services, func(op ir.IROperation) string { return ResolveMethodName(client, op) })
+	utils.LogWarnings(g.logger, client.Name, warnings)
+	methodName := methodNames.Get
+	modelDefs := modelDefsByName(in.ModelDefs)
+
 	funcMap := template.FuncMap{
-		"pascal":          toPascalCase,
-		"camel":           toCamelCase,
-		"snake":           toSnakeCase,
-		"kebab":           toKebabCase,
-		"serviceName":     func(tag string) string { return toPascalCase(tag) + "Service" },
-		"serviceField":    func(tag string) string { return toPascalCase(tag) },
-		"methodName":      func(op ir.IROperation) string { return ResolveMethodName(client, op) },
-		"queryTypeName":   func(op ir.IROperation) string { return toPascalCase(op.Tag) + ResolveMethodName(client, op) + "Query" },
-		"goType":          func(x any) string { return schemaToGoType(x) },
-		"goStructTag":     func(name string) string { return fmt.Sprintf("`json:\"%s\"`", name) },
-		"pathTemplate":    func(op ir.IROperation) string { return buildPathTemplate(op) },
-		"pathParams":      func(op ir.IROperation) []ir.IRParam { return orderPathParams(op) },
-		"queryParams":     func(op ir.IROperation) []ir.IRParam { return op.QueryParams },
-		"hasPathParams":   func(op ir.IROperation) bool { return len(op.PathParams) > 0 },
-		"hasQueryParams":  func(op ir.IROperation) bool { return len(op.QueryParams) > 0 },
-		"hasRequestBody":  func(op ir.IROperation) bool { return op.RequestBody != nil },
-		"methodSignature": func(op ir.IROperation) string { return buildMethodSignature(client, op, ResolveMethodName(client, op)) },
-		"reMatch":         func(pattern, s string) bool { r := regexp.MustCompile(pattern); return r.MatchString(s) },
-		"formatGoComment": formatGoComment,
-		"replace":         strings.ReplaceAll,
-		"printf":          fmt.Sprintf,
-		"packageName":     func() string { return sanitizePackageName(client.PackageName) },
+		"pascal":         toPascalCase,
+		"camel":          toCamelCase,
+		"snake":          toSnakeCase,
+		"kebab":          toKebabCase,
+		"serviceName":    func(tag string) string { return toPascalCase(tag) + "Service" },
+		"serviceField":   func(tag string) string { return toPascalCase(tag) },
+		"methodName":     methodName,
+		"queryTypeName":  func(op ir.IROperation) string { return queryTypeName(op, methodName(op)) },
+		"operationKey":   operationKey,
+		"operationConst": func(op ir.IROperation) string { return operationConst(op, methodName(op)) },
+		"goType":         func(x any) string { return schemaToGoType(x, typeOpts) },
+		"resultType":     func(op ir.IROperation) string { return responseGoType(op, typeOpts) },
+		"queryGoType": func(s ir.IRSchema) string {
+			// Optional query fields are pointers already and null cannot be sent
+			s.Nullable = false
+			return schemaToGoType(s, typeOpts)
+		},
+		"goStructTag":      func(name string) string { return fmt.Sprintf("`json:\"%s\"`", name) },
+		"modelStructTag":   func(f ir.IRField) string { return modelStructTag(f, typeOpts) },
+		"pathTemplate":     func(op ir.IROperation) string { return buildPathTemplate(op) },
+		"pathParams":       func(op ir.IROperation) []ir.IRParam { return orderPathParams(op) },
+		"queryParams":      func(op ir.IROperation) []ir.IRParam { return op.QueryParams },
+		"hasPathParams":    func(op ir.IROperation) bool { return len(op.PathParams) > 0 },
+		"hasQueryParams":   func(op ir.IROperation) bool { return len(op.QueryParams) > 0 },
+		"hasRequestBody":   func(op ir.IROperation) bool { return op.RequestBody != nil },
+		"queryValue":       func(x any, expr string) string { return queryValueExpr(x, expr, typeOpts) },
+		"isNativeDate":     func(x any) bool { return isNativeDate(x, typeOpts) },
+		"queryDefaults":    func(p ir.IRParam) []string { return queryDefaultValues(p) },
+		"hasQueryDefaults": func(op ir.IROperation) bool { return hasQueryDefaults(op) },
+		"errorModelCases":  func(op ir.IROperation) []errorModelCase { return errorModelCases(op, typeOpts) },
+		"enumBaseType":     goEnumBaseType,
+		"enumConsts":       goEnumConsts,
+		"structBody":       func(s ir.IRSchema) goStruct { return goStructBody(modelDefs, s) },
+		"structDefaults":   func(body goStruct) goDefaults { return goStructDefaults(modelDefs, body, typeOpts) },
+		"bodyHasDefaults":  func(op ir.IROperation) bool { return bodyHasDefaults(modelDefs, op, typeOpts) },
+		"mapValue":         goMapValue,
+		"methodSignature": func(op ir.IROperation, withContext bool) string {
+			return buildMethodSignature(client, op, methodName(op), withContext)
+		},
+		"reMatch":           func(pattern, s string) bool { r := regexp.MustCompile(pattern); return r.MatchString(s) },
+		"formatGoComment":   formatGoComment,
+		"fieldComment":      fieldComment,
+		"fieldDoc":          fieldDoc,
+		"modelFieldComment": modelFieldComment,
+		"replace":           strings.ReplaceAll,
+		"printf":            fmt.Sprintf,
+		"packageName":       func() string { return sanitizePackageName(client.PackageName) },
+		"modelImports":      func() []string { return modelFileImports(client, in) },
+		"serviceImports": func(service ir.IRService) []string {
+			return serviceFileImports(client, service, methodName)
+		},
+		"environments":   func() []utils.Environment { return utils.Environments(client.Environments, in.Servers) },
+		"defaultBaseURL": func() string { return defaultBaseURL(client, in.Servers) },
+		"interfaceImports": func(services []ir.IRService) []string {
+			return interfaceImports(client, services, methodName)
+		},
 		"moduleName": func() string {
 			if client.ModuleName != "" {
 				return client.ModuleName
@@ -98,25 +159,32 @@ func (g *GoGenerator) Generate(client config.Client, in ir.IR) error {
 			}
 			return tag // Return the whole tag if no dot
 		},
-		// Method signature helpers for dual context pattern
-		"methodSignatureWithContext": func(op ir.IROperation) string {
-			return buildMethodSignature(client, op, ResolveMethodName(client, op)+"WithContext")
-		},
-		"methodSignatureNoContext": func(op ir.IROperation) string {
-			return buildMethodSignatureNoContext(client, op, ResolveMethodName(client, op))
-		},
+		// Method variants chosen by goContextMode
+		"goContextMode":     func() string { return contextMode(client) },
+		"contextMethodName": func(op ir.IROperation) string { return contextMethodName(client, methodName(op)+acceptSuffix(op)) },
+		"acceptSuffix":      acceptSuffix,
+		"acceptHeader":      acceptHeader,
 	}
 
 	// Merge sprig functions
 	for k, v := range sprig.FuncMap() {
 		funcMap[k] = v
 	}
+	// User functions come last and replace helpers of the same name
+	for k, v := range g.funcs {
+		funcMap[k] = v
+	}
 
 	// Generate client.go
 	if err := renderFile(client, "client.go.gotmpl", filepath.Join(client.OutDir, "client.go"), funcMap, map[string]any{"Client": client, "IR": in}); err != nil {
 		return err
 	}
 
+	// Generate errors.go
+	if err := renderFile(client, "errors.go.gotmpl", filepath.Join(client.OutDir, "errors.go"), funcMap, map[string]any{"Client": client, "IR": in}); err != nil {
+		return err
+	}
+
 	// Generate models.go
 	if err := renderFile(client, "models.go.gotmpl", filepath.Join(client.OutDir, "models.go"), funcMap, map[string]any{"Client": client, "IR": in}); err != nil {
 		return err
@@ -128,8 +196,45 @@ func (g *GoGenerator) Generate(client config.Client, in ir.IR) error {
 		if len(service.Operations) == 0 {
 			continue
 		}
-		fileName := fmt.Sprintf("%s.go", toSnakeCase(service.Tag))
-		if err := renderFile(client, "service.go.gotmpl", filepath.Join(client.OutDir, fileName), funcMap, map[string]any{"Client": client, "Service": service}); err != nil {
+		if !client.GoFilePerOperation {
+			fileName := serviceFileName(client, service.Tag)
+			if err := renderFile(client, "service.go.gotmpl", filepath.Join(client.OutDir, fileName), funcMap, map[string]any{"Client": client, "Service": service, "DeclareService": true}); err != nil {
+				return err
+			}
+			continue
+		}
+
+		// One file for the service struct and one per operation, named after its method
+		declaration := ir.IRService{Tag: service.Tag}
+		if err := renderFile(client, "service.go.gotmpl", filepath.Join(client.OutDir, serviceFileName(client, service.Tag)), funcMap, map[string]any{"Client": client, "Service": declaration, "DeclareService": true}); err != nil {
+			return err
+		}
+		for _, op := range service.Operations {
+			fileName := serviceFileName(client, service.Tag, methodName(op))
+			single := ir.IRService{Tag: service.Tag, Operations: []ir.IROperation{op}}
+			if err := renderFile(client, "service.go.gotmpl", filepath.Join(client.OutDir, fileName), funcMap, map[string]any{"Client": client, "Service": single}); err != nil {
+				return err
+			}
+		}
+	}
+
+	// Generate interfaces.go
+	if client.GoServiceInterfaces {
+		if err := renderFile(client, "interfaces.go.gotmpl", filepath.Join(client.OutDir, "interfaces.go"), funcMap, map[string]any{"Client": client, "IR": in}); err != nil {
+			return err
+		}
+	}
+
+	// Generate operations.go
+	if client.EmitOperationMetadata {
+		if err := renderFile(client, "operations.go.gotmpl", filepath.Join(client.OutDir, "operations.go"), funcMap, map[string]any{"Client": client, "IR": in}); err != nil {
+			return err
+		}
+	}
+
+	// Generate example_test.go, compiled by go test to keep the examples in sync with the SDK
+	if examples := buildExamples(client, in, methodName); len(examples.Examples) > 0 {
+		if err := renderFile(client, "example_test.go.gotmpl", filepath.Join(client.OutDir, "example_test.go"), funcMap, map[string]any{"Client": client, "Examples": examples}); err != nil {
 			return err
 		}
 	}
@@ -164,14 +269,24 @@ func renderFile(client config.Client, templateName, targetPath string, funcMap t
 		return fmt.Errorf("failed to parse template %s: %w", templateName, err)
 	}
 
-	file, err := os.Create(targetPath)
-	if err != nil {
-		return fmt.Errorf("failed to create file %s: %w", targetPath, err)
+	var buf bytes.Buffer
+	buf.WriteString(utils.FileHeader(client.FileHeader, targetPath))
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return fmt.Errorf("failed to execute template %s: %w", templateName, err)
 	}
-	defer file.Close()
 
-	if err := tmpl.Execute(file, data); err != nil {
-		return fmt.Errorf("failed to execute template %s: %w", templateName, err)
+	// gofmt Go sources so the output is clean regardless of template whitespace
+	out := buf.Bytes()
+	if filepath.Ext(targetPath) == ".go" {
+		formatted, err := format.Source(out)
+		if err != nil {
+			return fmt.Errorf("failed to format %s: %w", targetPath, err)
+		}
+		out = formatted
+	}
+
+	if err := os.WriteFile(targetPath, out, 0o644); err != nil {
+		return fmt.Errorf("failed to write file %s: %w", targetPath, err)
 	}
 
 	return nil
@@ -0,0 +1,186 @@
+package golang
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/blimu-dev/sdk-gen/pkg/config"
+	"github.com/blimu-dev/sdk-gen/pkg/ir"
+)
+
+// roundTripTest checks that a model extending one with additional properties keeps its own
+// fields through JSON
+const roundTripTest = `package users
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestUserRoundTrip(t *testing.T) {
+	data, err := json.Marshal(User{Id: "1", Name: "ada", AdditionalProperties: map[string]string{"team": "core"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != ` + "`" + `{"id":"1","name":"ada","team":"core"}` + "`" + ` {
+		t.Errorf("Marshal() = %s", data)
+	}
+	var user User
+	if err := json.Unmarshal(data, &user); err != nil {
+		t.Fatal(err)
+	}
+	if user.Id != "1" || user.Name != "ada" || len(user.AdditionalProperties) != 1 || user.AdditionalProperties["team"] != "core" {
+		t.Errorf("Unmarshal() = %+v", user)
+	}
+}
+`
+
+func TestAdditionalPropertiesBaseRoundTrip(t *testing.T) {
+	if testing.Short() {
+		t.Skip("compiles the generated package")
+	}
+	goTool, err := exec.LookPath("go")
+	if err != nil {
+		t.Skip("go is not installed")
+	}
+
+	str := ir.IRSchema{Kind: ir.IRKindString}
+	field := func(name string) ir.IRField { return ir.IRField{Name: name, Type: &str} }
+	user := ir.IRSchema{Kind: ir.IRKindRef, Ref: "User"}
+	in := ir.IR{
+		ModelDefs: []ir.IRModelDef{
+			{Name: "Base", Schema: ir.IRSchema{Kind: ir.IRKindObject, Properties: []ir.IRField{field("id")}, AdditionalProperties: &str}},
+			{Name: "User", Schema: ir.IRSchema{Kind: ir.IRKindAllOf, AllOf: []*ir.IRSchema{
+				{Kind: ir.IRKindRef, Ref: "Base"},
+				{Kind: ir.IRKindObject, Properties: []ir.IRField{field("name")}},
+			}}},
+		},
+		Services: []ir.IRService{{Tag: "users", Operations: []ir.IROperation{{
+			OperationID: "listUsers",
+			Method:      "GET",
+			Path:        "/users",
+			Tag:         "users",
+			QueryParams: []ir.IRParam{{Name: "team", Schema: str}},
+			Response:    ir.IRResponse{TypeTS: "User", Schema: user, StatusCode: "200", ContentType: "application/json"},
+		}}}},
+	}
+	dir := t.TempDir()
+	client := config.Client{Type: "go", OutDir: dir, PackageName: "users", ModuleName: "example.com/users", Name: "Users"}
+	if err := NewGoGenerator().Generate(client, in); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "roundtrip_test.go"), []byte(roundTripTest), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	cmd := exec.Command(goTool, "test", "-run", "TestUserRoundTrip", ".")
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(), "GOFLAGS=-mod=mod")
+	if out, err := cmd.CombinedOutput(); err != nil {
+		t.Errorf("go test in the generated package: %v\n%s", err, out)
+	}
+}
+
+// hooksTest checks that request hooks see the request as sent and cannot hide or replace the
+// error of a failed one
+const hooksTest = `package items
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+type failingTransport struct{ err error }
+
+func (f failingTransport) RoundTrip(*http.Request) (*http.Response, error) { return nil, f.err }
+
+func TestRequestHooks(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(` + "`" + `"ok"` + "`" + `))
+	}))
+	defer server.Close()
+
+	team := "core"
+	var seen *http.Request
+	c := NewClient(WithBaseURL(server.URL), WithHeaders(map[string]string{"X-Team": "core"}), WithBearerAuth("secret"),
+		WithRequestHook(func(req *http.Request, next RoundTripFunc) (*http.Response, error) {
+			seen = req
+			return next(req)
+		}))
+	if _, err := c.Items.ListItems(context.Background(), &ItemsListItemsQuery{Team: &team}); err != nil {
+		t.Fatal(err)
+	}
+	if got := seen.URL.String(); got != server.URL+"/items?team=core" {
+		t.Errorf("hook saw URL %s", got)
+	}
+	if seen.Header.Get("Authorization") != "Bearer secret" || seen.Header.Get("X-Team") != "core" {
+		t.Errorf("hook saw headers %v", seen.Header)
+	}
+	if got := OperationIDFromContext(seen.Context()); got != "listItems" {
+		t.Errorf("OperationIDFromContext() = %q", got)
+	}
+
+	requestErr := errors.New("connection reset")
+	hookErr := errors.New("hook failed")
+	for name, hook := range map[string]RequestHook{
+		"swallow": func(req *http.Request, next RoundTripFunc) (*http.Response, error) {
+			next(req)
+			return &http.Response{StatusCode: http.StatusOK, Body: http.NoBody}, nil
+		},
+		"replace": func(req *http.Request, next RoundTripFunc) (*http.Response, error) {
+			next(req)
+			return nil, hookErr
+		},
+	} {
+		c := NewClient(WithBaseURL(server.URL), WithHTTPClient(&http.Client{Transport: failingTransport{requestErr}}), WithRequestHook(hook))
+		_, err := c.Items.ListItems(context.Background(), nil)
+		if !errors.Is(err, requestErr) || errors.Is(err, hookErr) {
+			t.Errorf("%s: ListItems() error = %v, expected the request's error", name, err)
+		}
+	}
+}
+`
+
+func TestRequestHooks(t *testing.T) {
+	if testing.Short() {
+		t.Skip("compiles the generated package")
+	}
+	goTool, err := exec.LookPath("go")
+	if err != nil {
+		t.Skip("go is not installed")
+	}
+
+	str := ir.IRSchema{Kind: ir.IRKindString}
+	in := ir.IR{
+		SecuritySchemes: []ir.IRSecurityScheme{{Key: "bearerAuth", Type: "http", Scheme: "bearer"}},
+		Services: []ir.IRService{{Tag: "items", Operations: []ir.IROperation{{
+			OperationID: "listItems",
+			Method:      "GET",
+			Path:        "/items",
+			Tag:         "items",
+			QueryParams: []ir.IRParam{{Name: "team", Schema: str}},
+			Response:    ir.IRResponse{TypeTS: "string", Schema: str, StatusCode: "200", ContentType: "application/json"},
+		}}}},
+	}
+	dir := t.TempDir()
+	client := config.Client{Type: "go", OutDir: dir, PackageName: "items", ModuleName: "example.com/items", Name: "Items"}
+	if err := NewGoGenerator().Generate(client, in); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "hooks_test.go"), []byte(hooksTest), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	cmd := exec.Command(goTool, "test", "-run", "TestRequestHooks", ".")
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(), "GOFLAGS=-mod=mod")
+	if out, err := cmd.CombinedOutput(); err != nil {
+		t.Errorf("go test in the generated package: %v\n%s", err, out)
+	}
+}
@@ -3,6 +3,8 @@ package golang
 import (
 	"fmt"
 	"regexp"
+	"sort"
+	"strconv"
 	"strings"
 
 	"github.com/blimu-dev/sdk-gen/pkg/config"
@@ -10,14 +12,25 @@ import (
 	"github.com/blimu-dev/sdk-gen/pkg/utils"
 )
 
+// typeOptions carries client settings that influence how IR schemas map to Go types
+type typeOptions struct {
+	// DateAsNativeType maps date-time strings to time.Time and date strings to Date
+	DateAsNativeType bool
+}
+
+// newTypeOptions derives type mapping options from the client configuration
+func newTypeOptions(client config.Client) typeOptions {
+	return typeOptions{DateAsNativeType: client.DateAsNativeType}
+}
+
 // schemaToGoType converts an IR schema to Go type string
-func schemaToGoType(x any) string {
+func schemaToGoType(x any, opts typeOptions) string {
 	switch v := x.(type) {
 	case ir.IRSchema:
-		return schemaToGoTypeImpl(v)
+		return schemaToGoTypeImpl(v, opts)
 	case *ir.IRSchema:
 		if v != nil {
-			return schemaToGoTypeImpl(*v)
+			return schemaToGoTypeImpl(*v, opts)
 		}
 		return "interface{}"
 	default:
@@ -25,12 +38,27 @@ func schemaToGoType(x any) string {
 	}
 }
 
-func schemaToGoTypeImpl(s ir.IRSchema) string {
+func schemaToGoTypeImpl(s ir.IRSchema, opts typeOptions) string {
+	// x-go-type replaces the inferred type
+	if override := s.TypeOverrides["go"]; override != "" {
+		if s.Nullable {
+			return "*" + override
+		}
+		return override
+	}
+
 	var t string
 	switch s.Kind {
 	case "string":
 		if s.Format == "binary" {
 			t = "[]byte"
+		} else if s.Format == "byte" {
+			// encoding/json already carries []byte as a base64 string
+			t = "[]byte"
+		} else if opts.DateAsNativeType && s.Format == "date-time" {
+			t = "time.Time"
+		} else if opts.DateAsNativeType && s.Format == "date" {
+			t = "Date"
 		} else {
 			t = "string"
 		}
@@ -50,7 +78,7 @@ func schemaToGoTypeImpl(s ir.IRSchema) string {
 		}
 	case "array":
 		if s.Items != nil {
-			inner := schemaToGoTypeImpl(*s.Items)
+			inner := schemaToGoTypeImpl(*s.Items, opts)
 			t = "[]" + inner
 		} else {
 			t = "[]interface{}"
@@ -64,15 +92,15 @@ func schemaToGoTypeImpl(s ir.IRSchema) string {
 		// In a more sophisticated implementation, we could generate embedded structs
 		t = "interface{}"
 	case "enum":
-		// Use string for enums, could be enhanced to use custom types
-		t = "string"
+		// Inline enums use their base type; named enums are refs to their own type
+		t = goEnumBaseType(s)
 	case "object":
-		if len(s.Properties) > 0 {
+		if value := goMapValue(s); value != nil {
+			t = "map[string]" + schemaToGoTypeImpl(*value, opts)
+		} else {
 			// For inline objects, we'll use map[string]interface{}
 			// In a more sophisticated implementation, we could generate inline structs
 			t = "map[string]interface{}"
-		} else {
-			t = "map[string]interface{}"
 		}
 	default:
 		t = "interface{}"
@@ -92,6 +120,51 @@ var toCamelCase = utils.ToCamelCaseAdvanced
 var toSnakeCase = utils.ToSnakeCaseAdvanced
 var toKebabCase = utils.ToKebabCaseAdvanced
 
+// fieldComment returns the trailing comment of a struct field (" // description"). Empty when
+// there is nothing to say or the field is deprecated, as those are documented by fieldDoc.
+func fieldComment(description string, deprecated bool) string {
+	if deprecated || description == "" {
+		return ""
+	}
+	return " // " + strings.ReplaceAll(description, "\n", " ")
+}
+
+// fieldDoc returns the doc comment above a deprecated struct field: its description followed by
+// a "Deprecated:" paragraph, which linters and editors recognize. Empty for other fields.
+func fieldDoc(description string, deprecated bool) string {
+	if !deprecated {
+		return ""
+	}
+	deprecation := "// Deprecated: the API marks this field as deprecated."
+	if description == "" {
+		return deprecation
+	}
+	return formatGoComment(description) + "\n//\n" + deprecation
+}
+
+// modelStructTag returns the json tag of a model field. Optional native dates are tagged
+// omitzero, as their zero value would otherwise be sent as 0001-01-01.
+func modelStructTag(f ir.IRField, opts typeOptions) string {
+	if !f.Required && f.Type != nil && !f.Type.Nullable && isNativeDate(*f.Type, opts) {
+		return fmt.Sprintf("`json:\"%s,omitzero\"`", f.Name)
+	}
+	return fmt.Sprintf("`json:\"%s\"`", f.Name)
+}
+
+// modelFieldComment returns the trailing comment of a model field: its fieldComment, with a
+// note on sensitive fields (format: password or writeOnly). Deprecated fields are documented
+// above the field with fieldDoc.
+func modelFieldComment(f ir.IRField) string {
+	comment := fieldComment(f.Annotations.Description, f.Annotations.Deprecated)
+	switch {
+	case !f.Sensitive():
+		return comment
+	case comment == "":
+		return " // Sensitive: keep out of logs"
+	}
+	return comment + " (sensitive: keep out of logs)"
+}
+
 // formatGoComment formats a string as a proper Go comment, handling multiline descriptions
 func formatGoComment(s string) string {
 	if s == "" {
@@ -186,7 +259,7 @@ func buildPathTemplate(op ir.IROperation) string {
 	// Replace OpenAPI path parameters {param} with Go format specifiers
 	for _, param := range op.PathParams {
 		placeholder := "{" + param.Name + "}"
-		path = strings.ReplaceAll(path, placeholder, "%v")
+		path = strings.ReplaceAll(path, placeholder, param.PathPrefix()+"%v")
 	}
 
 	return fmt.Sprintf(`"%s"`, path)
@@ -221,67 +294,516 @@ func orderPathParams(op ir.IROperation) []ir.IRParam {
 	return ordered
 }
 
-// buildMethodSignature builds the method signature for a Go method
-func buildMethodSignature(client config.Client, op ir.IROperation, methodName string) string {
+// contextMode returns the client's goContextMode, defaulting to contextOnly
+func contextMode(client config.Client) string {
+	if client.GoContextMode == "" {
+		return config.GoContextOnly
+	}
+	return client.GoContextMode
+}
+
+// contextMethodName returns the name of the method variant taking a context: the method name
+// itself, or <Method>WithContext when both variants are generated
+func contextMethodName(client config.Client, methodName string) string {
+	if contextMode(client) == config.GoContextBoth {
+		return methodName + "WithContext"
+	}
+	return methodName
+}
+
+// queryTypeName names the struct models.go declares for an operation's query parameters.
+// methodName is the operation's method name, never its WithContext variant.
+func queryTypeName(op ir.IROperation, methodName string) string {
+	return toPascalCase(op.Tag) + methodName + "Query"
+}
+
+// buildMethodSignature builds the signature of a Go service method. methodName is the
+// operation's method name; with withContext the signature takes a leading ctx parameter and
+// is named by contextMethodName.
+func buildMethodSignature(client config.Client, op ir.IROperation, methodName string, withContext bool) string {
+	opts := newTypeOptions(client)
 	var params []string
+	name := methodName + acceptSuffix(op)
 
 	// Context parameter (always first)
-	params = append(params, "ctx context.Context")
+	if withContext {
+		params = append(params, "ctx context.Context")
+		name = contextMethodName(client, name)
+	}
 
 	// Path parameters
 	for _, param := range orderPathParams(op) {
-		goType := schemaToGoType(param.Schema)
+		goType := schemaToGoType(param.Schema, opts)
 		params = append(params, fmt.Sprintf("%s %s", toCamelCase(param.Name), goType))
 	}
 
 	// Query parameters (as a struct)
 	if len(op.QueryParams) > 0 {
-		// Use proper naming that includes the operation tag/service name
-		queryTypeName := toPascalCase(op.Tag) + strings.TrimSuffix(ResolveMethodName(client, op), "WithContext") + "Query"
-		params = append(params, fmt.Sprintf("query *%s", queryTypeName))
+		params = append(params, fmt.Sprintf("query *%s", queryTypeName(op, methodName)))
 	}
 
 	// Request body
 	if op.RequestBody != nil {
-		goType := schemaToGoType(op.RequestBody.Schema)
+		goType := schemaToGoType(op.RequestBody.Schema, opts)
 		params = append(params, fmt.Sprintf("body %s", goType))
 	}
 
-	// Return type
-	responseType := schemaToGoType(op.Response.Schema)
+	// Return type, followed by the HTTP response with rawResponse
+	results := []string{responseGoType(op, opts)}
+	if client.RawResponse {
+		results = append(results, "*http.Response")
+	}
+	results = append(results, "error")
 
-	signature := fmt.Sprintf("%s(%s) (%s, error)", methodName, strings.Join(params, ", "), responseType)
+	signature := fmt.Sprintf("%s(%s) (%s)", name, strings.Join(params, ", "), strings.Join(results, ", "))
 	return signature
 }
 
-// buildMethodSignatureNoContext builds the method signature without context parameter
-func buildMethodSignatureNoContext(client config.Client, op ir.IROperation, methodName string) string {
-	var params []string
+// acceptSuffix returns what the method name of an accept variant (ir.IROperation.AcceptVariants)
+// ends with: empty for the preferred media type, else "As" and the media subtype (GetReportAsCsv
+// for text/csv), or the full media type when another one shares the subtype
+func acceptSuffix(op ir.IROperation) string {
+	contents := op.Response.Contents
+	if len(contents) < 2 || op.Response.ContentType == contents[0].ContentType {
+		return ""
+	}
+	name := func(ct string, full bool) string {
+		ct, _, _ = strings.Cut(ct, ";")
+		mediaType, subtype, _ := strings.Cut(strings.TrimSpace(ct), "/")
+		if full {
+			subtype = mediaType + "-" + subtype
+		}
+		return toPascalCase(strings.NewReplacer(".", "-", "+", "-").Replace(subtype))
+	}
+	suffix := name(op.Response.ContentType, false)
+	for _, content := range contents {
+		if content.ContentType != op.Response.ContentType && name(content.ContentType, false) == suffix {
+			suffix = name(op.Response.ContentType, true)
+			break
+		}
+	}
+	return "As" + suffix
+}
 
-	// Path parameters (no context parameter)
-	for _, param := range orderPathParams(op) {
-		goType := schemaToGoType(param.Schema)
-		params = append(params, fmt.Sprintf("%s %s", toCamelCase(param.Name), goType))
+// acceptHeader is the headers argument of a request: the Accept header choosing the media type
+// of a negotiated response, or nil
+func acceptHeader(op ir.IROperation) string {
+	if !op.Response.Negotiated() {
+		return "nil"
 	}
+	return fmt.Sprintf("map[string]string{\"Accept\": %q}", op.Response.ContentType)
+}
 
-	// Query parameters (as a struct)
-	if len(op.QueryParams) > 0 {
-		// Use proper naming that includes the operation tag/service name
-		queryTypeName := toPascalCase(op.Tag) + ResolveMethodName(client, op) + "Query"
-		params = append(params, fmt.Sprintf("query *%s", queryTypeName))
+// withAcceptVariants returns the service with every operation replaced by its accept variants,
+// the methods its file declares
+func withAcceptVariants(service ir.IRService) ir.IRService {
+	var ops []ir.IROperation
+	for _, op := range service.Operations {
+		ops = append(ops, op.AcceptVariants()...)
 	}
+	service.Operations = ops
+	return service
+}
 
-	// Request body
-	if op.RequestBody != nil {
-		goType := schemaToGoType(op.RequestBody.Schema)
-		params = append(params, fmt.Sprintf("body %s", goType))
+// responseGoType is the type a service method returns: the response model, or the unread
+// body for streamed responses
+func responseGoType(op ir.IROperation, opts typeOptions) string {
+	if op.Response.Stream {
+		return "io.ReadCloser"
+	}
+	return schemaToGoType(op.Response.Schema, opts)
+}
+
+// queryValueExpr returns a Go expression that renders expr (a value of the given schema)
+// as a query string value. Native dates are formatted as ISO 8601.
+func queryValueExpr(x any, expr string, opts typeOptions) string {
+	method := ""
+	switch schemaToGoType(x, opts) {
+	case "time.Time", "*time.Time":
+		method = ".Format(time.RFC3339)"
+	case "Date", "*Date":
+		method = ".String()"
+	}
+	if method == "" {
+		return fmt.Sprintf("fmt.Sprintf(\"%%v\", %s)", expr)
 	}
+	if strings.HasPrefix(expr, "*") {
+		expr = "(" + expr + ")"
+	}
+	return expr + method
+}
 
-	// Return type
-	responseType := schemaToGoType(op.Response.Schema)
+// isNativeDate reports whether the schema maps to time.Time or Date under the given options
+func isNativeDate(x any, opts typeOptions) bool {
+	switch strings.TrimPrefix(schemaToGoType(x, opts), "*") {
+	case "time.Time", "Date":
+		return true
+	}
+	return false
+}
 
-	signature := fmt.Sprintf("%s(%s) (%s, error)", methodName, strings.Join(params, ", "), responseType)
-	return signature
+// serviceUsesTime reports whether any method of the service references time.Time,
+// in which case the service file must import the time package
+func serviceUsesTime(client config.Client, service ir.IRService, methodName func(ir.IROperation) string) bool {
+	if !client.DateAsNativeType {
+		return false
+	}
+	for _, op := range service.Operations {
+		if strings.Contains(buildMethodSignature(client, op, methodName(op), true), "time.Time") {
+			return true
+		}
+	}
+	return false
+}
+
+// queryDefaultValues returns the string-encoded default(s) for a query parameter,
+// or nil when it has no default. Array defaults yield one entry per element.
+func queryDefaultValues(p ir.IRParam) []string {
+	switch v := p.Default.(type) {
+	case nil:
+		return nil
+	case []any:
+		out := make([]string, 0, len(v))
+		for _, item := range v {
+			out = append(out, ir.FormatEnumValue(item))
+		}
+		return out
+	default:
+		// FormatEnumValue keeps large numbers out of exponent form (1000000, not 1e+06)
+		return []string{ir.FormatEnumValue(v)}
+	}
+}
+
+// hasQueryDefaults reports whether any query parameter of the operation declares a default
+func hasQueryDefaults(op ir.IROperation) bool {
+	for _, p := range op.QueryParams {
+		if len(queryDefaultValues(p)) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// collectGoImports adds the x-go-import of every type override used by the Go type of s
+func collectGoImports(s ir.IRSchema, imports map[string]bool) {
+	if s.TypeOverrides["go"] != "" {
+		if imp := s.TypeImports["go"]; imp != "" {
+			imports[imp] = true
+		}
+		return
+	}
+	if s.Kind == ir.IRKindArray && s.Items != nil {
+		collectGoImports(*s.Items, imports)
+	}
+	if value := goMapValue(s); value != nil {
+		collectGoImports(*value, imports)
+	}
+}
+
+// modelImports returns the extra imports models.go needs for type overrides used by model
+// fields and query parameter structs, leaving out the ones the file already imports
+func modelImports(in ir.IR, skip ...string) []string {
+	imports := map[string]bool{}
+	defs := modelDefsByName(in.ModelDefs)
+	for _, md := range in.ModelDefs {
+		if value := goMapValue(md.Schema); value != nil {
+			collectGoImports(*value, imports)
+			continue
+		}
+		body := goStructBody(defs, md.Schema)
+		for _, f := range body.Fields {
+			if f.Type != nil {
+				collectGoImports(*f.Type, imports)
+			}
+		}
+		if body.Additional != nil {
+			// for the MarshalJSON and UnmarshalJSON methods
+			imports["encoding/json"] = true
+			collectGoImports(*body.Additional, imports)
+		}
+	}
+	for _, service := range in.Services {
+		for _, op := range service.Operations {
+			for _, p := range op.QueryParams {
+				collectGoImports(p.Schema, imports)
+			}
+		}
+	}
+	return sortedImports(imports, skip)
+}
+
+// modelFileImports returns the imports of models.go. The Date type and the JSON methods of
+// models with additional properties use encoding/json and fmt, and query structs use net/url
+// and fmt, so a package without them, such as a tag of packagePerTag with no query
+// parameters, leaves them out.
+func modelFileImports(client config.Client, in ir.IR) []string {
+	imports := map[string]bool{}
+	for _, imp := range modelImports(in) {
+		imports[imp] = true
+	}
+	if imports["encoding/json"] {
+		imports["fmt"] = true
+	}
+	if client.DateAsNativeType {
+		imports["encoding/json"] = true
+		imports["fmt"] = true
+		imports["time"] = true
+	}
+	for _, service := range in.Services {
+		for _, op := range service.Operations {
+			if len(op.QueryParams) > 0 {
+				imports["net/url"] = true
+			}
+			for _, p := range op.QueryParams {
+				if !p.IsJSON() {
+					imports["fmt"] = true
+				}
+			}
+		}
+	}
+	return sortedImports(imports, nil)
+}
+
+// serviceImports returns the extra imports a service file needs for type overrides used by
+// its method signatures and error models, leaving out the ones the file already imports
+func serviceImports(service ir.IRService, skip ...string) []string {
+	imports := map[string]bool{}
+	for _, op := range service.Operations {
+		for _, p := range op.PathParams {
+			collectGoImports(p.Schema, imports)
+		}
+		if op.RequestBody != nil {
+			collectGoImports(op.RequestBody.Schema, imports)
+		}
+		collectGoImports(op.Response.Schema, imports)
+		for _, er := range op.ErrorResponses {
+			collectGoImports(er.Schema, imports)
+		}
+	}
+	return sortedImports(imports, skip)
+}
+
+// serviceFileImports returns every import of a service file declaring the given operations:
+// the standard library packages their bodies use plus those of type overrides. A file without
+// operations (the service struct alone) needs none.
+func serviceFileImports(client config.Client, service ir.IRService, methodName func(ir.IROperation) string) []string {
+	if len(service.Operations) == 0 {
+		return nil
+	}
+	service = withAcceptVariants(service)
+	imports := map[string]bool{"context": true}
+	if client.RawResponse {
+		imports["net/http"] = true
+	}
+	for _, op := range service.Operations {
+		// Only paths with parameters are built with fmt.Sprintf
+		if len(op.PathParams) > 0 {
+			imports["fmt"] = true
+		}
+		if op.Response.Stream {
+			imports["io"] = true
+		}
+		// Query values are declared as url.Values unless ToValues always provides them
+		if len(op.QueryParams) == 0 || !hasQueryDefaults(op) {
+			imports["net/url"] = true
+		}
+	}
+	if serviceUsesTime(client, service, methodName) {
+		imports["time"] = true
+	}
+	for _, imp := range serviceImports(service) {
+		imports[imp] = true
+	}
+	return sortedImports(imports, nil)
+}
+
+// interfaceImports returns the imports of interfaces.go: those the method signatures of the
+// services need, which unlike service files excludes what method bodies and error models use
+func interfaceImports(client config.Client, services []ir.IRService, methodName func(ir.IROperation) string) []string {
+	imports := map[string]bool{}
+	for _, service := range services {
+		if len(service.Operations) == 0 {
+			continue
+		}
+		service = withAcceptVariants(service)
+		if contextMode(client) != config.GoNoContext {
+			imports["context"] = true
+		}
+		if client.RawResponse {
+			imports["net/http"] = true
+		}
+		if serviceUsesTime(client, service, methodName) {
+			imports["time"] = true
+		}
+		for _, op := range service.Operations {
+			if op.Response.Stream {
+				imports["io"] = true
+			}
+			for _, p := range op.PathParams {
+				collectGoImports(p.Schema, imports)
+			}
+			if op.RequestBody != nil {
+				collectGoImports(op.RequestBody.Schema, imports)
+			}
+			if !op.Response.Stream {
+				collectGoImports(op.Response.Schema, imports)
+			}
+		}
+	}
+	return sortedImports(imports, nil)
+}
+
+// reservedFileSuffixes are file name suffixes the go tool treats as build constraints
+// (_test, _GOOS, _GOARCH), which generated files must not end with
+var reservedFileSuffixes = map[string]bool{
+	"test": true,
+
+	"aix": true, "android": true, "darwin": true, "dragonfly": true, "freebsd": true, "hurd": true,
+	"illumos": true, "ios": true, "js": true, "linux": true, "nacl": true, "netbsd": true,
+	"openbsd": true, "plan9": true, "solaris": true, "wasip1": true, "windows": true, "zos": true,
+
+	"386": true, "amd64": true, "amd64p32": true, "arm": true, "armbe": true, "arm64": true,
+	"arm64be": true, "loong64": true, "mips": true, "mipsle": true, "mips64": true, "mips64le": true,
+	"mips64p32": true, "mips64p32le": true, "ppc": true, "ppc64": true, "ppc64le": true,
+	"riscv": true, "riscv64": true, "s390": true, "s390x": true, "sparc": true, "sparc64": true,
+	"wasm": true,
+}
+
+// goFileName joins snake_case name parts into a .go file name, adding a suffix when the name
+// would otherwise end like a test or platform-specific file
+func goFileName(parts ...string) string {
+	name := strings.Join(parts, "_")
+	if i := strings.LastIndex(name, "_"); i >= 0 && reservedFileSuffixes[name[i+1:]] {
+		name += "_gen"
+	}
+	return name + ".go"
+}
+
+// serviceFileName returns the name of the file generated for a service, or one of its
+// operations, from words: snake case through goFileName unless client.FileNaming picks
+// another style
+func serviceFileName(client config.Client, words ...string) string {
+	if client.FileNaming == "" || client.FileNaming == config.FileNamingSnake {
+		parts := make([]string, len(words))
+		for i, w := range words {
+			parts[i] = toSnakeCase(w)
+		}
+		return goFileName(parts...)
+	}
+	return client.FileName("", words...) + ".go"
+}
+
+// defaultBaseURL is the base URL of clients created without one: the configured
+// defaultBaseURL, or else the first server of the spec with an absolute URL
+func defaultBaseURL(client config.Client, servers []ir.IRServer) string {
+	if client.DefaultBaseURL != "" {
+		return client.DefaultBaseURL
+	}
+	for _, server := range servers {
+		if strings.HasPrefix(server.URL, "http://") || strings.HasPrefix(server.URL, "https://") {
+			return strings.TrimSuffix(server.URL, "/")
+		}
+	}
+	return ""
+}
+
+func sortedImports(imports map[string]bool, skip []string) []string {
+	for _, s := range skip {
+		delete(imports, s)
+	}
+	out := make([]string, 0, len(imports))
+	for imp := range imports {
+		out = append(out, imp)
+	}
+	sort.Strings(out)
+	return out
+}
+
+// errorModelCase is one branch of the function mapping a response status to the error model
+// its body decodes into
+type errorModelCase struct {
+	// Condition is a Go expression over status; empty for the final, unconditional branch
+	Condition string
+	// Type is the Go type of the model; empty when the branch returns nil
+	Type string
+}
+
+// errorModelCases returns the branches mapping statuses to the error models documented for an
+// operation, in match order: exact codes, ranges, then default. Responses without a typed JSON
+// body are skipped, and nil is returned when no model is documented at all.
+func errorModelCases(op ir.IROperation, opts typeOptions) []errorModelCase {
+	var cases []errorModelCase
+	hasDefault := false
+	for _, er := range op.ErrorResponses {
+		if er.Schema.Kind == ir.IRKindUnknown {
+			continue
+		}
+		t := schemaToGoType(er.Schema, opts)
+		if t == "interface{}" {
+			continue
+		}
+		c := errorModelCase{Type: t}
+		switch {
+		case er.StatusCode == "default":
+			hasDefault = true
+		case strings.HasSuffix(er.StatusCode, "XX"):
+			class := int(er.StatusCode[0]-'0') * 100
+			c.Condition = fmt.Sprintf("status >= %d && status < %d", class, class+100)
+		default:
+			c.Condition = "status == " + er.StatusCode
+		}
+		cases = append(cases, c)
+	}
+	if len(cases) > 0 && !hasDefault {
+		cases = append(cases, errorModelCase{})
+	}
+	return cases
+}
+
+// goEnumConst is one constant declared for a member of a named enum
+type goEnumConst struct {
+	Name  string
+	Value string
+}
+
+// goEnumBaseType returns the underlying Go type of a named enum model
+func goEnumBaseType(s ir.IRSchema) string {
+	switch s.EnumBase {
+	case ir.IRKindInteger:
+		return "int64"
+	case ir.IRKindNumber:
+		return "float64"
+	case ir.IRKindBoolean:
+		return "bool"
+	}
+	return "string"
+}
+
+// goEnumConsts returns the constants of a named enum model, one per value, named after the
+// model followed by the member (StatusActive). Numeric members are named from x-enum-varnames
+// when present. Boolean enums declare none.
+func goEnumConsts(name string, s ir.IRSchema) []goEnumConst {
+	if s.EnumBase == ir.IRKindBoolean {
+		return nil
+	}
+	literals := s.EnumLiterals()
+	names := s.EnumValues
+	if s.EnumBase == ir.IRKindNumber || s.EnumBase == ir.IRKindInteger {
+		if len(s.EnumNames) == len(literals) {
+			names = s.EnumNames
+		} else {
+			names = literals
+		}
+	}
+	members := utils.EnumMemberNames(names, utils.EnumNamePascal)
+	consts := make([]goEnumConst, 0, len(literals))
+	for i, v := range literals {
+		if s.EnumBase != ir.IRKindNumber && s.EnumBase != ir.IRKindInteger {
+			v = strconv.Quote(v)
+		}
+		consts = append(consts, goEnumConst{Name: toPascalCase(name) + members[i], Value: v})
+	}
+	return consts
 }
 
 // sanitizePackageName ensures the package name is valid for Go
@@ -308,3 +830,293 @@ func sanitizePackageName(name string) string {
 
 	return name
 }
+
+// goStruct is the body of a model struct: the names of the embedded models followed by fields
+type goStruct struct {
+	Embeds []string
+	Fields []ir.IRField
+	// Additional is the schema of the additionalProperties of an object with properties, kept
+	// in an AdditionalProperties map the struct's JSON methods merge with the fields
+	Additional *ir.IRSchema
+}
+
+// goMapValue returns the value schema of an object with additionalProperties and no
+// properties, which renders as map[string]T; nil for any other schema
+func goMapValue(s ir.IRSchema) *ir.IRSchema {
+	if s.Kind != ir.IRKindObject || len(s.Properties) > 0 {
+		return nil
+	}
+	return s.AdditionalProperties
+}
+
+// goStructBody returns the struct rendered for an object or allOf model. An allOf of one
+// ref to a struct model plus inline objects embeds the referenced type, keeping its method
+// set, and adds the inline fields. Any other allOf is flattened: referenced object models
+// contribute their properties, later members win. A model with additional properties has
+// its own JSON methods, which an embedding struct would inherit and lose its fields to, so
+// it is flattened too, keeping its additional properties.
+func goStructBody(defs map[string]ir.IRModelDef, s ir.IRSchema) goStruct {
+	if s.Kind != ir.IRKindAllOf {
+		body := goStruct{Fields: s.Properties}
+		if len(s.Properties) > 0 {
+			body.Additional = s.AdditionalProperties
+		}
+		return body
+	}
+	var base *ir.IRSchema
+	embeddable := true
+	for _, part := range s.AllOf {
+		switch {
+		case part == nil || part.Kind == ir.IRKindObject:
+		case part.Kind == ir.IRKindRef && base == nil && isStructModel(defs, part.Ref) && modelAdditional(defs, part.Ref) == nil:
+			base = part
+		default:
+			embeddable = false
+		}
+	}
+	if base != nil && embeddable {
+		var fields []ir.IRField
+		for _, part := range s.AllOf {
+			if part != nil && part.Kind == ir.IRKindObject {
+				fields = mergeFields(fields, part.Properties)
+			}
+		}
+		return goStruct{Embeds: []string{base.Ref}, Fields: fields}
+	}
+	body := goStruct{Fields: flattenFields(defs, s)}
+	for _, part := range s.AllOf {
+		if part != nil && part.Kind == ir.IRKindRef && body.Additional == nil {
+			body.Additional = modelAdditional(defs, part.Ref)
+		}
+	}
+	return body
+}
+
+// modelAdditional returns the additionalProperties schema of the struct model named name,
+// which gives it JSON methods, or nil when it has none
+func modelAdditional(defs map[string]ir.IRModelDef, name string) *ir.IRSchema {
+	md, ok := defs[name]
+	if !ok {
+		return nil
+	}
+	return goStructBody(defs, md.Schema).Additional
+}
+
+// flattenFields returns the properties of an object or allOf model with allOf members merged
+func flattenFields(defs map[string]ir.IRModelDef, s ir.IRSchema) []ir.IRField {
+	switch s.Kind {
+	case ir.IRKindObject:
+		return s.Properties
+	case ir.IRKindAllOf:
+		var fields []ir.IRField
+		for _, part := range s.AllOf {
+			if part == nil {
+				continue
+			}
+			if part.Kind == ir.IRKindRef {
+				if md, ok := defs[part.Ref]; ok {
+					fields = mergeFields(fields, flattenFields(defs, md.Schema))
+				}
+				continue
+			}
+			fields = mergeFields(fields, flattenFields(defs, *part))
+		}
+		return fields
+	}
+	return nil
+}
+
+// mergeFields appends props to fields, replacing fields of the same name in place
+func mergeFields(fields, props []ir.IRField) []ir.IRField {
+	for _, f := range props {
+		replaced := false
+		for i := range fields {
+			if fields[i].Name == f.Name {
+				fields[i] = f
+				replaced = true
+				break
+			}
+		}
+		if !replaced {
+			fields = append(fields, f)
+		}
+	}
+	return fields
+}
+
+// isStructModel reports whether the model named name renders as a struct
+func isStructModel(defs map[string]ir.IRModelDef, name string) bool {
+	md, ok := defs[name]
+	return ok && (md.Schema.Kind == ir.IRKindObject && goMapValue(md.Schema) == nil || md.Schema.Kind == ir.IRKindAllOf)
+}
+
+// goDefaults lists what the WithDefaults method of a struct model sets: the embedded bases
+// with defaults of their own and the fields declaring one
+type goDefaults struct {
+	Embeds []string
+	Fields []goFieldDefault
+}
+
+// goFieldDefault sets a field to Value when it holds Zero
+type goFieldDefault struct {
+	Name  string
+	Zero  string
+	Value string
+}
+
+// Empty reports whether there is no default to set
+func (d goDefaults) Empty() bool {
+	return len(d.Embeds) == 0 && len(d.Fields) == 0
+}
+
+// goStructDefaults returns the defaults the WithDefaults method of a struct sets. Only string,
+// numeric and list fields are covered, where the zero value means unset: a false bool, a nil
+// pointer or an overridden type could be a deliberate value, so their defaults are left to the
+// server.
+func goStructDefaults(defs map[string]ir.IRModelDef, body goStruct, opts typeOptions) goDefaults {
+	var d goDefaults
+	for _, name := range body.Embeds {
+		if hasGoDefaults(defs, name, opts) {
+			d.Embeds = append(d.Embeds, name)
+		}
+	}
+	for _, f := range body.Fields {
+		if f.Type == nil || f.Annotations.Default == nil {
+			continue
+		}
+		if def, ok := goFieldDefaultOf(defs, f, opts); ok {
+			d.Fields = append(d.Fields, def)
+		}
+	}
+	return d
+}
+
+// hasGoDefaults reports whether the model named name is a struct with a WithDefaults method
+func hasGoDefaults(defs map[string]ir.IRModelDef, name string, opts typeOptions) bool {
+	if !isStructModel(defs, name) {
+		return false
+	}
+	return !goStructDefaults(defs, goStructBody(defs, defs[name].Schema), opts).Empty()
+}
+
+// goFieldDefaultOf renders the default of a string, numeric or list field
+func goFieldDefaultOf(defs map[string]ir.IRModelDef, f ir.IRField, opts typeOptions) (goFieldDefault, bool) {
+	s := *f.Type
+	if s.Nullable || s.TypeOverrides["go"] != "" {
+		return goFieldDefault{}, false
+	}
+	if s.Kind == ir.IRKindArray {
+		values, ok := f.Annotations.Default.([]any)
+		if !ok || s.Items == nil || s.Items.Nullable {
+			return goFieldDefault{}, false
+		}
+		base := goScalarBase(defs, *s.Items, opts)
+		items := make([]string, 0, len(values))
+		for _, v := range values {
+			lit, ok := goLiteral(base, v)
+			if !ok {
+				return goFieldDefault{}, false
+			}
+			items = append(items, lit)
+		}
+		typ := schemaToGoType(s, opts)
+		return goFieldDefault{Name: toPascalCase(f.Name), Zero: "nil", Value: typ + "{" + strings.Join(items, ", ") + "}"}, true
+	}
+	base := goScalarBase(defs, s, opts)
+	lit, ok := goLiteral(base, f.Annotations.Default)
+	if !ok {
+		return goFieldDefault{}, false
+	}
+	zero := "0"
+	if base == "string" {
+		zero = `""`
+	}
+	return goFieldDefault{Name: toPascalCase(f.Name), Zero: zero, Value: lit}, true
+}
+
+// goScalarBase returns the Go base type of a string, numeric or named enum schema, or ""
+// for anything else
+func goScalarBase(defs map[string]ir.IRModelDef, s ir.IRSchema, opts typeOptions) string {
+	if s.Kind == ir.IRKindRef {
+		md, ok := defs[s.Ref]
+		if !ok || md.Schema.Kind != ir.IRKindEnum {
+			return ""
+		}
+		s = md.Schema
+	}
+	if s.Nullable || s.TypeOverrides["go"] != "" {
+		return ""
+	}
+	switch t := schemaToGoType(s, opts); t {
+	case "string", "int64", "float64":
+		return t
+	}
+	return ""
+}
+
+// goLiteral renders v as an untyped Go constant of the base type, reporting whether it fits
+func goLiteral(base string, v any) (string, bool) {
+	if base == "string" {
+		str, ok := v.(string)
+		return strconv.Quote(str), ok
+	}
+	var n float64
+	switch num := v.(type) {
+	case float64:
+		n = num
+	case int:
+		n = float64(num)
+	case int64:
+		n = float64(num)
+	case uint64:
+		n = float64(num)
+	default:
+		return "", false
+	}
+	switch base {
+	case "int64":
+		if n != float64(int64(n)) {
+			return "", false
+		}
+		return strconv.FormatInt(int64(n), 10), true
+	case "float64":
+		return strconv.FormatFloat(n, 'g', -1, 64), true
+	}
+	return "", false
+}
+
+// bodyHasDefaults reports whether the request body of an operation is a struct model with a
+// WithDefaults method, which the service applies before sending
+func bodyHasDefaults(defs map[string]ir.IRModelDef, op ir.IROperation, opts typeOptions) bool {
+	if op.RequestBody == nil {
+		return false
+	}
+	s := op.RequestBody.Schema
+	return s.Kind == ir.IRKindRef && !s.Nullable && s.TypeOverrides["go"] == "" && hasGoDefaults(defs, s.Ref, opts)
+}
+
+// modelDefsByName indexes model definitions by name
+func modelDefsByName(defs []ir.IRModelDef) map[string]ir.IRModelDef {
+	out := make(map[string]ir.IRModelDef, len(defs))
+	for _, md := range defs {
+		if _, ok := out[md.Name]; !ok {
+			out[md.Name] = md
+		}
+	}
+	return out
+}
+
+// operationKey returns the OperationID of an operation in operations.go: its operationId, or
+// "METHOD /path" when it has none
+func operationKey(op ir.IROperation) string {
+	if op.OperationID != "" {
+		return op.OperationID
+	}
+	return op.Method + " " + op.Path
+}
+
+// operationConst returns the name of the OperationID constant of an operation with the given
+// method name, e.g. OpUsersListUsers; like query type names, tag and method keep it unique
+func operationConst(op ir.IROperation, methodName string) string {
+	return "Op" + toPascalCase(op.Tag) + methodName
+}
@@ -1,8 +1,15 @@
 package golang
 
 import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
 	"testing"
 
+	"github.com/blimu-dev/sdk-gen/pkg/config"
+	"github.com/blimu-dev/sdk-gen/pkg/ir"
 	"github.com/blimu-dev/sdk-gen/pkg/utils"
 )
 
@@ -172,3 +179,811 @@ func TestFormatGoComment(t *testing.T) {
 		}
 	}
 }
+
+func TestQueryDefaultValues(t *testing.T) {
+	tests := []struct {
+		def      any
+		expected []string
+	}{
+		{nil, nil},
+		{float64(20), []string{"20"}},
+		{float64(1000000), []string{"1000000"}},
+		{0.25, []string{"0.25"}},
+		{[]any{float64(1000000), float64(2)}, []string{"1000000", "2"}},
+		{"asc", []string{"asc"}},
+		{true, []string{"true"}},
+		{[]any{"a", "b"}, []string{"a", "b"}},
+	}
+
+	for _, test := range tests {
+		result := queryDefaultValues(ir.IRParam{Name: "p", Default: test.def})
+		if len(result) != len(test.expected) {
+			t.Errorf("queryDefaultValues(%v) = %v, expected %v", test.def, result, test.expected)
+			continue
+		}
+		for i := range result {
+			if result[i] != test.expected[i] {
+				t.Errorf("queryDefaultValues(%v) = %v, expected %v", test.def, result, test.expected)
+				break
+			}
+		}
+	}
+}
+
+func TestSchemaToGoTypeDates(t *testing.T) {
+	native := typeOptions{DateAsNativeType: true}
+	tests := []struct {
+		schema   ir.IRSchema
+		opts     typeOptions
+		expected string
+	}{
+		{ir.IRSchema{Kind: ir.IRKindString, Format: "date-time"}, typeOptions{}, "string"},
+		{ir.IRSchema{Kind: ir.IRKindString, Format: "date-time"}, native, "time.Time"},
+		{ir.IRSchema{Kind: ir.IRKindString, Format: "date-time", Nullable: true}, native, "*time.Time"},
+		{ir.IRSchema{Kind: ir.IRKindString, Format: "date"}, native, "Date"},
+		{ir.IRSchema{Kind: ir.IRKindArray, Items: &ir.IRSchema{Kind: ir.IRKindString, Format: "date"}}, native, "[]Date"},
+	}
+
+	for _, test := range tests {
+		result := schemaToGoType(test.schema, test.opts)
+		if result != test.expected {
+			t.Errorf("schemaToGoType(%+v) = %q, expected %q", test.schema, result, test.expected)
+		}
+	}
+}
+
+func TestSchemaToGoTypeByteFormat(t *testing.T) {
+	if got := schemaToGoType(ir.IRSchema{Kind: ir.IRKindString, Format: "byte"}, typeOptions{}); got != "[]byte" {
+		t.Fatalf("schemaToGoType(format byte) = %q, expected []byte", got)
+	}
+
+	// A generated []byte field must read and write the base64 string the spec describes
+	type model struct {
+		Data []byte `json:"data"`
+	}
+	var m model
+	if err := json.Unmarshal([]byte(`{"data":"aGVsbG8="}`), &m); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	if string(m.Data) != "hello" {
+		t.Errorf("decoded %q, expected hello", m.Data)
+	}
+	out, err := json.Marshal(m)
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	if string(out) != `{"data":"aGVsbG8="}` {
+		t.Errorf("encoded %s", out)
+	}
+}
+
+func TestQueryValueExpr(t *testing.T) {
+	native := typeOptions{DateAsNativeType: true}
+	tests := []struct {
+		schema   ir.IRSchema
+		expr     string
+		expected string
+	}{
+		{ir.IRSchema{Kind: ir.IRKindInteger}, "q.Limit", `fmt.Sprintf("%v", q.Limit)`},
+		{ir.IRSchema{Kind: ir.IRKindString, Format: "date-time"}, "*q.Since", "(*q.Since).Format(time.RFC3339)"},
+		{ir.IRSchema{Kind: ir.IRKindString, Format: "date"}, "v", "v.String()"},
+	}
+
+	for _, test := range tests {
+		result := queryValueExpr(test.schema, test.expr, native)
+		if result != test.expected {
+			t.Errorf("queryValueExpr(%+v, %q) = %q, expected %q", test.schema, test.expr, result, test.expected)
+		}
+	}
+}
+
+func TestSchemaToGoTypeNullableArrays(t *testing.T) {
+	array := func(items ir.IRSchema, nullable bool) ir.IRSchema {
+		return ir.IRSchema{Kind: ir.IRKindArray, Items: &items, Nullable: nullable}
+	}
+	str := ir.IRSchema{Kind: ir.IRKindString}
+	nullableStr := ir.IRSchema{Kind: ir.IRKindString, Nullable: true}
+
+	tests := []struct {
+		name     string
+		schema   ir.IRSchema
+		expected string
+	}{
+		{"nullable array", array(str, true), "*[]string"},
+		{"nullable items", array(nullableStr, false), "[]*string"},
+		{"both", array(nullableStr, true), "*[]*string"},
+	}
+
+	for _, test := range tests {
+		if got := schemaToGoType(test.schema, typeOptions{}); got != test.expected {
+			t.Errorf("%s: schemaToGoType() = %q, expected %q", test.name, got, test.expected)
+		}
+	}
+}
+
+func TestErrorModelCases(t *testing.T) {
+	errModel := ir.IRSchema{Kind: ir.IRKindRef, Ref: "Error"}
+	tests := []struct {
+		name     string
+		errors   []ir.IRErrorResponse
+		expected []errorModelCase
+	}{
+		{"none", nil, nil},
+		{"untyped only", []ir.IRErrorResponse{{StatusCode: "404", Schema: ir.IRSchema{Kind: ir.IRKindUnknown}}}, nil},
+		{
+			"codes and ranges",
+			[]ir.IRErrorResponse{
+				{StatusCode: "404", Schema: ir.IRSchema{Kind: ir.IRKindRef, Ref: "NotFound"}},
+				{StatusCode: "4XX", Schema: errModel},
+			},
+			[]errorModelCase{
+				{Condition: "status == 404", Type: "NotFound"},
+				{Condition: "status >= 400 && status < 500", Type: "Error"},
+				{},
+			},
+		},
+		{
+			"default",
+			[]ir.IRErrorResponse{{StatusCode: "default", Schema: errModel}},
+			[]errorModelCase{{Type: "Error"}},
+		},
+	}
+
+	for _, test := range tests {
+		result := errorModelCases(ir.IROperation{ErrorResponses: test.errors}, typeOptions{})
+		if len(result) != len(test.expected) {
+			t.Errorf("%s: errorModelCases() = %+v, expected %+v", test.name, result, test.expected)
+			continue
+		}
+		for i := range result {
+			if result[i] != test.expected[i] {
+				t.Errorf("%s: errorModelCases() = %+v, expected %+v", test.name, result, test.expected)
+				break
+			}
+		}
+	}
+}
+
+func TestTypeOverrideGoType(t *testing.T) {
+	decimal := ir.IRSchema{
+		Kind:          ir.IRKindString,
+		TypeOverrides: map[string]string{"go": "decimal.Decimal"},
+		TypeImports:   map[string]string{"go": "github.com/shopspring/decimal"},
+	}
+	nullable := decimal
+	nullable.Nullable = true
+
+	if got := schemaToGoType(decimal, typeOptions{}); got != "decimal.Decimal" {
+		t.Errorf("schemaToGoType() = %q", got)
+	}
+	if got := schemaToGoType(ir.IRSchema{Kind: ir.IRKindArray, Items: &nullable}, typeOptions{}); got != "[]*decimal.Decimal" {
+		t.Errorf("schemaToGoType() = %q", got)
+	}
+
+	in := ir.IR{
+		ModelDefs: []ir.IRModelDef{{Name: "Order", Schema: ir.IRSchema{Kind: ir.IRKindObject, Properties: []ir.IRField{
+			{Name: "prices", Type: &ir.IRSchema{Kind: ir.IRKindArray, Items: &decimal}},
+			{Name: "at", Type: &ir.IRSchema{Kind: ir.IRKindString, TypeOverrides: map[string]string{"go": "time.Time"}, TypeImports: map[string]string{"go": "time"}}},
+		}}}},
+	}
+	imports := modelImports(in, "fmt", "net/url")
+	if len(imports) != 2 || imports[0] != "github.com/shopspring/decimal" || imports[1] != "time" {
+		t.Errorf("modelImports() = %v", imports)
+	}
+	if imports := modelImports(in, "fmt", "net/url", "time"); len(imports) != 1 {
+		t.Errorf("expected already imported packages to be skipped, got %v", imports)
+	}
+}
+
+func TestModelFileImports(t *testing.T) {
+	str := ir.IRSchema{Kind: ir.IRKindString}
+	search := ir.IROperation{QueryParams: []ir.IRParam{{Name: "q", Schema: str}}}
+	extensible := ir.IRModelDef{Name: "Extensible", Schema: ir.IRSchema{Kind: ir.IRKindObject, Properties: []ir.IRField{{Name: "id", Type: &str}}, AdditionalProperties: &str}}
+
+	tests := []struct {
+		name     string
+		client   config.Client
+		in       ir.IR
+		expected []string
+	}{
+		{"nothing to import", config.Client{}, ir.IR{Services: []ir.IRService{{Operations: []ir.IROperation{{}}}}}, []string{}},
+		{"query struct", config.Client{}, ir.IR{Services: []ir.IRService{{Operations: []ir.IROperation{search}}}}, []string{"fmt", "net/url"}},
+		{"additional properties", config.Client{}, ir.IR{ModelDefs: []ir.IRModelDef{extensible}}, []string{"encoding/json", "fmt"}},
+		{"native dates", config.Client{DateAsNativeType: true}, ir.IR{}, []string{"encoding/json", "fmt", "time"}},
+	}
+	for _, test := range tests {
+		if got := modelFileImports(test.client, test.in); !reflect.DeepEqual(got, test.expected) {
+			t.Errorf("%s: modelFileImports() = %v, expected %v", test.name, got, test.expected)
+		}
+	}
+}
+
+func TestGoFileName(t *testing.T) {
+	tests := []struct {
+		parts    []string
+		expected string
+	}{
+		{[]string{"users"}, "users.go"},
+		{[]string{"users", "list_all"}, "users_list_all.go"},
+		{[]string{"users", "test"}, "users_test_gen.go"},
+		{[]string{"builds", "run_windows"}, "builds_run_windows_gen.go"},
+		{[]string{"linux"}, "linux.go"},
+	}
+
+	for _, test := range tests {
+		if got := goFileName(test.parts...); got != test.expected {
+			t.Errorf("goFileName(%v) = %q, expected %q", test.parts, got, test.expected)
+		}
+	}
+}
+
+func TestServiceFileName(t *testing.T) {
+	tests := []struct {
+		fileNaming string
+		words      []string
+		expected   string
+	}{
+		{"", []string{"UserAccounts"}, "user_accounts.go"},
+		{config.FileNamingSnake, []string{"Users", "Test"}, "users_test_gen.go"},
+		{config.FileNamingKebab, []string{"Users", "Test"}, "users-test.go"},
+		{config.FileNamingCamel, []string{"UserAccounts", "ListAll"}, "userAccountsListAll.go"},
+		{config.FileNamingPascal, []string{"UserAccounts"}, "UserAccounts.go"},
+	}
+
+	for _, test := range tests {
+		if got := serviceFileName(config.Client{FileNaming: test.fileNaming}, test.words...); got != test.expected {
+			t.Errorf("serviceFileName(%q, %v) = %q, expected %q", test.fileNaming, test.words, got, test.expected)
+		}
+	}
+}
+
+func TestFieldComment(t *testing.T) {
+	tests := []struct {
+		description string
+		deprecated  bool
+		comment     string
+		doc         string
+	}{
+		{"", false, "", ""},
+		{"Page size\nin items", false, " // Page size in items", ""},
+		// Deprecated fields are documented above the field, with a Deprecated: paragraph
+		{"Page size\nin items", true, "", "// Page size\n// in items\n//\n// Deprecated: the API marks this field as deprecated."},
+		{"", true, "", "// Deprecated: the API marks this field as deprecated."},
+	}
+
+	for _, test := range tests {
+		if got := fieldComment(test.description, test.deprecated); got != test.comment {
+			t.Errorf("fieldComment(%q, %v) = %q, expected %q", test.description, test.deprecated, got, test.comment)
+		}
+		if got := fieldDoc(test.description, test.deprecated); got != test.doc {
+			t.Errorf("fieldDoc(%q, %v) = %q, expected %q", test.description, test.deprecated, got, test.doc)
+		}
+	}
+}
+
+func TestModelFieldComment(t *testing.T) {
+	password := &ir.IRSchema{Kind: ir.IRKindString, Format: "password"}
+	tests := []struct {
+		field    ir.IRField
+		expected string
+	}{
+		{ir.IRField{Name: "name", Type: &ir.IRSchema{Kind: ir.IRKindString}}, ""},
+		{ir.IRField{Name: "password", Type: password}, " // Sensitive: keep out of logs"},
+		{ir.IRField{Name: "secret", Annotations: ir.IRAnnotations{WriteOnly: true, Description: "Client secret"}}, " // Client secret (sensitive: keep out of logs)"},
+		{ir.IRField{Name: "pin", Type: password, Annotations: ir.IRAnnotations{Deprecated: true, Description: "Legacy PIN"}}, " // Sensitive: keep out of logs"},
+	}
+
+	for _, test := range tests {
+		if got := modelFieldComment(test.field); got != test.expected {
+			t.Errorf("modelFieldComment(%s) = %q, expected %q", test.field.Name, got, test.expected)
+		}
+	}
+}
+
+func TestModelStructTag(t *testing.T) {
+	dateTime := &ir.IRSchema{Kind: ir.IRKindString, Format: "date-time"}
+	date := &ir.IRSchema{Kind: ir.IRKindString, Format: "date"}
+	native := typeOptions{DateAsNativeType: true}
+	tests := []struct {
+		name     string
+		field    ir.IRField
+		opts     typeOptions
+		expected string
+	}{
+		{"optional date-time", ir.IRField{Name: "at", Type: dateTime}, native, "`json:\"at,omitzero\"`"},
+		{"optional date", ir.IRField{Name: "day", Type: date}, native, "`json:\"day,omitzero\"`"},
+		{"required date", ir.IRField{Name: "day", Type: date, Required: true}, native, "`json:\"day\"`"},
+		{"nullable date", ir.IRField{Name: "day", Type: &ir.IRSchema{Kind: ir.IRKindString, Format: "date", Nullable: true}}, native, "`json:\"day\"`"},
+		{"date as string", ir.IRField{Name: "day", Type: date}, typeOptions{}, "`json:\"day\"`"},
+		{"string", ir.IRField{Name: "name", Type: &ir.IRSchema{Kind: ir.IRKindString}}, native, "`json:\"name\"`"},
+	}
+	for _, test := range tests {
+		if got := modelStructTag(test.field, test.opts); got != test.expected {
+			t.Errorf("%s: modelStructTag() = %s, expected %s", test.name, got, test.expected)
+		}
+	}
+}
+
+func TestGoEnumConsts(t *testing.T) {
+	tests := []struct {
+		name     string
+		schema   ir.IRSchema
+		expected []goEnumConst
+	}{
+		{
+			name:     "string values colliding once sanitized",
+			schema:   ir.IRSchema{Kind: ir.IRKindEnum, EnumBase: ir.IRKindString, EnumValues: []string{"in-progress", "IN_PROGRESS", ""}},
+			expected: []goEnumConst{{"StatusInProgress", `"in-progress"`}, {"StatusInProgress2", `"IN_PROGRESS"`}, {"StatusEmpty", `""`}},
+		},
+		{
+			name:     "numeric with names",
+			schema:   ir.IRSchema{Kind: ir.IRKindEnum, EnumBase: ir.IRKindInteger, EnumValues: []string{"1", "2"}, EnumRaw: []any{float64(1), float64(2)}, EnumNames: []string{"low", "high"}},
+			expected: []goEnumConst{{"StatusLow", "1"}, {"StatusHigh", "2"}},
+		},
+		{
+			name:     "numeric without names",
+			schema:   ir.IRSchema{Kind: ir.IRKindEnum, EnumBase: ir.IRKindNumber, EnumValues: []string{"1.5"}, EnumRaw: []any{1.5}},
+			expected: []goEnumConst{{"StatusValue15", "1.5"}},
+		},
+		{
+			name:   "boolean",
+			schema: ir.IRSchema{Kind: ir.IRKindEnum, EnumBase: ir.IRKindBoolean, EnumValues: []string{"true"}, EnumRaw: []any{true}},
+		},
+	}
+
+	for _, test := range tests {
+		if got := goEnumConsts("status", test.schema); !reflect.DeepEqual(got, test.expected) {
+			t.Errorf("%s: goEnumConsts() = %v, expected %v", test.name, got, test.expected)
+		}
+	}
+}
+
+func TestBuildMethodSignature(t *testing.T) {
+	op := ir.IROperation{
+		Tag:         "users",
+		Method:      "GET",
+		Path:        "/users/{id}",
+		PathParams:  []ir.IRParam{{Name: "id", Schema: ir.IRSchema{Kind: ir.IRKindString}, Required: true}},
+		QueryParams: []ir.IRParam{{Name: "expand", Schema: ir.IRSchema{Kind: ir.IRKindBoolean}}},
+		Response:    ir.IRResponse{Schema: ir.IRSchema{Kind: ir.IRKindString}},
+	}
+	tests := []struct {
+		mode        string
+		withContext bool
+		expected    string
+	}{
+		{"", true, "GetUser(ctx context.Context, id string, query *UsersGetUserQuery) (string, error)"},
+		{config.GoContextOnly, true, "GetUser(ctx context.Context, id string, query *UsersGetUserQuery) (string, error)"},
+		{config.GoContextBoth, true, "GetUserWithContext(ctx context.Context, id string, query *UsersGetUserQuery) (string, error)"},
+		{config.GoContextBoth, false, "GetUser(id string, query *UsersGetUserQuery) (string, error)"},
+		{config.GoNoContext, false, "GetUser(id string, query *UsersGetUserQuery) (string, error)"},
+	}
+
+	for _, test := range tests {
+		got := buildMethodSignature(config.Client{GoContextMode: test.mode}, op, "GetUser", test.withContext)
+		if got != test.expected {
+			t.Errorf("buildMethodSignature(%q, %v) = %q, expected %q", test.mode, test.withContext, got, test.expected)
+		}
+	}
+	raw := buildMethodSignature(config.Client{RawResponse: true}, op, "GetUser", true)
+	if expected := "GetUser(ctx context.Context, id string, query *UsersGetUserQuery) (string, *http.Response, error)"; raw != expected {
+		t.Errorf("buildMethodSignature with rawResponse = %q, expected %q", raw, expected)
+	}
+	op.Response.Stream = true
+	stream := buildMethodSignature(config.Client{}, op, "GetUser", true)
+	if expected := "GetUser(ctx context.Context, id string, query *UsersGetUserQuery) (io.ReadCloser, error)"; stream != expected {
+		t.Errorf("buildMethodSignature with a streamed response = %q, expected %q", stream, expected)
+	}
+}
+
+func TestAcceptVariantMethods(t *testing.T) {
+	str := ir.IRSchema{Kind: ir.IRKindString}
+	op := ir.IROperation{Tag: "reports", Method: "GET", Path: "/report", Response: ir.IRResponse{
+		ContentType: "application/json",
+		Schema:      ir.IRSchema{Kind: ir.IRKindRef, Ref: "Report"},
+		Contents: []ir.IRResponseContent{
+			{ContentType: "application/json", Schema: ir.IRSchema{Kind: ir.IRKindRef, Ref: "Report"}},
+			{ContentType: "application/xml", Schema: str},
+			{ContentType: "text/csv; charset=utf-8", Schema: str},
+			{ContentType: "text/xml", Schema: str},
+		},
+	}}
+	client := config.Client{GoContextMode: config.GoContextBoth}
+
+	var got []string
+	for _, variant := range op.AcceptVariants() {
+		got = append(got, buildMethodSignature(client, variant, "GetReport", true)+" "+acceptHeader(variant))
+	}
+	expected := []string{
+		`GetReportWithContext(ctx context.Context) (Report, error) map[string]string{"Accept": "application/json"}`,
+		`GetReportAsApplicationXmlWithContext(ctx context.Context) (string, error) map[string]string{"Accept": "application/xml"}`,
+		`GetReportAsCsvWithContext(ctx context.Context) (string, error) map[string]string{"Accept": "text/csv; charset=utf-8"}`,
+		`GetReportAsTextXmlWithContext(ctx context.Context) (string, error) map[string]string{"Accept": "text/xml"}`,
+	}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("accept variants = %q, expected %q", got, expected)
+	}
+
+	op.Response.Contents = op.Response.Contents[:1]
+	if got := acceptHeader(op); got != "nil" {
+		t.Errorf("acceptHeader() = %s for a single media type", got)
+	}
+}
+
+func TestBuildPathTemplate(t *testing.T) {
+	op := ir.IROperation{Path: "/products/{id}/variants/{color}/sizes/{size}", PathParams: []ir.IRParam{
+		{Name: "color", Style: ir.PathStyleLabel},
+		{Name: "id", Style: ir.PathStyleMatrix},
+		{Name: "size"},
+	}}
+	if got, expected := buildPathTemplate(op), `"/products/;id=%v/variants/.%v/sizes/%v"`; got != expected {
+		t.Errorf("buildPathTemplate() = %s, expected %s", got, expected)
+	}
+}
+
+func TestServiceFileImports(t *testing.T) {
+	str := ir.IRSchema{Kind: ir.IRKindString}
+	getUser := ir.IROperation{Method: "GET", Path: "/users/{id}", PathParams: []ir.IRParam{{Name: "id", Schema: str, Required: true}}}
+	listUsers := ir.IROperation{Method: "GET", Path: "/users", Response: ir.IRResponse{Schema: ir.IRSchema{Kind: ir.IRKindString, Format: "date-time"}}}
+	searchUsers := ir.IROperation{Method: "GET", Path: "/users/search", QueryParams: []ir.IRParam{{Name: "limit", Schema: ir.IRSchema{Kind: ir.IRKindInteger}, Default: 20}}}
+	exportUsers := ir.IROperation{Method: "GET", Path: "/users/export", Response: ir.IRResponse{Stream: true}}
+	methodName := func(op ir.IROperation) string { return op.Method }
+	tests := []struct {
+		name     string
+		client   config.Client
+		ops      []ir.IROperation
+		expected []string
+	}{
+		{"declaration only", config.Client{}, nil, nil},
+		{"path params", config.Client{}, []ir.IROperation{getUser}, []string{"context", "fmt", "net/url"}},
+		{"no path params", config.Client{}, []ir.IROperation{listUsers}, []string{"context", "net/url"}},
+		{"native dates", config.Client{DateAsNativeType: true}, []ir.IROperation{listUsers}, []string{"context", "net/url", "time"}},
+		{"query defaults", config.Client{}, []ir.IROperation{searchUsers}, []string{"context"}},
+		{"streamed response", config.Client{}, []ir.IROperation{exportUsers}, []string{"context", "io", "net/url"}},
+	}
+
+	for _, test := range tests {
+		got := serviceFileImports(test.client, ir.IRService{Tag: "users", Operations: test.ops}, methodName)
+		if !reflect.DeepEqual(got, test.expected) {
+			t.Errorf("%s: serviceFileImports() = %v, expected %v", test.name, got, test.expected)
+		}
+	}
+}
+
+func TestInterfaceImports(t *testing.T) {
+	str := ir.IRSchema{Kind: ir.IRKindString}
+	getUser := ir.IROperation{Method: "GET", Path: "/users/{id}", PathParams: []ir.IRParam{{Name: "id", Schema: str, Required: true}}}
+	listUsers := ir.IROperation{Method: "GET", Path: "/users", Response: ir.IRResponse{Schema: ir.IRSchema{Kind: ir.IRKindString, Format: "date-time"}}}
+	exportUsers := ir.IROperation{Method: "GET", Path: "/users/export", Response: ir.IRResponse{Stream: true}}
+	methodName := func(op ir.IROperation) string { return op.Method }
+	tests := []struct {
+		name     string
+		client   config.Client
+		ops      []ir.IROperation
+		expected []string
+	}{
+		{"no operations", config.Client{}, nil, []string{}},
+		{"path params", config.Client{}, []ir.IROperation{getUser}, []string{"context"}},
+		{"no context", config.Client{GoContextMode: config.GoNoContext}, []ir.IROperation{getUser}, []string{}},
+		{"native dates", config.Client{DateAsNativeType: true}, []ir.IROperation{listUsers}, []string{"context", "time"}},
+		{"raw response", config.Client{RawResponse: true}, []ir.IROperation{listUsers}, []string{"context", "net/http"}},
+		{"streamed response", config.Client{}, []ir.IROperation{exportUsers}, []string{"context", "io"}},
+	}
+
+	for _, test := range tests {
+		got := interfaceImports(test.client, []ir.IRService{{Tag: "users", Operations: test.ops}}, methodName)
+		if !reflect.DeepEqual(got, test.expected) {
+			t.Errorf("%s: interfaceImports() = %v, expected %v", test.name, got, test.expected)
+		}
+	}
+}
+
+func TestExampleLiteral(t *testing.T) {
+	str := ir.IRSchema{Kind: ir.IRKindString}
+	r := exampleRenderer{pkg: "sdk", defs: map[string]ir.IRModelDef{
+		"User": {Name: "User", Schema: ir.IRSchema{Kind: ir.IRKindObject, Properties: []ir.IRField{
+			{Name: "name", Type: &str, Required: true},
+			{Name: "role", Type: &ir.IRSchema{Kind: ir.IRKindEnum, EnumValues: []string{"admin", "user"}}, Annotations: ir.IRAnnotations{Default: "user"}},
+			{Name: "manager", Type: &ir.IRSchema{Kind: ir.IRKindRef, Ref: "User", Nullable: true}, Required: true},
+			{Name: "nickname", Type: &str},
+		}}},
+		"status": {Name: "status", Schema: ir.IRSchema{Kind: ir.IRKindEnum, EnumBase: ir.IRKindString, EnumValues: []string{"active", "ACTIVE"}}},
+		"Admin": {Name: "Admin", Schema: ir.IRSchema{Kind: ir.IRKindAllOf, AllOf: []*ir.IRSchema{
+			{Kind: ir.IRKindRef, Ref: "User"},
+			{Kind: ir.IRKindObject, Properties: []ir.IRField{{Name: "level", Type: &ir.IRSchema{Kind: ir.IRKindInteger}, Required: true}}},
+		}}},
+	}, imports: map[string]bool{}}
+	tests := []struct {
+		name     string
+		schema   ir.IRSchema
+		value    any
+		expected string
+	}{
+		{"integer from JSON", ir.IRSchema{Kind: ir.IRKindInteger}, float64(42), "42"},
+		{"number", ir.IRSchema{Kind: ir.IRKindNumber}, 1.5, "1.5"},
+		{"nullable scalar", ir.IRSchema{Kind: ir.IRKindBoolean, Nullable: true}, true, "nil"},
+		{"unknown enum value", ir.IRSchema{Kind: ir.IRKindEnum, EnumValues: []string{"a", "b"}}, "z", `"a"`},
+		{"integer enum", ir.IRSchema{Kind: ir.IRKindEnum, EnumBase: ir.IRKindInteger, EnumValues: []string{"1", "2"}, EnumRaw: []any{1.0, 2.0}}, 2.0, "2"},
+		{"named enum", ir.IRSchema{Kind: ir.IRKindRef, Ref: "status"}, "ACTIVE", "sdk.StatusActive2"},
+		{"named enum without example", ir.IRSchema{Kind: ir.IRKindRef, Ref: "status"}, nil, "sdk.StatusActive"},
+		{"model placeholder", ir.IRSchema{Kind: ir.IRKindRef, Ref: "User"}, nil, `sdk.User{Name: "", Role: "user", Manager: nil}`},
+		{"model example", ir.IRSchema{Kind: ir.IRKindRef, Ref: "User"}, map[string]any{"name": "Ada", "manager": map[string]any{"name": "Bob"}}, `sdk.User{Name: "Ada", Manager: &sdk.User{Name: "Bob"}}`},
+		{"embedded model", ir.IRSchema{Kind: ir.IRKindRef, Ref: "Admin"}, map[string]any{"name": "Ada", "level": 2.0}, `sdk.Admin{User: sdk.User{Name: "Ada"}, Level: 2}`},
+		{"array of models", ir.IRSchema{Kind: ir.IRKindArray, Items: &ir.IRSchema{Kind: ir.IRKindRef, Ref: "User"}}, []any{map[string]any{"name": "Ada"}}, `[]sdk.User{sdk.User{Name: "Ada"}}`},
+		{"untyped object", ir.IRSchema{Kind: ir.IRKindObject}, map[string]any{"b": []any{1.0, "x"}, "a": nil}, `map[string]interface{}{"a": nil, "b": []interface{}{1, "x"}}`},
+		{"type override", ir.IRSchema{Kind: ir.IRKindString, TypeOverrides: map[string]string{"go": "decimal.Decimal"}, TypeImports: map[string]string{"go": "github.com/shopspring/decimal"}}, "1.5", "*new(decimal.Decimal)"},
+	}
+
+	for _, test := range tests {
+		if got := r.literal(test.schema, test.value); got != test.expected {
+			t.Errorf("%s: literal() = %s, expected %s", test.name, got, test.expected)
+		}
+	}
+	if !r.imports["github.com/shopspring/decimal"] {
+		t.Errorf("expected the override import to be collected, got %v", r.imports)
+	}
+}
+
+func TestExampleOperation(t *testing.T) {
+	list := ir.IRSchema{Kind: ir.IRKindArray, Items: &ir.IRSchema{Kind: ir.IRKindString}}
+	tests := []struct {
+		name     string
+		ops      []ir.IROperation
+		expected string
+	}{
+		{"list first", []ir.IROperation{
+			{OperationID: "getUser", Method: "GET", PathParams: []ir.IRParam{{Name: "id"}}},
+			{OperationID: "createUser", Method: "POST"},
+			{OperationID: "listUsers", Method: "GET", Response: ir.IRResponse{Schema: list}},
+		}, "listUsers"},
+		{"create before other reads", []ir.IROperation{
+			{OperationID: "me", Method: "GET"},
+			{OperationID: "createUser", Method: "POST"},
+		}, "createUser"},
+		{"first when all take path parameters", []ir.IROperation{
+			{OperationID: "deleteUser", Method: "DELETE", PathParams: []ir.IRParam{{Name: "id"}}},
+			{OperationID: "getUser", Method: "GET", PathParams: []ir.IRParam{{Name: "id"}}},
+		}, "deleteUser"},
+	}
+
+	for _, test := range tests {
+		if got := exampleOperation(test.ops).OperationID; got != test.expected {
+			t.Errorf("%s: exampleOperation() = %s, expected %s", test.name, got, test.expected)
+		}
+	}
+}
+
+func TestGoStructBody(t *testing.T) {
+	str := &ir.IRSchema{Kind: ir.IRKindString}
+	object := func(names ...string) *ir.IRSchema {
+		s := &ir.IRSchema{Kind: ir.IRKindObject}
+		for _, name := range names {
+			s.Properties = append(s.Properties, ir.IRField{Name: name, Type: str})
+		}
+		return s
+	}
+	ref := func(name string) *ir.IRSchema { return &ir.IRSchema{Kind: ir.IRKindRef, Ref: name} }
+	defs := modelDefsByName([]ir.IRModelDef{
+		{Name: "Pet", Schema: *object("id", "name")},
+		{Name: "Tagged", Schema: *object("tag")},
+		{Name: "Color", Schema: ir.IRSchema{Kind: ir.IRKindEnum, EnumValues: []string{"red"}}},
+	})
+	allOf := func(parts ...*ir.IRSchema) ir.IRSchema { return ir.IRSchema{Kind: ir.IRKindAllOf, AllOf: parts} }
+
+	tests := []struct {
+		name     string
+		schema   ir.IRSchema
+		embeds   []string
+		expected []string
+	}{
+		{"object", *object("a", "b"), nil, []string{"a", "b"}},
+		{"ref plus inline", allOf(ref("Pet"), object("breed")), []string{"Pet"}, []string{"breed"}},
+		{"several refs", allOf(ref("Pet"), ref("Tagged"), object("name")), nil, []string{"id", "name", "tag"}},
+		{"pure inline", allOf(object("a"), object("b", "a")), nil, []string{"a", "b"}},
+		{"ref to non-struct", allOf(ref("Color"), object("a")), nil, []string{"a"}},
+	}
+	for _, test := range tests {
+		body := goStructBody(defs, test.schema)
+		var names []string
+		for _, f := range body.Fields {
+			names = append(names, f.Name)
+		}
+		if !reflect.DeepEqual(body.Embeds, test.embeds) || !reflect.DeepEqual(names, test.expected) {
+			t.Errorf("%s: goStructBody() = %v %v, expected %v %v", test.name, body.Embeds, names, test.embeds, test.expected)
+		}
+	}
+
+	// A base with additional properties has JSON methods of its own, so it is flattened
+	extensible := *object("id")
+	extensible.AdditionalProperties = str
+	defs["Extensible"] = ir.IRModelDef{Name: "Extensible", Schema: extensible}
+	body := goStructBody(defs, allOf(ref("Extensible"), object("name")))
+	if len(body.Embeds) != 0 || len(body.Fields) != 2 || body.Additional != str {
+		t.Errorf("expected Extensible flattened with its additional properties, got %+v", body)
+	}
+}
+
+func TestGoStructDefaults(t *testing.T) {
+	field := func(name string, s ir.IRSchema, def any) ir.IRField {
+		return ir.IRField{Name: name, Type: &s, Annotations: ir.IRAnnotations{Default: def}}
+	}
+	str := ir.IRSchema{Kind: ir.IRKindString}
+	integer := ir.IRSchema{Kind: ir.IRKindInteger}
+	defs := modelDefsByName([]ir.IRModelDef{
+		{Name: "Role", Schema: ir.IRSchema{Kind: ir.IRKindEnum, EnumValues: []string{"member"}}},
+		{Name: "NewUser", Schema: ir.IRSchema{Kind: ir.IRKindObject, Properties: []ir.IRField{
+			field("name", str, nil),
+			field("age", integer, float64(18)),
+			field("role", ir.IRSchema{Kind: ir.IRKindRef, Ref: "Role"}, "member"),
+			field("score", ir.IRSchema{Kind: ir.IRKindNumber}, 1.5),
+			field("tags", ir.IRSchema{Kind: ir.IRKindArray, Items: &str}, []any{"a"}),
+			field("active", ir.IRSchema{Kind: ir.IRKindBoolean}, true),
+			field("nickname", ir.IRSchema{Kind: ir.IRKindString, Nullable: true}, "x"),
+			field("limit", integer, 1.5),
+		}}},
+		{Name: "Admin", Schema: ir.IRSchema{Kind: ir.IRKindAllOf, AllOf: []*ir.IRSchema{
+			{Kind: ir.IRKindRef, Ref: "NewUser"},
+			{Kind: ir.IRKindObject, Properties: []ir.IRField{field("level", str, nil)}},
+		}}},
+	})
+
+	d := goStructDefaults(defs, goStructBody(defs, defs["NewUser"].Schema), typeOptions{})
+	expected := []goFieldDefault{
+		{Name: "Age", Zero: "0", Value: "18"},
+		{Name: "Role", Zero: `""`, Value: `"member"`},
+		{Name: "Score", Zero: "0", Value: "1.5"},
+		{Name: "Tags", Zero: "nil", Value: `[]string{"a"}`},
+	}
+	if len(d.Embeds) != 0 || !reflect.DeepEqual(d.Fields, expected) {
+		t.Errorf("goStructDefaults(NewUser) = %+v, expected %+v", d, expected)
+	}
+
+	d = goStructDefaults(defs, goStructBody(defs, defs["Admin"].Schema), typeOptions{})
+	if !reflect.DeepEqual(d.Embeds, []string{"NewUser"}) || len(d.Fields) != 0 {
+		t.Errorf("goStructDefaults(Admin) = %+v, expected the NewUser base only", d)
+	}
+
+	body := func(ref string) ir.IROperation {
+		return ir.IROperation{RequestBody: &ir.IRRequestBody{ContentType: "application/json", Schema: ir.IRSchema{Kind: ir.IRKindRef, Ref: ref}}}
+	}
+	if !bodyHasDefaults(defs, body("Admin"), typeOptions{}) || bodyHasDefaults(defs, body("Role"), typeOptions{}) {
+		t.Error("expected only struct bodies with defaults to apply them")
+	}
+}
+
+func TestAdditionalPropertiesGoTypes(t *testing.T) {
+	widget := &ir.IRSchema{Kind: ir.IRKindRef, Ref: "widget"}
+	typedMap := ir.IRSchema{Kind: ir.IRKindObject, AdditionalProperties: widget}
+	mixed := ir.IRSchema{
+		Kind:                 ir.IRKindObject,
+		Properties:           []ir.IRField{{Name: "name", Type: &ir.IRSchema{Kind: ir.IRKindString}}},
+		AdditionalProperties: &ir.IRSchema{Kind: ir.IRKindInteger},
+	}
+
+	tests := []struct {
+		name     string
+		schema   ir.IRSchema
+		expected string
+	}{
+		{"typed map", typedMap, "map[string]Widget"},
+		{"map of arrays", ir.IRSchema{Kind: ir.IRKindObject, AdditionalProperties: &ir.IRSchema{Kind: ir.IRKindArray, Items: widget}}, "map[string][]Widget"},
+		{"nullable map", ir.IRSchema{Kind: ir.IRKindObject, AdditionalProperties: widget, Nullable: true}, "*map[string]Widget"},
+		{"inline object with properties", mixed, "map[string]interface{}"},
+		{"free-form object", ir.IRSchema{Kind: ir.IRKindObject}, "map[string]interface{}"},
+	}
+	for _, test := range tests {
+		if got := schemaToGoType(test.schema, typeOptions{}); got != test.expected {
+			t.Errorf("%s: schemaToGoType() = %q, expected %q", test.name, got, test.expected)
+		}
+	}
+
+	// A model that is only a typed map is a map type, not a struct to embed
+	defs := modelDefsByName([]ir.IRModelDef{{Name: "Widgets", Schema: typedMap}, {Name: "Labels", Schema: mixed}})
+	if isStructModel(defs, "Widgets") || !isStructModel(defs, "Labels") {
+		t.Error("expected Widgets to be a map model and Labels a struct model")
+	}
+	if body := goStructBody(defs, typedMap); body.Additional != nil {
+		t.Errorf("expected no additional properties field for a typed map, got %+v", body.Additional)
+	}
+	body := goStructBody(defs, mixed)
+	if body.Additional == nil || body.Additional.Kind != ir.IRKindInteger || len(body.Fields) != 1 {
+		t.Errorf("expected the name field plus an int64 AdditionalProperties map, got %+v", body)
+	}
+
+	r := exampleRenderer{pkg: "sdk", defs: defs, opts: typeOptions{}, imports: map[string]bool{}}
+	value := map[string]any{"b": 2.0, "a": 1.0}
+	if got := r.literal(ir.IRSchema{Kind: ir.IRKindObject, AdditionalProperties: &ir.IRSchema{Kind: ir.IRKindInteger}}, value); got != `map[string]int64{"a": 1, "b": 2}` {
+		t.Errorf("inline typed map literal = %s", got)
+	}
+	if got := r.literal(ir.IRSchema{Kind: ir.IRKindRef, Ref: "Widgets"}, map[string]any{"x": nil}); got != `sdk.Widgets{"x": sdk.Widget{}}` {
+		t.Errorf("typed map model literal = %s", got)
+	}
+}
+
+func TestOperationMetadataNames(t *testing.T) {
+	tests := []struct {
+		op                ir.IROperation
+		method            string
+		key, constantName string
+	}{
+		{ir.IROperation{OperationID: "listUsers", Method: "GET", Path: "/users", Tag: "users"}, "ListUsers", "listUsers", "OpUsersListUsers"},
+		{ir.IROperation{Method: "GET", Path: "/health", Tag: "misc"}, "List", "GET /health", "OpMiscList"},
+		{ir.IROperation{OperationID: "getUser", Method: "GET", Path: "/users/{id}", Tag: "admin.users"}, "GetUser", "getUser", "OpAdminUsersGetUser"},
+	}
+	for _, test := range tests {
+		if got := operationKey(test.op); got != test.key {
+			t.Errorf("operationKey(%s %s) = %q, expected %q", test.op.Method, test.op.Path, got, test.key)
+		}
+		if got := operationConst(test.op, test.method); got != test.constantName {
+			t.Errorf("operationConst(%s %s) = %q, expected %q", test.op.Method, test.op.Path, got, test.constantName)
+		}
+	}
+}
+
+func TestDefaultBaseURL(t *testing.T) {
+	servers := []ir.IRServer{{URL: "/v1"}, {URL: "https://api.example.com/v1/"}, {URL: "https://sandbox.example.com"}}
+	tests := []struct {
+		client   config.Client
+		servers  []ir.IRServer
+		expected string
+	}{
+		{config.Client{DefaultBaseURL: "https://configured.example.com"}, servers, "https://configured.example.com"},
+		{config.Client{}, servers, "https://api.example.com/v1"},
+		{config.Client{}, servers[:1], ""},
+		{config.Client{}, nil, ""},
+	}
+	for _, test := range tests {
+		if got := defaultBaseURL(test.client, test.servers); got != test.expected {
+			t.Errorf("defaultBaseURL(%q, %v) = %q, expected %q", test.client.DefaultBaseURL, test.servers, got, test.expected)
+		}
+	}
+}
+
+func TestDefaultHeaders(t *testing.T) {
+	in := ir.IR{Services: []ir.IRService{{Tag: "items", Operations: []ir.IROperation{
+		{OperationID: "listItems", Method: "GET", Path: "/items", Tag: "items", Response: ir.IRResponse{TypeTS: "void"}},
+	}}}}
+	for _, tc := range []struct {
+		name     string
+		headers  map[string]string
+		expected string
+	}{
+		{"none", nil, "headers:    make(map[string]string),"},
+		// Set before the options run, so WithHeaders and per-call headers override them
+		{"configured", map[string]string{"X-Client": "sdk", "User-Agent": "acme/1.0"}, "headers: map[string]string{\n\t\t\t\"User-Agent\": \"acme/1.0\",\n\t\t\t\"X-Client\":   \"sdk\",\n\t\t},"},
+	} {
+		dir := t.TempDir()
+		client := config.Client{Type: "go", OutDir: dir, PackageName: "items", ModuleName: "example.com/items", Name: "Items", DefaultHeaders: tc.headers}
+		if err := NewGoGenerator().Generate(client, in); err != nil {
+			t.Fatal(err)
+		}
+		data, err := os.ReadFile(filepath.Join(dir, "client.go"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !strings.Contains(string(data), tc.expected) {
+			t.Errorf("%s: client.go does not contain %q", tc.name, tc.expected)
+		}
+	}
+}
+
+func TestCookieAPIKey(t *testing.T) {
+	in := ir.IR{SecuritySchemes: []ir.IRSecurityScheme{{Key: "session", Type: "apiKey", In: "cookie", Name: "sid"}}}
+	dir := t.TempDir()
+	client := config.Client{Type: "go", OutDir: dir, PackageName: "items", ModuleName: "example.com/items", Name: "Items"}
+	if err := NewGoGenerator().Generate(client, in); err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(filepath.Join(dir, "client.go"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Added as a cookie, keeping cookies already on the request
+	expected := "\tif c.session != \"\" {\n\t\tname := \"sid\"\n\t\tif c.sessionName != \"\" {\n\t\t\tname = c.sessionName\n\t\t}\n\t\treq.AddCookie(&http.Cookie{Name: name, Value: c.session})\n\t}"
+	if !strings.Contains(string(data), expected) {
+		t.Errorf("client.go does not contain %q", expected)
+	}
+	if strings.Contains(string(data), `Header.Set("Cookie"`) {
+		t.Errorf("client.go sets the Cookie header")
+	}
+}
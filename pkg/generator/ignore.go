@@ -0,0 +1,107 @@
+package generator
+
+import (
+	"strings"
+
+	"github.com/blimu-dev/sdk-gen/pkg/ir"
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+// ignoreExtension marks an operation or component schema that SDKs leave out entirely
+const ignoreExtension = "x-sdk-ignore"
+
+// sdkIgnored reports whether extensions carry x-sdk-ignore: true
+func sdkIgnored(extensions map[string]any) bool {
+	ignored, _ := extensions[ignoreExtension].(bool)
+	return ignored
+}
+
+// ignoredSchemas returns the names of the component schemas marked with x-sdk-ignore
+func ignoredSchemas(doc *openapi3.T) map[string]bool {
+	out := map[string]bool{}
+	if doc.Components == nil {
+		return out
+	}
+	for name, sr := range doc.Components.Schemas {
+		if sr != nil && sr.Value != nil && sdkIgnored(sr.Value.Extensions) {
+			out[name] = true
+		}
+	}
+	return out
+}
+
+// pruneIgnoredRefs removes the references to ignored schemas left in the IR: properties and
+// oneOf/anyOf/allOf members typed by one are dropped, and any other use (array items, bodies,
+// parameters) becomes an unknown schema.
+func pruneIgnoredRefs(in ir.IR, ignored map[string]bool) ir.IR {
+	if len(ignored) == 0 {
+		return in
+	}
+	refersToIgnored := func(s *ir.IRSchema) bool { return refersTo(s, ignored) }
+
+	// Drop members first, while their references are still visible
+	out := mapSchemas(in, func(s ir.IRSchema) ir.IRSchema {
+		if s.Properties != nil {
+			props := make([]ir.IRField, 0, len(s.Properties))
+			for _, f := range s.Properties {
+				if !refersToIgnored(f.Type) {
+					props = append(props, f)
+				}
+			}
+			s.Properties = props
+		}
+		s.OneOf = withoutSchemas(s.OneOf, refersToIgnored)
+		s.AnyOf = withoutSchemas(s.AnyOf, refersToIgnored)
+		s.AllOf = withoutSchemas(s.AllOf, refersToIgnored)
+		if refersToIgnored(s.Not) {
+			s.Not = nil
+		}
+		if s.Discriminator != nil && len(s.Discriminator.Mapping) > 0 {
+			disc := *s.Discriminator
+			disc.Mapping = map[string]string{}
+			for value, ref := range s.Discriminator.Mapping {
+				if !ignored[ref[strings.LastIndex(ref, "/")+1:]] {
+					disc.Mapping[value] = ref
+				}
+			}
+			s.Discriminator = &disc
+		}
+		return s
+	})
+	return mapSchemas(out, func(s ir.IRSchema) ir.IRSchema {
+		if s.Kind == ir.IRKindRef && ignored[s.Ref] {
+			return ir.IRSchema{Kind: ir.IRKindUnknown, Nullable: s.Nullable}
+		}
+		return s
+	})
+}
+
+// refersTo reports whether s is a reference to one of names, directly or as the items of an
+// array or the values of a map
+func refersTo(s *ir.IRSchema, names map[string]bool) bool {
+	switch {
+	case s == nil:
+		return false
+	case s.Kind == ir.IRKindRef:
+		return names[s.Ref]
+	case s.Kind == ir.IRKindArray:
+		return refersTo(s.Items, names)
+	case s.Kind == ir.IRKindObject && len(s.Properties) == 0:
+		return refersTo(s.AdditionalProperties, names)
+	}
+	return false
+}
+
+// withoutSchemas returns list without the schemas drop reports, keeping nil lists nil
+func withoutSchemas(list []*ir.IRSchema, drop func(*ir.IRSchema) bool) []*ir.IRSchema {
+	if list == nil {
+		return nil
+	}
+	out := make([]*ir.IRSchema, 0, len(list))
+	for _, s := range list {
+		if !drop(s) {
+			out = append(out, s)
+		}
+	}
+	return out
+}
@@ -0,0 +1,82 @@
+package generator
+
+import (
+	"testing"
+
+	"github.com/blimu-dev/sdk-gen/pkg/config"
+	"github.com/blimu-dev/sdk-gen/pkg/ir"
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+func TestSDKIgnore(t *testing.T) {
+	doc, err := openapi3.NewLoader().LoadFromFile("testdata/sdk-ignore.yaml")
+	if err != nil {
+		t.Fatalf("load fixture: %v", err)
+	}
+	// The ignored reindex operation has no tags, which would otherwise fail the build
+	fullIR, err := BuildIRWithOptions(doc, BuildIROptions{UntaggedBehavior: config.UntaggedError})
+	if err != nil {
+		t.Fatalf("BuildIRWithOptions: %v", err)
+	}
+
+	var ops []string
+	for _, svc := range fullIR.Services {
+		for _, op := range svc.Operations {
+			ops = append(ops, op.OperationID)
+		}
+	}
+	if len(ops) != 1 || ops[0] != "listUsers" {
+		t.Errorf("operations = %v, expected only listUsers", ops)
+	}
+
+	out, err := (&Service{}).filterIR(fullIR, config.Client{})
+	if err != nil {
+		t.Fatalf("filterIR: %v", err)
+	}
+	models := map[string]ir.IRModelDef{}
+	for _, md := range out.ModelDefs {
+		models[md.Name] = md
+	}
+	if _, ok := models["InternalNote"]; ok {
+		t.Error("expected the ignored InternalNote schema to be skipped")
+	}
+	if _, ok := models["DebugInfo"]; ok {
+		t.Error("expected DebugInfo, only used by the ignored operation, to be pruned")
+	}
+	if _, ok := models["Cat"]; !ok {
+		t.Error("expected Cat to be kept")
+	}
+
+	var user []string
+	var pet ir.IRSchema
+	for _, f := range models["User"].Schema.Properties {
+		user = append(user, f.Name)
+		if f.Name == "pet" {
+			pet = *f.Type
+		}
+	}
+	if len(user) != 2 || user[0] != "id" || user[1] != "pet" {
+		t.Errorf("User properties = %v, expected [id pet] without the notes referencing InternalNote", user)
+	}
+	if len(pet.OneOf) != 1 || pet.OneOf[0].Ref != "Cat" {
+		t.Errorf("pet.oneOf = %+v, expected only Cat", pet.OneOf)
+	}
+}
+
+func TestPruneIgnoredRefs(t *testing.T) {
+	in := ir.IR{Services: []ir.IRService{{Tag: "notes", Operations: []ir.IROperation{{
+		RequestBody: &ir.IRRequestBody{Schema: ir.IRSchema{Kind: ir.IRKindRef, Ref: "Note"}},
+		Response:    ir.IRResponse{Schema: ir.IRSchema{Kind: ir.IRKindArray, Items: &ir.IRSchema{Kind: ir.IRKindRef, Ref: "Note", Nullable: true}}},
+	}}}}}
+
+	op := pruneIgnoredRefs(in, map[string]bool{"Note": true}).Services[0].Operations[0]
+	if op.RequestBody.Schema.Kind != ir.IRKindUnknown {
+		t.Errorf("request body = %+v, expected an unknown schema", op.RequestBody.Schema)
+	}
+	if items := op.Response.Schema.Items; items.Kind != ir.IRKindUnknown || !items.Nullable {
+		t.Errorf("response items = %+v, expected a nullable unknown schema", items)
+	}
+	if in.Services[0].Operations[0].RequestBody.Schema.Kind != ir.IRKindRef {
+		t.Error("expected the input IR to be left untouched")
+	}
+}
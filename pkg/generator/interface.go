@@ -1,18 +1,32 @@
 package generator
 
 import (
+	"errors"
 	"fmt"
+	"log/slog"
+	"maps"
 	"os"
 	"os/exec"
+	"runtime"
+	"sort"
+	"strconv"
 	"strings"
+	"sync"
+	"text/template"
+	"time"
 
 	"github.com/blimu-dev/sdk-gen/pkg/config"
 	"github.com/blimu-dev/sdk-gen/pkg/generator/golang"
+	"github.com/blimu-dev/sdk-gen/pkg/generator/jsonschema"
+	"github.com/blimu-dev/sdk-gen/pkg/generator/kotlin"
+	"github.com/blimu-dev/sdk-gen/pkg/generator/mock"
 	"github.com/blimu-dev/sdk-gen/pkg/generator/python"
+	"github.com/blimu-dev/sdk-gen/pkg/generator/swift"
 	"github.com/blimu-dev/sdk-gen/pkg/generator/typescript"
 	typescripttypes "github.com/blimu-dev/sdk-gen/pkg/generator/typescript-types"
 	"github.com/blimu-dev/sdk-gen/pkg/ir"
 	"github.com/blimu-dev/sdk-gen/pkg/openapi"
+	"github.com/getkin/kin-openapi/openapi3"
 )
 
 // Generator defines the interface for SDK generators
@@ -23,9 +37,28 @@ type Generator interface {
 	GetType() string
 }
 
+// TemplateFuncsSetter is implemented by generators that render templates, to make extra
+// functions available to them
+type TemplateFuncsSetter interface {
+	// SetTemplateFuncs adds funcs to the template functions, replacing built-in helpers of
+	// the same name
+	SetTemplateFuncs(funcs template.FuncMap)
+}
+
+// LoggerSetter is implemented by generators that report warnings while generating, such as
+// method names renamed to resolve a collision
+type LoggerSetter interface {
+	// SetLogger sets the logger warnings are reported to
+	SetLogger(logger *slog.Logger)
+}
+
 // Registry manages available generators
 type Registry struct {
 	generators map[string]Generator
+	// funcs are the template functions handed to generators as they are registered
+	funcs template.FuncMap
+	// logger is handed to generators as they are registered
+	logger *slog.Logger
 }
 
 // NewRegistry creates a new generator registry
@@ -37,21 +70,68 @@ func NewRegistry() *Registry {
 
 // Register adds a generator to the registry
 func (r *Registry) Register(gen Generator) {
+	if setter, ok := gen.(TemplateFuncsSetter); ok && r.funcs != nil {
+		setter.SetTemplateFuncs(r.funcs)
+	}
+	if setter, ok := gen.(LoggerSetter); ok && r.logger != nil {
+		setter.SetLogger(r.logger)
+	}
 	r.generators[gen.GetType()] = gen
 }
 
+// SetLogger sets the logger the registered generators, and those registered later, report
+// warnings to
+func (r *Registry) SetLogger(logger *slog.Logger) {
+	r.logger = logger
+	for _, gen := range r.generators {
+		if setter, ok := gen.(LoggerSetter); ok {
+			setter.SetLogger(logger)
+		}
+	}
+}
+
+// SetTemplateFuncs makes funcs available to the templates of the registered generators and of
+// those registered later. A function named like a built-in template helper of a generator
+// replaces it there, so helpers such as pascal can be customized as well.
+func (r *Registry) SetTemplateFuncs(funcs template.FuncMap) error {
+	if err := checkTemplateFuncs(funcs); err != nil {
+		return err
+	}
+	// Generators read the map while generating concurrently
+	r.funcs = maps.Clone(funcs)
+	for _, gen := range r.generators {
+		if setter, ok := gen.(TemplateFuncsSetter); ok {
+			setter.SetTemplateFuncs(r.funcs)
+		}
+	}
+	return nil
+}
+
+// checkTemplateFuncs reports the first function text/template would refuse, as it panics when
+// given a name that is not an identifier or a value that is not a suitable function
+func checkTemplateFuncs(funcs template.FuncMap) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("invalid template functions: %v", r)
+		}
+	}()
+	template.New("").Funcs(funcs)
+	return nil
+}
+
 // Get retrieves a generator by type
 func (r *Registry) Get(genType string) (Generator, bool) {
 	gen, exists := r.generators[genType]
 	return gen, exists
 }
 
-// GetAvailableTypes returns all registered generator types
+// GetAvailableTypes returns all registered generator types, sorted
 func (r *Registry) GetAvailableTypes() []string {
 	types := make([]string, 0, len(r.generators))
 	for t := range r.generators {
 		types = append(types, t)
 	}
+	sort.Strings(types)
 	return types
 }
 
@@ -59,7 +139,9 @@ func (r *Registry) GetAvailableTypes() []string {
 type GenerateOptions struct {
 	ConfigPath   string
 	SingleClient string
-	Fallback     FallbackOptions
+	// NoCache bypasses the spec cache even when enabled in the config
+	NoCache  bool
+	Fallback FallbackOptions
 }
 
 // FallbackOptions contains fallback options when no config file is provided
@@ -71,11 +153,14 @@ type FallbackOptions struct {
 	Name        string
 	IncludeTags []string
 	ExcludeTags []string
+	SpecHeaders map[string]string
 }
 
 // Service provides high-level SDK generation functionality
 type Service struct {
 	registry *Registry
+	// logger receives progress reports, see SetLogger
+	logger *slog.Logger
 }
 
 // NewService creates a new generator service with default generators
@@ -86,6 +171,10 @@ func NewService() *Service {
 	registry.Register(golang.NewGoGenerator())
 	registry.Register(python.NewPythonGenerator())
 	registry.Register(typescripttypes.NewTypeScriptTypesGenerator())
+	registry.Register(kotlin.NewKotlinGenerator())
+	registry.Register(swift.NewSwiftGenerator())
+	registry.Register(jsonschema.NewJSONSchemaGenerator())
+	registry.Register(mock.NewMockGenerator())
 	return &Service{
 		registry: registry,
 	}
@@ -98,6 +187,12 @@ func NewServiceWithRegistry(registry *Registry) *Service {
 	}
 }
 
+// SetTemplateFuncs makes funcs available to the templates of every generator of the service;
+// see Registry.SetTemplateFuncs
+func (s *Service) SetTemplateFuncs(funcs template.FuncMap) error {
+	return s.registry.SetTemplateFuncs(funcs)
+}
+
 // Generate generates SDKs based on the provided options
 func (s *Service) Generate(opts GenerateOptions) error {
 	var cfg *config.Config
@@ -111,7 +206,8 @@ func (s *Service) Generate(opts GenerateOptions) error {
 			return fmt.Errorf("either config path or all fallback options must be provided")
 		}
 		cfg = &config.Config{
-			Spec: opts.Fallback.Spec,
+			Spec:        opts.Fallback.Spec,
+			SpecHeaders: opts.Fallback.SpecHeaders,
 			Clients: []config.Client{
 				{
 					Type:        opts.Fallback.Type,
@@ -130,63 +226,206 @@ func (s *Service) Generate(opts GenerateOptions) error {
 		}
 	}
 
+	if opts.NoCache {
+		cfg.SpecCache.Enabled = false
+	}
+
 	return s.GenerateFromConfig(cfg, opts.SingleClient)
 }
 
 // GenerateFromConfig generates SDKs from a configuration
 func (s *Service) GenerateFromConfig(cfg *config.Config, onlyClient string) error {
-	// Load and validate OpenAPI document
-	doc, err := openapi.LoadDocument(cfg.Spec)
+	// Load the OpenAPI document, merging several specs when configured
+	doc, err := loadConfigDocument(cfg)
 	if err != nil {
 		return err
 	}
+	s.log().Info("spec loaded", "spec", configSpecs(cfg), "paths", doc.Paths.Len())
+
+	var order []string
+	if cfg.PreserveSpecOrder {
+		if order, err = loadSourceOrder(cfg); err != nil {
+			return err
+		}
+	}
 
 	// Build IR from OpenAPI document
-	fullIR, err := s.buildIR(doc)
+	fullIR, err := s.buildIR(doc, cfg, order)
 	if err != nil {
 		return err
 	}
+	s.log().Info("IR built", irCounts(fullIR)...)
 
-	// Generate for each client
+	// Select clients and resolve their generators up front so unknown types fail fast
+	clients := make([]config.Client, 0, len(cfg.Clients))
 	for _, client := range cfg.Clients {
 		if onlyClient != "" && client.Name != onlyClient {
 			continue
 		}
-
-		generator, exists := s.registry.Get(client.Type)
-		if !exists {
+		if _, exists := s.registry.Get(client.Type); !exists {
 			return fmt.Errorf("unsupported client type: %s", client.Type)
 		}
-
-		// Ensure output directory exists before pre-commands
-		if err := os.MkdirAll(client.OutDir, 0o755); err != nil {
-			return fmt.Errorf("failed to create output directory for client %s: %w", client.Name, err)
+		clients = append(clients, client)
+	}
+	for _, client := range clients {
+		if client.Type != "jsonschema" {
+			warnUnsupported(os.Stderr, fullIR)
+			break
 		}
+	}
+
+	// Generate clients concurrently; fullIR is read-only from here on
+	sem := make(chan struct{}, resolveConcurrency(cfg, len(clients)))
+	errs := make([]error, len(clients))
+	var wg sync.WaitGroup
+	for i, client := range clients {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int, client config.Client) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			errs[i] = s.generateClient(client, fullIR)
+		}(i, client)
+	}
+	wg.Wait()
+
+	return errors.Join(errs...)
+}
+
+// loadConfigDocument loads cfg.Spec, or loads every entry of cfg.Specs and merges them
+// into a single document
+func loadConfigDocument(cfg *config.Config) (*openapi3.T, error) {
+	cacheOpts := specCacheOptions(cfg)
+	if len(cfg.Specs) == 0 {
+		return openapi.LoadDocumentCached(cfg.Spec, cacheOpts)
+	}
 
-		// Execute pre-generation commands if specified
-		if err := s.executePreCommands(client); err != nil {
-			return fmt.Errorf("pre-generation commands failed for client %s: %w", client.Name, err)
+	sources := make([]openapi.MergeSource, 0, len(cfg.Specs))
+	for _, spec := range cfg.Specs {
+		doc, err := openapi.LoadDocumentCached(spec.Path, cacheOpts)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load spec %s: %w", spec.Path, err)
 		}
+		sources = append(sources, openapi.MergeSource{Name: spec.Path, Doc: doc, Prefix: spec.Prefix})
+	}
+	return openapi.MergeDocuments(sources)
+}
+
+// configSpecs returns the spec of cfg, or its specs joined with commas when several are merged
+func configSpecs(cfg *config.Config) string {
+	if len(cfg.Specs) == 0 {
+		return cfg.Spec
+	}
+	paths := make([]string, 0, len(cfg.Specs))
+	for _, spec := range cfg.Specs {
+		paths = append(paths, spec.Path)
+	}
+	return strings.Join(paths, ",")
+}
 
-		// Filter IR based on client configuration
-		filteredIR, err := s.filterIR(fullIR, client)
+// loadSourceOrder returns the operations of cfg.Spec, or of every cfg.Specs entry in turn,
+// in the order they are written
+func loadSourceOrder(cfg *config.Config) ([]string, error) {
+	inputs := []string{cfg.Spec}
+	if len(cfg.Specs) > 0 {
+		inputs = inputs[:0]
+		for _, spec := range cfg.Specs {
+			inputs = append(inputs, spec.Path)
+		}
+	}
+	var order []string
+	for _, input := range inputs {
+		keys, err := openapi.LoadSourceOrder(input, specCacheOptions(cfg))
 		if err != nil {
-			return err
+			return nil, err
 		}
+		order = append(order, keys...)
+	}
+	return order, nil
+}
+
+// specCacheOptions returns the options spec documents are loaded with
+func specCacheOptions(cfg *config.Config) openapi.CacheOptions {
+	return openapi.CacheOptions{
+		Dir:      cfg.SpecCache.Dir,
+		Disabled: !cfg.SpecCache.Enabled,
+		Headers:  cfg.SpecHeaders,
+	}
+}
 
-		if err := generator.Generate(client, filteredIR); err != nil {
+// generateClient filters the IR for a client and generates it, or one package per tag for
+// packagePerTag clients
+func (s *Service) generateClient(client config.Client, fullIR ir.IR) error {
+	// Filter IR based on client configuration
+	filteredIR, err := s.filterIR(fullIR, client)
+	if err != nil {
+		return err
+	}
+	s.log().Info("IR filtered", append([]any{"client", client.Name}, irCounts(filteredIR)...)...)
+
+	if !client.PackagePerTag {
+		return s.generatePackage(client, filteredIR)
+	}
+	for _, pkg := range splitPackagesPerTag(client, filteredIR) {
+		if err := s.generatePackage(pkg.Client, pkg.IR); err != nil {
 			return err
 		}
+	}
+	return nil
+}
 
-		// Execute post-generation commands if specified
-		if err := s.executePostGenCommands(client); err != nil {
-			return fmt.Errorf("post-generation commands failed for client %s: %w", client.Name, err)
-		}
+// generatePackage runs the pre-command, generation, and post-command for a single client in order
+func (s *Service) generatePackage(client config.Client, in ir.IR) error {
+	generator, _ := s.registry.Get(client.Type)
+	start := time.Now()
+	s.log().Info("generating client", "client", client.Name, "type", client.Type, "outDir", client.OutDir)
+
+	// Ensure output directory exists before pre-commands
+	if err := os.MkdirAll(client.OutDir, 0o755); err != nil {
+		return fmt.Errorf("failed to create output directory for client %s: %w", client.Name, err)
+	}
+
+	// Execute pre-generation commands if specified
+	if err := s.executePreCommands(client); err != nil {
+		return fmt.Errorf("pre-generation commands failed for client %s: %w", client.Name, err)
+	}
+
+	if err := generator.Generate(client, in); err != nil {
+		return err
 	}
+	s.logWrittenFiles(client.Name, client.OutDir, start)
 
+	// Execute post-generation commands if specified
+	if err := s.executePostGenCommands(client); err != nil {
+		return fmt.Errorf("post-generation commands failed for client %s: %w", client.Name, err)
+	}
+
+	s.log().Info("client generated", "client", client.Name, "duration", time.Since(start).Round(time.Millisecond))
 	return nil
 }
 
+// resolveConcurrency determines how many clients may be generated at once.
+// The SDKGEN_CONCURRENCY environment variable takes precedence over the config value;
+// when neither is set, the number of CPUs is used.
+func resolveConcurrency(cfg *config.Config, clients int) int {
+	n := cfg.Concurrency
+	if env := os.Getenv("SDKGEN_CONCURRENCY"); env != "" {
+		if v, err := strconv.Atoi(env); err == nil {
+			n = v
+		}
+	}
+	if n <= 0 {
+		n = runtime.NumCPU()
+	}
+	if n > clients {
+		n = clients
+	}
+	if n < 1 {
+		n = 1
+	}
+	return n
+}
+
 // GetRegistry returns the generator registry
 func (s *Service) GetRegistry() *Registry {
 	return s.registry
@@ -225,6 +464,7 @@ func (s *Service) executeCommand(command []string, workDir, commandLabel string)
 	cmd.Stderr = os.Stderr // Forward stderr to see errors
 
 	cmdDescription := strings.Join(command, " ")
+	s.log().Info("running "+commandLabel, "command", cmdDescription, "dir", workDir)
 
 	// Execute the command
 	if err := cmd.Run(); err != nil {
@@ -0,0 +1,326 @@
+package generator
+
+import (
+	"bytes"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"testing"
+	"text/template"
+
+	"github.com/blimu-dev/sdk-gen/pkg/config"
+	"github.com/blimu-dev/sdk-gen/pkg/generator/typescript"
+	"github.com/blimu-dev/sdk-gen/pkg/ir"
+)
+
+func TestResolveConcurrency(t *testing.T) {
+	tests := []struct {
+		name     string
+		cfg      int
+		env      string
+		clients  int
+		expected int
+	}{
+		{name: "config value", cfg: 2, clients: 5, expected: 2},
+		{name: "capped by client count", cfg: 8, clients: 3, expected: 3},
+		{name: "env overrides config", cfg: 2, env: "4", clients: 5, expected: 4},
+		{name: "invalid env ignored", cfg: 2, env: "many", clients: 5, expected: 2},
+		{name: "no clients", cfg: 2, clients: 0, expected: 1},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Setenv("SDKGEN_CONCURRENCY", test.env)
+			result := resolveConcurrency(&config.Config{Concurrency: test.cfg}, test.clients)
+			if result != test.expected {
+				t.Errorf("resolveConcurrency() = %d, expected %d", result, test.expected)
+			}
+		})
+	}
+}
+
+// TestGenerateTypesOnly generates TypeScript and Python packages with emitClient: false and
+// requires the models and entry points without the client and services
+func TestGenerateTypesOnly(t *testing.T) {
+	spec, err := filepath.Abs("testdata/determinism.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	root := t.TempDir()
+	emitClient := false
+	cfg := &config.Config{Spec: spec, UntaggedTag: config.DefaultUntaggedTag, UntaggedBehavior: config.UntaggedBucket}
+	for _, typ := range []string{"typescript", "python"} {
+		cfg.Clients = append(cfg.Clients, config.Client{
+			Type:        typ,
+			OutDir:      filepath.Join(root, typ),
+			PackageName: "store",
+			Name:        "Store",
+			EmitClient:  &emitClient,
+		})
+	}
+	if err := NewService().GenerateFromConfig(cfg, ""); err != nil {
+		t.Fatal(err)
+	}
+
+	files := readTree(t, root)
+	var names []string
+	for name := range files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	expected := []string{
+		"python/README.md", "python/pyproject.toml", "python/store/__init__.py", "python/store/models.py", "python/store/py.typed",
+		"typescript/.gitignore", "typescript/.prettierignore", "typescript/.prettierrc.json", "typescript/README.md",
+		"typescript/package.json", "typescript/src/index.ts", "typescript/src/schema.ts", "typescript/tsconfig.json",
+	}
+	if strings.Join(names, "\n") != strings.Join(expected, "\n") {
+		t.Errorf("generated files:\n%s\n\nexpected:\n%s", strings.Join(names, "\n"), strings.Join(expected, "\n"))
+	}
+	for _, name := range []string{"python/store/__init__.py", "python/pyproject.toml", "typescript/src/index.ts", "typescript/package.json"} {
+		for _, ref := range []string{"./client", "from .client", "httpx", "Service"} {
+			if strings.Contains(string(files[name]), ref) {
+				t.Errorf("%s mentions %q", name, ref)
+			}
+		}
+	}
+}
+
+func TestGenerateRequestBuilders(t *testing.T) {
+	spec, err := filepath.Abs("testdata/determinism.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, singleOptionsArg := range []bool{false, true} {
+		root := t.TempDir()
+		cfg := &config.Config{Spec: spec, UntaggedTag: config.DefaultUntaggedTag, UntaggedBehavior: config.UntaggedBucket, Clients: []config.Client{{
+			Type:                "typescript",
+			OutDir:              root,
+			PackageName:         "store",
+			Name:                "Store",
+			EmitRequestBuilders: true,
+			SingleOptionsArg:    singleOptionsArg,
+		}}}
+		if err := NewService().GenerateFromConfig(cfg, ""); err != nil {
+			t.Fatal(err)
+		}
+
+		files := readTree(t, root)
+		if !strings.Contains(string(files["src/client.ts"]), "describe(init: RequestOptions): RequestDescriptor {") {
+			t.Errorf("singleOptionsArg %v: client.ts has no describe method", singleOptionsArg)
+		}
+		methods := regexp.MustCompile(`(?m)^  (\w+)(?:<[^>]*>)?\(`)
+		blank := regexp.MustCompile(`(?m)^[ \t]+$`)
+		for name, data := range files {
+			if !strings.HasPrefix(name, "src/services/") {
+				continue
+			}
+			// Builders are separated from the methods by a single blank line, like the methods
+			if blank.Match(data) || strings.Contains(string(data), "\n\n\n") {
+				t.Errorf("singleOptionsArg %v: %s has stray blank lines:\n%s", singleOptionsArg, name, data)
+			}
+			declared := map[string]bool{}
+			for _, m := range methods.FindAllSubmatch(data, -1) {
+				declared[string(m[1])] = true
+			}
+			if len(declared) == 0 {
+				t.Errorf("singleOptionsArg %v: %s declares no methods", singleOptionsArg, name)
+			}
+			for method := range declared {
+				if method == "constructor" || strings.HasSuffix(method, "__request") {
+					continue
+				}
+				if !declared[method+"__request"] {
+					t.Errorf("singleOptionsArg %v: %s has no request builder for %s", singleOptionsArg, name, method)
+				}
+			}
+		}
+	}
+}
+
+func TestGenerateTypeScriptNullability(t *testing.T) {
+	spec, err := filepath.Abs("testdata/nullability.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	root := t.TempDir()
+	cfg := &config.Config{Spec: spec, UntaggedTag: config.DefaultUntaggedTag, UntaggedBehavior: config.UntaggedBucket, Clients: []config.Client{{
+		Type:        "typescript",
+		OutDir:      root,
+		PackageName: "entries",
+		Name:        "Entries",
+	}}}
+	if err := NewService().GenerateFromConfig(cfg, ""); err != nil {
+		t.Fatal(err)
+	}
+
+	schema := string(readTree(t, root)["src/schema.ts"])
+	// required x nullable for scalars, refs, arrays and maps, then refs to nullable models
+	for _, expected := range []string{
+		"scalar: string;",
+		"nullableScalar: string | null;",
+		"optionalScalar?: string;",
+		"optionalNullableScalar?: string | null;",
+		"ref: Audited;",
+		"nullableRef: Audited | null;",
+		"optionalRef?: Audited;",
+		"optionalNullableRef?: Audited | null;",
+		"array: Array<string>;",
+		"nullableArray: Array<string | null> | null;",
+		"optionalArray?: Array<Owner | null>;",
+		"optionalNullableArray?: Array<string> | null;",
+		"map: Record<string, number>;",
+		"nullableMap: Record<string, number | null> | null;",
+		"optionalMap?: Record<string, Owner | null>;",
+		"optionalNullableMap?: Record<string, number> | null;",
+		"owner?: Owner | null;",
+		"color?: Color | null;",
+		"labels?: Labels;",
+		"merged?: (Owner | null) & Audited;",
+		"export type Labels = Record<string, string | null> | null;",
+	} {
+		if !strings.Contains(schema, "\n    "+expected+"\n") && !strings.Contains(schema, "\n  "+expected+"\n") {
+			t.Errorf("schema.ts has no %q", expected)
+		}
+	}
+}
+
+func TestSetTemplateFuncs(t *testing.T) {
+	service := NewService()
+	for _, funcs := range []template.FuncMap{
+		{"not a name": strings.ToUpper},
+		{"upper": "not a function"},
+	} {
+		if err := service.SetTemplateFuncs(funcs); err == nil {
+			t.Errorf("SetTemplateFuncs(%v): expected an error", funcs)
+		}
+	}
+
+	// quote is a built-in helper of the mock server templates
+	if err := service.SetTemplateFuncs(template.FuncMap{
+		"quote": func(s string) string { return strconv.Quote(strings.ToUpper(s)) },
+	}); err != nil {
+		t.Fatalf("SetTemplateFuncs: %v", err)
+	}
+	spec, err := filepath.Abs("testdata/determinism.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	out := t.TempDir()
+	cfg := &config.Config{
+		Spec:             spec,
+		UntaggedTag:      config.DefaultUntaggedTag,
+		UntaggedBehavior: config.UntaggedBucket,
+		Clients:          []config.Client{{Type: "mock", OutDir: out, PackageName: "store", Name: "Store"}},
+	}
+	if err := service.GenerateFromConfig(cfg, ""); err != nil {
+		t.Fatal(err)
+	}
+	main, err := os.ReadFile(filepath.Join(out, "main.go"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(main), `contentType: "APPLICATION/JSON"`) {
+		t.Errorf("expected the quote helper to be replaced, got:\n%s", main)
+	}
+}
+
+func TestGenerateLogging(t *testing.T) {
+	spec, err := filepath.Abs("testdata/determinism.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg := &config.Config{
+		Spec:             spec,
+		UntaggedTag:      config.DefaultUntaggedTag,
+		UntaggedBehavior: config.UntaggedBucket,
+		Clients: []config.Client{{
+			Type:              "typescript",
+			OutDir:            t.TempDir(),
+			PackageName:       "store",
+			Name:              "Store",
+			ExcludeTags:       []string{"^admin"},
+			ExcludeOperations: []string{"^replaceOrderNotes$"},
+		}},
+	}
+
+	var buf bytes.Buffer
+	service := NewService()
+	service.SetLogger(slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug})))
+	if err := service.GenerateFromConfig(cfg, ""); err != nil {
+		t.Fatal(err)
+	}
+	out := buf.String()
+	for _, expected := range []string{
+		`msg="spec loaded"`,
+		`msg="IR built" operations=5`,
+		`msg="generating client" client=Store type=typescript`,
+		`msg="operation filtered out" client=Store operation=listUsers tags=[admin.users] by=includeTags/excludeTags`,
+		`msg="operation filtered out" client=Store operation=replaceOrderNotes by=includeOperations/excludeOperations`,
+		`msg="IR filtered" client=Store operations=2`,
+		`msg="file written" client=Store path=`,
+		`msg="client generated" client=Store`,
+	} {
+		if !strings.Contains(out, expected) {
+			t.Errorf("expected the log to contain %s, got:\n%s", expected, out)
+		}
+	}
+
+	// Without a logger nothing is reported, and filterIR works on a zero Service
+	if _, err := (&Service{}).filterIR(ir.IR{}, cfg.Clients[0]); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestMethodNameWarningsLogged(t *testing.T) {
+	op := func(path string) ir.IROperation {
+		return ir.IROperation{OperationID: "getItem", Method: "GET", Path: path, Tag: "items", Response: ir.IRResponse{TypeTS: "void"}}
+	}
+	in := ir.IR{Services: []ir.IRService{{Tag: "items", Operations: []ir.IROperation{op("/items/{id}"), op("/v2/items/{id}")}}}}
+
+	// The logger reaches generators registered before and after it is set
+	for name, newService := range map[string]func(*slog.Logger) *Service{
+		"Service.SetLogger": func(logger *slog.Logger) *Service {
+			service := NewService()
+			service.SetLogger(logger)
+			return service
+		},
+		"Registry.SetLogger": func(logger *slog.Logger) *Service {
+			registry := NewRegistry()
+			registry.SetLogger(logger)
+			registry.Register(typescript.NewTypeScriptGenerator())
+			return NewServiceWithRegistry(registry)
+		},
+	} {
+		var buf bytes.Buffer
+		service := newService(slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelWarn})))
+		client := config.Client{Type: "typescript", OutDir: t.TempDir(), PackageName: "items", Name: "Items"}
+		if err := service.generateClient(client, in); err != nil {
+			t.Fatal(err)
+		}
+		out := buf.String()
+		if !strings.Contains(out, "level=WARN") || !strings.Contains(out, "GET /v2/items/{id} -> getItem2") || !strings.Contains(out, "client=Items") {
+			t.Errorf("%s: expected a warning for the renamed method, got:\n%s", name, out)
+		}
+	}
+}
+
+func BenchmarkGenerateClient(b *testing.B) {
+	fullIR, err := BuildIR(largeSpec(b, 2000))
+	if err != nil {
+		b.Fatal(err)
+	}
+	for _, typ := range []string{"typescript", "python", "go"} {
+		b.Run(typ, func(b *testing.B) {
+			client := config.Client{Type: typ, OutDir: b.TempDir(), PackageName: "large", ModuleName: "example.com/large", Name: "Large"}
+			for i := 0; i < b.N; i++ {
+				if err := NewService().generateClient(client, fullIR); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
@@ -2,17 +2,65 @@ package generator
 
 import (
 	"fmt"
+	"maps"
+	"math"
 	"regexp"
+	"slices"
 	"sort"
+	"strings"
 
 	"github.com/blimu-dev/sdk-gen/pkg/config"
 	"github.com/blimu-dev/sdk-gen/pkg/ir"
+	"github.com/blimu-dev/sdk-gen/pkg/openapi"
 	"github.com/getkin/kin-openapi/openapi3"
 )
 
-// buildIR creates an IR from an OpenAPI document
-func (s *Service) buildIR(doc *openapi3.T) (ir.IR, error) {
-	tags := collectTags(doc)
+// BuildIR creates the complete, unfiltered IR from an OpenAPI document, including
+// ModelDefs and SecuritySchemes. No tag filtering is applied, so callers writing their
+// own generators can filter the result as they see fit.
+func BuildIR(doc *openapi3.T) (ir.IR, error) {
+	return BuildIRWithOptions(doc, BuildIROptions{})
+}
+
+// BuildIROptions controls how BuildIRWithOptions treats operations without tags and names
+// inline schemas
+type BuildIROptions struct {
+	// UntaggedTag is the service untagged operations are grouped into (defaults to "misc")
+	UntaggedTag string
+	// UntaggedBehavior is config.UntaggedBucket (default), config.UntaggedSkip or
+	// config.UntaggedError
+	UntaggedBehavior string
+	// UseSchemaTitleAsName turns titled inline object and enum schemas into models named after
+	// their title. The document is modified: those schemas move to its components.
+	UseSchemaTitleAsName bool
+	// PreserveSpecOrder orders services by the document's tags declaration and operations by
+	// OperationOrder; anything unlisted follows in alphabetical order
+	PreserveSpecOrder bool
+	// OperationOrder lists operations as openapi.OperationKey values in source order (see
+	// openapi.SourceOrder). Without it operations stay sorted by path and method.
+	OperationOrder []string
+}
+
+// BuildIRWithOptions is BuildIR with control over untagged operations. With
+// config.UntaggedError it fails, listing every operation that has no tags.
+func BuildIRWithOptions(doc *openapi3.T, opts BuildIROptions) (ir.IR, error) {
+	if opts.UntaggedTag == "" {
+		opts.UntaggedTag = config.DefaultUntaggedTag
+	}
+	if opts.UntaggedBehavior == "" {
+		opts.UntaggedBehavior = config.UntaggedBucket
+	}
+	if opts.UntaggedBehavior == config.UntaggedError {
+		if untagged := untaggedOperations(doc); len(untagged) > 0 {
+			return ir.IR{}, fmt.Errorf("operations without tags (untaggedBehavior is %q): %s", config.UntaggedError, strings.Join(untagged, ", "))
+		}
+	}
+
+	if opts.UseSchemaTitleAsName {
+		hoistTitledSchemas(doc)
+	}
+
+	tags := collectTags(doc, opts)
 	sec := collectSecuritySchemes(doc)
 	modelDefs := buildStructuredModels(doc)
 
@@ -23,11 +71,38 @@ func (s *Service) buildIR(doc *openapi3.T) (ir.IR, error) {
 	}
 
 	// Build IR with all operations
-	result := buildIRFromDoc(doc, allowed)
+	result := buildIRFromDoc(doc, allowed, opts)
 	result.SecuritySchemes = sec
-	result.ModelDefs = modelDefs
+	result.Servers = collectServers(doc)
+	webhooks, payloads, err := extractWebhooks(doc, modelDefs)
+	if err != nil {
+		return ir.IR{}, err
+	}
+	result.ModelDefs = append(modelDefs, payloads...)
+	result.Webhooks = webhooks
 
-	return result, nil
+	return pruneIgnoredRefs(result, ignoredSchemas(doc)), nil
+}
+
+// BuildIRFromSpec loads an OpenAPI document from a file path or HTTP(S) URL and builds its IR
+func BuildIRFromSpec(spec string) (ir.IR, error) {
+	doc, err := openapi.LoadDocument(spec)
+	if err != nil {
+		return ir.IR{}, err
+	}
+	return BuildIR(doc)
+}
+
+// buildIR creates an IR from an OpenAPI document, handling untagged operations as configured
+// and ordering operations by order when cfg.PreserveSpecOrder is set
+func (s *Service) buildIR(doc *openapi3.T, cfg *config.Config, order []string) (ir.IR, error) {
+	return BuildIRWithOptions(doc, BuildIROptions{
+		UntaggedTag:          cfg.UntaggedTag,
+		UntaggedBehavior:     cfg.UntaggedBehavior,
+		UseSchemaTitleAsName: cfg.UseSchemaTitleAsName,
+		PreserveSpecOrder:    cfg.PreserveSpecOrder,
+		OperationOrder:       order,
+	})
 }
 
 // filterIR filters the IR based on client configuration
@@ -36,13 +111,26 @@ func (s *Service) filterIR(fullIR ir.IR, client config.Client) (ir.IR, error) {
 	if err != nil {
 		return ir.IR{}, err
 	}
+	includeOps, err := compilePatterns("includeOperations", client.IncludeOperations)
+	if err != nil {
+		return ir.IR{}, err
+	}
+	excludeOps, err := compilePatterns("excludeOperations", client.ExcludeOperations)
+	if err != nil {
+		return ir.IR{}, err
+	}
 
-	// Filter services and operations based on their original tags
+	// Filter services and operations based on their original tags, then their operationIds
 	filteredServices := make([]ir.IRService, 0)
 	for _, service := range fullIR.Services {
 		filteredOps := make([]ir.IROperation, 0)
 		for _, op := range service.Operations {
-			if shouldIncludeOperation(op.OriginalTags, include, exclude) {
+			switch key := operationFilterKey(op); {
+			case !shouldIncludeOperation(op.OriginalTags, include, exclude):
+				s.log().Debug("operation filtered out", "client", client.Name, "operation", key, "tags", op.OriginalTags, "by", "includeTags/excludeTags")
+			case !shouldIncludeOperation([]string{key}, includeOps, excludeOps):
+				s.log().Debug("operation filtered out", "client", client.Name, "operation", key, "by", "includeOperations/excludeOperations")
+			default:
 				filteredOps = append(filteredOps, op)
 			}
 		}
@@ -54,23 +142,57 @@ func (s *Service) filterIR(fullIR ir.IR, client config.Client) (ir.IR, error) {
 		}
 	}
 
-	// Filter ModelDefs to only include those referenced by filtered operations
+	// Callbacks go with the operation declaring them; webhooks are always kept
+	keptOps := map[string]bool{}
+	for _, service := range filteredServices {
+		for _, op := range service.Operations {
+			keptOps[operationFilterKey(op)] = true
+		}
+	}
+	var webhooks []ir.IRWebhook
+	for _, wh := range fullIR.Webhooks {
+		if wh.Operation == "" || keptOps[wh.Operation] {
+			webhooks = append(webhooks, wh)
+		}
+	}
+
+	// Filter ModelDefs to only include those referenced by filtered operations and webhooks
 	filteredIR := ir.IR{
 		Services:        filteredServices,
 		Models:          fullIR.Models,
 		SecuritySchemes: fullIR.SecuritySchemes,
+		Servers:         fullIR.Servers,
 		ModelDefs:       fullIR.ModelDefs,
+		Webhooks:        webhooks,
 	}
 	filteredIR.ModelDefs = filterUnusedModelDefs(filteredIR, fullIR.ModelDefs)
+	if len(filteredIR.ModelDefs) < len(fullIR.ModelDefs) {
+		kept := map[string]bool{}
+		for _, md := range filteredIR.ModelDefs {
+			kept[md.Name] = true
+		}
+		for _, md := range fullIR.ModelDefs {
+			if !kept[md.Name] {
+				s.log().Debug("model left out, no remaining operation uses it", "client", client.Name, "model", md.Name)
+			}
+		}
+	}
 
-	return filteredIR, nil
+	if client.RequireRequestBodies {
+		filteredIR = requireRequestBodies(filteredIR)
+	}
+	filteredIR = noteUnsupportedConstraints(filteredIR, client)
+	filteredIR = applyTypeMappings(filteredIR, client)
+	return applyModelNameAffixes(filteredIR, client.ModelNamePrefix, client.ModelNameSuffix), nil
 }
 
 // collectTags extracts all tags from the OpenAPI document
-func collectTags(doc *openapi3.T) []string {
+func collectTags(doc *openapi3.T, opts BuildIROptions) []string {
 	uniq := map[string]struct{}{}
-	// consider untagged as "misc"
-	uniq["misc"] = struct{}{}
+	// consider untagged as the untagged bucket
+	if opts.UntaggedBehavior == config.UntaggedBucket {
+		uniq[opts.UntaggedTag] = struct{}{}
+	}
 	for path, item := range doc.Paths.Map() {
 		_ = path
 		for _, op := range []*openapi3.Operation{item.Get, item.Post, item.Put, item.Patch, item.Delete, item.Options, item.Head, item.Trace} {
@@ -95,23 +217,37 @@ func collectTags(doc *openapi3.T) []string {
 
 // compileTagFilters compiles regex patterns for tag filtering
 func compileTagFilters(include, exclude []string) ([]*regexp.Regexp, []*regexp.Regexp, error) {
-	inc := make([]*regexp.Regexp, 0, len(include))
-	for _, p := range include {
-		r, err := regexp.Compile(p)
-		if err != nil {
-			return nil, nil, fmt.Errorf("invalid includeTags pattern %q: %w", p, err)
-		}
-		inc = append(inc, r)
+	inc, err := compilePatterns("includeTags", include)
+	if err != nil {
+		return nil, nil, err
 	}
-	exc := make([]*regexp.Regexp, 0, len(exclude))
-	for _, p := range exclude {
+	exc, err := compilePatterns("excludeTags", exclude)
+	if err != nil {
+		return nil, nil, err
+	}
+	return inc, exc, nil
+}
+
+// compilePatterns compiles the regex patterns of the named config option
+func compilePatterns(option string, patterns []string) ([]*regexp.Regexp, error) {
+	out := make([]*regexp.Regexp, 0, len(patterns))
+	for _, p := range patterns {
 		r, err := regexp.Compile(p)
 		if err != nil {
-			return nil, nil, fmt.Errorf("invalid excludeTags pattern %q: %w", p, err)
+			return nil, fmt.Errorf("invalid %s pattern %q: %w", option, p, err)
 		}
-		exc = append(exc, r)
+		out = append(out, r)
 	}
-	return inc, exc, nil
+	return out, nil
+}
+
+// operationFilterKey is what includeOperations and excludeOperations patterns match: the
+// operationId, or "METHOD /path" for operations without one
+func operationFilterKey(op ir.IROperation) string {
+	if op.OperationID != "" {
+		return op.OperationID
+	}
+	return op.Method + " " + op.Path
 }
 
 // shouldIncludeOperation determines if an operation should be included based on its original tags
@@ -189,41 +325,106 @@ func filterTags(all []string, include, exclude []*regexp.Regexp) map[string]bool
 	return allowed
 }
 
+// untaggedOperations lists the operations of the document without tags, sorted
+func untaggedOperations(doc *openapi3.T) []string {
+	var out []string
+	for path, item := range doc.Paths.Map() {
+		for method, op := range item.Operations() {
+			if len(op.Tags) == 0 && !sdkIgnored(op.Extensions) {
+				out = append(out, method+" "+path)
+			}
+		}
+	}
+	sort.Strings(out)
+	return out
+}
+
+// operationServers returns the URLs of the servers an operation overrides the global servers
+// with: its own, else those of its path item. Variables are replaced by their defaults.
+func operationServers(item *openapi3.PathItem, op *openapi3.Operation) []string {
+	servers := item.Servers
+	if op.Servers != nil && len(*op.Servers) > 0 {
+		servers = *op.Servers
+	}
+	var out []string
+	for _, server := range servers {
+		if server == nil || server.URL == "" {
+			continue
+		}
+		out = append(out, serverURL(server))
+	}
+	return out
+}
+
+// collectServers returns the servers declared at the top of the document
+func collectServers(doc *openapi3.T) []ir.IRServer {
+	var out []ir.IRServer
+	for _, server := range doc.Servers {
+		if server == nil || server.URL == "" {
+			continue
+		}
+		out = append(out, ir.IRServer{URL: serverURL(server), Description: server.Description})
+	}
+	return out
+}
+
+// serverURL returns the URL of a server with its variables set to their defaults and without a
+// trailing slash
+func serverURL(server *openapi3.Server) string {
+	u := server.URL
+	for name, v := range server.Variables {
+		if v != nil {
+			u = strings.ReplaceAll(u, "{"+name+"}", v.Default)
+		}
+	}
+	return strings.TrimSuffix(u, "/")
+}
+
 // buildIRFromDoc builds IR structures from OpenAPI document
-func buildIRFromDoc(doc *openapi3.T, allowed map[string]bool) ir.IR {
+func buildIRFromDoc(doc *openapi3.T, allowed map[string]bool, opts BuildIROptions) ir.IR {
 	servicesMap := map[string]*ir.IRService{}
-	// Always prepare misc
-	servicesMap["misc"] = &ir.IRService{Tag: "misc"}
+	// Always prepare the untagged bucket
+	if opts.UntaggedBehavior == config.UntaggedBucket {
+		servicesMap[opts.UntaggedTag] = &ir.IRService{Tag: opts.UntaggedTag}
+	}
 
-	addOp := func(tag string, op *openapi3.Operation, method, path string) {
+	addOp := func(tag string, op *openapi3.Operation, item *openapi3.PathItem, method, path string) {
 		if _, ok := servicesMap[tag]; !ok {
 			servicesMap[tag] = &ir.IRService{Tag: tag}
 		}
 		id := op.OperationID
 		pathParams, queryParams := collectParams(doc, op)
-		reqBody := extractRequestBody(doc, op)
+		reqContents := extractRequestContents(doc, op)
+		var reqBody *ir.IRRequestBody
+		if len(reqContents) > 0 {
+			reqBody = &reqContents[0]
+		}
 		resp := extractResponse(doc, op)
+		errResps := extractErrorResponses(doc, op)
 
-		// Copy original tags, defaulting to ["misc"] if no tags
+		// Copy original tags, defaulting to the untagged bucket if no tags
 		originalTags := make([]string, len(op.Tags))
 		copy(originalTags, op.Tags)
 		if len(originalTags) == 0 {
-			originalTags = []string{"misc"}
+			originalTags = []string{opts.UntaggedTag}
 		}
 
 		servicesMap[tag].Operations = append(servicesMap[tag].Operations, ir.IROperation{
-			OperationID:  id,
-			Method:       method,
-			Path:         path,
-			Tag:          tag,
-			OriginalTags: originalTags,
-			Summary:      op.Summary,
-			Description:  op.Description,
-			Deprecated:   op.Deprecated,
-			PathParams:   pathParams,
-			QueryParams:  queryParams,
-			RequestBody:  reqBody,
-			Response:     resp,
+			OperationID:     id,
+			Method:          method,
+			Path:            path,
+			Tag:             tag,
+			OriginalTags:    originalTags,
+			Summary:         op.Summary,
+			Description:     op.Description,
+			Deprecated:      op.Deprecated,
+			PathParams:      pathParams,
+			QueryParams:     queryParams,
+			RequestBody:     reqBody,
+			RequestContents: reqContents,
+			Response:        resp,
+			ErrorResponses:  errResps,
+			Servers:         operationServers(item, op),
 		})
 	}
 
@@ -235,17 +436,17 @@ func buildIRFromDoc(doc *openapi3.T, allowed map[string]bool) ir.IR {
 		methods := []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS", "HEAD", "TRACE"}
 
 		for i, op := range operations {
-			if op == nil {
+			if op == nil || sdkIgnored(op.Extensions) {
 				continue
 			}
 			t := firstAllowedTag(op.Tags, allowed)
 			if t == "" {
-				if len(op.Tags) == 0 && allowed["misc"] {
-					t = "misc"
+				if len(op.Tags) == 0 && opts.UntaggedBehavior == config.UntaggedBucket && allowed[opts.UntaggedTag] {
+					t = opts.UntaggedTag
 				}
 			}
 			if t != "" {
-				addOp(t, op, methods[i], path)
+				addOp(t, op, item, methods[i], path)
 			}
 		}
 	}
@@ -262,9 +463,54 @@ func buildIRFromDoc(doc *openapi3.T, allowed map[string]bool) ir.IR {
 		services = append(services, *s)
 	}
 	sort.Slice(services, func(i, j int) bool { return services[i].Tag < services[j].Tag })
+	if opts.PreserveSpecOrder {
+		applySpecOrder(doc, services, opts.OperationOrder)
+	}
 	return ir.IR{Services: services}
 }
 
+// applySpecOrder reorders alphabetically sorted services by the document's tags declaration
+// and their operations by order. Stable sorts keep unlisted entries alphabetical after the
+// listed ones.
+func applySpecOrder(doc *openapi3.T, services []ir.IRService, order []string) {
+	tagRank := make(map[string]int, len(doc.Tags))
+	for i, tag := range doc.Tags {
+		if tag == nil {
+			continue
+		}
+		if _, ok := tagRank[tag.Name]; !ok {
+			tagRank[tag.Name] = i
+		}
+	}
+	sort.SliceStable(services, func(i, j int) bool {
+		return specRank(tagRank, services[i].Tag) < specRank(tagRank, services[j].Tag)
+	})
+
+	if len(order) == 0 {
+		return
+	}
+	opRank := make(map[string]int, len(order))
+	for i, key := range order {
+		if _, ok := opRank[key]; !ok {
+			opRank[key] = i
+		}
+	}
+	for _, s := range services {
+		ops := s.Operations
+		sort.SliceStable(ops, func(i, j int) bool {
+			return specRank(opRank, openapi.OperationKey(ops[i].Method, ops[i].Path)) < specRank(opRank, openapi.OperationKey(ops[j].Method, ops[j].Path))
+		})
+	}
+}
+
+// specRank returns the position of key in ranks, placing unlisted keys last
+func specRank(ranks map[string]int, key string) int {
+	if rank, ok := ranks[key]; ok {
+		return rank
+	}
+	return math.MaxInt
+}
+
 // firstAllowedTag returns the first allowed tag from a list
 func firstAllowedTag(tags []string, allowed map[string]bool) string {
 	for _, t := range tags {
@@ -318,14 +564,31 @@ func collectParams(doc *openapi3.T, op *openapi3.Operation) (pathParams, queryPa
 			continue
 		}
 		p := pr.Value
+		schema, contentType := p.Schema, ""
+		// A parameter declared with content (e.g. a JSON-encoded filter) takes its schema
+		// from the single media type instead
+		if schema == nil && len(p.Content) > 0 {
+			contentType = slices.Sorted(maps.Keys(p.Content))[0]
+			if media := p.Content[contentType]; media != nil {
+				schema = media.Schema
+			}
+		}
 		param := ir.IRParam{
 			Name:        p.Name,
 			Required:    p.Required,
-			Schema:      schemaRefToIR(doc, p.Schema),
+			Schema:      schemaRefToIR(doc, schema),
 			Description: p.Description,
+			ContentType: contentType,
+			Examples:    paramExamples(p, schema),
+			Deprecated:  p.Deprecated,
+		}
+		if schema != nil && schema.Value != nil {
+			param.Default = schema.Value.Default
 		}
 		switch p.In {
 		case openapi3.ParameterInPath:
+			param.Style = p.Style
+			param.Explode = p.Explode != nil && *p.Explode
 			pathParams = append(pathParams, param)
 		case openapi3.ParameterInQuery:
 			queryParams = append(queryParams, param)
@@ -337,52 +600,60 @@ func collectParams(doc *openapi3.T, op *openapi3.Operation) (pathParams, queryPa
 	return
 }
 
-// extractRequestBody extracts request body information
-func extractRequestBody(doc *openapi3.T, op *openapi3.Operation) *ir.IRRequestBody {
+// extractRequestContents models every media type of the request body, ordered by preference:
+// application/json, form-urlencoded, multipart/form-data, then the rest by name
+func extractRequestContents(doc *openapi3.T, op *openapi3.Operation) []ir.IRRequestBody {
 	if op.RequestBody == nil || op.RequestBody.Value == nil {
 		return nil
 	}
 	rb := op.RequestBody.Value
-	// Prefer application/json
-	if media, ok := rb.Content["application/json"]; ok {
-		return &ir.IRRequestBody{
-			ContentType: "application/json",
-			TypeTS:      "",
-			Schema:      schemaRefToIR(doc, media.Schema),
-			Required:    rb.Required,
-		}
-	}
-	if media, ok := rb.Content["application/x-www-form-urlencoded"]; ok {
-		return &ir.IRRequestBody{
-			ContentType: "application/x-www-form-urlencoded",
-			TypeTS:      "",
-			Schema:      schemaRefToIR(doc, media.Schema),
-			Required:    rb.Required,
+	preferred := []string{"application/json", ir.ContentTypeFormURLEncoded, "multipart/form-data"}
+	var rest []string
+	for ct := range rb.Content {
+		if !slices.Contains(preferred, ct) {
+			rest = append(rest, ct)
 		}
 	}
-	if _, ok := rb.Content["multipart/form-data"]; ok {
-		return &ir.IRRequestBody{
-			ContentType: "multipart/form-data",
-			TypeTS:      "",
-			Schema:      ir.IRSchema{Kind: ir.IRKindUnknown},
-			Required:    rb.Required,
+	sort.Strings(rest)
+
+	var out []ir.IRRequestBody
+	for _, ct := range append(preferred, rest...) {
+		media, ok := rb.Content[ct]
+		if !ok || media == nil {
+			continue
 		}
-	}
-	// Fallback to the first available media type
-	for ct, media := range rb.Content {
-		return &ir.IRRequestBody{
+		body := ir.IRRequestBody{
 			ContentType: ct,
 			TypeTS:      "",
 			Schema:      schemaRefToIR(doc, media.Schema),
 			Required:    rb.Required,
+			Examples:    mediaExamples(media),
 		}
+		if ct == "multipart/form-data" {
+			body.Schema = ir.IRSchema{Kind: ir.IRKindUnknown}
+		}
+		out = append(out, body)
+	}
+	return out
+}
+
+// firstMediaType returns the media type with the lowest content type name, so fallbacks do not
+// depend on map iteration order
+func firstMediaType(content openapi3.Content) (string, *openapi3.MediaType) {
+	if len(content) == 0 {
+		return "", nil
+	}
+	types := make([]string, 0, len(content))
+	for ct := range content {
+		types = append(types, ct)
 	}
-	return nil
+	sort.Strings(types)
+	return types[0], content[types[0]]
 }
 
 // extractResponse extracts response information
 func extractResponse(doc *openapi3.T, op *openapi3.Operation) ir.IRResponse {
-	// Choose 200, 201, or any 2xx; 204 => void
+	// Choose 200, 201, or any 2xx, then default; 204 or no content => void
 	pick := func(code string) (*openapi3.ResponseRef, bool) {
 		if op.Responses == nil {
 			return nil, false
@@ -394,61 +665,203 @@ func extractResponse(doc *openapi3.T, op *openapi3.Operation) ir.IRResponse {
 	try := []string{"200", "201"}
 	for _, code := range try {
 		if rr, ok := pick(code); ok && rr != nil && rr.Value != nil {
-			if media, ok := rr.Value.Content["application/json"]; ok {
-				desc := ""
-				if rr.Value.Description != nil {
-					desc = *rr.Value.Description
-				}
-				return ir.IRResponse{TypeTS: "", Schema: schemaRefToIR(doc, media.Schema), Description: desc}
-			}
-			// Fallback to any content
-			for _, media := range rr.Value.Content {
-				desc := ""
-				if rr.Value.Description != nil {
-					desc = *rr.Value.Description
-				}
-				return ir.IRResponse{TypeTS: "", Schema: schemaRefToIR(doc, media.Schema), Description: desc}
-			}
-			desc := ""
-			if rr.Value.Description != nil {
-				desc = *rr.Value.Description
-			}
-			return ir.IRResponse{TypeTS: "void", Description: desc}
+			return responseFor(doc, code, rr)
 		}
 	}
-	// any 2xx
+	// any 2xx, lowest code first, preferring one with content
 	if op.Responses != nil {
-		for code, rr := range op.Responses.Map() {
+		m := op.Responses.Map()
+		codes := make([]string, 0, len(m))
+		for code := range m {
+			codes = append(codes, code)
+		}
+		sort.Strings(codes)
+		var empty string
+		for _, code := range codes {
+			rr := m[code]
 			if len(code) == 3 && code[0] == '2' {
 				if rr != nil && rr.Value != nil {
 					if code == "204" {
-						desc := ""
-						if rr.Value.Description != nil {
-							desc = *rr.Value.Description
-						}
-						return ir.IRResponse{TypeTS: "void", Description: desc}
+						return withResponseHeaders(doc, rr, ir.IRResponse{TypeTS: "void", StatusCode: code, Description: responseDescription(rr)})
 					}
-					if media, ok := rr.Value.Content["application/json"]; ok {
-						desc := ""
-						if rr.Value.Description != nil {
-							desc = *rr.Value.Description
-						}
-						return ir.IRResponse{TypeTS: "", Schema: schemaRefToIR(doc, media.Schema), Description: desc}
+					if len(rr.Value.Content) > 0 {
+						return responseFor(doc, code, rr)
 					}
-					for _, media := range rr.Value.Content {
-						desc := ""
-						if rr.Value.Description != nil {
-							desc = *rr.Value.Description
-						}
-						return ir.IRResponse{TypeTS: "", Schema: schemaRefToIR(doc, media.Schema), Description: desc}
+					if empty == "" {
+						empty = code
 					}
 				}
 			}
 		}
+		// A 2xx without content, e.g. 202 Accepted, is void
+		if empty != "" {
+			return responseFor(doc, empty, m[empty])
+		}
+		// Without any 2xx the default response describes successes too
+		if rr := op.Responses.Default(); rr != nil && rr.Value != nil {
+			return responseFor(doc, "default", rr)
+		}
 	}
 	return ir.IRResponse{TypeTS: "unknown"}
 }
 
+// responseFor builds the IRResponse for the chosen status code, preferring application/json
+// and falling back to the first media type; a response without content is void. Contents
+// lists the preferred media type, then the others by name.
+func responseFor(doc *openapi3.T, code string, rr *openapi3.ResponseRef) ir.IRResponse {
+	resp := ir.IRResponse{StatusCode: code, Description: responseDescription(rr)}
+	ct, media := "application/json", rr.Value.Content["application/json"]
+	if media == nil {
+		ct, media = firstMediaType(rr.Value.Content)
+	}
+	if media == nil {
+		resp.TypeTS = "void"
+		return withResponseHeaders(doc, rr, resp)
+	}
+	resp.ContentType = ct
+	resp.Stream = isStreamContentType(ct)
+	resp.Schema = schemaRefToIR(doc, media.Schema)
+	resp.Examples = mediaExamples(media)
+	resp.Contents = []ir.IRResponseContent{{ContentType: ct, Schema: resp.Schema, Stream: resp.Stream}}
+	for _, other := range slices.Sorted(maps.Keys(rr.Value.Content)) {
+		if other == ct || rr.Value.Content[other] == nil {
+			continue
+		}
+		resp.Contents = append(resp.Contents, ir.IRResponseContent{
+			ContentType: other,
+			Schema:      schemaRefToIR(doc, rr.Value.Content[other].Schema),
+			Stream:      isStreamContentType(other),
+		})
+	}
+	return withResponseHeaders(doc, rr, resp)
+}
+
+// isStreamContentType reports whether responses of a media type are streamed to the caller
+// rather than read into memory
+func isStreamContentType(ct string) bool {
+	mediaType, _, _ := strings.Cut(strings.ToLower(ct), ";")
+	switch strings.TrimSpace(mediaType) {
+	case "application/octet-stream", "text/event-stream":
+		return true
+	}
+	return false
+}
+
+func responseDescription(rr *openapi3.ResponseRef) string {
+	if rr.Value.Description != nil {
+		return *rr.Value.Description
+	}
+	return ""
+}
+
+// extractErrorResponses collects the 4xx, 5xx and default responses of an operation.
+// Ranges are normalized to upper case ("4xx" -> "4XX"), and sorting the codes as strings
+// puts exact codes before ranges and default last.
+func extractErrorResponses(doc *openapi3.T, op *openapi3.Operation) []ir.IRErrorResponse {
+	if op.Responses == nil {
+		return nil
+	}
+	var out []ir.IRErrorResponse
+	for code, rr := range op.Responses.Map() {
+		if rr == nil || rr.Value == nil {
+			continue
+		}
+		code = strings.ToUpper(code)
+		if code == "DEFAULT" {
+			code = "default"
+		} else if len(code) != 3 || (code[0] != '4' && code[0] != '5') {
+			continue
+		}
+		er := ir.IRErrorResponse{StatusCode: code, Schema: ir.IRSchema{Kind: ir.IRKindUnknown}}
+		if rr.Value.Description != nil {
+			er.Description = *rr.Value.Description
+		}
+		if media, ok := rr.Value.Content["application/json"]; ok && media.Schema != nil {
+			er.Schema = schemaRefToIR(doc, media.Schema)
+		}
+		out = append(out, er)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].StatusCode < out[j].StatusCode })
+	return out
+}
+
+// mediaExamples collects the examples of a media type: its example, then its named examples
+// sorted by name, falling back to the schema examples when the media type declares none
+func mediaExamples(media *openapi3.MediaType) []any {
+	if media == nil {
+		return nil
+	}
+	var out []any
+	if media.Example != nil {
+		out = append(out, media.Example)
+	}
+	names := make([]string, 0, len(media.Examples))
+	for name := range media.Examples {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if ex := media.Examples[name]; ex != nil && ex.Value != nil && ex.Value.Value != nil {
+			out = append(out, ex.Value.Value)
+		}
+	}
+	if len(out) == 0 && media.Schema != nil {
+		out = schemaExamples(media.Schema.Value)
+	}
+	return out
+}
+
+// paramExamples collects the examples of a parameter like mediaExamples does for media types:
+// its example, then its named examples sorted by name, falling back to the schema examples
+func paramExamples(p *openapi3.Parameter, schema *openapi3.SchemaRef) []any {
+	var out []any
+	if p.Example != nil {
+		out = append(out, p.Example)
+	}
+	for _, name := range slices.Sorted(maps.Keys(p.Examples)) {
+		if ex := p.Examples[name]; ex != nil && ex.Value != nil && ex.Value.Value != nil {
+			out = append(out, ex.Value.Value)
+		}
+	}
+	if len(out) == 0 && schema != nil {
+		out = schemaExamples(schema.Value)
+	}
+	return out
+}
+
+// withResponseHeaders attaches the headers declared on rr to the response
+func withResponseHeaders(doc *openapi3.T, rr *openapi3.ResponseRef, resp ir.IRResponse) ir.IRResponse {
+	resp.Headers = collectResponseHeaders(doc, rr.Value)
+	return resp
+}
+
+// collectResponseHeaders extracts declared response headers in deterministic order
+func collectResponseHeaders(doc *openapi3.T, r *openapi3.Response) []ir.IRResponseHeader {
+	if r == nil || len(r.Headers) == 0 {
+		return nil
+	}
+	names := make([]string, 0, len(r.Headers))
+	for name := range r.Headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	out := make([]ir.IRResponseHeader, 0, len(names))
+	for _, name := range names {
+		hr := r.Headers[name]
+		if hr == nil || hr.Value == nil {
+			continue
+		}
+		h := hr.Value
+		out = append(out, ir.IRResponseHeader{
+			Name:        name,
+			Schema:      schemaRefToIR(doc, h.Schema),
+			Description: h.Description,
+			Required:    h.Required,
+		})
+	}
+	return out
+}
+
 // buildStructuredModels converts components.schemas into a language-agnostic IR
 func buildStructuredModels(doc *openapi3.T) []ir.IRModelDef {
 	out := []ir.IRModelDef{}
@@ -469,6 +882,9 @@ func buildStructuredModels(doc *openapi3.T) []ir.IRModelDef {
 
 	for _, name := range names {
 		sr := doc.Components.Schemas[name]
+		if sr != nil && sr.Value != nil && sdkIgnored(sr.Value.Extensions) {
+			continue
+		}
 		// For component schemas, use schemaRefToIR to get the actual schema without creating inline models
 		schema := schemaRefToIR(doc, sr)
 		out = append(out, ir.IRModelDef{
@@ -480,7 +896,7 @@ func buildStructuredModels(doc *openapi3.T) []ir.IRModelDef {
 	return out
 }
 
-// filterUnusedModelDefs removes ModelDefs that are not referenced by any operations
+// filterUnusedModelDefs removes ModelDefs that are not referenced by any operations or webhooks
 func filterUnusedModelDefs(filteredIR ir.IR, allModelDefs []ir.IRModelDef) []ir.IRModelDef {
 	// Build a map of all ModelDefs for quick lookup
 	modelDefMap := make(map[string]ir.IRModelDef)
@@ -488,72 +904,23 @@ func filterUnusedModelDefs(filteredIR ir.IR, allModelDefs []ir.IRModelDef) []ir.
 		modelDefMap[md.Name] = md
 	}
 
-	// Collect all schema references from filtered operations
+	// Collect the refs of operations and webhooks, then those of the models they reach
 	referenced := make(map[string]bool)
-	visited := make(map[string]bool) // Track visited refs to avoid cycles
-
-	// Helper function to collect references from a schema recursively
-	var collectRefs func(schema ir.IRSchema)
-	collectRefs = func(schema ir.IRSchema) {
-		if schema.Kind == ir.IRKindRef && schema.Ref != "" {
-			refName := schema.Ref
-			referenced[refName] = true
-			// If this ref points to a ModelDef and we haven't visited it, collect its transitive references
-			if !visited[refName] {
-				visited[refName] = true
-				if md, ok := modelDefMap[refName]; ok {
-					collectRefs(md.Schema)
-				}
-			}
-		}
-		if schema.Items != nil {
-			collectRefs(*schema.Items)
-		}
-		if schema.AdditionalProperties != nil {
-			collectRefs(*schema.AdditionalProperties)
-		}
-		for _, sub := range schema.OneOf {
-			if sub != nil {
-				collectRefs(*sub)
-			}
-		}
-		for _, sub := range schema.AnyOf {
-			if sub != nil {
-				collectRefs(*sub)
-			}
-		}
-		for _, sub := range schema.AllOf {
-			if sub != nil {
-				collectRefs(*sub)
-			}
-		}
-		if schema.Not != nil {
-			collectRefs(*schema.Not)
-		}
-		for _, field := range schema.Properties {
-			if field.Type != nil {
-				collectRefs(*field.Type)
-			}
-		}
-	}
-
-	// Collect references from all operations
-	for _, service := range filteredIR.Services {
-		for _, op := range service.Operations {
-			// Collect from path params
-			for _, param := range op.PathParams {
-				collectRefs(param.Schema)
-			}
-			// Collect from query params
-			for _, param := range op.QueryParams {
-				collectRefs(param.Schema)
-			}
-			// Collect from request body
-			if op.RequestBody != nil {
-				collectRefs(op.RequestBody.Schema)
-			}
-			// Collect from response
-			collectRefs(op.Response.Schema)
+	var pending []string
+	collectRef := func(schema ir.IRSchema) {
+		if schema.Kind == ir.IRKindRef && schema.Ref != "" && !referenced[schema.Ref] {
+			referenced[schema.Ref] = true
+			pending = append(pending, schema.Ref)
+		}
+	}
+	roots := filteredIR
+	roots.ModelDefs = nil
+	roots.WalkSchemas(collectRef)
+	for len(pending) > 0 {
+		name := pending[len(pending)-1]
+		pending = pending[:len(pending)-1]
+		if md, ok := modelDefMap[name]; ok {
+			ir.IR{ModelDefs: []ir.IRModelDef{md}}.WalkSchemas(collectRef)
 		}
 	}
 
@@ -0,0 +1,710 @@
+package generator
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/blimu-dev/sdk-gen/pkg/config"
+	"github.com/blimu-dev/sdk-gen/pkg/ir"
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+func TestCollectParamsDefault(t *testing.T) {
+	op := &openapi3.Operation{
+		Parameters: openapi3.Parameters{
+			{Value: &openapi3.Parameter{
+				Name:   "limit",
+				In:     openapi3.ParameterInQuery,
+				Schema: &openapi3.SchemaRef{Value: &openapi3.Schema{Type: &openapi3.Types{openapi3.TypeInteger}, Default: float64(20)}},
+			}},
+			{Value: &openapi3.Parameter{
+				Name:   "cursor",
+				In:     openapi3.ParameterInQuery,
+				Schema: &openapi3.SchemaRef{Value: &openapi3.Schema{Type: &openapi3.Types{openapi3.TypeString}}},
+			}},
+		},
+	}
+
+	_, query := collectParams(&openapi3.T{}, op)
+	if len(query) != 2 {
+		t.Fatalf("expected 2 query params, got %d", len(query))
+	}
+	// Params are sorted by name: cursor, limit
+	if query[0].Default != nil {
+		t.Errorf("cursor default = %v, expected nil", query[0].Default)
+	}
+	if query[1].Default != float64(20) {
+		t.Errorf("limit default = %v, expected 20", query[1].Default)
+	}
+}
+
+func TestCollectParamsDeprecated(t *testing.T) {
+	str := &openapi3.SchemaRef{Value: &openapi3.Schema{Type: &openapi3.Types{openapi3.TypeString}}}
+	op := &openapi3.Operation{
+		Parameters: openapi3.Parameters{
+			{Value: &openapi3.Parameter{Name: "id", In: openapi3.ParameterInPath, Required: true, Schema: str}},
+			{Value: &openapi3.Parameter{Name: "page", In: openapi3.ParameterInQuery, Deprecated: true, Schema: str}},
+			{Value: &openapi3.Parameter{Name: "cursor", In: openapi3.ParameterInQuery, Schema: str}},
+		},
+	}
+
+	path, query := collectParams(&openapi3.T{}, op)
+	if path[0].Deprecated || query[0].Deprecated || !query[1].Deprecated {
+		t.Errorf("unexpected deprecation flags: %+v %+v", path, query)
+	}
+	deprecated := ir.IROperation{PathParams: path, QueryParams: query}.DeprecatedParams()
+	if len(deprecated) != 1 || deprecated[0].Name != "page" {
+		t.Errorf("DeprecatedParams() = %+v, expected page", deprecated)
+	}
+}
+
+func TestBuildIR(t *testing.T) {
+	doc := &openapi3.T{
+		Paths: openapi3.NewPaths(
+			openapi3.WithPath("/users", &openapi3.PathItem{
+				Get: &openapi3.Operation{OperationID: "listUsers", Tags: []string{"users"}},
+			}),
+			openapi3.WithPath("/health", &openapi3.PathItem{
+				Get: &openapi3.Operation{OperationID: "health"},
+			}),
+		),
+		Components: &openapi3.Components{
+			Schemas: openapi3.Schemas{
+				"User": &openapi3.SchemaRef{Value: &openapi3.Schema{Type: &openapi3.Types{openapi3.TypeObject}}},
+			},
+			SecuritySchemes: openapi3.SecuritySchemes{
+				"bearer": &openapi3.SecuritySchemeRef{Value: &openapi3.SecurityScheme{Type: "http", Scheme: "bearer"}},
+			},
+		},
+	}
+
+	result, err := BuildIR(doc)
+	if err != nil {
+		t.Fatalf("BuildIR: %v", err)
+	}
+	ops := map[string]string{}
+	for _, svc := range result.Services {
+		for _, op := range svc.Operations {
+			ops[op.OperationID] = svc.Tag
+		}
+	}
+	if ops["listUsers"] != "users" || ops["health"] != "misc" {
+		t.Errorf("unexpected operation tags: %v", ops)
+	}
+	if len(result.ModelDefs) != 1 || result.ModelDefs[0].Name != "User" {
+		t.Errorf("expected User model def, got %+v", result.ModelDefs)
+	}
+	if len(result.SecuritySchemes) != 1 || result.SecuritySchemes[0].Scheme != "bearer" {
+		t.Errorf("expected bearer security scheme, got %+v", result.SecuritySchemes)
+	}
+}
+
+func TestBuildIRPreserveSpecOrder(t *testing.T) {
+	doc := &openapi3.T{
+		Tags: openapi3.Tags{{Name: "users"}, {Name: "admin"}},
+		Paths: openapi3.NewPaths(
+			openapi3.WithPath("/users", &openapi3.PathItem{
+				Get:  &openapi3.Operation{OperationID: "listUsers", Tags: []string{"users"}},
+				Post: &openapi3.Operation{OperationID: "createUser", Tags: []string{"users"}},
+			}),
+			openapi3.WithPath("/users/{id}", &openapi3.PathItem{
+				Get: &openapi3.Operation{OperationID: "getUser", Tags: []string{"users"}},
+			}),
+			openapi3.WithPath("/admin", &openapi3.PathItem{
+				Get: &openapi3.Operation{OperationID: "admin", Tags: []string{"admin"}},
+			}),
+			openapi3.WithPath("/billing", &openapi3.PathItem{
+				Get: &openapi3.Operation{OperationID: "billing", Tags: []string{"billing"}},
+			}),
+			openapi3.WithPath("/health", &openapi3.PathItem{
+				Get: &openapi3.Operation{OperationID: "health"},
+			}),
+		),
+	}
+	summary := func(in ir.IR) string {
+		var parts []string
+		for _, svc := range in.Services {
+			var ids []string
+			for _, op := range svc.Operations {
+				ids = append(ids, op.OperationID)
+			}
+			parts = append(parts, svc.Tag+"["+strings.Join(ids, " ")+"]")
+		}
+		return strings.Join(parts, " ")
+	}
+
+	tests := []struct {
+		name     string
+		opts     BuildIROptions
+		expected string
+	}{
+		{"alphabetical", BuildIROptions{}, "admin[admin] billing[billing] misc[health] users[listUsers createUser getUser]"},
+		{"tags only", BuildIROptions{PreserveSpecOrder: true}, "users[listUsers createUser getUser] admin[admin] billing[billing] misc[health]"},
+		{
+			"source order",
+			BuildIROptions{PreserveSpecOrder: true, OperationOrder: []string{"GET /users/{id}", "POST /users"}},
+			"users[getUser createUser listUsers] admin[admin] billing[billing] misc[health]",
+		},
+	}
+	for _, test := range tests {
+		in, err := BuildIRWithOptions(doc, test.opts)
+		if err != nil {
+			t.Fatalf("%s: %v", test.name, err)
+		}
+		if got := summary(in); got != test.expected {
+			t.Errorf("%s: services = %s, expected %s", test.name, got, test.expected)
+		}
+	}
+}
+
+func TestExtractResponseHeaders(t *testing.T) {
+	desc := "ok"
+	resp := &openapi3.Response{
+		Description: &desc,
+		Headers: openapi3.Headers{
+			"X-Total-Count": &openapi3.HeaderRef{Value: &openapi3.Header{Parameter: openapi3.Parameter{
+				Description: "Total number of items",
+				Required:    true,
+				Schema:      &openapi3.SchemaRef{Value: &openapi3.Schema{Type: &openapi3.Types{openapi3.TypeInteger}}},
+			}}},
+			"X-RateLimit-Remaining": &openapi3.HeaderRef{Value: &openapi3.Header{Parameter: openapi3.Parameter{
+				Schema: &openapi3.SchemaRef{Value: &openapi3.Schema{Type: &openapi3.Types{openapi3.TypeInteger}}},
+			}}},
+		},
+	}
+	op := &openapi3.Operation{Responses: openapi3.NewResponses(openapi3.WithStatus(200, &openapi3.ResponseRef{Value: resp}))}
+
+	result := extractResponse(&openapi3.T{}, op)
+	if len(result.Headers) != 2 {
+		t.Fatalf("expected 2 headers, got %d", len(result.Headers))
+	}
+	// Headers are sorted by name
+	if result.Headers[0].Name != "X-RateLimit-Remaining" || result.Headers[1].Name != "X-Total-Count" {
+		t.Errorf("unexpected header order: %+v", result.Headers)
+	}
+	if result.Headers[1].Schema.Kind != "integer" || result.Headers[1].Description != "Total number of items" || !result.Headers[1].Required {
+		t.Errorf("unexpected X-Total-Count header: %+v", result.Headers[1])
+	}
+	if result.Headers[0].Required {
+		t.Errorf("expected X-RateLimit-Remaining to be optional: %+v", result.Headers[0])
+	}
+}
+
+func TestMediaExamples(t *testing.T) {
+	tests := []struct {
+		name     string
+		media    *openapi3.MediaType
+		expected []any
+	}{
+		{"none", &openapi3.MediaType{}, nil},
+		{"media example", &openapi3.MediaType{Example: "a"}, []any{"a"}},
+		{
+			"named examples sorted by name",
+			&openapi3.MediaType{Examples: openapi3.Examples{
+				"second": &openapi3.ExampleRef{Value: &openapi3.Example{Value: "b"}},
+				"first":  &openapi3.ExampleRef{Value: &openapi3.Example{Value: "a"}},
+			}},
+			[]any{"a", "b"},
+		},
+		{
+			"schema example fallback",
+			&openapi3.MediaType{Schema: &openapi3.SchemaRef{Value: &openapi3.Schema{Example: "s"}}},
+			[]any{"s"},
+		},
+		{
+			"media example wins over schema example",
+			&openapi3.MediaType{Example: "m", Schema: &openapi3.SchemaRef{Value: &openapi3.Schema{Example: "s"}}},
+			[]any{"m"},
+		},
+	}
+
+	for _, test := range tests {
+		result := mediaExamples(test.media)
+		if len(result) != len(test.expected) {
+			t.Errorf("%s: mediaExamples() = %v, expected %v", test.name, result, test.expected)
+			continue
+		}
+		for i := range result {
+			if result[i] != test.expected[i] {
+				t.Errorf("%s: mediaExamples() = %v, expected %v", test.name, result, test.expected)
+				break
+			}
+		}
+	}
+}
+
+func TestExtractErrorResponses(t *testing.T) {
+	desc := "error"
+	jsonResp := &openapi3.ResponseRef{Value: &openapi3.Response{
+		Description: &desc,
+		Content:     openapi3.NewContentWithJSONSchemaRef(&openapi3.SchemaRef{Ref: "#/components/schemas/Error", Value: &openapi3.Schema{}}),
+	}}
+	op := &openapi3.Operation{Responses: openapi3.NewResponses(
+		openapi3.WithStatus(200, &openapi3.ResponseRef{Value: &openapi3.Response{Description: &desc}}),
+		openapi3.WithStatus(404, jsonResp),
+	)}
+	op.Responses.Set("5xx", &openapi3.ResponseRef{Value: &openapi3.Response{Description: &desc}})
+	op.Responses.Set("default", jsonResp)
+
+	result := extractErrorResponses(&openapi3.T{}, op)
+	codes := make([]string, len(result))
+	for i, er := range result {
+		codes[i] = er.StatusCode
+	}
+	if len(codes) != 3 || codes[0] != "404" || codes[1] != "5XX" || codes[2] != "default" {
+		t.Fatalf("unexpected error responses: %v", codes)
+	}
+	if result[0].Schema.Kind != "ref" || result[0].Schema.Ref != "Error" || result[0].Description != "error" {
+		t.Errorf("unexpected 404 response: %+v", result[0])
+	}
+	if result[1].Schema.Kind != "unknown" {
+		t.Errorf("expected an untyped 5XX response, got %+v", result[1])
+	}
+}
+
+func TestExtractResponseStatusAndContentType(t *testing.T) {
+	desc := "ok"
+	jsonBody := openapi3.NewContentWithJSONSchemaRef(&openapi3.SchemaRef{Value: &openapi3.Schema{Type: &openapi3.Types{openapi3.TypeObject}}})
+	textBody := openapi3.NewContentWithSchemaRef(&openapi3.SchemaRef{Value: &openapi3.Schema{Type: &openapi3.Types{openapi3.TypeString}}}, []string{"text/plain"})
+	tests := []struct {
+		name        string
+		responses   *openapi3.Responses
+		statusCode  string
+		contentType string
+		typeTS      string
+	}{
+		{
+			"created json",
+			openapi3.NewResponses(openapi3.WithStatus(201, &openapi3.ResponseRef{Value: &openapi3.Response{Description: &desc, Content: jsonBody}})),
+			"201", "application/json", "",
+		},
+		{
+			"accepted text",
+			openapi3.NewResponses(openapi3.WithStatus(202, &openapi3.ResponseRef{Value: &openapi3.Response{Description: &desc, Content: textBody}})),
+			"202", "text/plain", "",
+		},
+		{
+			"no content",
+			openapi3.NewResponses(openapi3.WithStatus(204, &openapi3.ResponseRef{Value: &openapi3.Response{Description: &desc}})),
+			"204", "", "void",
+		},
+		{
+			"ok without body",
+			openapi3.NewResponses(openapi3.WithStatus(200, &openapi3.ResponseRef{Value: &openapi3.Response{Description: &desc}})),
+			"200", "", "void",
+		},
+		{
+			"accepted without body",
+			openapi3.NewResponses(openapi3.WithStatus(202, &openapi3.ResponseRef{Value: &openapi3.Response{Description: &desc}})),
+			"202", "", "void",
+		},
+		{
+			"default only",
+			openapi3.NewResponses(openapi3.WithName("default", &openapi3.Response{Description: &desc, Content: jsonBody})),
+			"default", "application/json", "",
+		},
+		{
+			"default without body",
+			openapi3.NewResponses(openapi3.WithName("default", &openapi3.Response{Description: &desc})),
+			"default", "", "void",
+		},
+		{
+			"no success response",
+			openapi3.NewResponses(openapi3.WithStatus(404, &openapi3.ResponseRef{Value: &openapi3.Response{Description: &desc, Content: jsonBody}})),
+			"", "", "unknown",
+		},
+	}
+
+	for _, test := range tests {
+		result := extractResponse(&openapi3.T{}, &openapi3.Operation{Responses: test.responses})
+		if result.StatusCode != test.statusCode || result.ContentType != test.contentType || result.TypeTS != test.typeTS {
+			t.Errorf("%s: got status %q, content type %q, type %q; expected %q, %q, %q",
+				test.name, result.StatusCode, result.ContentType, result.TypeTS, test.statusCode, test.contentType, test.typeTS)
+		}
+	}
+}
+
+func TestDefaultAndEmptyResponses(t *testing.T) {
+	doc, err := openapi3.NewLoader().LoadFromFile("testdata/default-responses.yaml")
+	if err != nil {
+		t.Fatalf("load fixture: %v", err)
+	}
+	result, err := BuildIR(doc)
+	if err != nil {
+		t.Fatalf("BuildIR: %v", err)
+	}
+
+	responses := map[string]ir.IRResponse{}
+	for _, svc := range result.Services {
+		for _, op := range svc.Operations {
+			responses[op.OperationID] = op.Response
+		}
+	}
+	if got := responses["getJob"]; got.StatusCode != "default" || got.Schema.Kind != ir.IRKindRef || got.Schema.Ref != "Job" {
+		t.Errorf("getJob: expected the default response to model Job, got %+v", got)
+	}
+	for _, id := range []string{"deleteJob", "cancelJob", "retryJob"} {
+		if got := responses[id]; got.TypeTS != "void" {
+			t.Errorf("%s: expected a void response, got %+v", id, got)
+		}
+	}
+}
+
+func TestExtractResponseStream(t *testing.T) {
+	desc := "ok"
+	binary := &openapi3.SchemaRef{Value: &openapi3.Schema{Type: &openapi3.Types{openapi3.TypeString}, Format: "binary"}}
+	tests := []struct {
+		contentType string
+		expected    bool
+	}{
+		{"application/octet-stream", true},
+		{"text/event-stream; charset=utf-8", true},
+		{"application/json", false},
+		{"text/plain", false},
+	}
+
+	for _, test := range tests {
+		content := openapi3.NewContentWithSchemaRef(binary, []string{test.contentType})
+		responses := openapi3.NewResponses(openapi3.WithStatus(200, &openapi3.ResponseRef{Value: &openapi3.Response{Description: &desc, Content: content}}))
+		result := extractResponse(&openapi3.T{}, &openapi3.Operation{Responses: responses})
+		if result.Stream != test.expected {
+			t.Errorf("%s: Stream = %v, expected %v", test.contentType, result.Stream, test.expected)
+		}
+	}
+}
+
+func TestExtractResponseContents(t *testing.T) {
+	desc := "ok"
+	object := &openapi3.SchemaRef{Value: &openapi3.Schema{Type: &openapi3.Types{openapi3.TypeObject}}}
+	str := &openapi3.SchemaRef{Value: &openapi3.Schema{Type: &openapi3.Types{openapi3.TypeString}}}
+	content := openapi3.Content{
+		"text/event-stream": &openapi3.MediaType{Schema: str},
+		"text/csv":          &openapi3.MediaType{Schema: str},
+		"application/json":  &openapi3.MediaType{Schema: object},
+	}
+	responses := openapi3.NewResponses(openapi3.WithStatus(200, &openapi3.ResponseRef{Value: &openapi3.Response{Description: &desc, Content: content}}))
+	op := ir.IROperation{Response: extractResponse(&openapi3.T{}, &openapi3.Operation{Responses: responses})}
+
+	if !op.Response.Negotiated() {
+		t.Fatalf("expected a negotiated response, got %+v", op.Response)
+	}
+	var types []string
+	for _, variant := range op.AcceptVariants() {
+		types = append(types, fmt.Sprintf("%s %s %v", variant.Response.ContentType, variant.Response.Schema.Kind, variant.Response.Stream))
+	}
+	expected := []string{"application/json object false", "text/csv string false", "text/event-stream string true"}
+	if strings.Join(types, ",") != strings.Join(expected, ",") {
+		t.Errorf("accept variants = %v, expected %v", types, expected)
+	}
+
+	single := openapi3.NewResponses(openapi3.WithStatus(200, &openapi3.ResponseRef{Value: &openapi3.Response{Description: &desc, Content: openapi3.NewContentWithJSONSchemaRef(object)}}))
+	op = ir.IROperation{Response: extractResponse(&openapi3.T{}, &openapi3.Operation{Responses: single})}
+	if op.Response.Negotiated() || len(op.AcceptVariants()) != 1 {
+		t.Errorf("expected a single media type not to be negotiated, got %+v", op.Response.Contents)
+	}
+}
+
+func TestExtractRequestContents(t *testing.T) {
+	object := &openapi3.SchemaRef{Value: &openapi3.Schema{Type: &openapi3.Types{openapi3.TypeObject}}}
+	binary := &openapi3.SchemaRef{Value: &openapi3.Schema{Type: &openapi3.Types{openapi3.TypeString}, Format: "binary"}}
+	op := &openapi3.Operation{RequestBody: &openapi3.RequestBodyRef{Value: &openapi3.RequestBody{
+		Required: true,
+		Content: openapi3.Content{
+			"image/png":           &openapi3.MediaType{Schema: binary},
+			"multipart/form-data": &openapi3.MediaType{Schema: object},
+			"application/json":    &openapi3.MediaType{Schema: object},
+			"application/pdf":     &openapi3.MediaType{Schema: binary},
+		},
+	}}}
+
+	result := extractRequestContents(&openapi3.T{}, op)
+	var types []string
+	for _, body := range result {
+		types = append(types, body.ContentType)
+	}
+	expected := []string{"application/json", "multipart/form-data", "application/pdf", "image/png"}
+	if strings.Join(types, ",") != strings.Join(expected, ",") {
+		t.Fatalf("content types = %v, expected %v", types, expected)
+	}
+	if result[0].Schema.Kind != "object" || !result[0].Required {
+		t.Errorf("unexpected json content: %+v", result[0])
+	}
+	if result[1].Schema.Kind != "unknown" {
+		t.Errorf("expected an untyped multipart content, got %+v", result[1])
+	}
+	if result[3].Schema.Format != "binary" {
+		t.Errorf("unexpected image content: %+v", result[3])
+	}
+
+	if result := extractRequestContents(&openapi3.T{}, &openapi3.Operation{}); result != nil {
+		t.Errorf("expected no contents without a request body, got %+v", result)
+	}
+}
+
+func TestFilterUnusedModelDefs(t *testing.T) {
+	ref := func(name string) ir.IRSchema { return ir.IRSchema{Kind: ir.IRKindRef, Ref: name} }
+	object := func(field string, typ ir.IRSchema) ir.IRSchema {
+		return ir.IRSchema{Kind: ir.IRKindObject, Properties: []ir.IRField{{Name: field, Type: &typ}}}
+	}
+	json := ir.IRRequestBody{ContentType: "application/json", Schema: ref("NewUser")}
+	op := ir.IROperation{
+		Method:      "POST",
+		Path:        "/users",
+		RequestBody: &json,
+		// The xml body is only reachable through the alternate content type
+		RequestContents: []ir.IRRequestBody{json, {ContentType: "application/xml", Schema: ref("XmlUser")}},
+		Response:        ir.IRResponse{Headers: []ir.IRResponseHeader{{Name: "X-Rate", Schema: ref("RateLimit")}}},
+	}
+	defs := []ir.IRModelDef{
+		{Name: "NewUser", Schema: object("name", ir.IRSchema{Kind: ir.IRKindString})},
+		{Name: "XmlUser", Schema: object("address", ref("Address"))},
+		{Name: "Address", Schema: object("city", ir.IRSchema{Kind: ir.IRKindString})},
+		{Name: "RateLimit", Schema: ir.IRSchema{Kind: ir.IRKindInteger}},
+		{Name: "Unused", Schema: object("user", ref("NewUser"))},
+	}
+
+	filtered := ir.IR{Services: []ir.IRService{{Tag: "users", Operations: []ir.IROperation{op}}}, ModelDefs: defs}
+	var names []string
+	for _, md := range filterUnusedModelDefs(filtered, defs) {
+		names = append(names, md.Name)
+	}
+	if expected := "NewUser,XmlUser,Address,RateLimit"; strings.Join(names, ",") != expected {
+		t.Errorf("kept models %v, expected %s", names, expected)
+	}
+}
+
+func TestOperationServers(t *testing.T) {
+	doc, err := openapi3.NewLoader().LoadFromFile("testdata/servers.yaml")
+	if err != nil {
+		t.Fatalf("load fixture: %v", err)
+	}
+	result, err := BuildIR(doc)
+	if err != nil {
+		t.Fatalf("BuildIR: %v", err)
+	}
+
+	servers := map[string][]string{}
+	for _, svc := range result.Services {
+		for _, op := range svc.Operations {
+			servers[op.OperationID] = op.Servers
+		}
+	}
+	expected := map[string][]string{
+		"listFiles":       nil,
+		"uploadFile":      {"https://eu.uploads.example.com", "https://uploads.example.com"},
+		"getArchivedFile": {"/archive"},
+	}
+	for id, want := range expected {
+		if got := servers[id]; strings.Join(got, ",") != strings.Join(want, ",") {
+			t.Errorf("%s: servers %v, expected %v", id, got, want)
+		}
+	}
+}
+
+func TestCollectServers(t *testing.T) {
+	doc := &openapi3.T{Servers: openapi3.Servers{
+		{URL: "https://{region}.example.com/", Description: "Production", Variables: map[string]*openapi3.ServerVariable{"region": {Default: "eu"}}},
+		{URL: ""},
+		{URL: "/v2"},
+	}}
+	expected := []ir.IRServer{{URL: "https://eu.example.com", Description: "Production"}, {URL: "/v2"}}
+	if got := collectServers(doc); !reflect.DeepEqual(got, expected) {
+		t.Errorf("collectServers() = %+v, expected %+v", got, expected)
+	}
+}
+
+func TestParameterContent(t *testing.T) {
+	doc, err := openapi3.NewLoader().LoadFromFile("testdata/param-content.yaml")
+	if err != nil {
+		t.Fatalf("load fixture: %v", err)
+	}
+	result, err := BuildIR(doc)
+	if err != nil {
+		t.Fatalf("BuildIR: %v", err)
+	}
+
+	var query []ir.IRParam
+	for _, svc := range result.Services {
+		for _, op := range svc.Operations {
+			if op.OperationID == "listUsers" {
+				query = op.QueryParams
+			}
+		}
+	}
+	if len(query) != 2 {
+		t.Fatalf("expected 2 query params, got %+v", query)
+	}
+	filter, limit := query[0], query[1]
+	if filter.Name != "filter" || !filter.IsJSON() || filter.Schema.Kind != ir.IRKindRef || filter.Schema.Ref != "UserFilter" {
+		t.Errorf("filter: got %+v, expected a JSON parameter referencing UserFilter", filter)
+	}
+	if limit.Name != "limit" || limit.IsJSON() || limit.Schema.Kind != ir.IRKindInteger {
+		t.Errorf("limit: got %+v, expected a plain integer parameter", limit)
+	}
+}
+
+func TestPathParameterStyles(t *testing.T) {
+	doc, err := openapi3.NewLoader().LoadFromFile("testdata/path-styles.yaml")
+	if err != nil {
+		t.Fatalf("load fixture: %v", err)
+	}
+	result, err := BuildIR(doc)
+	if err != nil {
+		t.Fatalf("BuildIR: %v", err)
+	}
+
+	var op ir.IROperation
+	for _, svc := range result.Services {
+		for _, o := range svc.Operations {
+			if o.OperationID == "getVariantSize" {
+				op = o
+			}
+		}
+	}
+	if len(op.PathParams) != 3 {
+		t.Fatalf("expected 3 path params, got %+v", op.PathParams)
+	}
+	expected := map[string]string{"id": ";id=", "color": ".", "size": ""}
+	for _, p := range op.PathParams {
+		if got := op.PathPrefix(p.Name); got != expected[p.Name] {
+			t.Errorf("%s (style %q): PathPrefix() = %q, expected %q", p.Name, p.Style, got, expected[p.Name])
+		}
+	}
+}
+
+func TestArrayPathParameters(t *testing.T) {
+	doc, err := openapi3.NewLoader().LoadFromFile("testdata/array-path-params.yaml")
+	if err != nil {
+		t.Fatalf("load fixture: %v", err)
+	}
+	result, err := BuildIR(doc)
+	if err != nil {
+		t.Fatalf("BuildIR: %v", err)
+	}
+	var op ir.IROperation
+	for _, svc := range result.Services {
+		for _, o := range svc.Operations {
+			if o.OperationID == "getItems" {
+				op = o
+			}
+		}
+	}
+	if len(op.PathParams) != 4 {
+		t.Fatalf("expected 4 path params, got %+v", op.PathParams)
+	}
+
+	// /items/1,2/colors/.red.blue/sizes/;sizes=s;sizes=m/tags/;tags=a,b
+	expected := map[string][2]string{
+		"ids":    {"", ","},
+		"colors": {".", "."},
+		"sizes":  {";sizes=", ";sizes="},
+		"tags":   {";tags=", ","},
+	}
+	for _, p := range op.PathParams {
+		if p.Schema.Kind != ir.IRKindArray {
+			t.Errorf("%s: expected an array schema, got %q", p.Name, p.Schema.Kind)
+		}
+		if got := [2]string{p.PathPrefix(), p.PathSeparator()}; got != expected[p.Name] {
+			t.Errorf("%s (style %q, explode %t): prefix and separator %q, expected %q", p.Name, p.Style, p.Explode, got, expected[p.Name])
+		}
+	}
+}
+
+func TestRequireRequestBodies(t *testing.T) {
+	body := func(required bool, content openapi3.Content) *openapi3.RequestBodyRef {
+		return &openapi3.RequestBodyRef{Value: &openapi3.RequestBody{Required: required, Content: content}}
+	}
+	jsonContent := openapi3.NewContentWithJSONSchema(openapi3.NewStringSchema())
+	doc := &openapi3.T{
+		Paths: openapi3.NewPaths(
+			openapi3.WithPath("/required", &openapi3.PathItem{
+				Post: &openapi3.Operation{OperationID: "required", RequestBody: body(true, jsonContent)},
+			}),
+			openapi3.WithPath("/optional", &openapi3.PathItem{
+				Post: &openapi3.Operation{OperationID: "optional", RequestBody: body(false, jsonContent)},
+			}),
+			openapi3.WithPath("/empty", &openapi3.PathItem{
+				Post: &openapi3.Operation{OperationID: "empty", RequestBody: body(false, nil)},
+			}),
+		),
+	}
+	fullIR, err := BuildIR(doc)
+	if err != nil {
+		t.Fatalf("BuildIR: %v", err)
+	}
+
+	required := func(in ir.IR) map[string]any {
+		out := map[string]any{}
+		for _, svc := range in.Services {
+			for _, op := range svc.Operations {
+				if op.RequestBody == nil {
+					out[op.OperationID] = nil
+				} else {
+					out[op.OperationID] = op.RequestBody.Required && op.RequestContents[0].Required
+				}
+			}
+		}
+		return out
+	}
+	tests := []struct {
+		client   config.Client
+		expected map[string]any
+	}{
+		{config.Client{}, map[string]any{"required": true, "optional": false, "empty": nil}},
+		{config.Client{RequireRequestBodies: true}, map[string]any{"required": true, "optional": true, "empty": nil}},
+	}
+	for _, test := range tests {
+		out, err := (&Service{}).filterIR(fullIR, test.client)
+		if err != nil {
+			t.Fatalf("filterIR: %v", err)
+		}
+		if got := required(out); !reflect.DeepEqual(got, test.expected) {
+			t.Errorf("requireRequestBodies %v: required bodies = %v, expected %v", test.client.RequireRequestBodies, got, test.expected)
+		}
+	}
+	if got := required(fullIR); got["optional"] != false {
+		t.Errorf("expected the full IR to be left unchanged, got %v", got)
+	}
+}
+
+// largeSpec returns a spec with n component schemas, each with nested inline objects, enums,
+// arrays and references to other schemas, and an operation per schema
+func largeSpec(tb testing.TB, n int) *openapi3.T {
+	tb.Helper()
+	var b strings.Builder
+	b.WriteString("openapi: 3.0.3\ninfo: {title: Large, version: '1'}\npaths:\n")
+	for i := 0; i < n; i++ {
+		fmt.Fprintf(&b, "  /things%d/{id}:\n    get:\n      operationId: getThing%d\n      tags: [tag%d]\n", i, i, i%20)
+		b.WriteString("      parameters:\n        - {name: id, in: path, required: true, schema: {type: string}}\n        - {name: limit, in: query, schema: {type: integer}}\n")
+		fmt.Fprintf(&b, "      responses:\n        '200':\n          description: ok\n          content:\n            application/json:\n              schema: {$ref: '#/components/schemas/Thing%d'}\n", i)
+	}
+	b.WriteString("components:\n  schemas:\n")
+	for i := 0; i < n; i++ {
+		fmt.Fprintf(&b, "    Thing%d:\n      type: object\n      description: Thing %d\n      required: [id, name]\n      properties:\n", i, i)
+		b.WriteString("        id: {type: string, format: uuid, readOnly: true}\n        name: {type: string, example: widget}\n")
+		b.WriteString("        status: {type: string, enum: [active, inactive, archived]}\n")
+		fmt.Fprintf(&b, "        next: {$ref: '#/components/schemas/Thing%d'}\n", (i+1)%n)
+		b.WriteString("        tags:\n          type: array\n          items: {type: object, properties: {key: {type: string}, value: {type: string}}}\n")
+		b.WriteString("        meta:\n          type: object\n          properties:\n            created: {type: string, format: date-time}\n            owner:\n              type: object\n              properties: {name: {type: string}, email: {type: string, format: email}}\n")
+		for j := 0; j < 20; j++ {
+			fmt.Fprintf(&b, "        field%d: {type: integer, description: Field %d, default: %d}\n", j, j, j)
+		}
+	}
+	doc, err := openapi3.NewLoader().LoadFromData([]byte(b.String()))
+	if err != nil {
+		tb.Fatal(err)
+	}
+	return doc
+}
+
+func BenchmarkBuildIR(b *testing.B) {
+	doc := largeSpec(b, 2000)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := BuildIR(doc); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
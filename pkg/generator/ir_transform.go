@@ -0,0 +1,164 @@
+package generator
+
+import "github.com/blimu-dev/sdk-gen/pkg/ir"
+
+// mapSchemas returns a copy of the IR with fn applied to every schema, nested ones first. The
+// input IR is shared between clients, so schemas are copied rather than modified in place.
+func mapSchemas(in ir.IR, fn func(ir.IRSchema) ir.IRSchema) ir.IR {
+	m := schemaMapper{fn: fn}
+
+	out := in
+	out.ModelDefs = make([]ir.IRModelDef, len(in.ModelDefs))
+	for i, md := range in.ModelDefs {
+		md.Schema = m.schema(md.Schema)
+		out.ModelDefs[i] = md
+	}
+
+	out.Services = make([]ir.IRService, len(in.Services))
+	for i, service := range in.Services {
+		ops := make([]ir.IROperation, len(service.Operations))
+		for j, op := range service.Operations {
+			op.PathParams = m.params(op.PathParams)
+			op.QueryParams = m.params(op.QueryParams)
+			if op.RequestBody != nil {
+				body := *op.RequestBody
+				body.Schema = m.schema(body.Schema)
+				op.RequestBody = &body
+			}
+			if len(op.RequestContents) > 0 {
+				contents := make([]ir.IRRequestBody, len(op.RequestContents))
+				for k, body := range op.RequestContents {
+					body.Schema = m.schema(body.Schema)
+					contents[k] = body
+				}
+				op.RequestContents = contents
+			}
+			op.Response.Schema = m.schema(op.Response.Schema)
+			if len(op.Response.Contents) > 0 {
+				contents := make([]ir.IRResponseContent, len(op.Response.Contents))
+				for k, content := range op.Response.Contents {
+					content.Schema = m.schema(content.Schema)
+					contents[k] = content
+				}
+				op.Response.Contents = contents
+			}
+			if len(op.Response.Headers) > 0 {
+				headers := make([]ir.IRResponseHeader, len(op.Response.Headers))
+				for k, h := range op.Response.Headers {
+					h.Schema = m.schema(h.Schema)
+					headers[k] = h
+				}
+				op.Response.Headers = headers
+			}
+			if len(op.ErrorResponses) > 0 {
+				errResps := make([]ir.IRErrorResponse, len(op.ErrorResponses))
+				for k, er := range op.ErrorResponses {
+					er.Schema = m.schema(er.Schema)
+					errResps[k] = er
+				}
+				op.ErrorResponses = errResps
+			}
+			ops[j] = op
+		}
+		service.Operations = ops
+		out.Services[i] = service
+	}
+
+	if in.Webhooks != nil {
+		out.Webhooks = make([]ir.IRWebhook, len(in.Webhooks))
+		for i, wh := range in.Webhooks {
+			if wh.Payload != nil {
+				payload := *wh.Payload
+				payload.Schema = m.schema(payload.Schema)
+				wh.Payload = &payload
+			}
+			out.Webhooks[i] = wh
+		}
+	}
+
+	return out
+}
+
+// schemaMapper copies schemas recursively, applying fn to every copy
+type schemaMapper struct {
+	fn func(ir.IRSchema) ir.IRSchema
+}
+
+func (m schemaMapper) params(params []ir.IRParam) []ir.IRParam {
+	if params == nil {
+		return nil
+	}
+	out := make([]ir.IRParam, len(params))
+	for i, p := range params {
+		p.Schema = m.schema(p.Schema)
+		out[i] = p
+	}
+	return out
+}
+
+func (m schemaMapper) schema(s ir.IRSchema) ir.IRSchema {
+	s.Items = m.schemaPtr(s.Items)
+	s.AdditionalProperties = m.schemaPtr(s.AdditionalProperties)
+	s.Not = m.schemaPtr(s.Not)
+	s.OneOf = m.schemaList(s.OneOf)
+	s.AnyOf = m.schemaList(s.AnyOf)
+	s.AllOf = m.schemaList(s.AllOf)
+
+	if s.Properties != nil {
+		props := make([]ir.IRField, len(s.Properties))
+		for i, f := range s.Properties {
+			f.Type = m.schemaPtr(f.Type)
+			props[i] = f
+		}
+		s.Properties = props
+	}
+	return m.fn(s)
+}
+
+func (m schemaMapper) schemaPtr(s *ir.IRSchema) *ir.IRSchema {
+	if s == nil {
+		return nil
+	}
+	mapped := m.schema(*s)
+	return &mapped
+}
+
+func (m schemaMapper) schemaList(list []*ir.IRSchema) []*ir.IRSchema {
+	if list == nil {
+		return nil
+	}
+	out := make([]*ir.IRSchema, len(list))
+	for i, s := range list {
+		out[i] = m.schemaPtr(s)
+	}
+	return out
+}
+
+// requireRequestBodies marks every request body of in as required, for clients configured
+// with requireRequestBodies. Operations without request content have no body to require.
+func requireRequestBodies(in ir.IR) ir.IR {
+	out := in
+	out.Services = make([]ir.IRService, len(in.Services))
+	for i, service := range in.Services {
+		ops := make([]ir.IROperation, len(service.Operations))
+		for j, op := range service.Operations {
+			if op.RequestBody != nil {
+				body := *op.RequestBody
+				body.Required = true
+				op.RequestBody = &body
+			}
+			if len(op.RequestContents) > 0 {
+				contents := make([]ir.IRRequestBody, len(op.RequestContents))
+				for k, body := range op.RequestContents {
+					body.Required = true
+					contents[k] = body
+				}
+				op.RequestContents = contents
+			}
+			ops[j] = op
+		}
+		service.Operations = ops
+		out.Services[i] = service
+	}
+	return out
+}
@@ -0,0 +1,261 @@
+package jsonschema
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/blimu-dev/sdk-gen/pkg/config"
+	"github.com/blimu-dev/sdk-gen/pkg/ir"
+)
+
+// Draft is the JSON Schema dialect of the generated documents
+const Draft = "https://json-schema.org/draft/2020-12/schema"
+
+// JSONSchemaGenerator implements the Generator interface for JSON Schema
+type JSONSchemaGenerator struct{}
+
+// NewJSONSchemaGenerator creates a new JSON Schema generator
+func NewJSONSchemaGenerator() *JSONSchemaGenerator {
+	return &JSONSchemaGenerator{}
+}
+
+// GetType returns the generator type identifier
+func (g *JSONSchemaGenerator) GetType() string {
+	return "jsonschema"
+}
+
+// Generate writes one standalone JSON Schema (draft 2020-12) document per ModelDef. Each
+// document holds the model at its root and every model it references under $defs, so it
+// can be used by any validator without the original spec.
+func (g *JSONSchemaGenerator) Generate(client config.Client, in ir.IR) error {
+	if err := os.MkdirAll(client.OutDir, 0o755); err != nil {
+		return err
+	}
+
+	defs := make(map[string]ir.IRModelDef, len(in.ModelDefs))
+	for _, md := range in.ModelDefs {
+		defs[md.Name] = md
+	}
+
+	for _, md := range in.ModelDefs {
+		targetPath := filepath.Join(client.OutDir, md.Name+".schema.json")
+		if client.ShouldExcludeFile(targetPath) {
+			continue
+		}
+		var buf bytes.Buffer
+		enc := json.NewEncoder(&buf)
+		enc.SetEscapeHTML(false)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(Document(md, defs)); err != nil {
+			return fmt.Errorf("failed to encode schema %s: %w", md.Name, err)
+		}
+		if err := os.WriteFile(targetPath, buf.Bytes(), 0o644); err != nil {
+			return fmt.Errorf("failed to write file %s: %w", targetPath, err)
+		}
+	}
+	return nil
+}
+
+// Document returns the standalone JSON Schema document of a model: the model itself plus the
+// transitive closure of the models it references under $defs
+func Document(md ir.IRModelDef, defs map[string]ir.IRModelDef) map[string]any {
+	doc := modelSchema(md)
+	doc["$schema"] = Draft
+
+	refs := map[string]bool{}
+	collectRefs(md.Schema, defs, refs)
+	if len(refs) > 0 {
+		names := make([]string, 0, len(refs))
+		for name := range refs {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		all := make(map[string]any, len(names))
+		for _, name := range names {
+			if def, ok := defs[name]; ok {
+				all[name] = modelSchema(def)
+			} else {
+				// Unknown refs accept anything rather than producing a dangling $ref
+				all[name] = map[string]any{}
+			}
+		}
+		doc["$defs"] = all
+	}
+	return doc
+}
+
+// collectRefs adds the names of all models reachable from s to refs
+func collectRefs(s ir.IRSchema, defs map[string]ir.IRModelDef, refs map[string]bool) {
+	if s.Kind == ir.IRKindRef && s.Ref != "" && !refs[s.Ref] {
+		refs[s.Ref] = true
+		if def, ok := defs[s.Ref]; ok {
+			collectRefs(def.Schema, defs, refs)
+		}
+	}
+	for _, sub := range []*ir.IRSchema{s.Items, s.AdditionalProperties, s.Not} {
+		if sub != nil {
+			collectRefs(*sub, defs, refs)
+		}
+	}
+	for _, list := range [][]*ir.IRSchema{s.OneOf, s.AnyOf, s.AllOf} {
+		for _, sub := range list {
+			if sub != nil {
+				collectRefs(*sub, defs, refs)
+			}
+		}
+	}
+	for _, f := range s.Properties {
+		if f.Type != nil {
+			collectRefs(*f.Type, defs, refs)
+		}
+	}
+}
+
+// modelSchema converts a model and its annotations
+func modelSchema(md ir.IRModelDef) map[string]any {
+	out := schemaToJSON(md.Schema)
+	if md.Annotations.Title != "" {
+		out["title"] = md.Annotations.Title
+	} else {
+		out["title"] = md.Name
+	}
+	annotate(out, md.Annotations)
+	return out
+}
+
+// annotate copies the annotations that JSON Schema understands
+func annotate(out map[string]any, a ir.IRAnnotations) {
+	if a.Description != "" {
+		out["description"] = a.Description
+	}
+	if a.Deprecated {
+		out["deprecated"] = true
+	}
+	if a.ReadOnly {
+		out["readOnly"] = true
+	}
+	if a.WriteOnly {
+		out["writeOnly"] = true
+	}
+	if a.Default != nil {
+		out["default"] = a.Default
+	}
+	if len(a.Examples) > 0 {
+		out["examples"] = a.Examples
+	}
+}
+
+// schemaToJSON converts an IR schema to its JSON Schema form
+func schemaToJSON(s ir.IRSchema) map[string]any {
+	out := map[string]any{}
+	switch s.Kind {
+	case ir.IRKindString, ir.IRKindNumber, ir.IRKindInteger, ir.IRKindBoolean:
+		out["type"] = string(s.Kind)
+		if s.Format != "" {
+			out["format"] = s.Format
+		}
+		if s.ExclusiveMinimum != nil {
+			out["exclusiveMinimum"] = *s.ExclusiveMinimum
+		}
+		if s.ExclusiveMaximum != nil {
+			out["exclusiveMaximum"] = *s.ExclusiveMaximum
+		}
+		if s.MultipleOf != nil {
+			out["multipleOf"] = *s.MultipleOf
+		}
+	case ir.IRKindNull:
+		out["type"] = "null"
+		return out
+	case ir.IRKindArray:
+		out["type"] = "array"
+		if s.Items != nil {
+			out["items"] = schemaToJSON(*s.Items)
+		}
+	case ir.IRKindObject:
+		out["type"] = "object"
+		if len(s.Properties) > 0 {
+			props := make(map[string]any, len(s.Properties))
+			var required []string
+			for _, f := range s.Properties {
+				prop := map[string]any{}
+				if f.Type != nil {
+					prop = schemaToJSON(*f.Type)
+				}
+				annotate(prop, f.Annotations)
+				props[f.Name] = prop
+				if f.Required {
+					required = append(required, f.Name)
+				}
+			}
+			out["properties"] = props
+			if len(required) > 0 {
+				out["required"] = required
+			}
+		}
+		if s.AdditionalProperties != nil {
+			out["additionalProperties"] = schemaToJSON(*s.AdditionalProperties)
+		}
+		if s.MinProperties != nil {
+			out["minProperties"] = *s.MinProperties
+		}
+		if s.MaxProperties != nil {
+			out["maxProperties"] = *s.MaxProperties
+		}
+	case ir.IRKindEnum:
+		values := make([]any, 0, len(s.EnumValues))
+		if len(s.EnumRaw) > 0 {
+			values = append(values, s.EnumRaw...)
+		} else {
+			for _, v := range s.EnumValues {
+				values = append(values, v)
+			}
+		}
+		switch s.EnumBase {
+		case ir.IRKindString, ir.IRKindNumber, ir.IRKindInteger, ir.IRKindBoolean:
+			out["type"] = string(s.EnumBase)
+		}
+		if s.Nullable {
+			values = append(values, nil)
+		}
+		out["enum"] = values
+	case ir.IRKindRef:
+		out["$ref"] = "#/$defs/" + s.Ref
+	case ir.IRKindOneOf:
+		out["oneOf"] = schemaList(s.OneOf)
+	case ir.IRKindAnyOf:
+		out["anyOf"] = schemaList(s.AnyOf)
+	case ir.IRKindAllOf:
+		out["allOf"] = schemaList(s.AllOf)
+	case ir.IRKindNot:
+		if s.Not != nil {
+			out["not"] = schemaToJSON(*s.Not)
+		}
+	default:
+		// Unknown schemas accept any value
+		return out
+	}
+
+	if s.Nullable {
+		if t, ok := out["type"].(string); ok {
+			out["type"] = []string{t, "null"}
+		} else if s.Kind != ir.IRKindEnum {
+			// Refs and compositions have no type to widen, so null becomes an alternative
+			return map[string]any{"anyOf": []any{out, map[string]any{"type": "null"}}}
+		}
+	}
+	return out
+}
+
+func schemaList(list []*ir.IRSchema) []any {
+	out := make([]any, 0, len(list))
+	for _, s := range list {
+		if s != nil {
+			out = append(out, schemaToJSON(*s))
+		}
+	}
+	return out
+}
@@ -0,0 +1,80 @@
+package jsonschema
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/blimu-dev/sdk-gen/pkg/ir"
+)
+
+func TestSchemaToJSON(t *testing.T) {
+	str := ir.IRSchema{Kind: ir.IRKindString}
+	one, ten := uint64(1), uint64(10)
+	zero, cent := 0.0, 0.01
+	tests := []struct {
+		name     string
+		schema   ir.IRSchema
+		expected string
+	}{
+		{"string with format", ir.IRSchema{Kind: ir.IRKindString, Format: "date-time"}, `{"format":"date-time","type":"string"}`},
+		{"nullable integer", ir.IRSchema{Kind: ir.IRKindInteger, Nullable: true}, `{"type":["integer","null"]}`},
+		{"positive amount", ir.IRSchema{Kind: ir.IRKindNumber, ExclusiveMinimum: &zero, MultipleOf: &cent}, `{"exclusiveMinimum":0,"multipleOf":0.01,"type":"number"}`},
+		{"array of refs", ir.IRSchema{Kind: ir.IRKindArray, Items: &ir.IRSchema{Kind: ir.IRKindRef, Ref: "User"}}, `{"items":{"$ref":"#/$defs/User"},"type":"array"}`},
+		{"nullable ref", ir.IRSchema{Kind: ir.IRKindRef, Ref: "User", Nullable: true}, `{"anyOf":[{"$ref":"#/$defs/User"},{"type":"null"}]}`},
+		{
+			"nullable enum",
+			ir.IRSchema{Kind: ir.IRKindEnum, EnumBase: ir.IRKindString, EnumValues: []string{"a", "b"}, EnumRaw: []any{"a", "b"}, Nullable: true},
+			`{"enum":["a","b",null],"type":["string","null"]}`,
+		},
+		{"numeric enum", ir.IRSchema{Kind: ir.IRKindEnum, EnumBase: ir.IRKindNumber, EnumValues: []string{"1.5"}, EnumRaw: []any{1.5}}, `{"enum":[1.5],"type":"number"}`},
+		{
+			"object",
+			ir.IRSchema{Kind: ir.IRKindObject, Properties: []ir.IRField{
+				{Name: "id", Type: &str, Required: true, Annotations: ir.IRAnnotations{ReadOnly: true}},
+				{Name: "tags", Type: &ir.IRSchema{Kind: ir.IRKindArray, Items: &str}},
+			}, AdditionalProperties: &str},
+			`{"additionalProperties":{"type":"string"},"properties":{"id":{"readOnly":true,"type":"string"},"tags":{"items":{"type":"string"},"type":"array"}},"required":["id"],"type":"object"}`,
+		},
+		{
+			"bounded map",
+			ir.IRSchema{Kind: ir.IRKindObject, AdditionalProperties: &str, MinProperties: &one, MaxProperties: &ten},
+			`{"additionalProperties":{"type":"string"},"maxProperties":10,"minProperties":1,"type":"object"}`,
+		},
+		{"oneOf", ir.IRSchema{Kind: ir.IRKindOneOf, OneOf: []*ir.IRSchema{&str, {Kind: ir.IRKindNull}}}, `{"oneOf":[{"type":"string"},{"type":"null"}]}`},
+		{"unknown", ir.IRSchema{Kind: ir.IRKindUnknown}, `{}`},
+	}
+
+	for _, test := range tests {
+		data, err := json.Marshal(schemaToJSON(test.schema))
+		if err != nil {
+			t.Fatalf("%s: %v", test.name, err)
+		}
+		if string(data) != test.expected {
+			t.Errorf("%s: got %s, expected %s", test.name, data, test.expected)
+		}
+	}
+}
+
+func TestDocumentDefs(t *testing.T) {
+	defs := map[string]ir.IRModelDef{
+		"Order": {Name: "Order", Annotations: ir.IRAnnotations{Description: "An order"}, Schema: ir.IRSchema{Kind: ir.IRKindObject, Properties: []ir.IRField{
+			{Name: "customer", Type: &ir.IRSchema{Kind: ir.IRKindRef, Ref: "Customer"}},
+		}}},
+		"Customer": {Name: "Customer", Schema: ir.IRSchema{Kind: ir.IRKindObject, Properties: []ir.IRField{
+			{Name: "referrer", Type: &ir.IRSchema{Kind: ir.IRKindRef, Ref: "Customer", Nullable: true}},
+		}}},
+		"Unused": {Name: "Unused", Schema: ir.IRSchema{Kind: ir.IRKindString}},
+	}
+
+	doc := Document(defs["Order"], defs)
+	if doc["$schema"] != Draft || doc["title"] != "Order" || doc["description"] != "An order" {
+		t.Errorf("unexpected document header: %v", doc)
+	}
+	all, ok := doc["$defs"].(map[string]any)
+	if !ok || len(all) != 1 || all["Customer"] == nil {
+		t.Errorf("expected only the transitively referenced Customer under $defs, got %v", doc["$defs"])
+	}
+	if _, ok := Document(defs["Unused"], defs)["$defs"]; ok {
+		t.Errorf("expected no $defs for a model without references")
+	}
+}
@@ -0,0 +1,191 @@
+package kotlin
+
+import (
+	"embed"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+
+	"github.com/Masterminds/sprig/v3"
+
+	"github.com/blimu-dev/sdk-gen/pkg/config"
+	"github.com/blimu-dev/sdk-gen/pkg/ir"
+	"github.com/blimu-dev/sdk-gen/pkg/utils"
+)
+
+//go:embed templates/*
+var templatesFS embed.FS
+
+// KotlinGenerator implements the Generator interface for Kotlin
+type KotlinGenerator struct {
+	// funcs are the template functions set with SetTemplateFuncs
+	funcs template.FuncMap
+	// logger receives warnings, see SetLogger
+	logger *slog.Logger
+}
+
+// NewKotlinGenerator creates a new Kotlin generator
+func NewKotlinGenerator() *KotlinGenerator {
+	return &KotlinGenerator{}
+}
+
+// GetType returns the generator type identifier
+func (g *KotlinGenerator) GetType() string {
+	return "kotlin"
+}
+
+// SetTemplateFuncs adds funcs to the functions available to the templates. A function named
+// like a built-in helper replaces it.
+func (g *KotlinGenerator) SetTemplateFuncs(funcs template.FuncMap) {
+	g.funcs = funcs
+}
+
+// SetLogger sets the logger warnings found while generating, such as renamed methods, are
+// reported to
+func (g *KotlinGenerator) SetLogger(logger *slog.Logger) {
+	g.logger = logger
+}
+
+// Generate creates a Kotlin SDK (Gradle project, kotlinx.serialization models and Ktor
+// services with suspend functions) from the given configuration and IR
+func (g *KotlinGenerator) Generate(client config.Client, in ir.IR) error {
+	pkg := sanitizePackageName(client.PackageName)
+	srcDir := filepath.Join(client.OutDir, "src", "main", "kotlin", packagePath(pkg))
+	if err := os.MkdirAll(srcDir, 0o755); err != nil {
+		return err
+	}
+
+	clientName := toPascalCase(client.Name)
+	if !strings.HasSuffix(clientName, "Client") {
+		clientName += "Client"
+	}
+	parents := collectSealedParents(in.ModelDefs)
+
+	methodNames, warnings := utils.NewMethodNames(in.Services, func(op ir.IROperation) string { return ResolveMethodName(client, op) })
+	utils.LogWarnings(g.logger, client.Name, warnings)
+	methodName := methodNames.Get
+
+	funcMap := template.FuncMap{
+		"pascal":          toPascalCase,
+		"camel":           toCamelCase,
+		"kebab":           toKebabCase,
+		"packageName":     func() string { return pkg },
+		"packageVersion":  client.PackageVersion,
+		"clientName":      func() string { return clientName },
+		"serviceName":     func(tag string) string { return toPascalCase(tag) + "Service" },
+		"serviceField":    func(tag string) string { return kotlinIdent(toCamelCase(tag)) },
+		"methodName":      methodName,
+		"methodParams":    buildMethodParams,
+		"pathTemplate":    buildPathTemplate,
+		"returnType":      returnType,
+		"kotlinType":      schemaToKotlinType,
+		"fieldType":       fieldType,
+		"propertyName":    propertyName,
+		"fieldName":       func(name string) string { return fieldName(name, client.PreservePropertyNames) },
+		"needsSerialName": func(name string) bool { return needsSerialName(name, client.PreservePropertyNames) },
+		"enumEntries":     enumEntries,
+		"enumValueType":   enumValueType,
+		"numericEnums":    func() bool { return hasNumericEnums(in.ModelDefs) },
+		"kdoc":            formatKDoc,
+		"isSealedUnion":   isSealedUnion,
+		"isDataClass":     isDataClass,
+		"sealedParents":   func(name string) []sealedParent { return sortedParents(parents[name]) },
+		"discriminatorProps": func(ps []sealedParent) map[string]bool {
+			return discriminatorProperties(in.ModelDefs, ps)
+		},
+		"modelFields": func(s ir.IRSchema) []ir.IRField { return modelFields(in.ModelDefs, s) },
+		"fieldsWithout": func(fields []ir.IRField, skip map[string]bool) []ir.IRField {
+			var out []ir.IRField
+			for _, f := range fields {
+				if !skip[f.Name] {
+					out = append(out, f)
+				}
+			}
+			return out
+		},
+	}
+
+	// Merge sprig functions
+	for k, v := range sprig.FuncMap() {
+		funcMap[k] = v
+	}
+	// User functions come last and replace helpers of the same name
+	for k, v := range g.funcs {
+		funcMap[k] = v
+	}
+
+	data := map[string]any{"Client": client, "IR": in}
+
+	// Generate Gradle build files
+	if err := renderFile(client, "build.gradle.kts.gotmpl", filepath.Join(client.OutDir, "build.gradle.kts"), funcMap, data); err != nil {
+		return err
+	}
+	if err := renderFile(client, "settings.gradle.kts.gotmpl", filepath.Join(client.OutDir, "settings.gradle.kts"), funcMap, data); err != nil {
+		return err
+	}
+
+	// Generate the client and models
+	if err := renderFile(client, "Client.kt.gotmpl", filepath.Join(srcDir, clientName+".kt"), funcMap, data); err != nil {
+		return err
+	}
+	if err := renderFile(client, "Models.kt.gotmpl", filepath.Join(srcDir, "Models.kt"), funcMap, data); err != nil {
+		return err
+	}
+
+	// Generate services
+	for _, service := range in.Services {
+		// Skip services with no operations
+		if len(service.Operations) == 0 {
+			continue
+		}
+		fileName := client.FileName(toPascalCase(service.Tag)+"Service", service.Tag, "service") + ".kt"
+		if err := renderFile(client, "Service.kt.gotmpl", filepath.Join(srcDir, fileName), funcMap, map[string]any{"Client": client, "Service": service}); err != nil {
+			return err
+		}
+	}
+
+	// Generate README.md
+	if err := renderFile(client, "README.md.gotmpl", filepath.Join(client.OutDir, "README.md"), funcMap, data); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// renderFile renders a template file to the target path
+func renderFile(client config.Client, templateName, targetPath string, funcMap template.FuncMap, data map[string]any) error {
+	// Check if file should be excluded
+	if client.ShouldExcludeFile(targetPath) {
+		return nil // Skip this file silently
+	}
+
+	tmplContent, err := templatesFS.ReadFile("templates/" + templateName)
+	if err != nil {
+		return fmt.Errorf("failed to read template %s: %w", templateName, err)
+	}
+
+	tmpl, err := template.New(templateName).Funcs(funcMap).Parse(string(tmplContent))
+	if err != nil {
+		return fmt.Errorf("failed to parse template %s: %w", templateName, err)
+	}
+
+	file, err := os.Create(targetPath)
+	if err != nil {
+		return fmt.Errorf("failed to create file %s: %w", targetPath, err)
+	}
+	defer file.Close()
+
+	if _, err := io.WriteString(file, utils.FileHeader(client.FileHeader, targetPath)); err != nil {
+		return fmt.Errorf("failed to write file %s: %w", targetPath, err)
+	}
+
+	if err := tmpl.Execute(file, data); err != nil {
+		return fmt.Errorf("failed to execute template %s: %w", templateName, err)
+	}
+
+	return nil
+}
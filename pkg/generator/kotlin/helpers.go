@@ -0,0 +1,477 @@
+package kotlin
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/blimu-dev/sdk-gen/pkg/config"
+	"github.com/blimu-dev/sdk-gen/pkg/ir"
+	"github.com/blimu-dev/sdk-gen/pkg/utils"
+)
+
+// Alias functions to use centralized utilities (advanced versions for better camelCase handling)
+var toPascalCase = utils.ToPascalCaseAdvanced
+var toCamelCase = utils.ToCamelCaseAdvanced
+var toSnakeCase = utils.ToSnakeCaseAdvanced
+var toKebabCase = utils.ToKebabCaseAdvanced
+
+// unknownType is used where the schema does not pin down a concrete type. JsonElement
+// is the serializable stand-in for Any? in kotlinx.serialization.
+const unknownType = "JsonElement?"
+
+// kotlinKeywords lists hard keywords that must be escaped with backticks when used as identifiers
+var kotlinKeywords = map[string]bool{
+	"as": true, "break": true, "class": true, "continue": true, "do": true, "else": true,
+	"false": true, "for": true, "fun": true, "if": true, "in": true, "interface": true,
+	"is": true, "null": true, "object": true, "package": true, "return": true, "super": true,
+	"this": true, "throw": true, "true": true, "try": true, "typealias": true, "typeof": true,
+	"val": true, "var": true, "when": true, "while": true,
+}
+
+var identPattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// schemaToKotlinType converts an IR schema to a Kotlin type string
+func schemaToKotlinType(x any) string {
+	switch v := x.(type) {
+	case ir.IRSchema:
+		return schemaToKotlinTypeImpl(v)
+	case *ir.IRSchema:
+		if v != nil {
+			return schemaToKotlinTypeImpl(*v)
+		}
+		return unknownType
+	default:
+		return unknownType
+	}
+}
+
+func schemaToKotlinTypeImpl(s ir.IRSchema) string {
+	var t string
+	switch s.Kind {
+	case ir.IRKindString:
+		if s.Format == "binary" {
+			t = "ByteArray"
+		} else {
+			t = "String"
+		}
+	case ir.IRKindNumber:
+		t = "Double"
+	case ir.IRKindInteger:
+		t = "Long"
+	case ir.IRKindBoolean:
+		t = "Boolean"
+	case ir.IRKindRef:
+		if s.Ref == "" {
+			return unknownType
+		}
+		t = toPascalCase(s.Ref)
+	case ir.IRKindArray:
+		if s.Items != nil {
+			t = "List<" + schemaToKotlinTypeImpl(*s.Items) + ">"
+		} else {
+			t = "List<" + unknownType + ">"
+		}
+	case ir.IRKindEnum:
+		// Inline enums have no class of their own; named enums arrive as refs
+		switch s.EnumBase {
+		case ir.IRKindInteger:
+			t = "Long"
+		case ir.IRKindNumber:
+			t = "Double"
+		case ir.IRKindBoolean:
+			t = "Boolean"
+		default:
+			t = "String"
+		}
+	case ir.IRKindObject:
+		if s.AdditionalProperties != nil {
+			t = "Map<String, " + schemaToKotlinTypeImpl(*s.AdditionalProperties) + ">"
+		} else {
+			t = "Map<String, " + unknownType + ">"
+		}
+	default:
+		// null, oneOf/anyOf/allOf without a named model, not and unknown
+		return unknownType
+	}
+
+	if s.Nullable {
+		t += "?"
+	}
+	return t
+}
+
+// kotlinIdent escapes a name that collides with a Kotlin keyword
+func kotlinIdent(name string) string {
+	if kotlinKeywords[name] {
+		return "`" + name + "`"
+	}
+	return name
+}
+
+// propertyName returns the Kotlin property name for a JSON field
+func propertyName(jsonName string) string {
+	name := toCamelCase(jsonName)
+	if name == "" {
+		name = "value"
+	}
+	if name[0] >= '0' && name[0] <= '9' {
+		name = "_" + name
+	}
+	return kotlinIdent(name)
+}
+
+// fieldName returns the Kotlin property name of a model field: its JSON name when property
+// names are preserved and the JSON name is a valid identifier, else the camelCase propertyName
+func fieldName(jsonName string, preserve bool) string {
+	if preserve && identPattern.MatchString(jsonName) {
+		return kotlinIdent(jsonName)
+	}
+	return propertyName(jsonName)
+}
+
+// needsSerialName reports whether a field requires @SerialName because its JSON name
+// is not a valid Kotlin identifier or differs from the generated property name
+func needsSerialName(jsonName string, preserve bool) bool {
+	return !identPattern.MatchString(jsonName) || strings.Trim(fieldName(jsonName, preserve), "`") != jsonName
+}
+
+// enumEntry is one constant of a generated Kotlin enum class
+type enumEntry struct {
+	Name string
+	// Value is the @SerialName of a string enum constant, or the constructor argument
+	// holding the value of a numeric one
+	Value string
+}
+
+// enumEntries returns the constants of a named enum. String constants are named after their
+// values; numeric ones after x-enum-varnames when present.
+func enumEntries(s ir.IRSchema) []enumEntry {
+	literals := s.EnumLiterals()
+	names := s.EnumValues
+	numeric := enumValueType(s) != ""
+	if numeric {
+		names = literals
+		if len(s.EnumNames) == len(literals) {
+			names = s.EnumNames
+		}
+	}
+	members := utils.EnumMemberNames(names, utils.EnumNameUpperSnake)
+	entries := make([]enumEntry, 0, len(literals))
+	for i, v := range literals {
+		if s.EnumBase == ir.IRKindNumber && !strings.ContainsAny(v, ".eE") {
+			// A Double parameter does not take an integer literal
+			v += ".0"
+		}
+		entries = append(entries, enumEntry{Name: members[i], Value: v})
+	}
+	return entries
+}
+
+// enumValueType returns the Kotlin type of the values of a numeric enum, which its serializer
+// encodes as JSON numbers, or "" for enums serialized by name
+func enumValueType(s ir.IRSchema) string {
+	switch s.EnumBase {
+	case ir.IRKindInteger:
+		return "Long"
+	case ir.IRKindNumber:
+		return "Double"
+	}
+	return ""
+}
+
+// hasNumericEnums reports whether any model is a numeric enum needing its own serializer
+func hasNumericEnums(defs []ir.IRModelDef) bool {
+	for _, md := range defs {
+		if md.Schema.Kind == ir.IRKindEnum && enumValueType(md.Schema) != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// fieldType returns the Kotlin type of an object field. Optional fields are nullable
+// so they can default to null.
+func fieldType(f ir.IRField) string {
+	t := schemaToKotlinType(f.Type)
+	if !f.Required && !strings.HasSuffix(t, "?") {
+		t += "?"
+	}
+	return t
+}
+
+// sealedParent records a discriminated union a model belongs to
+type sealedParent struct {
+	Name  string
+	Value string
+}
+
+// collectSealedParents maps every ref member of a discriminated oneOf model to the union
+// it implements, together with the discriminator value that selects it
+func collectSealedParents(defs []ir.IRModelDef) map[string][]sealedParent {
+	parents := map[string][]sealedParent{}
+	for _, md := range defs {
+		if !isSealedUnion(md.Schema) {
+			continue
+		}
+		// Walk the mapping in value order so a model listed under several values always
+		// gets the lowest one
+		mapping := md.Schema.Discriminator.Mapping
+		keys := make([]string, 0, len(mapping))
+		for value := range mapping {
+			keys = append(keys, value)
+		}
+		sort.Strings(keys)
+		values := map[string]string{}
+		for _, value := range keys {
+			if name := refName(mapping[value]); values[name] == "" {
+				values[name] = value
+			}
+		}
+		for _, member := range md.Schema.OneOf {
+			value, ok := values[member.Ref]
+			if !ok {
+				value = member.Ref
+			}
+			parents[member.Ref] = append(parents[member.Ref], sealedParent{Name: toPascalCase(md.Name), Value: value})
+		}
+	}
+	return parents
+}
+
+// isSealedUnion reports whether a schema can be rendered as a sealed interface: a oneOf
+// with a discriminator whose members are all named models
+func isSealedUnion(s ir.IRSchema) bool {
+	if s.Kind != ir.IRKindOneOf || s.Discriminator == nil || s.Discriminator.PropertyName == "" || len(s.OneOf) == 0 {
+		return false
+	}
+	for _, member := range s.OneOf {
+		if member == nil || member.Kind != ir.IRKindRef || member.Ref == "" {
+			return false
+		}
+	}
+	return true
+}
+
+// refName extracts the component name from a mapping target like "#/components/schemas/Dog"
+func refName(ref string) string {
+	if i := strings.LastIndex(ref, "/"); i >= 0 {
+		return ref[i+1:]
+	}
+	return ref
+}
+
+// discriminatorProperties returns the discriminator property names of the unions a model
+// implements; kotlinx.serialization writes those itself so they are not declared as fields
+func discriminatorProperties(defs []ir.IRModelDef, parents []sealedParent) map[string]bool {
+	out := map[string]bool{}
+	for _, p := range parents {
+		for _, md := range defs {
+			if toPascalCase(md.Name) == p.Name && md.Schema.Discriminator != nil {
+				out[md.Schema.Discriminator.PropertyName] = true
+			}
+		}
+	}
+	return out
+}
+
+// modelFields returns the fields rendered for an object or allOf model. allOf members are
+// flattened: referenced object models contribute their properties, later members win.
+func modelFields(defs []ir.IRModelDef, s ir.IRSchema) []ir.IRField {
+	switch s.Kind {
+	case ir.IRKindObject:
+		return s.Properties
+	case ir.IRKindAllOf:
+		var fields []ir.IRField
+		index := map[string]int{}
+		add := func(props []ir.IRField) {
+			for _, f := range props {
+				if i, ok := index[f.Name]; ok {
+					fields[i] = f
+					continue
+				}
+				index[f.Name] = len(fields)
+				fields = append(fields, f)
+			}
+		}
+		for _, part := range s.AllOf {
+			if part == nil {
+				continue
+			}
+			if part.Kind == ir.IRKindRef {
+				for _, md := range defs {
+					if md.Name == part.Ref {
+						add(modelFields(defs, md.Schema))
+					}
+				}
+				continue
+			}
+			add(modelFields(defs, *part))
+		}
+		return fields
+	}
+	return nil
+}
+
+// isDataClass reports whether a model renders as a data class
+func isDataClass(s ir.IRSchema) bool {
+	return s.Kind == ir.IRKindObject && s.AdditionalProperties == nil || s.Kind == ir.IRKindAllOf
+}
+
+// ResolveMethodName chooses final method name using operationId, then heuristic
+func ResolveMethodName(client config.Client, op ir.IROperation) string {
+	if parsed := defaultParseOperationID(op.OperationID); parsed != "" {
+		return kotlinIdent(toCamelCase(parsed))
+	}
+	return deriveMethodName(op)
+}
+
+// defaultParseOperationID strips any prefix up to and including "Controller_"
+func defaultParseOperationID(opID string) string {
+	if idx := strings.Index(opID, "Controller_"); idx >= 0 {
+		return opID[idx+len("Controller_"):]
+	}
+	return opID
+}
+
+// deriveMethodName creates method names using basic REST-style heuristics
+func deriveMethodName(op ir.IROperation) string {
+	hasID := strings.Contains(op.Path, "{") && strings.Contains(op.Path, "}")
+	switch strings.ToUpper(op.Method) {
+	case "GET":
+		if hasID {
+			return "get"
+		}
+		return "list"
+	case "POST":
+		return "create"
+	case "PUT", "PATCH":
+		return "update"
+	case "DELETE":
+		return "delete"
+	default:
+		return toCamelCase(op.Method)
+	}
+}
+
+// orderPathParams returns path parameters in the order they appear in the path
+func orderPathParams(op ir.IROperation) []ir.IRParam {
+	byName := make(map[string]ir.IRParam, len(op.PathParams))
+	for _, p := range op.PathParams {
+		byName[p.Name] = p
+	}
+	var ordered []ir.IRParam
+	for _, m := range regexp.MustCompile(`\{([^}]+)\}`).FindAllStringSubmatch(op.Path, -1) {
+		if p, ok := byName[m[1]]; ok {
+			ordered = append(ordered, p)
+		}
+	}
+	return ordered
+}
+
+// buildPathTemplate builds a Kotlin string template for the operation path with each
+// path parameter URL-encoded; the values of array parameters are joined with the separator
+// of their style
+func buildPathTemplate(op ir.IROperation) string {
+	path := strings.ReplaceAll(op.Path, "$", "\\$")
+	for _, p := range op.PathParams {
+		expr := fmt.Sprintf("%s${%s.toString().encodeURLPathPart()}", p.PathPrefix(), propertyName(p.Name))
+		if p.Schema.Kind == ir.IRKindArray {
+			expr = fmt.Sprintf("%s${%s.joinToString(%s) { it.toString().encodeURLPathPart() }}", p.PathPrefix(), propertyName(p.Name), strconv.Quote(p.PathSeparator()))
+		}
+		path = strings.ReplaceAll(path, "{"+p.Name+"}", expr)
+	}
+	return `"` + path + `"`
+}
+
+// buildMethodParams renders the parameter list of a service method: path parameters,
+// the request body, then query parameters (optional ones default to null)
+func buildMethodParams(op ir.IROperation) string {
+	var params []string
+	for _, p := range orderPathParams(op) {
+		params = append(params, fmt.Sprintf("%s: %s", propertyName(p.Name), schemaToKotlinType(p.Schema)))
+	}
+	if op.RequestBody != nil {
+		t := schemaToKotlinType(op.RequestBody.Schema)
+		if !op.RequestBody.Required && !strings.HasSuffix(t, "?") {
+			params = append(params, fmt.Sprintf("body: %s? = null", t))
+		} else {
+			params = append(params, "body: "+t)
+		}
+	}
+	required, optional := []string{}, []string{}
+	for _, p := range op.QueryParams {
+		t := schemaToKotlinType(p.Schema)
+		if p.Required {
+			required = append(required, fmt.Sprintf("%s: %s", propertyName(p.Name), t))
+			continue
+		}
+		if !strings.HasSuffix(t, "?") {
+			t += "?"
+		}
+		optional = append(optional, fmt.Sprintf("%s: %s = null", propertyName(p.Name), t))
+	}
+	params = append(params, required...)
+	params = append(params, optional...)
+	return strings.Join(params, ", ")
+}
+
+// returnType returns the Kotlin return type of an operation
+func returnType(op ir.IROperation) string {
+	if op.Response.TypeTS == "void" {
+		return "Unit"
+	}
+	return schemaToKotlinType(op.Response.Schema)
+}
+
+// sanitizePackageName turns the configured package name into a valid Kotlin package
+func sanitizePackageName(name string) string {
+	name = strings.ToLower(strings.ReplaceAll(name, "/", "."))
+	var parts []string
+	for _, part := range strings.Split(name, ".") {
+		part = regexp.MustCompile(`[^a-z0-9_]`).ReplaceAllString(part, "")
+		if part == "" {
+			continue
+		}
+		if part[0] >= '0' && part[0] <= '9' {
+			part = "_" + part
+		}
+		parts = append(parts, kotlinIdent(part))
+	}
+	if len(parts) == 0 {
+		return "client"
+	}
+	return strings.Join(parts, ".")
+}
+
+// packagePath returns the source directory for a Kotlin package
+func packagePath(pkg string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(pkg, "`", ""), ".", "/")
+}
+
+// formatKDoc formats a string as a KDoc comment body at the given indentation
+func formatKDoc(s, indent string) string {
+	if strings.TrimSpace(s) == "" {
+		return ""
+	}
+	lines := []string{indent + "/**"}
+	for _, line := range strings.Split(strings.ReplaceAll(s, "*/", "*&#47;"), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			lines = append(lines, indent+" *")
+		} else {
+			lines = append(lines, indent+" * "+line)
+		}
+	}
+	lines = append(lines, indent+" */")
+	return strings.Join(lines, "\n")
+}
+
+// sortedParents returns a model's sealed parents sorted by name for stable output
+func sortedParents(parents []sealedParent) []sealedParent {
+	out := append([]sealedParent(nil), parents...)
+	sort.Slice(out, func(i, j int) bool { return out[i].Name < out[j].Name })
+	return out
+}
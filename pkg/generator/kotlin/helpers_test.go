@@ -0,0 +1,179 @@
+package kotlin
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/blimu-dev/sdk-gen/pkg/config"
+	"github.com/blimu-dev/sdk-gen/pkg/ir"
+)
+
+func TestSchemaToKotlinType(t *testing.T) {
+	str := ir.IRSchema{Kind: ir.IRKindString}
+	tests := []struct {
+		name     string
+		schema   ir.IRSchema
+		expected string
+	}{
+		{"string", str, "String"},
+		{"integer", ir.IRSchema{Kind: ir.IRKindInteger}, "Long"},
+		{"number", ir.IRSchema{Kind: ir.IRKindNumber}, "Double"},
+		{"boolean", ir.IRSchema{Kind: ir.IRKindBoolean}, "Boolean"},
+		{"nullable", ir.IRSchema{Kind: ir.IRKindString, Nullable: true}, "String?"},
+		{"array", ir.IRSchema{Kind: ir.IRKindArray, Items: &str}, "List<String>"},
+		{"map", ir.IRSchema{Kind: ir.IRKindObject, AdditionalProperties: &str}, "Map<String, String>"},
+		{"ref", ir.IRSchema{Kind: ir.IRKindRef, Ref: "user_profile"}, "UserProfile"},
+		{"unknown", ir.IRSchema{Kind: ir.IRKindUnknown}, "JsonElement?"},
+		{"union", ir.IRSchema{Kind: ir.IRKindAnyOf}, "JsonElement?"},
+	}
+
+	for _, test := range tests {
+		if result := schemaToKotlinType(test.schema); result != test.expected {
+			t.Errorf("%s: schemaToKotlinType() = %q, expected %q", test.name, result, test.expected)
+		}
+	}
+}
+
+func TestEnumEntries(t *testing.T) {
+	tests := []struct {
+		name      string
+		schema    ir.IRSchema
+		valueType string
+		expected  []enumEntry
+	}{
+		{
+			"string",
+			ir.IRSchema{Kind: ir.IRKindEnum, EnumBase: ir.IRKindString, EnumValues: []string{"active", "on-hold"}},
+			"",
+			[]enumEntry{{"ACTIVE", "active"}, {"ON_HOLD", "on-hold"}},
+		},
+		{
+			"integer with x-enum-varnames",
+			ir.IRSchema{Kind: ir.IRKindEnum, EnumBase: ir.IRKindInteger, EnumValues: []string{"1", "2"}, EnumRaw: []any{float64(1), float64(2)}, EnumNames: []string{"Low", "High"}},
+			"Long",
+			[]enumEntry{{"LOW", "1"}, {"HIGH", "2"}},
+		},
+		{
+			"integer without names",
+			ir.IRSchema{Kind: ir.IRKindEnum, EnumBase: ir.IRKindInteger, EnumValues: []string{"1e+06"}, EnumRaw: []any{float64(1000000)}},
+			"Long",
+			[]enumEntry{{"VALUE_1000000", "1000000"}},
+		},
+		{
+			"number",
+			ir.IRSchema{Kind: ir.IRKindEnum, EnumBase: ir.IRKindNumber, EnumValues: []string{"0.5", "2"}, EnumRaw: []any{0.5, float64(2)}},
+			"Double",
+			[]enumEntry{{"VALUE_0_5", "0.5"}, {"VALUE_2", "2.0"}},
+		},
+	}
+	for _, test := range tests {
+		if got := enumValueType(test.schema); got != test.valueType {
+			t.Errorf("%s: enumValueType() = %q, expected %q", test.name, got, test.valueType)
+		}
+		if got := enumEntries(test.schema); !reflect.DeepEqual(got, test.expected) {
+			t.Errorf("%s: enumEntries() = %v, expected %v", test.name, got, test.expected)
+		}
+	}
+}
+
+func TestPropertyName(t *testing.T) {
+	tests := []struct {
+		input      string
+		expected   string
+		serialName bool
+		// preserved is the model field name when property names are preserved
+		preserved           string
+		preservedSerialName bool
+	}{
+		{"name", "name", false, "name", false},
+		{"createdAt", "createdAt", false, "createdAt", false},
+		{"created_at", "createdAt", true, "created_at", false},
+		{"x-request-id", "xRequestId", true, "xRequestId", true},
+		{"2fa", "_2fa", true, "_2fa", true},
+		{"object", "`object`", false, "`object`", false},
+	}
+
+	for _, test := range tests {
+		if result := propertyName(test.input); result != test.expected {
+			t.Errorf("propertyName(%q) = %q, expected %q", test.input, result, test.expected)
+		}
+		if result := needsSerialName(test.input, false); result != test.serialName {
+			t.Errorf("needsSerialName(%q) = %v, expected %v", test.input, result, test.serialName)
+		}
+		if result := fieldName(test.input, true); result != test.preserved {
+			t.Errorf("fieldName(%q, true) = %q, expected %q", test.input, result, test.preserved)
+		}
+		if result := needsSerialName(test.input, true); result != test.preservedSerialName {
+			t.Errorf("needsSerialName(%q, true) = %v, expected %v", test.input, result, test.preservedSerialName)
+		}
+	}
+}
+
+func TestCollectSealedParents(t *testing.T) {
+	defs := []ir.IRModelDef{
+		{Name: "Pet", Schema: ir.IRSchema{
+			Kind: ir.IRKindOneOf,
+			OneOf: []*ir.IRSchema{
+				{Kind: ir.IRKindRef, Ref: "Dog"},
+				{Kind: ir.IRKindRef, Ref: "Cat"},
+			},
+			Discriminator: &ir.IRDiscriminator{
+				PropertyName: "petType",
+				Mapping:      map[string]string{"dog": "#/components/schemas/Dog"},
+			},
+		}},
+		{Name: "Shape", Schema: ir.IRSchema{
+			Kind:  ir.IRKindOneOf,
+			OneOf: []*ir.IRSchema{{Kind: ir.IRKindRef, Ref: "Circle"}},
+		}},
+	}
+
+	parents := collectSealedParents(defs)
+	if got := parents["Dog"]; len(got) != 1 || got[0].Name != "Pet" || got[0].Value != "dog" {
+		t.Errorf("Dog parents = %+v, expected Pet with value dog", got)
+	}
+	if got := parents["Cat"]; len(got) != 1 || got[0].Value != "Cat" {
+		t.Errorf("Cat parents = %+v, expected Pet with value Cat", got)
+	}
+	if _, ok := parents["Circle"]; ok {
+		t.Errorf("Circle should not have a sealed parent without a discriminator")
+	}
+	if props := discriminatorProperties(defs, parents["Dog"]); !props["petType"] {
+		t.Errorf("discriminatorProperties() = %v, expected petType", props)
+	}
+}
+
+func TestGenerateArrayQueryParams(t *testing.T) {
+	integer := ir.IRSchema{Kind: ir.IRKindInteger}
+	list := ir.IRSchema{Kind: ir.IRKindArray, Items: &integer}
+	in := ir.IR{Services: []ir.IRService{{Tag: "items", Operations: []ir.IROperation{{
+		OperationID: "listItems",
+		Method:      "GET",
+		Path:        "/items",
+		Tag:         "items",
+		QueryParams: []ir.IRParam{{Name: "ids", Schema: list}, {Name: "tags", Schema: list, Required: true}, {Name: "page", Schema: integer}},
+		Response:    ir.IRResponse{TypeTS: "void"},
+	}}}}}
+	dir := t.TempDir()
+	client := config.Client{Type: "kotlin", OutDir: dir, PackageName: "com.example.items", Name: "Items"}
+	if err := NewKotlinGenerator().Generate(client, in); err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(filepath.Join(dir, "src", "main", "kotlin", "com", "example", "items", "ItemsService.kt"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Arrays repeat their key (?ids=1&ids=2) instead of sending the list's toString
+	for _, expected := range []string{
+		`ids?.forEach { parameter("ids", it) }`,
+		`tags.forEach { parameter("tags", it) }`,
+		`parameter("page", page)`,
+	} {
+		if !strings.Contains(string(data), expected) {
+			t.Errorf("ItemsService.kt does not contain %q:\n%s", expected, data)
+		}
+	}
+}
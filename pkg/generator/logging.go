@@ -0,0 +1,67 @@
+package generator
+
+import (
+	"context"
+	"io/fs"
+	"log/slog"
+	"path/filepath"
+	"time"
+
+	"github.com/blimu-dev/sdk-gen/pkg/ir"
+)
+
+// discardLogger is used by services without a logger
+var discardLogger = slog.New(slog.DiscardHandler)
+
+// SetLogger sets the logger generation progress is reported to: each phase (spec loaded, IR
+// built, per-client generation and commands) at info level, and operations dropped by filters,
+// unused models and written files at debug level. Generators report warnings, such as method
+// names renamed to resolve a collision, at warn level. Without a logger generation is silent.
+func (s *Service) SetLogger(logger *slog.Logger) {
+	s.logger = logger
+	s.registry.SetLogger(logger)
+}
+
+// log returns the logger of the service
+func (s *Service) log() *slog.Logger {
+	if s.logger == nil {
+		return discardLogger
+	}
+	return s.logger
+}
+
+// irCounts returns the number of operations, models and webhooks of in as logger attributes
+func irCounts(in ir.IR) []any {
+	operations := 0
+	for _, service := range in.Services {
+		operations += len(service.Operations)
+	}
+	return []any{"operations", operations, "models", len(in.ModelDefs), "webhooks", len(in.Webhooks)}
+}
+
+// logWrittenFiles logs at debug level the files under dir modified since start, i.e. written by
+// the generator
+func (s *Service) logWrittenFiles(client, dir string, start time.Time) {
+	logger := s.log()
+	if !logger.Enabled(context.Background(), slog.LevelDebug) {
+		return
+	}
+	// Some file systems keep modification times to the second
+	since := start.Truncate(time.Second)
+	_ = filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+		if d.IsDir() {
+			// Dependencies a post-command installed on an earlier run
+			if d.Name() == "node_modules" || d.Name() == ".git" {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if info, err := d.Info(); err == nil && !info.ModTime().Before(since) {
+			logger.Debug("file written", "client", client, "path", path)
+		}
+		return nil
+	})
+}
@@ -0,0 +1,369 @@
+package mock
+
+import (
+	"bytes"
+	"embed"
+	"encoding/json"
+	"fmt"
+	"go/format"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"text/template"
+
+	"github.com/blimu-dev/sdk-gen/pkg/config"
+	"github.com/blimu-dev/sdk-gen/pkg/ir"
+	"github.com/blimu-dev/sdk-gen/pkg/utils"
+)
+
+//go:embed templates/*
+var templatesFS embed.FS
+
+// MockGenerator implements the Generator interface for a mock server: a standalone Go
+// net/http program answering every operation with an example response
+type MockGenerator struct {
+	// funcs are the template functions set with SetTemplateFuncs
+	funcs template.FuncMap
+}
+
+// NewMockGenerator creates a new mock server generator
+func NewMockGenerator() *MockGenerator {
+	return &MockGenerator{}
+}
+
+// GetType returns the generator type identifier
+func (g *MockGenerator) GetType() string {
+	return "mock"
+}
+
+// SetTemplateFuncs adds funcs to the functions available to the templates. A function named
+// like a built-in helper replaces it.
+func (g *MockGenerator) SetTemplateFuncs(funcs template.FuncMap) {
+	g.funcs = funcs
+}
+
+// Route is the canned response the mock server sends for one operation
+type Route struct {
+	// Pattern is the net/http ServeMux pattern, e.g. "GET /users/{id}"
+	Pattern     string
+	OperationID string
+	Status      int
+	// ContentType is empty for responses without a body
+	ContentType string
+	Body        string
+}
+
+// Generate writes main.go, go.mod and README.md of a mock server for the client's operations
+func (g *MockGenerator) Generate(client config.Client, in ir.IR) error {
+	if err := os.MkdirAll(client.OutDir, 0o755); err != nil {
+		return err
+	}
+
+	routes, warnings := Routes(in)
+	for _, w := range warnings {
+		fmt.Fprintf(os.Stderr, "warning: %s\n", w)
+	}
+
+	funcMap := template.FuncMap{
+		"quote": strconv.Quote,
+	}
+	// User functions replace helpers of the same name
+	for k, v := range g.funcs {
+		funcMap[k] = v
+	}
+	data := map[string]any{"Client": client, "Routes": routes}
+	for _, file := range []struct{ template, name string }{
+		{"main.go.gotmpl", "main.go"},
+		{"go.mod.gotmpl", "go.mod"},
+		{"README.md.gotmpl", "README.md"},
+	} {
+		if err := renderFile(client, file.template, filepath.Join(client.OutDir, file.name), funcMap, data); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Routes builds the route of every operation, in IR order. Operations whose pattern repeats
+// or conflicts with an earlier route are skipped with a warning, since net/http would panic
+// when registering them.
+func Routes(in ir.IR) ([]Route, []string) {
+	defs := make(map[string]ir.IRModelDef, len(in.ModelDefs))
+	for _, md := range in.ModelDefs {
+		defs[md.Name] = md
+	}
+
+	var routes []Route
+	var warnings []string
+	mux := http.NewServeMux()
+	for _, service := range in.Services {
+		for _, op := range service.Operations {
+			route := Route{
+				Pattern:     strings.ToUpper(op.Method) + " " + routePath(op.Path),
+				OperationID: op.OperationID,
+				Status:      responseStatus(op.Response),
+				ContentType: op.Response.ContentType,
+			}
+			if route.ContentType != "" && route.Status != http.StatusNoContent {
+				body, err := responseBody(op.Response, defs)
+				if err != nil {
+					warnings = append(warnings, fmt.Sprintf("%s %s: cannot encode the example response: %v", op.Method, op.Path, err))
+					continue
+				}
+				route.Body = body
+			}
+			if err := register(mux, route.Pattern); err != nil {
+				warnings = append(warnings, fmt.Sprintf("%s %s: skipped in the mock server: %v", op.Method, op.Path, err))
+				continue
+			}
+			routes = append(routes, route)
+		}
+	}
+	return routes, warnings
+}
+
+// register adds pattern to mux, turning the panic net/http raises for invalid or
+// conflicting patterns into an error
+func register(mux *http.ServeMux, pattern string) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("%v", r)
+		}
+	}()
+	mux.HandleFunc(pattern, func(http.ResponseWriter, *http.Request) {})
+	return nil
+}
+
+// routePath converts an OpenAPI path to a ServeMux path. Parameters spanning a whole segment
+// ({id}) become wildcards named like Go identifiers; segments mixing text and parameters
+// ({id}.json) match any value. A trailing slash only matches itself.
+func routePath(path string) string {
+	segments := strings.Split(path, "/")
+	for i, seg := range segments {
+		start := strings.Index(seg, "{")
+		end := strings.Index(seg, "}")
+		if start < 0 || end < start {
+			continue
+		}
+		segments[i] = "{" + wildcardName(seg[start+1:end], i) + "}"
+	}
+	out := strings.Join(segments, "/")
+	if strings.HasSuffix(out, "/") {
+		out += "{$}"
+	}
+	return out
+}
+
+// wildcardName makes a parameter name a valid ServeMux wildcard (a Go identifier)
+func wildcardName(name string, index int) string {
+	var b strings.Builder
+	for i, r := range name {
+		switch {
+		case r == '_' || r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z':
+			b.WriteRune(r)
+		case r >= '0' && r <= '9' && i > 0:
+			b.WriteRune(r)
+		default:
+			b.WriteRune('_')
+		}
+	}
+	if b.Len() == 0 {
+		return "p" + strconv.Itoa(index)
+	}
+	return b.String()
+}
+
+// responseStatus returns the documented success status, 204 for an undocumented one without
+// a body and 200 otherwise (e.g. for ranges like 2XX)
+func responseStatus(resp ir.IRResponse) int {
+	if code, err := strconv.Atoi(resp.StatusCode); err == nil {
+		return code
+	}
+	if resp.ContentType == "" {
+		return http.StatusNoContent
+	}
+	return http.StatusOK
+}
+
+// responseBody renders the first documented example of the response, or a value shaped like
+// its schema. Bodies are JSON-encoded, except string values of non-JSON media types (text,
+// CSV, ...), which are sent as they are.
+func responseBody(resp ir.IRResponse, defs map[string]ir.IRModelDef) (string, error) {
+	var value any
+	if len(resp.Examples) > 0 {
+		value = resp.Examples[0]
+	} else {
+		value = ExampleValue(resp.Schema, defs)
+	}
+	if !strings.Contains(resp.ContentType, "json") {
+		if value == nil {
+			return "", nil
+		}
+		if s, ok := value.(string); ok {
+			return s, nil
+		}
+	}
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(value); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+// ExampleValue builds a value shaped like s: model and property examples and defaults win,
+// then the first enum value or variant, then a placeholder for the type. Recursive models
+// end in null.
+func ExampleValue(s ir.IRSchema, defs map[string]ir.IRModelDef) any {
+	return exampleValue(s, defs, map[string]bool{})
+}
+
+func exampleValue(s ir.IRSchema, defs map[string]ir.IRModelDef, seen map[string]bool) any {
+	switch s.Kind {
+	case ir.IRKindString:
+		return stringPlaceholder(s.Format)
+	case ir.IRKindInteger, ir.IRKindNumber:
+		return 0
+	case ir.IRKindBoolean:
+		return false
+	case ir.IRKindEnum:
+		if len(s.EnumRaw) > 0 {
+			return s.EnumRaw[0]
+		}
+		if len(s.EnumValues) > 0 {
+			return s.EnumValues[0]
+		}
+	case ir.IRKindArray:
+		if s.Items == nil {
+			return []any{}
+		}
+		return []any{exampleValue(*s.Items, defs, seen)}
+	case ir.IRKindObject:
+		out := map[string]any{}
+		for _, f := range s.Properties {
+			if v, ok := annotatedValue(f.Annotations); ok {
+				out[f.Name] = v
+			} else if f.Type != nil {
+				out[f.Name] = exampleValue(*f.Type, defs, seen)
+			}
+		}
+		return out
+	case ir.IRKindRef:
+		md, ok := defs[s.Ref]
+		if !ok || seen[s.Ref] {
+			return nil
+		}
+		if v, ok := annotatedValue(md.Annotations); ok {
+			return v
+		}
+		seen[s.Ref] = true
+		defer delete(seen, s.Ref)
+		return exampleValue(md.Schema, defs, seen)
+	case ir.IRKindOneOf:
+		return firstVariant(s.OneOf, defs, seen)
+	case ir.IRKindAnyOf:
+		return firstVariant(s.AnyOf, defs, seen)
+	case ir.IRKindAllOf:
+		// Object parts are merged; anything else is taken from the last part
+		var out any
+		merged := map[string]any{}
+		for _, sub := range s.AllOf {
+			if sub == nil {
+				continue
+			}
+			v := exampleValue(*sub, defs, seen)
+			if m, ok := v.(map[string]any); ok {
+				for k, fv := range m {
+					merged[k] = fv
+				}
+				out = merged
+			} else if v != nil {
+				out = v
+			}
+		}
+		return out
+	}
+	return nil
+}
+
+// annotatedValue returns the first example, else the default, of a model or property
+func annotatedValue(a ir.IRAnnotations) (any, bool) {
+	if len(a.Examples) > 0 {
+		return a.Examples[0], true
+	}
+	if a.Default != nil {
+		return a.Default, true
+	}
+	return nil, false
+}
+
+func firstVariant(variants []*ir.IRSchema, defs map[string]ir.IRModelDef, seen map[string]bool) any {
+	for _, sub := range variants {
+		if sub != nil {
+			return exampleValue(*sub, defs, seen)
+		}
+	}
+	return nil
+}
+
+// stringPlaceholder returns a valid value for common string formats
+func stringPlaceholder(format string) string {
+	switch format {
+	case "date-time":
+		return "2024-01-01T00:00:00Z"
+	case "date":
+		return "2024-01-01"
+	case "time":
+		return "00:00:00"
+	case "uuid":
+		return "00000000-0000-0000-0000-000000000000"
+	case "email":
+		return "user@example.com"
+	case "uri", "url":
+		return "https://example.com"
+	case "byte", "binary":
+		return ""
+	}
+	return "string"
+}
+
+func renderFile(client config.Client, templateName, targetPath string, funcMap template.FuncMap, data map[string]any) error {
+	// Check if file should be excluded
+	if client.ShouldExcludeFile(targetPath) {
+		return nil
+	}
+
+	tmplContent, err := templatesFS.ReadFile("templates/" + templateName)
+	if err != nil {
+		return fmt.Errorf("failed to read template %s: %w", templateName, err)
+	}
+
+	tmpl, err := template.New(templateName).Funcs(funcMap).Parse(string(tmplContent))
+	if err != nil {
+		return fmt.Errorf("failed to parse template %s: %w", templateName, err)
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString(utils.FileHeader(client.FileHeader, targetPath))
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return fmt.Errorf("failed to execute template %s: %w", templateName, err)
+	}
+
+	out := buf.Bytes()
+	if filepath.Ext(targetPath) == ".go" {
+		formatted, err := format.Source(out)
+		if err != nil {
+			return fmt.Errorf("failed to format %s: %w", targetPath, err)
+		}
+		out = formatted
+	}
+
+	if err := os.WriteFile(targetPath, out, 0o644); err != nil {
+		return fmt.Errorf("failed to write file %s: %w", targetPath, err)
+	}
+	return nil
+}
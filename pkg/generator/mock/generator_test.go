@@ -0,0 +1,103 @@
+package mock
+
+import (
+	"encoding/json"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/blimu-dev/sdk-gen/pkg/ir"
+)
+
+func TestRoutePath(t *testing.T) {
+	tests := []struct {
+		path     string
+		expected string
+	}{
+		{"/users", "/users"},
+		{"/users/{id}", "/users/{id}"},
+		{"/users/{user-id}/files/{2fa}", "/users/{user_id}/files/{_fa}"},
+		{"/files/{name}.json", "/files/{name}"},
+		{"/users/", "/users/{$}"},
+		{"/", "/{$}"},
+	}
+
+	for _, test := range tests {
+		if got := routePath(test.path); got != test.expected {
+			t.Errorf("routePath(%q) = %q, expected %q", test.path, got, test.expected)
+		}
+	}
+}
+
+func TestExampleValue(t *testing.T) {
+	str := ir.IRSchema{Kind: ir.IRKindString}
+	defs := map[string]ir.IRModelDef{
+		"User": {Name: "User", Schema: ir.IRSchema{Kind: ir.IRKindObject, Properties: []ir.IRField{
+			{Name: "id", Type: &ir.IRSchema{Kind: ir.IRKindString, Format: "uuid"}},
+			{Name: "name", Type: &str, Annotations: ir.IRAnnotations{Examples: []any{"Ada"}}},
+			{Name: "limit", Type: &ir.IRSchema{Kind: ir.IRKindInteger}, Annotations: ir.IRAnnotations{Default: 20}},
+			{Name: "status", Type: &ir.IRSchema{Kind: ir.IRKindRef, Ref: "Status"}},
+			{Name: "manager", Type: &ir.IRSchema{Kind: ir.IRKindRef, Ref: "User"}},
+		}}},
+		"Status": {Name: "Status", Schema: ir.IRSchema{Kind: ir.IRKindEnum, EnumBase: ir.IRKindString, EnumValues: []string{"active", "banned"}, EnumRaw: []any{"active", "banned"}}},
+		"Money":  {Name: "Money", Schema: ir.IRSchema{Kind: ir.IRKindObject}, Annotations: ir.IRAnnotations{Examples: []any{map[string]any{"amount": 5}}}},
+	}
+	tests := []struct {
+		name     string
+		schema   ir.IRSchema
+		expected string
+	}{
+		{"date", ir.IRSchema{Kind: ir.IRKindString, Format: "date"}, `"2024-01-01"`},
+		{"model with a recursive field", ir.IRSchema{Kind: ir.IRKindRef, Ref: "User"}, `{"id":"00000000-0000-0000-0000-000000000000","limit":20,"manager":null,"name":"Ada","status":"active"}`},
+		{"model example", ir.IRSchema{Kind: ir.IRKindArray, Items: &ir.IRSchema{Kind: ir.IRKindRef, Ref: "Money"}}, `[{"amount":5}]`},
+		{"first variant", ir.IRSchema{Kind: ir.IRKindOneOf, OneOf: []*ir.IRSchema{{Kind: ir.IRKindBoolean}, &str}}, `false`},
+		{
+			"merged parts",
+			ir.IRSchema{Kind: ir.IRKindAllOf, AllOf: []*ir.IRSchema{
+				{Kind: ir.IRKindObject, Properties: []ir.IRField{{Name: "a", Type: &str}}},
+				{Kind: ir.IRKindObject, Properties: []ir.IRField{{Name: "b", Type: &ir.IRSchema{Kind: ir.IRKindNumber}}}},
+			}},
+			`{"a":"string","b":0}`,
+		},
+		{"unknown", ir.IRSchema{Kind: ir.IRKindUnknown}, `null`},
+	}
+
+	for _, test := range tests {
+		got, err := json.Marshal(ExampleValue(test.schema, defs))
+		if err != nil {
+			t.Fatalf("%s: %v", test.name, err)
+		}
+		if string(got) != test.expected {
+			t.Errorf("%s: ExampleValue() = %s, expected %s", test.name, got, test.expected)
+		}
+	}
+}
+
+func TestRoutes(t *testing.T) {
+	user := ir.IRSchema{Kind: ir.IRKindObject, Properties: []ir.IRField{{Name: "id", Type: &ir.IRSchema{Kind: ir.IRKindString}}}}
+	in := ir.IR{Services: []ir.IRService{{Tag: "users", Operations: []ir.IROperation{
+		{OperationID: "getUser", Method: "GET", Path: "/users/{id}", Response: ir.IRResponse{StatusCode: "200", ContentType: "application/json", Schema: user}},
+		{OperationID: "getUserByName", Method: "GET", Path: "/users/{name}", Response: ir.IRResponse{StatusCode: "200", ContentType: "application/json", Schema: user}},
+		{OperationID: "createUser", Method: "POST", Path: "/users", Response: ir.IRResponse{StatusCode: "201", ContentType: "application/json", Examples: []any{map[string]any{"id": "u1"}}}},
+		{OperationID: "deleteUser", Method: "delete", Path: "/users/{id}"},
+		{OperationID: "exportUsers", Method: "GET", Path: "/users/export", Response: ir.IRResponse{StatusCode: "2XX", ContentType: "text/csv", Schema: ir.IRSchema{Kind: ir.IRKindString}}},
+	}}}}
+
+	routes, warnings := Routes(in)
+	got := make([]string, len(routes))
+	for i, r := range routes {
+		got[i] = strings.Join([]string{r.Pattern, r.OperationID, r.ContentType, strings.TrimSpace(r.Body)}, " | ") + " | " + strconv.Itoa(r.Status)
+	}
+	expected := []string{
+		"GET /users/{id} | getUser | application/json | {\n  \"id\": \"string\"\n} | 200",
+		"POST /users | createUser | application/json | {\n  \"id\": \"u1\"\n} | 201",
+		"DELETE /users/{id} | deleteUser |  |  | 204",
+		"GET /users/export | exportUsers | text/csv | string | 200",
+	}
+	if strings.Join(got, "\n") != strings.Join(expected, "\n") {
+		t.Errorf("Routes():\n%s\n\nexpected:\n%s", strings.Join(got, "\n"), strings.Join(expected, "\n"))
+	}
+	if len(warnings) != 1 || !strings.Contains(warnings[0], "GET /users/{name}: skipped") {
+		t.Errorf("expected a warning for the conflicting route, got %v", warnings)
+	}
+}
@@ -0,0 +1,68 @@
+package generator
+
+import (
+	"strings"
+
+	"github.com/blimu-dev/sdk-gen/pkg/ir"
+)
+
+// applyModelNameAffixes returns a copy of the IR where every ModelDef name, and every reference
+// to it, carries the given prefix and suffix. The input IR is shared between clients, so schemas
+// are copied rather than modified in place.
+func applyModelNameAffixes(in ir.IR, prefix, suffix string) ir.IR {
+	if prefix == "" && suffix == "" {
+		return in
+	}
+
+	names := make(map[string]string, len(in.ModelDefs))
+	for _, md := range in.ModelDefs {
+		names[md.Name] = prefix + md.Name + suffix
+	}
+	r := modelRenamer{names: names}
+
+	out := mapSchemas(in, r.schema)
+	for i := range out.ModelDefs {
+		out.ModelDefs[i].Name = names[out.ModelDefs[i].Name]
+	}
+	return out
+}
+
+// modelRenamer rewrites model references according to a name mapping
+type modelRenamer struct {
+	names map[string]string
+}
+
+// schema renames the reference and discriminator mapping of a single schema; mapSchemas
+// takes care of nested schemas
+func (r modelRenamer) schema(s ir.IRSchema) ir.IRSchema {
+	if s.Kind == ir.IRKindRef {
+		if renamed, ok := r.names[s.Ref]; ok {
+			s.Ref = renamed
+		}
+	}
+
+	if s.Discriminator != nil && len(s.Discriminator.Mapping) > 0 {
+		disc := *s.Discriminator
+		disc.Mapping = make(map[string]string, len(s.Discriminator.Mapping))
+		for value, target := range s.Discriminator.Mapping {
+			disc.Mapping[value] = r.mappingTarget(target)
+		}
+		s.Discriminator = &disc
+	}
+	return s
+}
+
+// mappingTarget renames a discriminator mapping target, which may be a full component ref or a
+// bare schema name, keeping its original form
+func (r modelRenamer) mappingTarget(target string) string {
+	if name, ok := strings.CutPrefix(target, "#/components/schemas/"); ok {
+		if renamed, ok := r.names[name]; ok {
+			return "#/components/schemas/" + renamed
+		}
+		return target
+	}
+	if renamed, ok := r.names[target]; ok {
+		return renamed
+	}
+	return target
+}
@@ -0,0 +1,171 @@
+package generator
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/blimu-dev/sdk-gen/pkg/config"
+	"github.com/blimu-dev/sdk-gen/pkg/ir"
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+const modelNamesSpec = `openapi: 3.0.3
+info:
+  title: Pets
+  version: "1.0"
+paths:
+  /pets/{id}:
+    put:
+      operationId: updatePet
+      parameters:
+        - name: id
+          in: path
+          required: true
+          schema:
+            $ref: '#/components/schemas/PetId'
+      requestBody:
+        content:
+          application/json:
+            schema:
+              $ref: '#/components/schemas/Pet'
+      responses:
+        "200":
+          description: ok
+          content:
+            application/json:
+              schema:
+                type: array
+                items:
+                  $ref: '#/components/schemas/Pet'
+components:
+  schemas:
+    PetId:
+      type: string
+    Pet:
+      oneOf:
+        - $ref: '#/components/schemas/Cat'
+        - $ref: '#/components/schemas/Dog'
+      discriminator:
+        propertyName: kind
+        mapping:
+          cat: '#/components/schemas/Cat'
+          dog: Dog
+    Cat:
+      type: object
+      properties:
+        kind:
+          type: string
+        friends:
+          type: object
+          additionalProperties:
+            $ref: '#/components/schemas/Dog'
+    Dog:
+      type: object
+      properties:
+        kind:
+          type: string
+`
+
+// collectIRRefs gathers every model reference and discriminator mapping target in the IR
+func collectIRRefs(in ir.IR) []string {
+	var refs []string
+	var walk func(s *ir.IRSchema)
+	walk = func(s *ir.IRSchema) {
+		if s == nil {
+			return
+		}
+		if s.Kind == ir.IRKindRef {
+			refs = append(refs, s.Ref)
+		}
+		walk(s.Items)
+		walk(s.AdditionalProperties)
+		walk(s.Not)
+		for _, list := range [][]*ir.IRSchema{s.OneOf, s.AnyOf, s.AllOf} {
+			for _, sub := range list {
+				walk(sub)
+			}
+		}
+		for _, f := range s.Properties {
+			walk(f.Type)
+		}
+		if s.Discriminator != nil {
+			for _, target := range s.Discriminator.Mapping {
+				refs = append(refs, strings.TrimPrefix(target, "#/components/schemas/"))
+			}
+		}
+	}
+	for _, md := range in.ModelDefs {
+		walk(&md.Schema)
+	}
+	for _, svc := range in.Services {
+		for _, op := range svc.Operations {
+			for _, p := range append(op.PathParams, op.QueryParams...) {
+				walk(&p.Schema)
+			}
+			if op.RequestBody != nil {
+				walk(&op.RequestBody.Schema)
+			}
+			walk(&op.Response.Schema)
+		}
+	}
+	return refs
+}
+
+func TestModelNameAffixes(t *testing.T) {
+	doc, err := openapi3.NewLoader().LoadFromData([]byte(modelNamesSpec))
+	if err != nil {
+		t.Fatalf("load spec: %v", err)
+	}
+	fullIR, err := BuildIR(doc)
+	if err != nil {
+		t.Fatalf("BuildIR: %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		prefix   string
+		suffix   string
+		expected []string
+	}{
+		{name: "no affixes", expected: []string{"Cat", "Dog", "Pet", "PetId"}},
+		{name: "prefix", prefix: "Api", expected: []string{"ApiCat", "ApiDog", "ApiPet", "ApiPetId"}},
+		{name: "suffix", suffix: "Model", expected: []string{"CatModel", "DogModel", "PetModel", "PetIdModel"}},
+		{name: "both", prefix: "Api", suffix: "Dto", expected: []string{"ApiCatDto", "ApiDogDto", "ApiPetDto", "ApiPetIdDto"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := config.Client{ModelNamePrefix: tt.prefix, ModelNameSuffix: tt.suffix}
+			out, err := (&Service{}).filterIR(fullIR, client)
+			if err != nil {
+				t.Fatalf("filterIR: %v", err)
+			}
+
+			defined := map[string]bool{}
+			var names []string
+			for _, md := range out.ModelDefs {
+				defined[md.Name] = true
+				names = append(names, md.Name)
+			}
+			if strings.Join(names, ",") != strings.Join(tt.expected, ",") {
+				t.Errorf("model names = %v, expected %v", names, tt.expected)
+			}
+			refs := collectIRRefs(out)
+			if len(refs) == 0 {
+				t.Fatal("expected references in the IR")
+			}
+			for _, ref := range refs {
+				if !defined[ref] {
+					t.Errorf("dangling reference %q", ref)
+				}
+			}
+		})
+	}
+
+	// The shared IR must not be modified by a client's affixes
+	for _, ref := range collectIRRefs(fullIR) {
+		if strings.HasPrefix(ref, "Api") {
+			t.Errorf("full IR was modified: found %q", ref)
+		}
+	}
+}
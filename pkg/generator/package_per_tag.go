@@ -0,0 +1,68 @@
+package generator
+
+import (
+	"path/filepath"
+	"strings"
+
+	"github.com/blimu-dev/sdk-gen/pkg/config"
+	"github.com/blimu-dev/sdk-gen/pkg/ir"
+	"github.com/blimu-dev/sdk-gen/pkg/utils"
+)
+
+// tagPackage is the client and IR of one package of a packagePerTag client
+type tagPackage struct {
+	Client config.Client
+	IR     ir.IR
+}
+
+// splitPackagesPerTag splits the filtered IR of a packagePerTag client into one package per
+// service with operations. Each package gets the service's operations, their callbacks and
+// the models they use; webhooks belong to no tag and are left out.
+func splitPackagesPerTag(client config.Client, in ir.IR) []tagPackage {
+	var packages []tagPackage
+	for _, service := range in.Services {
+		if len(service.Operations) == 0 {
+			continue
+		}
+		ops := map[string]bool{}
+		for _, op := range service.Operations {
+			ops[operationFilterKey(op)] = true
+		}
+		var webhooks []ir.IRWebhook
+		for _, wh := range in.Webhooks {
+			if wh.Operation != "" && ops[wh.Operation] {
+				webhooks = append(webhooks, wh)
+			}
+		}
+
+		tagIR := in
+		tagIR.Services = []ir.IRService{service}
+		tagIR.Webhooks = webhooks
+		tagIR.ModelDefs = filterUnusedModelDefs(tagIR, in.ModelDefs)
+		packages = append(packages, tagPackage{Client: tagClient(client, service.Tag), IR: tagIR})
+	}
+	return packages
+}
+
+// tagClient returns the client generating the package of a tag: written to <outDir>/<tag>,
+// named <name><Tag>, with the tag appended to the package name (and the Go module path) in
+// the language's style, e.g. @acme/api-billing, acme_api_billing or acmeapibilling
+func tagClient(client config.Client, tag string) config.Client {
+	c := client
+	c.PackagePerTag = false
+	dir := utils.ToKebabCase(tag)
+	c.OutDir = filepath.Join(client.OutDir, dir)
+	c.Name = client.Name + utils.ToPascalCase(tag)
+	switch client.Type {
+	case "typescript":
+		c.PackageName = client.PackageName + "-" + dir
+	case "go":
+		c.PackageName = client.PackageName + strings.ReplaceAll(utils.ToSnakeCase(tag), "_", "")
+		if client.ModuleName != "" {
+			c.ModuleName = client.ModuleName + "/" + dir
+		}
+	default:
+		c.PackageName = client.PackageName + "_" + utils.ToSnakeCase(tag)
+	}
+	return c
+}
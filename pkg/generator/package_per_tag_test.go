@@ -0,0 +1,120 @@
+package generator
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/blimu-dev/sdk-gen/pkg/config"
+	"github.com/blimu-dev/sdk-gen/pkg/generator/golang"
+	"github.com/blimu-dev/sdk-gen/pkg/ir"
+)
+
+func TestSplitPackagesPerTag(t *testing.T) {
+	ref := func(name string) ir.IRSchema { return ir.IRSchema{Kind: ir.IRKindRef, Ref: name} }
+	in := ir.IR{
+		Services: []ir.IRService{
+			{Tag: "misc"},
+			{Tag: "billing", Operations: []ir.IROperation{
+				{OperationID: "getInvoice", Method: "GET", Path: "/invoices/{id}", Tag: "billing", Response: ir.IRResponse{Schema: ref("Invoice")}},
+			}},
+			{Tag: "users", Operations: []ir.IROperation{
+				{OperationID: "getUser", Method: "GET", Path: "/users/{id}", Tag: "users", Response: ir.IRResponse{Schema: ref("User")}},
+			}},
+		},
+		ModelDefs: []ir.IRModelDef{
+			{Name: "Invoice", Schema: ir.IRSchema{Kind: ir.IRKindObject, Properties: []ir.IRField{{Name: "owner", Type: &ir.IRSchema{Kind: ir.IRKindRef, Ref: "User"}}}}},
+			{Name: "User", Schema: ir.IRSchema{Kind: ir.IRKindObject}},
+		},
+		Webhooks: []ir.IRWebhook{
+			{Name: "invoicePaid", Operation: "getInvoice"},
+			{Name: "userCreated"},
+		},
+	}
+	client := config.Client{Type: "typescript", OutDir: "sdk", PackageName: "@acme/api", Name: "Acme"}
+
+	type summary struct {
+		outDir, packageName, name string
+		models, webhooks          []string
+	}
+	var got []summary
+	for _, pkg := range splitPackagesPerTag(client, in) {
+		s := summary{outDir: pkg.Client.OutDir, packageName: pkg.Client.PackageName, name: pkg.Client.Name}
+		for _, md := range pkg.IR.ModelDefs {
+			s.models = append(s.models, md.Name)
+		}
+		for _, wh := range pkg.IR.Webhooks {
+			s.webhooks = append(s.webhooks, wh.Name)
+		}
+		if len(pkg.IR.Services) != 1 || pkg.Client.PackagePerTag {
+			t.Errorf("%s: expected a single service and no further split", pkg.Client.Name)
+		}
+		got = append(got, s)
+	}
+	expected := []summary{
+		{filepath.Join("sdk", "billing"), "@acme/api-billing", "AcmeBilling", []string{"Invoice", "User"}, []string{"invoicePaid"}},
+		{filepath.Join("sdk", "users"), "@acme/api-users", "AcmeUsers", []string{"User"}, nil},
+	}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("packages = %+v, expected %+v", got, expected)
+	}
+}
+
+func TestTagClientPackageNames(t *testing.T) {
+	tests := []struct {
+		client                  config.Client
+		packageName, moduleName string
+	}{
+		{config.Client{Type: "typescript", PackageName: "acme-sdk"}, "acme-sdk-admin-users", ""},
+		{config.Client{Type: "python", PackageName: "acme_sdk"}, "acme_sdk_admin_users", ""},
+		{config.Client{Type: "go", PackageName: "acme", ModuleName: "github.com/acme/sdk"}, "acmeadminusers", "github.com/acme/sdk/admin-users"},
+		{config.Client{Type: "go", PackageName: "acme"}, "acmeadminusers", ""},
+	}
+	for _, test := range tests {
+		c := tagClient(test.client, "adminUsers")
+		if c.PackageName != test.packageName || c.ModuleName != test.moduleName {
+			t.Errorf("%s: package %q, module %q, expected %q, %q", test.client.Type, c.PackageName, c.ModuleName, test.packageName, test.moduleName)
+		}
+	}
+}
+
+func TestPackagePerTagGoPackagesBuild(t *testing.T) {
+	if testing.Short() {
+		t.Skip("compiles the generated packages")
+	}
+	goTool, err := exec.LookPath("go")
+	if err != nil {
+		t.Skip("go is not installed")
+	}
+
+	str := ir.IRSchema{Kind: ir.IRKindString}
+	pet := ir.IRSchema{Kind: ir.IRKindRef, Ref: "Pet"}
+	op := func(tag, id, path string) ir.IROperation {
+		return ir.IROperation{OperationID: id, Method: "GET", Path: path, Tag: tag, Response: ir.IRResponse{TypeTS: "Pet", Schema: pet, StatusCode: "200", ContentType: "application/json"}}
+	}
+	listPets := op("pets", "listPets", "/pets")
+	listPets.QueryParams = []ir.IRParam{{Name: "species", Schema: str}}
+	in := ir.IR{
+		Services: []ir.IRService{
+			{Tag: "pets", Operations: []ir.IROperation{listPets}},
+			{Tag: "users", Operations: []ir.IROperation{op("users", "getOwner", "/owner")}},
+			{Tag: "misc", Operations: []ir.IROperation{op("misc", "ping", "/ping")}},
+		},
+		ModelDefs: []ir.IRModelDef{{Name: "Pet", Schema: ir.IRSchema{Kind: ir.IRKindObject, Properties: []ir.IRField{{Name: "name", Type: &str}}}}},
+	}
+	client := config.Client{Type: "go", OutDir: t.TempDir(), PackageName: "pg", ModuleName: "example.com/pg", Name: "Pg"}
+
+	for _, pkg := range splitPackagesPerTag(client, in) {
+		if err := golang.NewGoGenerator().Generate(pkg.Client, pkg.IR); err != nil {
+			t.Fatal(err)
+		}
+		cmd := exec.Command(goTool, "vet", "./...")
+		cmd.Dir = pkg.Client.OutDir
+		cmd.Env = append(os.Environ(), "GOFLAGS=-mod=mod")
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Errorf("go vet in %s: %v\n%s", filepath.Base(pkg.Client.OutDir), err, out)
+		}
+	}
+}
@@ -3,6 +3,8 @@ package python
 import (
 	"embed"
 	"fmt"
+	"io"
+	"log/slog"
 	"os"
 	"path/filepath"
 	"regexp"
@@ -13,13 +15,19 @@ import (
 
 	"github.com/blimu-dev/sdk-gen/pkg/config"
 	"github.com/blimu-dev/sdk-gen/pkg/ir"
+	"github.com/blimu-dev/sdk-gen/pkg/utils"
 )
 
 //go:embed templates/*
 var templatesFS embed.FS
 
 // PythonGenerator implements the Generator interface for Python
-type PythonGenerator struct{}
+type PythonGenerator struct {
+	// funcs are the template functions set with SetTemplateFuncs
+	funcs template.FuncMap
+	// logger receives warnings, see SetLogger
+	logger *slog.Logger
+}
 
 // NewPythonGenerator creates a new Python generator
 func NewPythonGenerator() *PythonGenerator {
@@ -31,15 +39,34 @@ func (g *PythonGenerator) GetType() string {
 	return "python"
 }
 
+// SetTemplateFuncs adds funcs to the functions available to the templates. A function named
+// like a built-in helper replaces it.
+func (g *PythonGenerator) SetTemplateFuncs(funcs template.FuncMap) {
+	g.funcs = funcs
+}
+
+// SetLogger sets the logger warnings found while generating, such as renamed methods, are
+// reported to
+func (g *PythonGenerator) SetLogger(logger *slog.Logger) {
+	g.logger = logger
+}
+
 // Generate creates a Python SDK from the given configuration and IR
 func (g *PythonGenerator) Generate(client config.Client, in ir.IR) error {
 	// Ensure directories
 	srcDir := filepath.Join(client.OutDir, client.PackageName)
 	servicesDir := filepath.Join(srcDir, "services")
-	if err := os.MkdirAll(servicesDir, 0o755); err != nil {
+	emitClient := client.ShouldEmitClient()
+	if err := os.MkdirAll(srcDir, 0o755); err != nil {
 		return err
 	}
 
+	typeOpts := newTypeOptions(client)
+	methodNames, warnings := utils.NewMethodNames(in.Services, func(op ir.IROperation) string { return resolveMethodName(client, op) })
+	utils.LogWarnings(g.logger, client.Name, warnings)
+	methodName := methodNames.Get
+	imports := typeImports(in)
+
 	funcMap := template.FuncMap{
 		"snake":             toSnakeCase,
 		"pascal":            toPascalCase,
@@ -47,18 +74,22 @@ func (g *PythonGenerator) Generate(client config.Client, in ir.IR) error {
 		"kebab":             toKebabCase,
 		"serviceName":       func(tag string) string { return toPascalCase(tag) + "Service" },
 		"serviceVar":        func(tag string) string { return toSnakeCase(tag) },
-		"fileBase":          func(tag string) string { return strings.ToLower(toSnakeCase(tag)) },
-		"methodName":        func(op ir.IROperation) string { return resolveMethodName(client, op) },
+		"fileBase":          func(tag string) string { return serviceModule(client, tag) },
+		"methodName":        methodName,
+		"exampleLines":      func(v any) []string { return utils.ExampleLines(v, "    ") },
 		"pathTemplate":      func(op ir.IROperation) string { return buildPathTemplate(op) },
 		"pathParamsInOrder": func(op ir.IROperation) []ir.IRParam { return orderPathParams(op) },
-		"methodSignature":   func(op ir.IROperation) []string { return buildMethodSignature(op, resolveMethodName(client, op)) },
+		"methodSignature": func(op ir.IROperation) []string {
+			return buildMethodSignature(op, methodName(op), typeOpts)
+		},
+		"resultType": func(op ir.IROperation) string { return pyResultType(op, typeOpts) },
 		"pyType": func(x any) string {
 			switch v := x.(type) {
 			case ir.IRSchema:
-				return schemaToPyType(v)
+				return schemaToPyType(v, typeOpts)
 			case *ir.IRSchema:
 				if v != nil {
-					return schemaToPyType(*v)
+					return schemaToPyType(*v, typeOpts)
 				}
 				return "Any"
 			default:
@@ -68,17 +99,20 @@ func (g *PythonGenerator) Generate(client config.Client, in ir.IR) error {
 		"pyTypeForService": func(x any) string {
 			switch v := x.(type) {
 			case ir.IRSchema:
-				return schemaToPyTypeForService(v)
+				return schemaToPyTypeForService(v, typeOpts)
 			case *ir.IRSchema:
 				if v != nil {
-					return schemaToPyTypeForService(*v)
+					return schemaToPyTypeForService(*v, typeOpts)
 				}
 				return "Any"
 			default:
 				return "Any"
 			}
 		},
-		"pyFieldType":    func(field ir.IRField) string { return fieldToPyType(field) },
+		"pyFieldType":    func(field ir.IRField) string { return fieldToPyType(field, typeOpts) },
+		"pyFieldName":    func(field ir.IRField) string { return pyFieldName(field.Name, client.PreservePropertyNames) },
+		"pyFieldValue":   func(field ir.IRField) string { return pyFieldValue(field, client.PreservePropertyNames) },
+		"pyModelConfig":  func(s ir.IRSchema) string { return pyModelConfig(s, client.PreservePropertyNames) },
 		"isOptional":     func(field ir.IRField) bool { return !field.Required },
 		"hasPathParams":  func(op ir.IROperation) bool { return len(op.PathParams) > 0 },
 		"hasQueryParams": func(op ir.IROperation) bool { return len(op.QueryParams) > 0 },
@@ -93,7 +127,15 @@ func (g *PythonGenerator) Generate(client config.Client, in ir.IR) error {
 		"httpMethodUpper":     func(method string) string { return strings.ToUpper(method) },
 		"isStringEnum":        func(schema ir.IRSchema) bool { return schema.Kind == "enum" && schema.EnumBase == "string" },
 		"enumValues":          func(schema ir.IRSchema) []string { return schema.EnumValues },
+		"enumLiterals":        enumPyLiterals,
+		"enumMembers":         enumPyMembers,
 		"formatPythonComment": func(s string) string { return formatPythonComment(s) },
+		"typeImports":         func() []string { return imports },
+		"serviceImports":      func() []pyImport { return serviceImports(client, in) },
+		"packageExports":      func() pyExports { return packageExports(client, in) },
+		"emitClient":          func() bool { return emitClient },
+		"environments":        func() []utils.Environment { return utils.Environments(client.Environments, in.Servers) },
+		"packageVersion":      client.PackageVersion,
 		// Namespace helper functions
 		"groupByNamespace": func(services []ir.IRService) map[string][]ir.IRService {
 			namespaces := make(map[string][]ir.IRService)
@@ -129,10 +171,9 @@ func (g *PythonGenerator) Generate(client config.Client, in ir.IR) error {
 	for k, v := range sprig.FuncMap() {
 		funcMap[k] = v
 	}
-
-	// client.py
-	if err := renderFile(client, "client.py.gotmpl", filepath.Join(srcDir, "client.py"), funcMap, map[string]any{"Client": client, "IR": in}); err != nil {
-		return err
+	// User functions come last and replace helpers of the same name
+	for k, v := range g.funcs {
+		funcMap[k] = v
 	}
 
 	// __init__.py
@@ -145,26 +186,24 @@ func (g *PythonGenerator) Generate(client config.Client, in ir.IR) error {
 		return err
 	}
 
-	// services per tag
-	for _, s := range in.Services {
-		target := filepath.Join(servicesDir, fmt.Sprintf("%s.py", strings.ToLower(toSnakeCase(s.Tag))))
-		if err := renderFile(client, "service.py.gotmpl", target, funcMap, map[string]any{"Client": client, "Service": s}); err != nil {
+	// client.py and the services are left out of types-only packages
+	if emitClient {
+		if err := g.generateClient(client, in, srcDir, servicesDir, funcMap); err != nil {
 			return err
 		}
 	}
 
-	// services/__init__.py
-	if err := renderFile(client, "services_init.py.gotmpl", filepath.Join(servicesDir, "__init__.py"), funcMap, map[string]any{"Client": client, "IR": in}); err != nil {
-		return err
-	}
-
 	// pyproject.toml
 	if err := renderFile(client, "pyproject.toml.gotmpl", filepath.Join(client.OutDir, "pyproject.toml"), funcMap, map[string]any{"Client": client}); err != nil {
 		return err
 	}
 
 	// README.md
-	if err := renderFile(client, "README.md.gotmpl", filepath.Join(client.OutDir, "README.md"), funcMap, map[string]any{"Client": client, "IR": in}); err != nil {
+	readme := "README.md.gotmpl"
+	if !emitClient {
+		readme = "README.types.md.gotmpl"
+	}
+	if err := renderFile(client, readme, filepath.Join(client.OutDir, "README.md"), funcMap, map[string]any{"Client": client, "IR": in}); err != nil {
 		return err
 	}
 
@@ -176,6 +215,42 @@ func (g *PythonGenerator) Generate(client config.Client, in ir.IR) error {
 	return nil
 }
 
+// generateClient renders client.py, the services and, when enabled, their async variants
+func (g *PythonGenerator) generateClient(client config.Client, in ir.IR, srcDir, servicesDir string, funcMap template.FuncMap) error {
+	if err := os.MkdirAll(servicesDir, 0o755); err != nil {
+		return err
+	}
+
+	// client.py
+	if err := renderFile(client, "client.py.gotmpl", filepath.Join(srcDir, "client.py"), funcMap, map[string]any{"Client": client, "IR": in}); err != nil {
+		return err
+	}
+
+	// services per tag
+	for _, s := range in.Services {
+		target := filepath.Join(servicesDir, serviceModule(client, s.Tag)+".py")
+		if err := renderFile(client, "service.py.gotmpl", target, funcMap, map[string]any{"Client": client, "Service": s, "Async": false}); err != nil {
+			return err
+		}
+	}
+
+	// async client and services, generated alongside the sync ones
+	if client.AsyncClient {
+		if err := renderFile(client, "async_client.py.gotmpl", filepath.Join(srcDir, "async_client.py"), funcMap, map[string]any{"Client": client, "IR": in}); err != nil {
+			return err
+		}
+		for _, s := range in.Services {
+			target := filepath.Join(servicesDir, asyncServiceModule(client, s.Tag)+".py")
+			if err := renderFile(client, "service.py.gotmpl", target, funcMap, map[string]any{"Client": client, "Service": s, "Async": true}); err != nil {
+				return err
+			}
+		}
+	}
+
+	// services/__init__.py
+	return renderFile(client, "services_init.py.gotmpl", filepath.Join(servicesDir, "__init__.py"), funcMap, map[string]any{"Client": client, "IR": in})
+}
+
 // renderFile renders a template file to the target path
 func renderFile(client config.Client, templateName, targetPath string, funcMap template.FuncMap, data map[string]any) error {
 	// Check if file should be excluded
@@ -199,6 +274,10 @@ func renderFile(client config.Client, templateName, targetPath string, funcMap t
 	}
 	defer file.Close()
 
+	if _, err := io.WriteString(file, utils.FileHeader(client.FileHeader, targetPath)); err != nil {
+		return fmt.Errorf("failed to write file %s: %w", targetPath, err)
+	}
+
 	if err := tmpl.Execute(file, data); err != nil {
 		return fmt.Errorf("failed to execute template %s: %w", templateName, err)
 	}
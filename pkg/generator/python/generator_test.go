@@ -0,0 +1,299 @@
+package python
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/blimu-dev/sdk-gen/pkg/config"
+	"github.com/blimu-dev/sdk-gen/pkg/ir"
+)
+
+// httpxStub stands in for the parts of httpx the generated client.py uses, so the request
+// loop runs against a MockTransport without the dependency or a network
+const httpxStub = `import json as _json
+from urllib.parse import urlencode
+
+
+class HTTPError(Exception):
+    pass
+
+
+class TransportError(HTTPError):
+    pass
+
+
+class ConnectError(TransportError):
+    pass
+
+
+class HTTPStatusError(HTTPError):
+    def __init__(self, message, *, request, response):
+        super().__init__(message)
+        self.request = request
+        self.response = response
+
+
+class Limits:
+    def __init__(self, **kwargs):
+        self.kwargs = kwargs
+
+
+class Cookies(dict):
+    def set(self, name, value):
+        self[name] = value
+
+
+class Request:
+    def __init__(self, method, url, headers=None):
+        self.method = method
+        self.url = url
+        self.headers = dict(headers or {})
+
+
+class Response:
+    def __init__(self, status_code, headers=None, json=None, request=None):
+        self.status_code = status_code
+        self.headers = {k.lower(): v for k, v in (headers or {}).items()}
+        self.content = b"" if json is None else _json.dumps(json).encode()
+        if json is not None:
+            self.headers.setdefault("content-type", "application/json")
+        self.request = request
+
+    @property
+    def text(self):
+        return self.content.decode()
+
+    def json(self):
+        return _json.loads(self.content)
+
+    def close(self):
+        pass
+
+    def raise_for_status(self):
+        if self.status_code >= 400:
+            raise HTTPStatusError(str(self.status_code), request=self.request, response=self)
+        return self
+
+
+class MockTransport:
+    def __init__(self, handler):
+        self.handler = handler
+
+
+class Client:
+    def __init__(self, base_url="", transport=None, **kwargs):
+        self.base_url = base_url
+        self.transport = transport
+        self.cookies = Cookies()
+
+    def request(self, method, url, params=None, json=None, data=None, headers=None, **kwargs):
+        if "://" not in url:
+            url = self.base_url.rstrip("/") + url
+        if params:
+            url += "?" + urlencode(params, doseq=True)
+        request = Request(method, url, headers)
+        try:
+            response = self.transport.handler(request)
+        except TransportError as exc:
+            exc.request = request
+            raise
+        response.request = request
+        return response
+
+    def close(self):
+        pass
+`
+
+const pydanticCoreStub = `def to_jsonable_python(value, **kwargs):
+    return value
+`
+
+// runClientScript generates a client for in and runs script with the generated client.py
+// importable as client, next to the httpx stand-in
+func runClientScript(t *testing.T, client config.Client, in ir.IR, script string) {
+	t.Helper()
+	python, err := exec.LookPath("python3")
+	if err != nil {
+		t.Skip("python3 is not installed")
+	}
+	dir := t.TempDir()
+	client.Type, client.OutDir = "python", dir
+	if err := NewPythonGenerator().Generate(client, in); err != nil {
+		t.Fatal(err)
+	}
+	stubs := filepath.Join(dir, "stubs")
+	if err := os.MkdirAll(stubs, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	for name, content := range map[string]string{"httpx.py": httpxStub, "pydantic_core.py": pydanticCoreStub} {
+		if err := os.WriteFile(filepath.Join(stubs, name), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	cmd := exec.Command(python, "-c", script)
+	cmd.Env = append(os.Environ(), "PYTHONPATH="+stubs+string(os.PathListSeparator)+filepath.Join(dir, client.PackageName), "PYTHONDONTWRITEBYTECODE=1")
+	if out, err := cmd.CombinedOutput(); err != nil {
+		t.Errorf("python3: %v\n%s", err, out)
+	}
+}
+
+// retryScript sends requests through CoreClient against canned responses, recording the
+// delays slept between attempts
+const retryScript = `import datetime
+import email.utils
+import httpx
+import client
+
+delays = []
+client.time.sleep = delays.append
+
+
+def send(method, responses, **config):
+    """Send one request answered by responses in turn; returns the methods sent and the result."""
+    calls = []
+
+    def handler(request):
+        calls.append(request.method)
+        response = responses[len(calls) - 1]
+        if isinstance(response, Exception):
+            raise response
+        status, headers = response
+        return httpx.Response(status, headers=headers, json={"ok": True})
+
+    delays.clear()
+    core = client.CoreClient(client.ClientConfig(base_url="https://api.test", transport=httpx.MockTransport(handler), **config))
+    try:
+        return calls, core.request(method, "/items")
+    except httpx.HTTPError as exc:
+        return calls, exc
+
+
+# Exponential backoff: backoff_factor * 2**attempt
+calls, result = send("GET", [(500, {}), (502, {}), (200, {})], max_retries=3, backoff_factor=0.5)
+assert result == {"ok": True} and calls == ["GET"] * 3 and delays == [0.5, 1.0], (calls, result, delays)
+
+# Network errors are retried as well
+calls, result = send("GET", [httpx.ConnectError("refused"), (200, {})], max_retries=1, backoff_factor=0.25)
+assert result == {"ok": True} and delays == [0.25], (result, delays)
+
+# The last response is raised once max_retries retries were made
+calls, result = send("GET", [(503, {}), (503, {}), (503, {})], max_retries=2)
+assert isinstance(result, httpx.HTTPStatusError) and result.response.status_code == 503 and len(calls) == 3, (calls, result)
+
+# Statuses that are not retryable and the default of no retries
+calls, result = send("GET", [(404, {}), (200, {})], max_retries=2)
+assert isinstance(result, httpx.HTTPStatusError) and calls == ["GET"], calls
+calls, result = send("GET", [(503, {}), (200, {})])
+assert isinstance(result, httpx.HTTPStatusError) and calls == ["GET"], calls
+
+# Retry-After in delta-seconds replaces the backoff
+calls, result = send("GET", [(429, {"Retry-After": "7"}), (200, {})], max_retries=1)
+assert result == {"ok": True} and delays == [7.0], delays
+
+# Retry-After as an HTTP-date
+when = datetime.datetime.now(datetime.timezone.utc) + datetime.timedelta(seconds=30)
+calls, result = send("GET", [(503, {"Retry-After": email.utils.format_datetime(when, usegmt=True)}), (200, {})], max_retries=1)
+assert result == {"ok": True} and len(delays) == 1 and 25 < delays[0] <= 30, delays
+
+# A date in the past retries right away; an unparsable value falls back to the backoff
+past = email.utils.format_datetime(datetime.datetime(2000, 1, 1, tzinfo=datetime.timezone.utc), usegmt=True)
+calls, result = send("GET", [(503, {"Retry-After": past}), (200, {})], max_retries=1)
+assert delays == [0.0], delays
+calls, result = send("GET", [(429, {"Retry-After": "soon"}), (200, {})], max_retries=1, backoff_factor=2)
+assert delays == [2], delays
+
+# Non-idempotent methods are not retried, neither after a response nor a network error
+for method in ("POST", "PATCH"):
+    calls, result = send(method, [(503, {}), (200, {})], max_retries=2)
+    assert isinstance(result, httpx.HTTPStatusError) and calls == [method] and delays == [], (method, calls)
+calls, result = send("POST", [httpx.ConnectError("reset"), (200, {})], max_retries=2)
+assert isinstance(result, httpx.ConnectError) and calls == ["POST"], calls
+
+# unless retry_methods lists them
+calls, result = send("POST", [(503, {}), (200, {})], max_retries=2, retry_methods=["post"])
+assert result == {"ok": True} and calls == ["POST", "POST"], calls
+`
+
+func TestClientRetries(t *testing.T) {
+	runClientScript(t, config.Client{PackageName: "items", Name: "Items"}, ir.IR{}, retryScript)
+}
+
+// hooksScript checks that on_response sees the request as sent and that a raising hook
+// cannot hide or replace the request's own error
+const hooksScript = `import httpx
+import client
+
+
+def send(handler, on_response, **kwargs):
+    config = client.ClientConfig(
+        base_url="https://api.test",
+        headers={"X-Team": "core"},
+        bearer_auth="secret",
+        on_response=on_response,
+        transport=httpx.MockTransport(handler),
+    )
+    return client.CoreClient(config).request("GET", "/items", **kwargs)
+
+
+def ok(request):
+    return httpx.Response(200, json={"ok": True})
+
+
+def unavailable(request):
+    return httpx.Response(503, json={"error": "down"})
+
+
+def refuse(request):
+    raise httpx.ConnectError("refused")
+
+
+def failing_hook(event):
+    raise RuntimeError("hook failed")
+
+
+# The hook sees the final URL and headers, credentials included
+events = []
+send(ok, events.append, params={"team": "core"}, operation_id="listItems")
+event = events[-1]
+assert event.url == "https://api.test/items?team=core", event.url
+assert event.headers["Authorization"] == "Bearer secret" and event.headers["X-Team"] == "core", event.headers
+assert event.status_code == 200 and event.operation_id == "listItems" and event.error is None, event
+
+# also when no response arrived
+try:
+    send(refuse, events.append, params={"team": "core"})
+except httpx.ConnectError as exc:
+    error = exc
+else:
+    raise AssertionError("the network error was swallowed")
+event = events[-1]
+assert event.url == "https://api.test/items?team=core" and event.headers["Authorization"] == "Bearer secret", event
+assert event.status_code is None and event.error is error, event
+
+# A raising hook neither swallows nor replaces the request's error
+for handler, expected in ((refuse, httpx.ConnectError), (unavailable, httpx.HTTPStatusError)):
+    try:
+        send(handler, failing_hook)
+    except expected:
+        pass
+    except RuntimeError:
+        raise AssertionError(f"the hook's error replaced {expected.__name__}")
+    else:
+        raise AssertionError(f"{expected.__name__} was swallowed")
+
+# while its failure surfaces for successful requests
+try:
+    send(ok, failing_hook)
+except RuntimeError:
+    pass
+else:
+    raise AssertionError("the hook's error was hidden")
+`
+
+func TestClientResponseHook(t *testing.T) {
+	in := ir.IR{SecuritySchemes: []ir.IRSecurityScheme{{Key: "bearerAuth", Type: "http", Scheme: "bearer"}}}
+	runClientScript(t, config.Client{PackageName: "items", Name: "Items"}, in, hooksScript)
+}
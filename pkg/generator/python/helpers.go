@@ -3,6 +3,10 @@ package python
 import (
 	"fmt"
 	"os/exec"
+	"regexp"
+	"slices"
+	"sort"
+	"strconv"
 	"strings"
 
 	"github.com/blimu-dev/sdk-gen/pkg/config"
@@ -10,14 +14,38 @@ import (
 	"github.com/blimu-dev/sdk-gen/pkg/utils"
 )
 
+// typeOptions carries client settings that influence how IR schemas map to Python types
+type typeOptions struct {
+	// DateAsNativeType maps date/date-time strings to datetime.date/datetime.datetime
+	DateAsNativeType bool
+}
+
+// newTypeOptions derives type mapping options from the client configuration
+func newTypeOptions(client config.Client) typeOptions {
+	return typeOptions{DateAsNativeType: client.DateAsNativeType}
+}
+
 // schemaToPyTypeForService converts an IR schema to Python type string without quoting (for service files)
-func schemaToPyTypeForService(s ir.IRSchema) string {
+func schemaToPyTypeForService(s ir.IRSchema, opts typeOptions) string {
+	// x-python-type replaces the inferred type
+	if override := s.TypeOverrides["python"]; override != "" {
+		if s.Nullable {
+			return "Optional[" + override + "]"
+		}
+		return override
+	}
 	// Base type string without nullability; append Optional later
 	var t string
 	switch s.Kind {
 	case "string":
 		if s.Format == "binary" {
 			t = "bytes"
+		} else if s.Format == "byte" {
+			t = "models.Base64Bytes"
+		} else if opts.DateAsNativeType && s.Format == "date-time" {
+			t = "datetime.datetime"
+		} else if opts.DateAsNativeType && s.Format == "date" {
+			t = "datetime.date"
 		} else {
 			t = "str"
 		}
@@ -38,7 +66,7 @@ func schemaToPyTypeForService(s ir.IRSchema) string {
 		}
 	case "array":
 		if s.Items != nil {
-			inner := schemaToPyTypeForService(*s.Items)
+			inner := schemaToPyTypeForService(*s.Items, opts)
 			t = "List[" + inner + "]"
 		} else {
 			t = "List[Any]"
@@ -46,20 +74,20 @@ func schemaToPyTypeForService(s ir.IRSchema) string {
 	case "oneOf":
 		parts := make([]string, 0, len(s.OneOf))
 		for _, sub := range s.OneOf {
-			parts = append(parts, schemaToPyTypeForService(*sub))
+			parts = append(parts, schemaToPyTypeForService(*sub, opts))
 		}
 		t = "Union[" + strings.Join(parts, ", ") + "]"
 	case "anyOf":
 		parts := make([]string, 0, len(s.AnyOf))
 		for _, sub := range s.AnyOf {
-			parts = append(parts, schemaToPyTypeForService(*sub))
+			parts = append(parts, schemaToPyTypeForService(*sub, opts))
 		}
 		t = "Union[" + strings.Join(parts, ", ") + "]"
 	case "allOf":
 		// Python doesn't have intersection types like TypeScript
 		// We'll use the first type or Any as fallback
 		if len(s.AllOf) > 0 {
-			t = schemaToPyTypeForService(*s.AllOf[0])
+			t = schemaToPyTypeForService(*s.AllOf[0], opts)
 		} else {
 			t = "Any"
 		}
@@ -106,13 +134,26 @@ func schemaToPyTypeForService(s ir.IRSchema) string {
 }
 
 // schemaToPyType converts an IR schema to Python type string (for models file with quoting)
-func schemaToPyType(s ir.IRSchema) string {
+func schemaToPyType(s ir.IRSchema, opts typeOptions) string {
+	// x-python-type replaces the inferred type
+	if override := s.TypeOverrides["python"]; override != "" {
+		if s.Nullable {
+			return "Optional[" + override + "]"
+		}
+		return override
+	}
 	// Base type string without nullability; append Optional later
 	var t string
 	switch s.Kind {
 	case "string":
 		if s.Format == "binary" {
 			t = "bytes"
+		} else if s.Format == "byte" {
+			t = "Base64Bytes"
+		} else if opts.DateAsNativeType && s.Format == "date-time" {
+			t = "datetime.datetime"
+		} else if opts.DateAsNativeType && s.Format == "date" {
+			t = "datetime.date"
 		} else {
 			t = "str"
 		}
@@ -133,7 +174,7 @@ func schemaToPyType(s ir.IRSchema) string {
 		}
 	case "array":
 		if s.Items != nil {
-			inner := schemaToPyType(*s.Items)
+			inner := schemaToPyType(*s.Items, opts)
 			t = "List[" + inner + "]"
 		} else {
 			t = "List[Any]"
@@ -141,20 +182,20 @@ func schemaToPyType(s ir.IRSchema) string {
 	case "oneOf":
 		parts := make([]string, 0, len(s.OneOf))
 		for _, sub := range s.OneOf {
-			parts = append(parts, schemaToPyType(*sub))
+			parts = append(parts, schemaToPyType(*sub, opts))
 		}
 		t = "Union[" + strings.Join(parts, ", ") + "]"
 	case "anyOf":
 		parts := make([]string, 0, len(s.AnyOf))
 		for _, sub := range s.AnyOf {
-			parts = append(parts, schemaToPyType(*sub))
+			parts = append(parts, schemaToPyType(*sub, opts))
 		}
 		t = "Union[" + strings.Join(parts, ", ") + "]"
 	case "allOf":
 		// Python doesn't have intersection types like TypeScript
 		// We'll use the first type or Any as fallback
 		if len(s.AllOf) > 0 {
-			t = schemaToPyType(*s.AllOf[0])
+			t = schemaToPyType(*s.AllOf[0], opts)
 		} else {
 			t = "Any"
 		}
@@ -201,16 +242,114 @@ func schemaToPyType(s ir.IRSchema) string {
 }
 
 // fieldToPyType converts an IR field to Python type string with proper Optional handling
-func fieldToPyType(field ir.IRField) string {
-	baseType := schemaToPyType(*field.Type)
+func fieldToPyType(field ir.IRField, opts typeOptions) string {
+	baseType := schemaToPyType(*field.Type, opts)
 	if !field.Required && !strings.HasPrefix(baseType, "Optional[") {
 		return "Optional[" + baseType + "]"
 	}
 	return baseType
 }
 
+// pythonKeywords are the reserved words that cannot name a model attribute
+var pythonKeywords = map[string]bool{
+	"False": true, "None": true, "True": true, "and": true, "as": true, "assert": true,
+	"async": true, "await": true, "break": true, "class": true, "continue": true, "def": true,
+	"del": true, "elif": true, "else": true, "except": true, "finally": true, "for": true,
+	"from": true, "global": true, "if": true, "import": true, "in": true, "is": true,
+	"lambda": true, "nonlocal": true, "not": true, "or": true, "pass": true, "raise": true,
+	"return": true, "try": true, "while": true, "with": true, "yield": true,
+}
+
+var pyIdentPattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// pyFieldName returns the attribute name of a model field: snake_case, or the JSON name itself
+// when property names are preserved and it is a valid identifier. Leading underscores are
+// dropped (pydantic treats those attributes as private) and keywords get a trailing underscore.
+func pyFieldName(jsonName string, preserve bool) string {
+	name := jsonName
+	if !preserve || !pyIdentPattern.MatchString(name) {
+		name = toSnakeCase(jsonName)
+	}
+	name = strings.TrimLeft(name, "_")
+	if name == "" || (name[0] >= '0' && name[0] <= '9') {
+		name = "field_" + name
+	}
+	if pythonKeywords[name] {
+		name += "_"
+	}
+	return name
+}
+
+// pyFieldValue returns what a model attribute is assigned: its default, or a pydantic Field
+// carrying the default and the JSON name as alias when the attribute is named differently.
+// Sensitive fields (format: password or writeOnly) are left out of the model's repr.
+func pyFieldValue(field ir.IRField, preserve bool) string {
+	def := getPyDefault(field)
+	var args []string
+	if pyFieldName(field.Name, preserve) != field.Name {
+		args = append(args, "alias="+strconv.Quote(field.Name))
+	}
+	if field.Sensitive() {
+		args = append(args, "repr=False")
+	}
+	args = append(args, pyNumericConstraints(field.Type)...)
+	if len(args) == 0 {
+		return def
+	}
+	if def != "" {
+		args = append([]string{"default=" + def}, args...)
+	}
+	return "Field(" + strings.Join(args, ", ") + ")"
+}
+
+// pyNumericConstraints returns the pydantic Field arguments validating the exclusive bounds
+// and multipleOf of a number or integer field
+func pyNumericConstraints(s *ir.IRSchema) []string {
+	if s == nil || (s.Kind != ir.IRKindNumber && s.Kind != ir.IRKindInteger) {
+		return nil
+	}
+	var args []string
+	if s.ExclusiveMinimum != nil {
+		args = append(args, "gt="+pyNumber(*s.ExclusiveMinimum))
+	}
+	if s.ExclusiveMaximum != nil {
+		args = append(args, "lt="+pyNumber(*s.ExclusiveMaximum))
+	}
+	if s.MultipleOf != nil {
+		args = append(args, "multiple_of="+pyNumber(*s.MultipleOf))
+	}
+	return args
+}
+
+// pyNumber renders f as a Python number literal
+func pyNumber(f float64) string {
+	return strconv.FormatFloat(f, 'g', -1, 64)
+}
+
+// pyModelConfig returns the model_config of a model, or an empty string when it needs none.
+// Aliased fields also accept their attribute name; additionalProperties keep unknown keys.
+func pyModelConfig(s ir.IRSchema, preserve bool) string {
+	var entries []string
+	for _, f := range s.Properties {
+		if pyFieldName(f.Name, preserve) != f.Name {
+			entries = append(entries, `"populate_by_name": True`)
+			break
+		}
+	}
+	if s.AdditionalProperties != nil {
+		entries = append(entries, `"extra": "allow"`)
+	}
+	if len(entries) == 0 {
+		return ""
+	}
+	return "{" + strings.Join(entries, ", ") + "}"
+}
+
 // getPyDefault returns the default value for a Python field
 func getPyDefault(field ir.IRField) string {
+	if lit := pyLiteral(field.Annotations.Default); lit != "" {
+		return lit
+	}
 	if !field.Required {
 		return "None"
 	}
@@ -218,6 +357,29 @@ func getPyDefault(field ir.IRField) string {
 	return ""
 }
 
+// pyLiteral renders a schema default as a Python literal.
+// It returns an empty string for missing values and for lists/dicts,
+// which must not be used as mutable function or field defaults.
+func pyLiteral(v any) string {
+	switch val := v.(type) {
+	case bool:
+		if val {
+			return "True"
+		}
+		return "False"
+	case string:
+		return strconv.Quote(val)
+	case float64:
+		return strconv.FormatFloat(val, 'f', -1, 64)
+	case float32:
+		return strconv.FormatFloat(float64(val), 'f', -1, 32)
+	case int, int32, int64:
+		return fmt.Sprint(val)
+	default:
+		return ""
+	}
+}
+
 // deriveMethodName creates method names using basic REST-style heuristics
 // This should only be used as a last resort when no OperationID is available
 func deriveMethodName(op ir.IROperation) string {
@@ -299,7 +461,8 @@ var toCamelCase = utils.ToCamelCase
 var toSnakeCase = utils.ToSnakeCase
 var toKebabCase = utils.ToKebabCase
 
-// buildPathTemplate converts OpenAPI path to Python f-string
+// buildPathTemplate converts OpenAPI path to Python f-string; array parameters are joined
+// with the separator of their style
 func buildPathTemplate(op ir.IROperation) string {
 	// Convert /foo/{id}/bar/{slug} -> f"/foo/{id}/bar/{slug}"
 	path := op.Path
@@ -315,9 +478,15 @@ func buildPathTemplate(op ir.IROperation) string {
 			}
 			if j < len(path) {
 				name := path[i+1 : j]
-				b.WriteString("{")
-				b.WriteString(name)
-				b.WriteString("}")
+				b.WriteString(op.PathPrefix(name))
+				if p, ok := op.PathParam(name); ok && p.Schema.Kind == ir.IRKindArray {
+					// Single quotes, as the f-string is double quoted
+					fmt.Fprintf(&b, "{'%s'.join(str(v) for v in %s)}", p.PathSeparator(), name)
+				} else {
+					b.WriteString("{")
+					b.WriteString(name)
+					b.WriteString("}")
+				}
 				i = j
 				continue
 			}
@@ -356,12 +525,12 @@ func orderPathParams(op ir.IROperation) []ir.IRParam {
 }
 
 // buildMethodSignature constructs the Python parameter list for service methods
-func buildMethodSignature(op ir.IROperation, methodName string) []string {
+func buildMethodSignature(op ir.IROperation, methodName string, opts typeOptions) []string {
 	parts := []string{}
 
 	// path params as positional args
 	for _, p := range orderPathParams(op) {
-		pyType := schemaToPyTypeForService(p.Schema)
+		pyType := schemaToPyTypeForService(p.Schema, opts)
 		parts = append(parts, fmt.Sprintf("%s: %s", toSnakeCase(p.Name), pyType))
 	}
 
@@ -369,11 +538,14 @@ func buildMethodSignature(op ir.IROperation, methodName string) []string {
 	if len(op.QueryParams) > 0 {
 		// For simplicity, we'll use individual optional parameters for each query param
 		for _, p := range op.QueryParams {
-			pyType := schemaToPyTypeForService(p.Schema)
+			pyType := schemaToPyTypeForService(p.Schema, opts)
 			if !p.Required && !strings.HasPrefix(pyType, "Optional[") {
 				pyType = "Optional[" + pyType + "]"
 			}
 			defaultVal := "None"
+			if lit := pyLiteral(p.Default); lit != "" {
+				defaultVal = lit
+			}
 			if p.Required {
 				// Required query params don't get default values
 				parts = append(parts, fmt.Sprintf("%s: %s", toSnakeCase(p.Name), pyType))
@@ -385,7 +557,7 @@ func buildMethodSignature(op ir.IROperation, methodName string) []string {
 
 	// body
 	if op.RequestBody != nil {
-		pyType := schemaToPyTypeForService(op.RequestBody.Schema)
+		pyType := schemaToPyTypeForService(op.RequestBody.Schema, opts)
 		if !op.RequestBody.Required && !strings.HasPrefix(pyType, "Optional[") {
 			pyType = "Optional[" + pyType + "]"
 		}
@@ -396,9 +568,36 @@ func buildMethodSignature(op ir.IROperation, methodName string) []string {
 		}
 	}
 
+	// media type to accept, when the response has several
+	if op.Response.Negotiated() {
+		types := make([]string, 0, len(op.Response.Contents))
+		for _, content := range op.Response.Contents {
+			types = append(types, strconv.Quote(content.ContentType))
+		}
+		parts = append(parts, fmt.Sprintf("accept: Literal[%s] = %s", strings.Join(types, ", "), types[0]))
+	}
+
 	return parts
 }
 
+// pyResultType is the type a service method returns: the response model, or the Union of the
+// types of every media type a negotiated response can be returned as
+func pyResultType(op ir.IROperation, opts typeOptions) string {
+	if !op.Response.Negotiated() {
+		return schemaToPyTypeForService(op.Response.Schema, opts)
+	}
+	var types []string
+	for _, content := range op.Response.Contents {
+		if t := schemaToPyTypeForService(content.Schema, opts); !slices.Contains(types, t) {
+			types = append(types, t)
+		}
+	}
+	if len(types) == 1 {
+		return types[0]
+	}
+	return "Union[" + strings.Join(types, ", ") + "]"
+}
+
 // formatDocstring formats a string for use in Python docstrings
 func formatDocstring(s string) string {
 	if s == "" {
@@ -428,3 +627,134 @@ func formatPythonComment(s string) string {
 	// Build the raw string docstring with proper indentation (no extra spaces needed)
 	return "r\"\"\"" + escaped + "\"\"\""
 }
+
+// enumPyLiterals returns the Python literal for each enum value. Numeric and boolean
+// enums are rendered from the raw values so numbers stay exact and booleans become True/False.
+func enumPyLiterals(s ir.IRSchema) []string {
+	vals := make([]string, 0, len(s.EnumValues))
+	switch s.EnumBase {
+	case ir.IRKindNumber, ir.IRKindInteger:
+		vals = append(vals, s.EnumLiterals()...)
+	case ir.IRKindBoolean:
+		for _, v := range s.EnumLiterals() {
+			switch v {
+			case "true":
+				vals = append(vals, "True")
+			case "false":
+				vals = append(vals, "False")
+			default:
+				vals = append(vals, "\""+v+"\"")
+			}
+		}
+	default:
+		for _, v := range s.EnumValues {
+			vals = append(vals, "\""+v+"\"")
+		}
+	}
+	return vals
+}
+
+// enumPyMembers returns the Enum member name of each value of a string enum, unique and
+// valid even when values differ only in case or start with a digit
+func enumPyMembers(s ir.IRSchema) []string {
+	return utils.EnumMemberNames(s.EnumValues, utils.EnumNameUpperSnake)
+}
+
+// typeImports returns the modules named by x-python-import for the type overrides used
+// anywhere in the IR, sorted, so models and services can import them
+func typeImports(in ir.IR) []string {
+	return in.TypeImports("python")
+}
+
+// serviceModule returns the module of the services package generated for tag: snake case
+// unless client.FileNaming picks another style
+func serviceModule(client config.Client, tag string) string {
+	return client.FileName(strings.ToLower(toSnakeCase(tag)), tag)
+}
+
+// asyncServiceModule returns the module of the async service generated for tag
+func asyncServiceModule(client config.Client, tag string) string {
+	return client.FileName("async_"+strings.ToLower(toSnakeCase(tag)), "async", tag)
+}
+
+// pyImport is a class imported from a module of the generated package
+type pyImport struct {
+	Module string
+	Name   string
+}
+
+// pyExports lists what the package __init__.py re-exports; each list is sorted and free of
+// duplicates
+type pyExports struct {
+	Services []pyImport
+	Models   []string
+	All      []string
+}
+
+// serviceImports returns the service classes of the services package (async ones too when the
+// async client is enabled), sorted by name. Tags mapping to the same class name yield one entry.
+func serviceImports(client config.Client, in ir.IR) []pyImport {
+	seen := map[string]bool{}
+	var out []pyImport
+	add := func(module, name string) {
+		if !seen[name] {
+			seen[name] = true
+			out = append(out, pyImport{Module: module, Name: name})
+		}
+	}
+	for _, s := range in.Services {
+		name := toPascalCase(s.Tag) + "Service"
+		add(serviceModule(client, s.Tag), name)
+		if client.AsyncClient {
+			add(asyncServiceModule(client, s.Tag), "Async"+name)
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Name < out[j].Name })
+	return out
+}
+
+// packageExports returns the service and model classes the package re-exports next to the
+// client classes (only the models for types-only packages). A model named like one of those
+// is left out; it stays importable from the models module.
+func packageExports(client config.Client, in ir.IR) pyExports {
+	taken := map[string]bool{"models": true}
+	var exports pyExports
+	if client.ShouldEmitClient() {
+		for _, name := range []string{client.Name, "ClientConfig", "CoreClient", "RequestEvent"} {
+			taken[name] = true
+		}
+		if client.AsyncClient {
+			taken["Async"+client.Name] = true
+			taken["AsyncCoreClient"] = true
+		}
+		if client.RawResponse {
+			taken["RawResponse"] = true
+		}
+		if len(utils.Environments(client.Environments, in.Servers)) > 0 {
+			taken["ENVIRONMENTS"] = true
+		}
+		exports.Services = serviceImports(client, in)
+		for _, s := range exports.Services {
+			taken[s.Name] = true
+		}
+	}
+
+	// ErrorResponse is always defined in models.py
+	names := []string{"ErrorResponse"}
+	for _, md := range in.ModelDefs {
+		names = append(names, md.Name)
+	}
+	for _, name := range names {
+		if !taken[name] {
+			taken[name] = true
+			exports.Models = append(exports.Models, name)
+		}
+	}
+	sort.Strings(exports.Models)
+
+	for name := range taken {
+		exports.All = append(exports.All, name)
+	}
+	sort.Strings(exports.All)
+	return exports
+}
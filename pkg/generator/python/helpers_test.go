@@ -0,0 +1,340 @@
+package python
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/blimu-dev/sdk-gen/pkg/config"
+	"github.com/blimu-dev/sdk-gen/pkg/ir"
+)
+
+func TestNullableArrayPyType(t *testing.T) {
+	array := func(items ir.IRSchema, nullable bool) *ir.IRSchema {
+		return &ir.IRSchema{Kind: ir.IRKindArray, Items: &items, Nullable: nullable}
+	}
+	str := ir.IRSchema{Kind: ir.IRKindString}
+	nullableStr := ir.IRSchema{Kind: ir.IRKindString, Nullable: true}
+
+	tests := []struct {
+		name    string
+		field   ir.IRField
+		model   string
+		service string
+	}{
+		{
+			name:    "nullable array",
+			field:   ir.IRField{Type: array(str, true), Required: true},
+			model:   "Optional[List[str]]",
+			service: "Optional[List[str]]",
+		},
+		{
+			name:    "nullable items",
+			field:   ir.IRField{Type: array(nullableStr, false), Required: true},
+			model:   "List[Optional[str]]",
+			service: "List[Optional[str]]",
+		},
+		{
+			name:    "both",
+			field:   ir.IRField{Type: array(nullableStr, true), Required: true},
+			model:   "Optional[List[Optional[str]]]",
+			service: "Optional[List[Optional[str]]]",
+		},
+		{
+			name:    "optional field with nullable items",
+			field:   ir.IRField{Type: array(nullableStr, false)},
+			model:   "Optional[List[Optional[str]]]",
+			service: "List[Optional[str]]",
+		},
+		{
+			name:    "nullable enum",
+			field:   ir.IRField{Type: &ir.IRSchema{Kind: ir.IRKindEnum, EnumBase: ir.IRKindString, EnumValues: []string{"a", "b"}, Nullable: true}, Required: true},
+			model:   `Optional[Literal["a", "b"]]`,
+			service: `Optional[Literal["a", "b"]]`,
+		},
+		{
+			name:    "nullable ref items",
+			field:   ir.IRField{Type: array(ir.IRSchema{Kind: ir.IRKindRef, Ref: "User", Nullable: true}, false), Required: true},
+			model:   `List[Optional["User"]]`,
+			service: "List[Optional[models.User]]",
+		},
+	}
+
+	for _, test := range tests {
+		if got := fieldToPyType(test.field, typeOptions{}); got != test.model {
+			t.Errorf("%s: fieldToPyType() = %q, expected %q", test.name, got, test.model)
+		}
+		if got := schemaToPyTypeForService(*test.field.Type, typeOptions{}); got != test.service {
+			t.Errorf("%s: schemaToPyTypeForService() = %q, expected %q", test.name, got, test.service)
+		}
+	}
+}
+
+func TestTypeOverridePyType(t *testing.T) {
+	s := ir.IRSchema{Kind: ir.IRKindRef, Ref: "Money", Nullable: true,
+		TypeOverrides: map[string]string{"python": "decimal.Decimal"}, TypeImports: map[string]string{"python": "decimal"}}
+	for _, got := range []string{schemaToPyType(s, typeOptions{}), schemaToPyTypeForService(s, typeOptions{})} {
+		if got != "Optional[decimal.Decimal]" {
+			t.Errorf("expected Optional[decimal.Decimal], got %q", got)
+		}
+	}
+	in := ir.IR{Services: []ir.IRService{{Operations: []ir.IROperation{{Response: ir.IRResponse{Schema: ir.IRSchema{Kind: ir.IRKindArray, Items: &s}}}}}}}
+	if imports := typeImports(in); len(imports) != 1 || imports[0] != "decimal" {
+		t.Errorf("typeImports() = %v", imports)
+	}
+}
+
+func TestByteFormatPyType(t *testing.T) {
+	s := ir.IRSchema{Kind: ir.IRKindString, Format: "byte"}
+	if got := schemaToPyType(s, typeOptions{}); got != "Base64Bytes" {
+		t.Errorf("schemaToPyType() = %q, expected Base64Bytes", got)
+	}
+	if got := schemaToPyTypeForService(s, typeOptions{}); got != "models.Base64Bytes" {
+		t.Errorf("schemaToPyTypeForService() = %q, expected models.Base64Bytes", got)
+	}
+	if got := schemaToPyType(ir.IRSchema{Kind: ir.IRKindString, Format: "binary"}, typeOptions{}); got != "bytes" {
+		t.Errorf("schemaToPyType(binary) = %q, expected bytes", got)
+	}
+}
+
+func TestPackageExports(t *testing.T) {
+	in := ir.IR{
+		Services: []ir.IRService{{Tag: "users"}, {Tag: "admin.audit"}, {Tag: "admin_audit"}},
+		ModelDefs: []ir.IRModelDef{
+			{Name: "User"},
+			{Name: "ClientConfig"},
+			{Name: "UsersService"},
+			{Name: "Address"},
+			{Name: "Address"},
+		},
+	}
+	exports := packageExports(config.Client{Name: "Shop", AsyncClient: true}, in)
+
+	var services []string
+	for _, s := range exports.Services {
+		services = append(services, s.Module+"."+s.Name)
+	}
+	tests := []struct {
+		name     string
+		got      []string
+		expected []string
+	}{
+		{"services", services, []string{
+			"admin_audit.AdminAuditService", "async_admin_audit.AsyncAdminAuditService",
+			"async_users.AsyncUsersService", "users.UsersService",
+		}},
+		{"models", exports.Models, []string{"Address", "ErrorResponse", "User"}},
+		{"all", exports.All, []string{
+			"Address", "AdminAuditService", "AsyncAdminAuditService", "AsyncCoreClient", "AsyncShop",
+			"AsyncUsersService", "ClientConfig", "CoreClient", "ErrorResponse", "RequestEvent",
+			"Shop", "User", "UsersService", "models",
+		}},
+	}
+	for _, test := range tests {
+		if strings.Join(test.got, ",") != strings.Join(test.expected, ",") {
+			t.Errorf("%s = %v, expected %v", test.name, test.got, test.expected)
+		}
+	}
+
+	// Types-only packages export the models alone, including those named like client classes
+	emitClient := false
+	exports = packageExports(config.Client{Name: "Shop", AsyncClient: true, EmitClient: &emitClient}, in)
+	if len(exports.Services) != 0 {
+		t.Errorf("expected no services, got %v", exports.Services)
+	}
+	if got := strings.Join(exports.All, ","); got != "Address,ClientConfig,ErrorResponse,User,UsersService,models" {
+		t.Errorf("types-only all = %s", got)
+	}
+}
+
+func TestPyFieldName(t *testing.T) {
+	tests := []struct {
+		input     string
+		snake     string
+		preserved string
+	}{
+		{"name", "name", "name"},
+		{"firstName", "first_name", "firstName"},
+		{"x-request-id", "x_request_id", "x_request_id"},
+		{"_id", "id", "id"},
+		{"2fa", "field_2fa", "field_2fa"},
+		{"class", "class_", "class_"},
+	}
+	for _, test := range tests {
+		if got := pyFieldName(test.input, false); got != test.snake {
+			t.Errorf("pyFieldName(%q, false) = %q, expected %q", test.input, got, test.snake)
+		}
+		if got := pyFieldName(test.input, true); got != test.preserved {
+			t.Errorf("pyFieldName(%q, true) = %q, expected %q", test.input, got, test.preserved)
+		}
+	}
+
+	str := &ir.IRSchema{Kind: ir.IRKindString}
+	zero, cent, hundred := 0.0, 0.01, 100.0
+	model := ir.IRSchema{Kind: ir.IRKindObject, Properties: []ir.IRField{
+		{Name: "id", Type: str, Required: true},
+		{Name: "firstName", Type: str, Required: true},
+		{Name: "lastName", Type: str, Annotations: ir.IRAnnotations{Default: "Doe"}},
+		{Name: "password", Type: &ir.IRSchema{Kind: ir.IRKindString, Format: "password"}, Required: true},
+		{Name: "apiSecret", Type: str, Annotations: ir.IRAnnotations{WriteOnly: true}},
+		{Name: "amount", Type: &ir.IRSchema{Kind: ir.IRKindNumber, ExclusiveMinimum: &zero, MultipleOf: &cent}, Required: true},
+		{Name: "percent", Type: &ir.IRSchema{Kind: ir.IRKindInteger, ExclusiveMaximum: &hundred}},
+	}, AdditionalProperties: str}
+	values := []string{}
+	for _, f := range model.Properties {
+		values = append(values, pyFieldValue(f, false))
+	}
+	expected := []string{"", `Field(alias="firstName")`, `Field(default="Doe", alias="lastName")`, `Field(repr=False)`, `Field(default=None, alias="apiSecret", repr=False)`, `Field(gt=0, multiple_of=0.01)`, `Field(default=None, lt=100)`}
+	if strings.Join(values, "|") != strings.Join(expected, "|") {
+		t.Errorf("pyFieldValue() = %q, expected %q", values, expected)
+	}
+	if got := pyModelConfig(model, false); got != `{"populate_by_name": True, "extra": "allow"}` {
+		t.Errorf("pyModelConfig(snake) = %s", got)
+	}
+	if got := pyModelConfig(model, true); got != `{"extra": "allow"}` {
+		t.Errorf("pyModelConfig(preserved) = %s", got)
+	}
+}
+
+func TestAcceptResponse(t *testing.T) {
+	str := ir.IRSchema{Kind: ir.IRKindString}
+	op := ir.IROperation{Response: ir.IRResponse{
+		ContentType: "application/json",
+		Schema:      ir.IRSchema{Kind: ir.IRKindRef, Ref: "Report"},
+		Contents: []ir.IRResponseContent{
+			{ContentType: "application/json", Schema: ir.IRSchema{Kind: ir.IRKindRef, Ref: "Report"}},
+			{ContentType: "text/csv", Schema: str},
+			{ContentType: "text/plain", Schema: str},
+		},
+	}}
+
+	if got := pyResultType(op, typeOptions{}); got != "Union[models.Report, str]" {
+		t.Errorf("pyResultType() = %s", got)
+	}
+	params := buildMethodSignature(op, "get_report", typeOptions{})
+	if expected := `accept: Literal["application/json", "text/csv", "text/plain"] = "application/json"`; len(params) != 1 || params[0] != expected {
+		t.Errorf("buildMethodSignature() = %v, expected [%s]", params, expected)
+	}
+}
+
+func TestGenerateAsyncClient(t *testing.T) {
+	in := ir.IR{Services: []ir.IRService{{Tag: "items", Operations: []ir.IROperation{{
+		OperationID: "listItems",
+		Method:      "GET",
+		Path:        "/items",
+		Tag:         "items",
+		QueryParams: []ir.IRParam{{Name: "page", Schema: ir.IRSchema{Kind: ir.IRKindInteger}}},
+		Response:    ir.IRResponse{TypeTS: "void"},
+	}}}}}
+	generate := func(async bool) string {
+		dir := t.TempDir()
+		client := config.Client{Type: "python", OutDir: dir, PackageName: "items", Name: "Items", AsyncClient: async}
+		if err := NewPythonGenerator().Generate(client, in); err != nil {
+			t.Fatal(err)
+		}
+		return filepath.Join(dir, "items")
+	}
+	read := func(path string) string {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return string(data)
+	}
+
+	asyncDir := generate(true)
+	for file, expected := range map[string][]string{
+		"async_client.py":         {"class AsyncCoreClient", "httpx.AsyncClient"},
+		"services/async_items.py": {"class AsyncItemsService", "async def list_items(", "await self._client.request("},
+	} {
+		content := read(filepath.Join(asyncDir, file))
+		for _, e := range expected {
+			if !strings.Contains(content, e) {
+				t.Errorf("%s does not contain %q:\n%s", file, e, content)
+			}
+		}
+	}
+
+	syncDir := generate(false)
+	for _, file := range []string{"async_client.py", "services/async_items.py"} {
+		if _, err := os.Stat(filepath.Join(syncDir, file)); !os.IsNotExist(err) {
+			t.Errorf("%s generated without asyncClient", file)
+		}
+	}
+	// The sync client and services are the same whether or not the async ones are emitted
+	for _, file := range []string{"client.py", "services/items.py"} {
+		content := read(filepath.Join(syncDir, file))
+		if strings.Contains(content, "async def") || strings.Contains(content, "await ") {
+			t.Errorf("%s contains async code:\n%s", file, content)
+		}
+		if content != read(filepath.Join(asyncDir, file)) {
+			t.Errorf("%s differs when asyncClient is enabled", file)
+		}
+	}
+}
+
+func TestDefaultHeaders(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		headers  map[string]string
+		expected []string
+	}{
+		{"none", nil, []string{"self.headers = headers or {}"}},
+		// ClientConfig headers are merged over the defaults
+		{"configured", map[string]string{"X-Client": "sdk", "User-Agent": "acme/1.0"}, []string{
+			"DEFAULT_HEADERS: Dict[str, str] = {\n    \"User-Agent\": \"acme/1.0\",\n    \"X-Client\": \"sdk\",\n}",
+			"self.headers = {**DEFAULT_HEADERS, **(headers or {})}",
+		}},
+	} {
+		dir := t.TempDir()
+		client := config.Client{Type: "python", OutDir: dir, PackageName: "items", Name: "Items", DefaultHeaders: tc.headers}
+		if err := NewPythonGenerator().Generate(client, ir.IR{}); err != nil {
+			t.Fatal(err)
+		}
+		data, err := os.ReadFile(filepath.Join(dir, "items", "client.py"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, e := range tc.expected {
+			if !strings.Contains(string(data), e) {
+				t.Errorf("%s: client.py does not contain %q", tc.name, e)
+			}
+		}
+		if tc.headers == nil && strings.Contains(string(data), "DEFAULT_HEADERS") {
+			t.Errorf("%s: client.py declares DEFAULT_HEADERS", tc.name)
+		}
+	}
+}
+
+func TestCookieAPIKey(t *testing.T) {
+	in := ir.IR{SecuritySchemes: []ir.IRSecurityScheme{{Key: "session", Type: "apiKey", In: "cookie", Name: "sid"}}}
+	dir := t.TempDir()
+	client := config.Client{Type: "python", OutDir: dir, PackageName: "items", Name: "Items", AsyncClient: true}
+	if err := NewPythonGenerator().Generate(client, in); err != nil {
+		t.Fatal(err)
+	}
+	// The cookie goes on the client's cookie jar, next to cookies the server sets, instead
+	// of a Cookie header replacing them
+	for file, expected := range map[string][]string{
+		"client.py": {
+			"        session: Optional[str] = None,\n        session_name: Optional[str] = None,",
+			"    if config.session:\n        cookies.set(config.session_name or \"sid\", config.session)",
+			"        apply_cookies(self.config, self._client.cookies)",
+		},
+		"async_client.py": {"        apply_cookies(self.config, self._client.cookies)"},
+	} {
+		data, err := os.ReadFile(filepath.Join(dir, "items", file))
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, e := range expected {
+			if !strings.Contains(string(data), e) {
+				t.Errorf("%s does not contain %q", file, e)
+			}
+		}
+		if strings.Contains(string(data), `"Cookie"`) {
+			t.Errorf("%s sets the Cookie header", file)
+		}
+	}
+}
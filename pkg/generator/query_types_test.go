@@ -0,0 +1,66 @@
+package generator
+
+import (
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/blimu-dev/sdk-gen/pkg/config"
+)
+
+// TestQueryTypeNames generates the TypeScript and Go SDKs for a fixture spec and requires
+// every query type referenced by a method signature to be declared, and every declared
+// query type to be referenced.
+func TestQueryTypeNames(t *testing.T) {
+	spec, err := filepath.Abs("testdata/determinism.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name        string
+		client      config.Client
+		declaration *regexp.Regexp
+		reference   *regexp.Regexp
+	}{
+		{"typescript", config.Client{Type: "typescript"}, regexp.MustCompile(`export interface (\w+Query) \{`), regexp.MustCompile(`query\?: Schema\.(\w+)`)},
+		{"go", config.Client{Type: "go"}, regexp.MustCompile(`type (\w+Query) struct`), regexp.MustCompile(`query \*(\w+)`)},
+		{"go both context modes", config.Client{Type: "go", GoContextMode: config.GoContextBoth}, regexp.MustCompile(`type (\w+Query) struct`), regexp.MustCompile(`query \*(\w+)`)},
+	}
+
+	for _, test := range tests {
+		root := t.TempDir()
+		client := test.client
+		client.OutDir, client.PackageName, client.ModuleName, client.Name = root, "store", "example.com/store", "Store"
+		cfg := &config.Config{Spec: spec, UntaggedTag: config.DefaultUntaggedTag, UntaggedBehavior: config.UntaggedBucket, Clients: []config.Client{client}}
+		if err := NewService().GenerateFromConfig(cfg, ""); err != nil {
+			t.Fatalf("%s: %v", test.name, err)
+		}
+
+		declared, referenced := map[string]bool{}, map[string]bool{}
+		for _, data := range readTree(t, root) {
+			for _, m := range test.declaration.FindAllSubmatch(data, -1) {
+				declared[string(m[1])] = true
+			}
+			for _, m := range test.reference.FindAllSubmatch(data, -1) {
+				referenced[string(m[1])] = true
+			}
+		}
+		if len(declared) == 0 {
+			t.Errorf("%s: no query types declared", test.name)
+		}
+		if got, want := sortedKeys(referenced), sortedKeys(declared); got != want {
+			t.Errorf("%s: referenced query types %s, declared %s", test.name, got, want)
+		}
+	}
+}
+
+func sortedKeys(m map[string]bool) string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return strings.Join(keys, ", ")
+}
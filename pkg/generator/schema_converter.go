@@ -1,17 +1,26 @@
 package generator
 
 import (
-	"fmt"
+	"encoding/json"
+	"math"
+	"reflect"
 	"regexp"
+	"slices"
 	"sort"
 	"strings"
 
 	"github.com/blimu-dev/sdk-gen/pkg/ir"
+	"github.com/blimu-dev/sdk-gen/pkg/openapi"
 	"github.com/getkin/kin-openapi/openapi3"
 )
 
 // schemaRefToIR converts an OpenAPI schema reference to IR schema
 func schemaRefToIR(doc *openapi3.T, sr *openapi3.SchemaRef) ir.IRSchema {
+	return withTypeOverrides(sr, convertSchemaRef(doc, sr))
+}
+
+// convertSchemaRef converts a schema reference without applying type override extensions
+func convertSchemaRef(doc *openapi3.T, sr *openapi3.SchemaRef) ir.IRSchema {
 	if sr == nil {
 		return ir.IRSchema{Kind: ir.IRKindUnknown}
 	}
@@ -40,6 +49,13 @@ func schemaRefToIR(doc *openapi3.T, sr *openapi3.SchemaRef) ir.IRSchema {
 		disc = &ir.IRDiscriminator{PropertyName: s.Discriminator.PropertyName, Mapping: s.Discriminator.Mapping}
 	}
 
+	// A composition of one subschema ({allOf: [{$ref: X}]}, common in NestJS output) is that subschema
+	if sub, ok := singleComposition(s); ok {
+		inner := schemaRefToIR(doc, sub)
+		inner.Nullable = inner.Nullable || s.Nullable
+		return inner
+	}
+
 	// Compositions
 	if len(s.OneOf) > 0 {
 		subs := make([]*ir.IRSchema, 0, len(s.OneOf))
@@ -70,64 +86,63 @@ func schemaRefToIR(doc *openapi3.T, sr *openapi3.SchemaRef) ir.IRSchema {
 		return ir.IRSchema{Kind: ir.IRKindNot, Not: &not, Nullable: s.Nullable, Discriminator: disc}
 	}
 
-	// Enum (support non-string by coercing to string representation)
-	if len(s.Enum) > 0 {
-		vals := make([]string, 0, len(s.Enum))
-		for _, v := range s.Enum {
-			vals = append(vals, fmt.Sprint(v))
-		}
-		base := inferEnumBaseKind(s)
-		return ir.IRSchema{Kind: ir.IRKindEnum, EnumValues: vals, EnumRaw: s.Enum, EnumBase: base, Nullable: s.Nullable, Discriminator: disc}
+	// Enum (support non-string by coercing to an exact string representation);
+	// a 3.1 const is a single-value enum
+	if len(schemaEnumValues(s)) > 0 {
+		return enumSchema(s, disc)
 	}
 
 	// Primitive kinds and object/array
-	if s.Type != nil {
-		switch {
-		case s.Type.Is(openapi3.TypeString):
-			return ir.IRSchema{Kind: ir.IRKindString, Nullable: s.Nullable, Format: s.Format, Discriminator: disc}
-		case s.Type.Is(openapi3.TypeInteger):
-			return ir.IRSchema{Kind: ir.IRKindInteger, Nullable: s.Nullable, Discriminator: disc}
-		case s.Type.Is(openapi3.TypeNumber):
-			return ir.IRSchema{Kind: ir.IRKindNumber, Nullable: s.Nullable, Discriminator: disc}
-		case s.Type.Is(openapi3.TypeBoolean):
-			return ir.IRSchema{Kind: ir.IRKindBoolean, Nullable: s.Nullable, Discriminator: disc}
-		case s.Type.Is(openapi3.TypeArray):
-			item := schemaRefToIR(doc, s.Items)
-			return ir.IRSchema{Kind: ir.IRKindArray, Items: &item, Nullable: s.Nullable, Discriminator: disc}
-		case s.Type.Is(openapi3.TypeObject):
-			// Properties
-			fields := make([]ir.IRField, 0, len(s.Properties))
-			// deterministic order
-			names := make([]string, 0, len(s.Properties))
-			for n := range s.Properties {
-				names = append(names, n)
-			}
-			sort.Strings(names)
-			for _, n := range names {
-				pr := s.Properties[n]
-				fieldType := schemaRefToIR(doc, pr)
-				required := false
-				for _, r := range s.Required {
-					if r == n {
-						required = true
-						break
-					}
+	switch {
+	case s.Type.Is(openapi3.TypeString):
+		return ir.IRSchema{Kind: ir.IRKindString, Nullable: s.Nullable, Format: s.Format, Discriminator: disc}
+	case s.Type.Is(openapi3.TypeInteger):
+		return withNumericBounds(ir.IRSchema{Kind: ir.IRKindInteger, Nullable: s.Nullable, Format: s.Format, Discriminator: disc}, s)
+	case s.Type.Is(openapi3.TypeNumber):
+		return withNumericBounds(ir.IRSchema{Kind: ir.IRKindNumber, Nullable: s.Nullable, Format: s.Format, Discriminator: disc}, s)
+	case s.Type.Is(openapi3.TypeBoolean):
+		return ir.IRSchema{Kind: ir.IRKindBoolean, Nullable: s.Nullable, Discriminator: disc}
+	case isArraySchema(s):
+		item := schemaRefToIR(doc, s.Items)
+		return ir.IRSchema{Kind: ir.IRKindArray, Items: &item, Nullable: s.Nullable, Discriminator: disc}
+	case isObjectSchema(s):
+		// Properties
+		fields := make([]ir.IRField, 0, len(s.Properties))
+		// deterministic order
+		names := make([]string, 0, len(s.Properties))
+		for n := range s.Properties {
+			names = append(names, n)
+		}
+		sort.Strings(names)
+		for _, n := range names {
+			pr := s.Properties[n]
+			fieldType := schemaRefToIR(doc, pr)
+			required := false
+			for _, r := range s.Required {
+				if r == n {
+					required = true
+					break
 				}
-				fields = append(fields, ir.IRField{Name: n, Type: &fieldType, Required: required, Annotations: extractAnnotations(pr)})
 			}
-			var addl *ir.IRSchema
-			if s.AdditionalProperties.Schema != nil {
-				ap := schemaRefToIR(doc, s.AdditionalProperties.Schema)
-				addl = &ap
-			}
-			return ir.IRSchema{Kind: ir.IRKindObject, Properties: fields, AdditionalProperties: addl, Nullable: s.Nullable, Discriminator: disc}
+			fields = append(fields, ir.IRField{Name: n, Type: &fieldType, Required: required, Annotations: extractAnnotations(pr)})
+		}
+		var addl *ir.IRSchema
+		if s.AdditionalProperties.Schema != nil {
+			ap := schemaRefToIR(doc, s.AdditionalProperties.Schema)
+			addl = &ap
 		}
+		return withPropertyCounts(ir.IRSchema{Kind: ir.IRKindObject, Properties: fields, AdditionalProperties: addl, Nullable: s.Nullable, Discriminator: disc}, s)
 	}
 	return ir.IRSchema{Kind: ir.IRKindUnknown, Nullable: s.Nullable, Discriminator: disc}
 }
 
 // schemaRefToIRWithNaming converts schema with naming for nested types
 func schemaRefToIRWithNaming(doc *openapi3.T, sr *openapi3.SchemaRef, parentName, propName string, isArrayItem bool, out *[]ir.IRModelDef, seen map[string]struct{}) ir.IRSchema {
+	return withTypeOverrides(sr, convertSchemaRefWithNaming(doc, sr, parentName, propName, isArrayItem, out, seen))
+}
+
+// convertSchemaRefWithNaming is schemaRefToIRWithNaming without type override extensions
+func convertSchemaRefWithNaming(doc *openapi3.T, sr *openapi3.SchemaRef, parentName, propName string, isArrayItem bool, out *[]ir.IRModelDef, seen map[string]struct{}) ir.IRSchema {
 	if sr == nil {
 		return ir.IRSchema{Kind: ir.IRKindUnknown}
 	}
@@ -156,6 +171,13 @@ func schemaRefToIRWithNaming(doc *openapi3.T, sr *openapi3.SchemaRef, parentName
 		disc = &ir.IRDiscriminator{PropertyName: s.Discriminator.PropertyName, Mapping: s.Discriminator.Mapping}
 	}
 
+	// A composition of one subschema ({allOf: [{$ref: X}]}, common in NestJS output) is that subschema
+	if sub, ok := singleComposition(s); ok {
+		inner := schemaRefToIRWithNaming(doc, sub, parentName, propName, isArrayItem, out, seen)
+		inner.Nullable = inner.Nullable || s.Nullable
+		return inner
+	}
+
 	// Compositions (no naming for subs; inline)
 	if len(s.OneOf) > 0 {
 		subs := make([]*ir.IRSchema, 0, len(s.OneOf))
@@ -186,8 +208,17 @@ func schemaRefToIRWithNaming(doc *openapi3.T, sr *openapi3.SchemaRef, parentName
 		return ir.IRSchema{Kind: ir.IRKindNot, Not: &not, Nullable: s.Nullable, Discriminator: disc}
 	}
 
+	// Const: keep inline as a single-value enum so it renders as a literal type
+	if _, ok := schemaConst(s); ok && len(s.Enum) == 0 {
+		return enumSchema(s, disc)
+	}
+
 	// Enum: create named model when in a nested context
 	if len(s.Enum) > 0 {
+		enum := enumSchema(s, disc)
+		if enum.Kind != ir.IRKindEnum {
+			return enum
+		}
 		baseName := parentName
 		if propName != "" {
 			baseName = baseName + "_" + toPascal(propName)
@@ -196,165 +227,234 @@ func schemaRefToIRWithNaming(doc *openapi3.T, sr *openapi3.SchemaRef, parentName
 			baseName = baseName + "_Item"
 		}
 		if _, ok := seen[baseName]; !ok {
-			vals := make([]string, 0, len(s.Enum))
-			for _, v := range s.Enum {
-				vals = append(vals, fmt.Sprint(v))
-			}
 			md := ir.IRModelDef{
 				Name:        baseName,
-				Schema:      ir.IRSchema{Kind: ir.IRKindEnum, EnumValues: vals, EnumRaw: s.Enum, EnumBase: inferEnumBaseKind(s), Nullable: s.Nullable, Discriminator: disc},
+				Schema:      enum,
 				Annotations: extractAnnotations(sr),
 			}
 			*out = append(*out, md)
 			seen[baseName] = struct{}{}
 		}
-		return ir.IRSchema{Kind: ir.IRKindRef, Ref: baseName, Nullable: s.Nullable}
-	}
-
-	if s.Type != nil {
-		switch {
-		case s.Type.Is(openapi3.TypeString):
-			return ir.IRSchema{Kind: ir.IRKindString, Nullable: s.Nullable, Format: s.Format, Discriminator: disc}
-		case s.Type.Is(openapi3.TypeInteger):
-			return ir.IRSchema{Kind: ir.IRKindInteger, Nullable: s.Nullable, Discriminator: disc}
-		case s.Type.Is(openapi3.TypeNumber):
-			return ir.IRSchema{Kind: ir.IRKindNumber, Nullable: s.Nullable, Discriminator: disc}
-		case s.Type.Is(openapi3.TypeBoolean):
-			return ir.IRSchema{Kind: ir.IRKindBoolean, Nullable: s.Nullable, Discriminator: disc}
-		case s.Type.Is(openapi3.TypeArray):
-			// Name array item if it is an inline object or enum
-			itemSchema := s.Items
-			if itemSchema != nil && itemSchema.Value != nil {
-				itemVal := itemSchema.Value
-				if len(itemVal.Enum) > 0 {
-					// Use enum naming path
-					ref := schemaRefToIRWithNaming(doc, itemSchema, parentName, propName, true, out, seen)
-					return ir.IRSchema{Kind: ir.IRKindArray, Items: &ref, Nullable: s.Nullable, Discriminator: disc}
+		return ir.IRSchema{Kind: ir.IRKindRef, Ref: baseName, Nullable: enum.Nullable}
+	}
+
+	switch {
+	case s.Type.Is(openapi3.TypeString):
+		return ir.IRSchema{Kind: ir.IRKindString, Nullable: s.Nullable, Format: s.Format, Discriminator: disc}
+	case s.Type.Is(openapi3.TypeInteger):
+		return withNumericBounds(ir.IRSchema{Kind: ir.IRKindInteger, Nullable: s.Nullable, Format: s.Format, Discriminator: disc}, s)
+	case s.Type.Is(openapi3.TypeNumber):
+		return withNumericBounds(ir.IRSchema{Kind: ir.IRKindNumber, Nullable: s.Nullable, Format: s.Format, Discriminator: disc}, s)
+	case s.Type.Is(openapi3.TypeBoolean):
+		return ir.IRSchema{Kind: ir.IRKindBoolean, Nullable: s.Nullable, Discriminator: disc}
+	case isArraySchema(s):
+		// Name array item if it is an inline object or enum
+		itemSchema := s.Items
+		if itemSchema != nil && itemSchema.Value != nil {
+			itemVal := itemSchema.Value
+			if len(itemVal.Enum) > 0 {
+				// Use enum naming path
+				ref := schemaRefToIRWithNaming(doc, itemSchema, parentName, propName, true, out, seen)
+				return ir.IRSchema{Kind: ir.IRKindArray, Items: &ref, Nullable: s.Nullable, Discriminator: disc}
+			}
+			if isObjectSchema(itemVal) && len(itemVal.Properties) > 0 {
+				base := parentName
+				if propName != "" {
+					base = base + "_" + toPascal(propName)
 				}
-				if itemVal.Type != nil && itemVal.Type.Is(openapi3.TypeObject) && len(itemVal.Properties) > 0 {
-					base := parentName
-					if propName != "" {
-						base = base + "_" + toPascal(propName)
-					}
-					name := base + "_Item"
-					if _, ok := seen[name]; !ok {
-						def := buildNamedObjectDef(doc, itemVal, name, out, seen)
-						*out = append(*out, def)
-						seen[name] = struct{}{}
-					}
-					ref := ir.IRSchema{Kind: ir.IRKindRef, Ref: name}
-					return ir.IRSchema{Kind: ir.IRKindArray, Items: &ref, Nullable: s.Nullable, Discriminator: disc}
+				name := base + "_Item"
+				if _, ok := seen[name]; !ok {
+					def := buildNamedObjectDef(doc, itemVal, name, out, seen)
+					*out = append(*out, def)
+					seen[name] = struct{}{}
 				}
+				// Nullability of the item stays on the item ref, not on the array or the model
+				ref := ir.IRSchema{Kind: ir.IRKindRef, Ref: name, Nullable: itemVal.Nullable}
+				return ir.IRSchema{Kind: ir.IRKindArray, Items: &ref, Nullable: s.Nullable, Discriminator: disc}
 			}
-			itm := schemaRefToIRWithNaming(doc, s.Items, parentName, propName, true, out, seen)
-			return ir.IRSchema{Kind: ir.IRKindArray, Items: &itm, Nullable: s.Nullable, Discriminator: disc}
-		case s.Type.Is(openapi3.TypeObject):
-			// Build object and emit named model defs for nested inline object properties
-			// Properties in deterministic order
-			propNames := make([]string, 0, len(s.Properties))
-			for n := range s.Properties {
-				propNames = append(propNames, n)
-			}
-			sort.Strings(propNames)
-			fields := make([]ir.IRField, 0, len(propNames))
-			for _, n := range propNames {
-				pr := s.Properties[n]
-				val := pr.Value
-				var fType ir.IRSchema
-				if (propName != "" || isArrayItem) && val != nil && val.Type != nil && val.Type.Is(openapi3.TypeObject) && len(val.Properties) > 0 {
-					// Nested inline object under a non-top-level object -> name it
-					base := parentName
-					if propName != "" {
-						base = base + "_" + toPascal(propName)
-					}
-					name := base + "_" + toPascal(n)
-					if _, ok := seen[name]; !ok {
-						def := buildNamedObjectDef(doc, val, name, out, seen)
-						*out = append(*out, def)
-						seen[name] = struct{}{}
-					}
-					fType = ir.IRSchema{Kind: ir.IRKindRef, Ref: name}
-				} else {
-					fType = schemaRefToIRWithNaming(doc, pr, parentName, n, false, out, seen)
+		}
+		itm := schemaRefToIRWithNaming(doc, s.Items, parentName, propName, true, out, seen)
+		return ir.IRSchema{Kind: ir.IRKindArray, Items: &itm, Nullable: s.Nullable, Discriminator: disc}
+	case isObjectSchema(s):
+		// Build object and emit named model defs for nested inline object properties
+		// Properties in deterministic order
+		propNames := make([]string, 0, len(s.Properties))
+		for n := range s.Properties {
+			propNames = append(propNames, n)
+		}
+		sort.Strings(propNames)
+		fields := make([]ir.IRField, 0, len(propNames))
+		for _, n := range propNames {
+			pr := s.Properties[n]
+			val := pr.Value
+			var fType ir.IRSchema
+			if (propName != "" || isArrayItem) && val != nil && isObjectSchema(val) && len(val.Properties) > 0 {
+				// Nested inline object under a non-top-level object -> name it
+				base := parentName
+				if propName != "" {
+					base = base + "_" + toPascal(propName)
 				}
-				required := false
-				for _, r := range s.Required {
-					if r == n {
-						required = true
-						break
-					}
+				name := base + "_" + toPascal(n)
+				if _, ok := seen[name]; !ok {
+					def := buildNamedObjectDef(doc, val, name, out, seen)
+					*out = append(*out, def)
+					seen[name] = struct{}{}
 				}
-				fields = append(fields, ir.IRField{Name: n, Type: &fType, Required: required, Annotations: extractAnnotations(pr)})
+				fType = ir.IRSchema{Kind: ir.IRKindRef, Ref: name}
+			} else {
+				fType = schemaRefToIRWithNaming(doc, pr, parentName, n, false, out, seen)
 			}
-			var addl *ir.IRSchema
-			if s.AdditionalProperties.Schema != nil {
-				addlSchema := s.AdditionalProperties.Schema
-
-				// If additionalProperties is an object with properties, merge them into the parent
-				if addlSchema.Value != nil && addlSchema.Value.Type != nil &&
-					addlSchema.Value.Type.Is(openapi3.TypeObject) && len(addlSchema.Value.Properties) > 0 {
-
-					// Merge additionalProperties into the current object's fields
-					addlPropNames := make([]string, 0, len(addlSchema.Value.Properties))
-					for n := range addlSchema.Value.Properties {
-						addlPropNames = append(addlPropNames, n)
-					}
-					sort.Strings(addlPropNames)
-
-					for _, n := range addlPropNames {
-						pr := addlSchema.Value.Properties[n]
-						fType := schemaRefToIRWithNaming(doc, pr, parentName, n, false, out, seen)
-						required := false
-						for _, r := range addlSchema.Value.Required {
-							if r == n {
-								required = true
-								break
-							}
-						}
-						fields = append(fields, ir.IRField{Name: n, Type: &fType, Required: required, Annotations: extractAnnotations(pr)})
-					}
+			required := false
+			for _, r := range s.Required {
+				if r == n {
+					required = true
+					break
+				}
+			}
+			fields = append(fields, ir.IRField{Name: n, Type: &fType, Required: required, Annotations: extractAnnotations(pr)})
+		}
+		var addl *ir.IRSchema
+		if s.AdditionalProperties.Schema != nil {
+			addlSchema := s.AdditionalProperties.Schema
 
-					// Don't set addl since we merged the properties
-					addl = nil
-				} else {
-					// For non-object additionalProperties, keep the current behavior
-					addlParent := parentName
-					if propName != "" {
-						addlParent = addlParent + "_" + toPascal(propName)
-					}
-					if isArrayItem {
-						addlParent = addlParent + "_Item"
+			// If additionalProperties is an object with properties, merge them into the parent
+			if addlSchema.Value != nil && isObjectSchema(addlSchema.Value) && len(addlSchema.Value.Properties) > 0 {
+
+				// Merge additionalProperties into the current object's fields
+				addlPropNames := make([]string, 0, len(addlSchema.Value.Properties))
+				for n := range addlSchema.Value.Properties {
+					addlPropNames = append(addlPropNames, n)
+				}
+				sort.Strings(addlPropNames)
+
+				for _, n := range addlPropNames {
+					pr := addlSchema.Value.Properties[n]
+					fType := schemaRefToIRWithNaming(doc, pr, parentName, n, false, out, seen)
+					required := false
+					for _, r := range addlSchema.Value.Required {
+						if r == n {
+							required = true
+							break
+						}
 					}
-					aps := schemaRefToIRWithNaming(doc, s.AdditionalProperties.Schema, addlParent, "Properties", false, out, seen)
-					addl = &aps
+					fields = append(fields, ir.IRField{Name: n, Type: &fType, Required: required, Annotations: extractAnnotations(pr)})
 				}
-			}
-			// If this object itself is nested (not top-level), produce a named ref
-			if propName != "" || isArrayItem {
-				base := parentName
+
+				// Don't set addl since we merged the properties
+				addl = nil
+			} else {
+				// For non-object additionalProperties, keep the current behavior
+				addlParent := parentName
 				if propName != "" {
-					base = base + "_" + toPascal(propName)
+					addlParent = addlParent + "_" + toPascal(propName)
 				}
 				if isArrayItem {
-					base = base + "_Item"
+					addlParent = addlParent + "_Item"
 				}
-				if _, ok := seen[base]; !ok {
-					def := ir.IRModelDef{
-						Name:        base,
-						Schema:      ir.IRSchema{Kind: ir.IRKindObject, Properties: fields, AdditionalProperties: addl, Nullable: s.Nullable, Discriminator: disc},
-						Annotations: extractAnnotations(sr),
-					}
-					*out = append(*out, def)
-					seen[base] = struct{}{}
+				aps := schemaRefToIRWithNaming(doc, s.AdditionalProperties.Schema, addlParent, "Properties", false, out, seen)
+				addl = &aps
+			}
+		}
+		// If this object itself is nested (not top-level), produce a named ref
+		if propName != "" || isArrayItem {
+			base := parentName
+			if propName != "" {
+				base = base + "_" + toPascal(propName)
+			}
+			if isArrayItem {
+				base = base + "_Item"
+			}
+			if _, ok := seen[base]; !ok {
+				def := ir.IRModelDef{
+					Name:        base,
+					Schema:      withPropertyCounts(ir.IRSchema{Kind: ir.IRKindObject, Properties: fields, AdditionalProperties: addl, Discriminator: disc}, s),
+					Annotations: extractAnnotations(sr),
 				}
-				return ir.IRSchema{Kind: ir.IRKindRef, Ref: base}
+				*out = append(*out, def)
+				seen[base] = struct{}{}
 			}
-			return ir.IRSchema{Kind: ir.IRKindObject, Properties: fields, AdditionalProperties: addl, Nullable: s.Nullable, Discriminator: disc}
+			// Nullability belongs to this usage, not to the named model
+			return ir.IRSchema{Kind: ir.IRKindRef, Ref: base, Nullable: s.Nullable}
 		}
+		return withPropertyCounts(ir.IRSchema{Kind: ir.IRKindObject, Properties: fields, AdditionalProperties: addl, Nullable: s.Nullable, Discriminator: disc}, s)
 	}
 	return ir.IRSchema{Kind: ir.IRKindUnknown, Nullable: s.Nullable, Discriminator: disc}
 }
 
+// withPropertyCounts copies the minProperties and maxProperties bounds of s onto an object schema
+func withPropertyCounts(out ir.IRSchema, s *openapi3.Schema) ir.IRSchema {
+	if s.MinProps > 0 {
+		n := s.MinProps
+		out.MinProperties = &n
+	}
+	if s.MaxProps != nil {
+		n := *s.MaxProps
+		out.MaxProperties = &n
+	}
+	return out
+}
+
+// withNumericBounds copies the exclusive bounds and multipleOf of s onto a number or integer
+// schema. Exclusive bounds come in two forms: the OpenAPI 3.0 boolean exclusiveMinimum that
+// makes minimum exclusive, and the 3.1 number that is the bound itself (moved to an extension
+// while loading).
+func withNumericBounds(out ir.IRSchema, s *openapi3.Schema) ir.IRSchema {
+	if v, ok := numberValue(s.Extensions[openapi.ExclusiveMinimumExtension]); ok {
+		out.ExclusiveMinimum = &v
+	} else if s.ExclusiveMin && s.Min != nil {
+		n := *s.Min
+		out.ExclusiveMinimum = &n
+	}
+	if v, ok := numberValue(s.Extensions[openapi.ExclusiveMaximumExtension]); ok {
+		out.ExclusiveMaximum = &v
+	} else if s.ExclusiveMax && s.Max != nil {
+		n := *s.Max
+		out.ExclusiveMaximum = &n
+	}
+	if s.MultipleOf != nil && *s.MultipleOf > 0 {
+		n := *s.MultipleOf
+		out.MultipleOf = &n
+	}
+	return out
+}
+
+// numberValue returns v as a float64 when it is a number
+func numberValue(v any) (float64, bool) {
+	switch x := v.(type) {
+	case float64:
+		return x, true
+	case float32:
+		return float64(x), true
+	case int:
+		return float64(x), true
+	case int64:
+		return float64(x), true
+	case json.Number:
+		f, err := x.Float64()
+		return f, err == nil
+	}
+	return 0, false
+}
+
+// isObjectSchema reports whether s describes an object: its type is object, or it has no type
+// but uses object keywords (properties, additionalProperties or required)
+func isObjectSchema(s *openapi3.Schema) bool {
+	if len(s.Type.Slice()) > 0 {
+		return s.Type.Is(openapi3.TypeObject)
+	}
+	return len(s.Properties) > 0 || s.AdditionalProperties.Schema != nil ||
+		s.AdditionalProperties.Has != nil || len(s.Required) > 0
+}
+
+// isArraySchema reports whether s describes an array: its type is array, or it has no type
+// but has items
+func isArraySchema(s *openapi3.Schema) bool {
+	if len(s.Type.Slice()) > 0 {
+		return s.Type.Is(openapi3.TypeArray)
+	}
+	return s.Items != nil
+}
+
 // extractAnnotations extracts annotations from a schema reference
 func extractAnnotations(sr *openapi3.SchemaRef) ir.IRAnnotations {
 	var a ir.IRAnnotations
@@ -368,12 +468,124 @@ func extractAnnotations(sr *openapi3.SchemaRef) ir.IRAnnotations {
 	a.ReadOnly = s.ReadOnly
 	a.WriteOnly = s.WriteOnly
 	a.Default = s.Default
-	if s.Example != nil {
-		a.Examples = []any{s.Example}
-	}
+	a.Examples = schemaExamples(s)
 	return a
 }
 
+// singleComposition returns the only subschema of an allOf, anyOf or oneOf with exactly one
+// member. Schemas that combine it with a discriminator or their own properties are kept as is.
+func singleComposition(s *openapi3.Schema) (*openapi3.SchemaRef, bool) {
+	if s.Discriminator != nil || len(s.Properties) > 0 {
+		return nil, false
+	}
+	var subs openapi3.SchemaRefs
+	switch {
+	case len(s.OneOf) > 0:
+		subs = s.OneOf
+	case len(s.AnyOf) > 0:
+		subs = s.AnyOf
+	default:
+		subs = s.AllOf
+	}
+	if len(subs) != 1 || subs[0] == nil || len(s.OneOf)+len(s.AnyOf)+len(s.AllOf) != 1 {
+		return nil, false
+	}
+	return subs[0], true
+}
+
+// schemaConst returns the OpenAPI 3.1 `const` value of a schema. kin-openapi has no
+// field for it, so it surfaces as an extension.
+func schemaConst(s *openapi3.Schema) (any, bool) {
+	v, ok := s.Extensions["const"]
+	if !ok || v == nil {
+		return nil, false
+	}
+	return v, true
+}
+
+// schemaExamples returns the examples of a schema: the OpenAPI 3.1 `examples` array, which
+// kin-openapi has no field for and surfaces as an extension, then the 3.0 `example` unless it
+// repeats one of them
+func schemaExamples(s *openapi3.Schema) []any {
+	if s == nil {
+		return nil
+	}
+	var out []any
+	if examples, ok := s.Extensions["examples"].([]any); ok {
+		out = append(out, examples...)
+	}
+	if s.Example != nil && !slices.ContainsFunc(out, func(ex any) bool { return reflect.DeepEqual(ex, s.Example) }) {
+		out = append(out, s.Example)
+	}
+	return out
+}
+
+// schemaEnumValues returns the allowed values of a schema: its enum, or the const
+// value as a single-value enum
+func schemaEnumValues(s *openapi3.Schema) []any {
+	if len(s.Enum) > 0 {
+		return s.Enum
+	}
+	if v, ok := schemaConst(s); ok {
+		return []any{v}
+	}
+	return nil
+}
+
+// enumSchema builds an enum IR schema from the allowed values of s. A null member is not a
+// value of the enum: it is dropped and makes the enum nullable, and an enum of null alone is
+// the null schema.
+func enumSchema(s *openapi3.Schema, disc *ir.IRDiscriminator) ir.IRSchema {
+	all := schemaEnumValues(s)
+	allNames := enumNames(s, len(all))
+	raw := make([]any, 0, len(all))
+	vals := make([]string, 0, len(all))
+	var names []string
+	for i, v := range all {
+		if v == nil {
+			continue
+		}
+		raw = append(raw, v)
+		vals = append(vals, ir.FormatEnumValue(v))
+		if allNames != nil {
+			names = append(names, allNames[i])
+		}
+	}
+	if len(raw) == 0 {
+		return ir.IRSchema{Kind: ir.IRKindNull, Nullable: true, Discriminator: disc}
+	}
+	if allNames == nil {
+		// x-enum-varnames may leave out the null member
+		names = enumNames(s, len(raw))
+	}
+	nullable := s.Nullable || len(raw) < len(all)
+	return ir.IRSchema{Kind: ir.IRKindEnum, EnumValues: vals, EnumRaw: raw, EnumBase: inferEnumBaseKind(s), EnumNames: names, Nullable: nullable, Discriminator: disc}
+}
+
+// enumNames returns the member names of the x-enum-varnames (or x-enumNames) extension,
+// or nil unless it lists one non-empty string per enum value
+func enumNames(s *openapi3.Schema, count int) []string {
+	for _, key := range []string{"x-enum-varnames", "x-enumNames"} {
+		list, ok := s.Extensions[key].([]any)
+		if !ok {
+			continue
+		}
+		if len(list) != count {
+			return nil
+		}
+		names := make([]string, 0, count)
+		for _, v := range list {
+			name, ok := v.(string)
+			if !ok || name == "" {
+				return nil
+			}
+			names = append(names, name)
+		}
+		return names
+	}
+	return nil
+}
+
 // inferEnumBaseKind infers the base kind for an enum
 func inferEnumBaseKind(s *openapi3.Schema) ir.IRSchemaKind {
 	// Prefer explicit type when present
@@ -389,22 +601,57 @@ func inferEnumBaseKind(s *openapi3.Schema) ir.IRSchemaKind {
 			return ir.IRKindBoolean
 		}
 	}
-	// Fallback: inspect first enum value
-	if len(s.Enum) > 0 {
-		switch s.Enum[0].(type) {
+	// Fallback: inspect the first enum value that is not null. Specs decode every number as
+	// float64, so numbers are integers when all the numeric values are integral.
+	values := schemaEnumValues(s)
+	for _, v := range values {
+		switch v.(type) {
+		case nil:
+			continue
 		case string:
 			return ir.IRKindString
-		case int, int32, int64:
-			return ir.IRKindInteger
-		case float32, float64:
-			return ir.IRKindNumber
 		case bool:
 			return ir.IRKindBoolean
+		case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64, float32, float64, json.Number:
+			for _, other := range values {
+				if integral, numeric := integralEnumValue(other); numeric && !integral {
+					return ir.IRKindNumber
+				}
+			}
+			return ir.IRKindInteger
 		}
+		break
 	}
 	return ir.IRKindUnknown
 }
 
+// integralEnumValue reports whether v is a number, and whether it is a whole number that fits
+// an int64. A float is integral when it has no fraction, e.g. 3 decoded as float64(3).
+func integralEnumValue(v any) (integral, numeric bool) {
+	switch x := v.(type) {
+	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32:
+		return true, true
+	case uint64:
+		return x <= math.MaxInt64, true
+	case float32:
+		return integralFloat(float64(x)), true
+	case float64:
+		return integralFloat(x), true
+	case json.Number:
+		if _, err := x.Int64(); err == nil {
+			return true, true
+		}
+		f, err := x.Float64()
+		return err == nil && integralFloat(f), true
+	}
+	return false, false
+}
+
+// integralFloat reports whether f is a whole number in the int64 range
+func integralFloat(f float64) bool {
+	return f == math.Trunc(f) && f >= math.MinInt64 && f < math.MaxInt64
+}
+
 // buildNamedObjectDef constructs a named object model def for an inline object schema
 func buildNamedObjectDef(doc *openapi3.T, s *openapi3.Schema, name string, out *[]ir.IRModelDef, seen map[string]struct{}) ir.IRModelDef {
 	// Properties in deterministic order
@@ -431,8 +678,7 @@ func buildNamedObjectDef(doc *openapi3.T, s *openapi3.Schema, name string, out *
 		addlSchema := s.AdditionalProperties.Schema
 
 		// If additionalProperties is an object with properties, merge them into the parent
-		if addlSchema.Value != nil && addlSchema.Value.Type != nil &&
-			addlSchema.Value.Type.Is(openapi3.TypeObject) && len(addlSchema.Value.Properties) > 0 {
+		if addlSchema.Value != nil && isObjectSchema(addlSchema.Value) && len(addlSchema.Value.Properties) > 0 {
 
 			// Merge additionalProperties into the current object's fields
 			addlPropNames := make([]string, 0, len(addlSchema.Value.Properties))
@@ -464,7 +710,7 @@ func buildNamedObjectDef(doc *openapi3.T, s *openapi3.Schema, name string, out *
 	}
 	return ir.IRModelDef{
 		Name:        name,
-		Schema:      ir.IRSchema{Kind: ir.IRKindObject, Properties: fields, AdditionalProperties: addl, Nullable: s.Nullable},
+		Schema:      ir.IRSchema{Kind: ir.IRKindObject, Properties: fields, AdditionalProperties: addl},
 		Annotations: ir.IRAnnotations{Title: s.Title, Description: s.Description, Deprecated: s.Deprecated, ReadOnly: s.ReadOnly, WriteOnly: s.WriteOnly, Default: s.Default},
 	}
 }
@@ -548,3 +794,45 @@ func splitCamelCaseSchema(s string) []string {
 func isUppercaseSchema(r rune) bool {
 	return r >= 'A' && r <= 'Z'
 }
+
+// typeOverrideExtensions maps the vendor extensions that override the generated type to
+// the language key they are stored under in IRSchema.TypeOverrides
+var typeOverrideExtensions = map[string]string{
+	"x-go-type":     "go",
+	"x-ts-type":     "ts",
+	"x-python-type": "python",
+}
+
+// typeImportExtensions maps the vendor extensions naming the import an override needs to
+// the language key they are stored under in IRSchema.TypeImports
+var typeImportExtensions = map[string]string{
+	"x-go-import":     "go",
+	"x-python-import": "python",
+}
+
+// withTypeOverrides records the x-go-type, x-ts-type and x-python-type extensions (and
+// their imports) of a schema on its IR form. For references the extensions of the target
+// schema apply, so every use of the component gets the native type.
+func withTypeOverrides(sr *openapi3.SchemaRef, s ir.IRSchema) ir.IRSchema {
+	if sr == nil || sr.Value == nil || len(sr.Value.Extensions) == 0 {
+		return s
+	}
+	ext := sr.Value.Extensions
+	for name, lang := range typeOverrideExtensions {
+		if v, ok := ext[name].(string); ok && v != "" {
+			if s.TypeOverrides == nil {
+				s.TypeOverrides = map[string]string{}
+			}
+			s.TypeOverrides[lang] = v
+		}
+	}
+	for name, lang := range typeImportExtensions {
+		if v, ok := ext[name].(string); ok && v != "" && s.TypeOverrides[lang] != "" {
+			if s.TypeImports == nil {
+				s.TypeImports = map[string]string{}
+			}
+			s.TypeImports[lang] = v
+		}
+	}
+	return s
+}
@@ -1,7 +1,15 @@
 package generator
 
 import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
 	"testing"
+
+	"github.com/blimu-dev/sdk-gen/pkg/ir"
+	"github.com/blimu-dev/sdk-gen/pkg/openapi"
+	"github.com/getkin/kin-openapi/openapi3"
 )
 
 func TestToPascal(t *testing.T) {
@@ -59,3 +67,522 @@ func TestSplitCamelCaseSchema(t *testing.T) {
 		}
 	}
 }
+
+func TestConstInOneOfMembers(t *testing.T) {
+	doc, err := openapi3.NewLoader().LoadFromFile("testdata/const-oneof.yaml")
+	if err != nil {
+		t.Fatalf("load fixture: %v", err)
+	}
+	result, err := BuildIR(doc)
+	if err != nil {
+		t.Fatalf("BuildIR: %v", err)
+	}
+
+	fields := map[string]ir.IRField{}
+	for _, md := range result.ModelDefs {
+		for _, f := range md.Schema.Properties {
+			fields[md.Name+"."+f.Name] = f
+		}
+	}
+
+	tests := []struct {
+		field string
+		base  ir.IRSchemaKind
+		value string
+	}{
+		{"Dog.petType", ir.IRKindString, "dog"},
+		{"Cat.petType", ir.IRKindString, "cat"},
+		{"Cat.lives", ir.IRKindInteger, "9"},
+	}
+
+	for _, test := range tests {
+		f, ok := fields[test.field]
+		if !ok || f.Type == nil {
+			t.Errorf("%s: field not found", test.field)
+			continue
+		}
+		s := *f.Type
+		if s.Kind != ir.IRKindEnum || s.EnumBase != test.base || len(s.EnumValues) != 1 || s.EnumValues[0] != test.value {
+			t.Errorf("%s: got kind=%s base=%s values=%v, expected single-value %s enum %q", test.field, s.Kind, s.EnumBase, s.EnumValues, test.base, test.value)
+		}
+	}
+	if len(result.ModelDefs) != 3 {
+		t.Errorf("const values should stay inline, got %d model defs", len(result.ModelDefs))
+	}
+}
+
+func TestSingleComposition(t *testing.T) {
+	userRef := &openapi3.SchemaRef{Ref: "#/components/schemas/User"}
+	tests := []struct {
+		name     string
+		schema   *openapi3.Schema
+		expected ir.IRSchema
+	}{
+		{
+			name:     "nullable allOf single ref",
+			schema:   &openapi3.Schema{AllOf: openapi3.SchemaRefs{userRef}, Nullable: true},
+			expected: ir.IRSchema{Kind: ir.IRKindRef, Ref: "User", Nullable: true},
+		},
+		{
+			name:     "non-nullable allOf single ref",
+			schema:   &openapi3.Schema{AllOf: openapi3.SchemaRefs{userRef}},
+			expected: ir.IRSchema{Kind: ir.IRKindRef, Ref: "User"},
+		},
+		{
+			name:     "oneOf single ref",
+			schema:   &openapi3.Schema{OneOf: openapi3.SchemaRefs{userRef}},
+			expected: ir.IRSchema{Kind: ir.IRKindRef, Ref: "User"},
+		},
+		{
+			name:     "nullable anyOf single string",
+			schema:   &openapi3.Schema{AnyOf: openapi3.SchemaRefs{{Value: &openapi3.Schema{Type: &openapi3.Types{openapi3.TypeString}}}}, Nullable: true},
+			expected: ir.IRSchema{Kind: ir.IRKindString, Nullable: true},
+		},
+		{
+			name:     "inner nullability is kept",
+			schema:   &openapi3.Schema{AllOf: openapi3.SchemaRefs{{Value: &openapi3.Schema{Type: &openapi3.Types{openapi3.TypeInteger}, Nullable: true}}}},
+			expected: ir.IRSchema{Kind: ir.IRKindInteger, Nullable: true},
+		},
+		{
+			name:     "single oneOf with a discriminator",
+			schema:   &openapi3.Schema{OneOf: openapi3.SchemaRefs{userRef}, Discriminator: &openapi3.Discriminator{PropertyName: "kind"}},
+			expected: ir.IRSchema{Kind: ir.IRKindOneOf},
+		},
+		{
+			name:     "nullable allOf with extra members",
+			schema:   &openapi3.Schema{AllOf: openapi3.SchemaRefs{userRef, {Value: &openapi3.Schema{Type: &openapi3.Types{openapi3.TypeObject}}}}, Nullable: true},
+			expected: ir.IRSchema{Kind: ir.IRKindAllOf, Nullable: true},
+		},
+	}
+
+	for _, test := range tests {
+		sr := &openapi3.SchemaRef{Value: test.schema}
+		for _, got := range []ir.IRSchema{
+			schemaRefToIR(nil, sr),
+			schemaRefToIRWithNaming(nil, sr, "Parent", "owner", false, &[]ir.IRModelDef{}, map[string]struct{}{}),
+		} {
+			if got.Kind != test.expected.Kind || got.Ref != test.expected.Ref || got.Nullable != test.expected.Nullable {
+				t.Errorf("%s: got kind=%s ref=%q nullable=%v, expected kind=%s ref=%q nullable=%v",
+					test.name, got.Kind, got.Ref, got.Nullable, test.expected.Kind, test.expected.Ref, test.expected.Nullable)
+			}
+		}
+	}
+}
+
+func TestSingleCompositionNamesInlineObject(t *testing.T) {
+	inline := &openapi3.SchemaRef{Value: &openapi3.Schema{
+		Type:       &openapi3.Types{openapi3.TypeObject},
+		Properties: openapi3.Schemas{"id": {Value: &openapi3.Schema{Type: &openapi3.Types{openapi3.TypeString}}}},
+	}}
+	sr := &openapi3.SchemaRef{Value: &openapi3.Schema{AllOf: openapi3.SchemaRefs{inline}, Nullable: true}}
+
+	var defs []ir.IRModelDef
+	got := schemaRefToIRWithNaming(nil, sr, "Parent", "owner", false, &defs, map[string]struct{}{})
+	if got.Kind != ir.IRKindRef || got.Ref != "Parent_Owner" || !got.Nullable {
+		t.Errorf("got kind=%s ref=%q nullable=%v, expected a nullable ref to Parent_Owner", got.Kind, got.Ref, got.Nullable)
+	}
+	if len(defs) != 1 || defs[0].Name != "Parent_Owner" {
+		t.Errorf("expected a Parent_Owner model def, got %+v", defs)
+	}
+}
+
+func TestNullableArrayItems(t *testing.T) {
+	str := func(nullable bool) *openapi3.SchemaRef {
+		return &openapi3.SchemaRef{Value: &openapi3.Schema{Type: &openapi3.Types{openapi3.TypeString}, Nullable: nullable}}
+	}
+	obj := func(nullable bool) *openapi3.SchemaRef {
+		return &openapi3.SchemaRef{Value: &openapi3.Schema{
+			Type:       &openapi3.Types{openapi3.TypeObject},
+			Properties: openapi3.Schemas{"id": str(false)},
+			Nullable:   nullable,
+		}}
+	}
+	array := func(items *openapi3.SchemaRef, nullable bool) *openapi3.SchemaRef {
+		return &openapi3.SchemaRef{Value: &openapi3.Schema{Type: &openapi3.Types{openapi3.TypeArray}, Items: items, Nullable: nullable}}
+	}
+
+	tests := []struct {
+		name          string
+		schema        *openapi3.SchemaRef
+		arrayNullable bool
+		itemNullable  bool
+	}{
+		{name: "nullable array", schema: array(str(false), true), arrayNullable: true},
+		{name: "nullable items", schema: array(str(true), false), itemNullable: true},
+		{name: "both", schema: array(str(true), true), arrayNullable: true, itemNullable: true},
+		{name: "nullable object items", schema: array(obj(true), false), itemNullable: true},
+		{name: "nullable array of objects", schema: array(obj(false), true), arrayNullable: true},
+	}
+
+	for _, test := range tests {
+		var defs []ir.IRModelDef
+		for _, got := range []ir.IRSchema{
+			schemaRefToIR(nil, test.schema),
+			schemaRefToIRWithNaming(nil, test.schema, "Parent", "tags", false, &defs, map[string]struct{}{}),
+		} {
+			if got.Kind != ir.IRKindArray || got.Items == nil {
+				t.Fatalf("%s: expected an array, got %+v", test.name, got)
+			}
+			if got.Nullable != test.arrayNullable || got.Items.Nullable != test.itemNullable {
+				t.Errorf("%s: array nullable=%v items nullable=%v, expected %v and %v",
+					test.name, got.Nullable, got.Items.Nullable, test.arrayNullable, test.itemNullable)
+			}
+		}
+		// Named item models never carry the usage's nullability
+		for _, def := range defs {
+			if def.Schema.Nullable {
+				t.Errorf("%s: model %s should not be nullable", test.name, def.Name)
+			}
+		}
+	}
+}
+
+func TestTypeOverrides(t *testing.T) {
+	money := &openapi3.SchemaRef{Value: &openapi3.Schema{
+		Type: &openapi3.Types{openapi3.TypeString},
+		Extensions: map[string]any{
+			"x-go-type":       "decimal.Decimal",
+			"x-go-import":     "github.com/shopspring/decimal",
+			"x-ts-type":       "Money",
+			"x-python-type":   "decimal.Decimal",
+			"x-python-import": "decimal",
+		},
+	}}
+	ref := &openapi3.SchemaRef{Ref: "#/components/schemas/Money", Value: money.Value}
+	obj := &openapi3.SchemaRef{Value: &openapi3.Schema{
+		Type:       &openapi3.Types{openapi3.TypeObject},
+		Properties: openapi3.Schemas{"price": ref},
+	}}
+
+	var defs []ir.IRModelDef
+	direct := schemaRefToIR(nil, money)
+	field := schemaRefToIRWithNaming(nil, obj, "Order", "", false, &defs, map[string]struct{}{}).Properties[0].Type
+	for _, got := range []ir.IRSchema{direct, *field} {
+		if got.TypeOverrides["go"] != "decimal.Decimal" || got.TypeOverrides["ts"] != "Money" || got.TypeOverrides["python"] != "decimal.Decimal" {
+			t.Errorf("unexpected overrides: %v", got.TypeOverrides)
+		}
+		if got.TypeImports["go"] != "github.com/shopspring/decimal" || got.TypeImports["python"] != "decimal" {
+			t.Errorf("unexpected imports: %v", got.TypeImports)
+		}
+	}
+	if field.Kind != ir.IRKindRef || field.Ref != "Money" {
+		t.Errorf("expected the field to stay a ref, got %+v", field)
+	}
+
+	// An import without a matching type override is ignored
+	plain := schemaRefToIR(nil, &openapi3.SchemaRef{Value: &openapi3.Schema{Extensions: map[string]any{"x-go-import": "fmt"}}})
+	if plain.TypeOverrides != nil || plain.TypeImports != nil {
+		t.Errorf("expected no overrides, got %v %v", plain.TypeOverrides, plain.TypeImports)
+	}
+}
+
+func TestEnumNames(t *testing.T) {
+	enum := func(ext map[string]any) ir.IRSchema {
+		return schemaRefToIR(nil, &openapi3.SchemaRef{Value: &openapi3.Schema{
+			Type:       &openapi3.Types{openapi3.TypeInteger},
+			Enum:       []any{float64(1), float64(2)},
+			Extensions: ext,
+		}})
+	}
+
+	tests := []struct {
+		name     string
+		ext      map[string]any
+		expected []string
+	}{
+		{"x-enum-varnames", map[string]any{"x-enum-varnames": []any{"Active", "Banned"}}, []string{"Active", "Banned"}},
+		{"x-enumNames", map[string]any{"x-enumNames": []any{"Active", "Banned"}}, []string{"Active", "Banned"}},
+		{"count mismatch", map[string]any{"x-enum-varnames": []any{"Active"}}, nil},
+		{"non-string name", map[string]any{"x-enum-varnames": []any{"Active", float64(2)}}, nil},
+		{"absent", nil, nil},
+	}
+	for _, test := range tests {
+		got := enum(test.ext)
+		if got.Kind != ir.IRKindEnum || !reflect.DeepEqual(got.EnumNames, test.expected) {
+			t.Errorf("%s: EnumNames = %v, expected %v", test.name, got.EnumNames, test.expected)
+		}
+	}
+}
+
+func TestTypelessObjectAndArraySchemas(t *testing.T) {
+	doc, err := openapi3.NewLoader().LoadFromFile("testdata/typeless.yaml")
+	if err != nil {
+		t.Fatalf("load fixture: %v", err)
+	}
+	result, err := BuildIR(doc)
+	if err != nil {
+		t.Fatalf("BuildIR: %v", err)
+	}
+
+	defs := map[string]ir.IRSchema{}
+	for _, md := range result.ModelDefs {
+		defs[md.Name] = md.Schema
+	}
+	user := defs["User"]
+	if user.Kind != ir.IRKindObject {
+		t.Fatalf("User: got kind %s, expected object", user.Kind)
+	}
+	fields := map[string]ir.IRField{}
+	for _, f := range user.Properties {
+		fields[f.Name] = f
+	}
+
+	tests := []struct {
+		field string
+		kind  ir.IRSchemaKind
+	}{
+		{"id", ir.IRKindString},
+		{"address", ir.IRKindObject},
+		{"tags", ir.IRKindArray},
+		{"labels", ir.IRKindObject},
+	}
+
+	for _, test := range tests {
+		f, ok := fields[test.field]
+		if !ok || f.Type == nil {
+			t.Errorf("User.%s: field not found", test.field)
+			continue
+		}
+		if f.Type.Kind != test.kind {
+			t.Errorf("User.%s: got kind %s, expected %s", test.field, f.Type.Kind, test.kind)
+		}
+	}
+	if !fields["id"].Required {
+		t.Errorf("User.id should be required")
+	}
+	if labels := fields["labels"].Type; labels.AdditionalProperties == nil || labels.AdditionalProperties.Kind != ir.IRKindString {
+		t.Errorf("User.labels: expected string additionalProperties, got %+v", labels.AdditionalProperties)
+	}
+	if kind := defs["Anything"].Kind; kind != ir.IRKindUnknown {
+		t.Errorf("Anything: got kind %s, expected unknown", kind)
+	}
+
+	var resp ir.IRSchema
+	for _, service := range result.Services {
+		for _, op := range service.Operations {
+			if op.OperationID == "listUsers" {
+				resp = op.Response.Schema
+			}
+		}
+	}
+	if resp.Kind != ir.IRKindArray || resp.Items == nil || resp.Items.Ref != "User" {
+		t.Errorf("listUsers response: got %+v, expected array of User", resp)
+	}
+}
+
+func TestPropertyCounts(t *testing.T) {
+	maxProps := uint64(20)
+	metadata := &openapi3.SchemaRef{Value: &openapi3.Schema{
+		Type:                 &openapi3.Types{openapi3.TypeObject},
+		MinProps:             1,
+		MaxProps:             &maxProps,
+		AdditionalProperties: openapi3.AdditionalProperties{Schema: &openapi3.SchemaRef{Value: openapi3.NewStringSchema()}},
+	}}
+	nested := &openapi3.SchemaRef{Value: &openapi3.Schema{
+		Type:       &openapi3.Types{openapi3.TypeObject},
+		MaxProps:   &maxProps,
+		Properties: openapi3.Schemas{"a": &openapi3.SchemaRef{Value: openapi3.NewStringSchema()}},
+	}}
+	obj := &openapi3.SchemaRef{Value: &openapi3.Schema{
+		Type:       &openapi3.Types{openapi3.TypeObject},
+		Properties: openapi3.Schemas{"extra": nested},
+	}}
+
+	direct := schemaRefToIR(nil, metadata)
+	if direct.MinProperties == nil || *direct.MinProperties != 1 || direct.MaxProperties == nil || *direct.MaxProperties != 20 {
+		t.Errorf("unexpected bounds: min=%v max=%v", direct.MinProperties, direct.MaxProperties)
+	}
+
+	var defs []ir.IRModelDef
+	parent := schemaRefToIRWithNaming(nil, obj, "Order", "", false, &defs, map[string]struct{}{})
+	if parent.MinProperties != nil || parent.MaxProperties != nil {
+		t.Errorf("expected no bounds on Order, got min=%v max=%v", parent.MinProperties, parent.MaxProperties)
+	}
+	if len(defs) != 1 || defs[0].Name != "Order_Extra" {
+		t.Fatalf("expected the nested object to become Order_Extra, got %+v", defs)
+	}
+	if got := defs[0].Schema; got.MinProperties != nil || got.MaxProperties == nil || *got.MaxProperties != 20 {
+		t.Errorf("Order_Extra: unexpected bounds min=%v max=%v", got.MinProperties, got.MaxProperties)
+	}
+}
+
+func TestNullableEnum(t *testing.T) {
+	doc, err := openapi3.NewLoader().LoadFromFile("testdata/nullable-enum.yaml")
+	if err != nil {
+		t.Fatalf("load fixture: %v", err)
+	}
+	result, err := BuildIR(doc)
+	if err != nil {
+		t.Fatalf("BuildIR: %v", err)
+	}
+
+	schemas := map[string]ir.IRSchema{}
+	for _, md := range result.ModelDefs {
+		schemas[md.Name] = md.Schema
+		for _, f := range md.Schema.Properties {
+			schemas[md.Name+"."+f.Name] = *f.Type
+		}
+	}
+	for _, svc := range result.Services {
+		for _, op := range svc.Operations {
+			for _, p := range op.QueryParams {
+				schemas[op.OperationID+"."+p.Name] = p.Schema
+			}
+		}
+	}
+
+	tests := []struct {
+		name   string
+		values []string
+		names  []string
+	}{
+		{"Status", []string{"open", "done"}, nil},
+		{"Task.size", []string{"1", "2"}, []string{"Small", "Large"}},
+		{"listTasks.priority", []string{"low", "high"}, nil},
+	}
+	for _, test := range tests {
+		s, ok := schemas[test.name]
+		if !ok {
+			t.Errorf("%s: schema not found", test.name)
+			continue
+		}
+		if s.Kind != ir.IRKindEnum || !s.Nullable || !reflect.DeepEqual(s.EnumValues, test.values) || len(s.EnumRaw) != len(test.values) || !reflect.DeepEqual(s.EnumNames, test.names) {
+			t.Errorf("%s: got kind=%s nullable=%v values=%v names=%v, expected nullable enum %v %v", test.name, s.Kind, s.Nullable, s.EnumValues, s.EnumNames, test.values, test.names)
+		}
+	}
+	if s := schemas["Task.nothing"]; s.Kind != ir.IRKindNull {
+		t.Errorf("Task.nothing: got kind %s, expected null", s.Kind)
+	}
+}
+
+func TestSchemaExamples(t *testing.T) {
+	doc, err := openapi3.NewLoader().LoadFromFile("testdata/schema-examples.yaml")
+	if err != nil {
+		t.Fatalf("load fixture: %v", err)
+	}
+	result, err := BuildIR(doc)
+	if err != nil {
+		t.Fatalf("BuildIR: %v", err)
+	}
+
+	examples := map[string][]any{}
+	for _, md := range result.ModelDefs {
+		examples[md.Name] = md.Annotations.Examples
+		for _, f := range md.Schema.Properties {
+			examples[md.Name+"."+f.Name] = f.Annotations.Examples
+		}
+	}
+	for _, svc := range result.Services {
+		for _, op := range svc.Operations {
+			for _, p := range op.QueryParams {
+				examples[op.OperationID+"."+p.Name] = p.Examples
+			}
+			examples[op.OperationID+".response"] = op.Response.Examples
+		}
+	}
+
+	ada := map[string]any{"id": float64(1), "name": "Ada"}
+	grace := map[string]any{"id": float64(2), "name": "Grace"}
+	tests := []struct {
+		name     string
+		expected []any
+	}{
+		// The 3.0 example repeats the first of the 3.1 examples
+		{"User", []any{ada, grace}},
+		{"User.id", []any{float64(7)}},
+		{"User.name", []any{"Ada", "Grace", "Linus"}},
+		{"listUsers.role", []any{"admin", "member"}},
+		{"listUsers.response", []any{[]any{ada}}},
+	}
+	for _, test := range tests {
+		if got := examples[test.name]; !reflect.DeepEqual(got, test.expected) {
+			t.Errorf("%s: examples = %#v, expected %#v", test.name, got, test.expected)
+		}
+	}
+}
+
+func TestInferEnumBaseKind(t *testing.T) {
+	typed := func(typ string, values ...any) *openapi3.Schema {
+		return &openapi3.Schema{Type: &openapi3.Types{typ}, Enum: values}
+	}
+	untyped := func(values ...any) *openapi3.Schema { return &openapi3.Schema{Enum: values} }
+
+	tests := []struct {
+		name     string
+		schema   *openapi3.Schema
+		expected ir.IRSchemaKind
+	}{
+		{"declared type wins", typed(openapi3.TypeNumber, 1.0, 2.0), ir.IRKindNumber},
+		{"declared integer", typed(openapi3.TypeInteger, 1.0, 2.0), ir.IRKindInteger},
+		{"decoded integers", untyped(1.0, 2.0, 3.0), ir.IRKindInteger},
+		{"null first", untyped(nil, 1.0, 2.0), ir.IRKindInteger},
+		{"fraction anywhere", untyped(1.0, 2.5), ir.IRKindNumber},
+		{"beyond int64", untyped(1.0, 1e19), ir.IRKindNumber},
+		{"int64 bounds", untyped(int64(math.MinInt64), int64(math.MaxInt64)), ir.IRKindInteger},
+		{"json numbers", untyped(json.Number("9223372036854775807"), json.Number("-1")), ir.IRKindInteger},
+		{"uint64 overflow", untyped(uint64(math.MaxUint64)), ir.IRKindNumber},
+		{"strings", untyped(nil, "a", 1.0), ir.IRKindString},
+		{"only null", untyped(nil), ir.IRKindUnknown},
+	}
+	for _, test := range tests {
+		if got := inferEnumBaseKind(test.schema); got != test.expected {
+			t.Errorf("%s: inferEnumBaseKind() = %s, expected %s", test.name, got, test.expected)
+		}
+	}
+}
+
+func TestInt64EnumValues(t *testing.T) {
+	s := enumSchema(&openapi3.Schema{Enum: []any{int64(math.MinInt64), nil, int64(9007199254740993), json.Number("9223372036854775807")}}, nil)
+	if s.EnumBase != ir.IRKindInteger || !s.Nullable {
+		t.Fatalf("expected a nullable integer enum, got base %s nullable %v", s.EnumBase, s.Nullable)
+	}
+	expected := []string{"-9223372036854775808", "9007199254740993", "9223372036854775807"}
+	if got := s.EnumLiterals(); !reflect.DeepEqual(got, expected) {
+		t.Errorf("EnumLiterals() = %v, expected %v", got, expected)
+	}
+	if !reflect.DeepEqual(s.EnumValues, expected) {
+		t.Errorf("EnumValues = %v, expected %v", s.EnumValues, expected)
+	}
+}
+
+func TestNumericBounds(t *testing.T) {
+	doc, err := openapi.LoadDocument("testdata/numeric-bounds.yaml")
+	if err != nil {
+		t.Fatalf("load fixture: %v", err)
+	}
+	result, err := BuildIR(doc)
+	if err != nil {
+		t.Fatalf("BuildIR: %v", err)
+	}
+
+	fields := map[string]*ir.IRSchema{}
+	for _, f := range result.ModelDefs[0].Schema.Properties {
+		fields[f.Name] = f.Type
+	}
+	format := func(f *float64) string {
+		if f == nil {
+			return "-"
+		}
+		return fmt.Sprint(*f)
+	}
+	tests := []struct {
+		field, min, max, multipleOf string
+	}{
+		{"amount", "0", "-", "0.01"},
+		{"discount", "-", "100", "-"},
+		{"installments", "1", "24", "-"},
+		{"quantity", "-", "-", "-"},
+	}
+	for _, test := range tests {
+		s := fields[test.field]
+		if got := format(s.ExclusiveMinimum); got != test.min {
+			t.Errorf("%s: exclusive minimum = %s, expected %s", test.field, got, test.min)
+		}
+		if got := format(s.ExclusiveMaximum); got != test.max {
+			t.Errorf("%s: exclusive maximum = %s, expected %s", test.field, got, test.max)
+		}
+		if got := format(s.MultipleOf); got != test.multipleOf {
+			t.Errorf("%s: multipleOf = %s, expected %s", test.field, got, test.multipleOf)
+		}
+	}
+}
@@ -0,0 +1,155 @@
+package generator
+
+import (
+	"bytes"
+	"encoding/json"
+	"maps"
+	"slices"
+	"strconv"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+// hoistTitledSchemas turns inline object and enum schemas that carry a title into component
+// schemas named after the PascalCased title, replacing them with refs, so they become named
+// models instead of inline types. The document is walked in a fixed order (components by
+// name, then paths, methods, parameters, media types and status codes), so when titles
+// collide the first schema keeps the plain name and later ones get 2, 3, ... appended. A
+// titled schema identical to the component already holding its name reuses that component.
+func hoistTitledSchemas(doc *openapi3.T) {
+	if doc.Components == nil {
+		doc.Components = &openapi3.Components{}
+	}
+	if doc.Components.Schemas == nil {
+		doc.Components.Schemas = openapi3.Schemas{}
+	}
+	h := titleHoister{doc: doc, names: map[*openapi3.Schema]string{}}
+
+	for _, name := range slices.Sorted(maps.Keys(doc.Components.Schemas)) {
+		if sr := doc.Components.Schemas[name]; sr != nil && sr.Ref == "" {
+			h.children(sr.Value)
+		}
+	}
+	if doc.Paths == nil {
+		return
+	}
+	for _, path := range slices.Sorted(maps.Keys(doc.Paths.Map())) {
+		item := doc.Paths.Value(path)
+		h.parameters(item.Parameters)
+		ops := item.Operations()
+		for _, method := range slices.Sorted(maps.Keys(ops)) {
+			op := ops[method]
+			h.parameters(op.Parameters)
+			if op.RequestBody != nil && op.RequestBody.Value != nil {
+				h.content(op.RequestBody.Value.Content)
+			}
+			if op.Responses == nil {
+				continue
+			}
+			responses := op.Responses.Map()
+			for _, code := range slices.Sorted(maps.Keys(responses)) {
+				resp := responses[code]
+				if resp == nil || resp.Value == nil {
+					continue
+				}
+				h.content(resp.Value.Content)
+				for _, header := range slices.Sorted(maps.Keys(resp.Value.Headers)) {
+					if hr := resp.Value.Headers[header]; hr != nil && hr.Value != nil {
+						h.schema(hr.Value.Schema)
+					}
+				}
+			}
+		}
+	}
+}
+
+// titleHoister remembers the name given to every hoisted schema, so a schema reached twice
+// (e.g. through a shared parameter) is hoisted once
+type titleHoister struct {
+	doc   *openapi3.T
+	names map[*openapi3.Schema]string
+}
+
+func (h *titleHoister) parameters(params openapi3.Parameters) {
+	for _, p := range params {
+		if p != nil && p.Value != nil {
+			h.schema(p.Value.Schema)
+			h.content(p.Value.Content)
+		}
+	}
+}
+
+func (h *titleHoister) content(content openapi3.Content) {
+	for _, mediaType := range slices.Sorted(maps.Keys(content)) {
+		if mt := content[mediaType]; mt != nil {
+			h.schema(mt.Schema)
+		}
+	}
+}
+
+// schema hoists sr when it is a titled inline object or enum, then walks its subschemas
+func (h *titleHoister) schema(sr *openapi3.SchemaRef) {
+	if sr == nil || sr.Ref != "" || sr.Value == nil {
+		return
+	}
+	s := sr.Value
+	if s.Title != "" && ((isObjectSchema(s) && len(s.Properties) > 0) || len(s.Enum) > 0) {
+		if name := h.name(s); name != "" {
+			sr.Ref = "#/components/schemas/" + name
+		}
+	}
+	h.children(s)
+}
+
+func (h *titleHoister) children(s *openapi3.Schema) {
+	if s == nil {
+		return
+	}
+	for _, name := range slices.Sorted(maps.Keys(s.Properties)) {
+		h.schema(s.Properties[name])
+	}
+	h.schema(s.Items)
+	h.schema(s.AdditionalProperties.Schema)
+	for _, list := range []openapi3.SchemaRefs{s.AllOf, s.OneOf, s.AnyOf} {
+		for _, sub := range list {
+			h.schema(sub)
+		}
+	}
+	h.schema(s.Not)
+}
+
+// name returns the component name for a titled schema, registering it on first use
+func (h *titleHoister) name(s *openapi3.Schema) string {
+	if name, ok := h.names[s]; ok {
+		return name
+	}
+	base := toPascal(s.Title)
+	if base == "" {
+		return ""
+	}
+	schemas := h.doc.Components.Schemas
+	name := base
+	for i := 2; ; i++ {
+		existing, taken := schemas[name]
+		if !taken {
+			schemas[name] = &openapi3.SchemaRef{Value: s}
+			break
+		}
+		if existing != nil && sameSchema(existing.Value, s) {
+			break
+		}
+		name = base + strconv.Itoa(i)
+	}
+	h.names[s] = name
+	return name
+}
+
+// sameSchema reports whether two schemas serialize identically
+func sameSchema(a, b *openapi3.Schema) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	ja, errA := json.Marshal(a)
+	jb, errB := json.Marshal(b)
+	return errA == nil && errB == nil && bytes.Equal(ja, jb)
+}
@@ -0,0 +1,92 @@
+package generator
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/blimu-dev/sdk-gen/pkg/ir"
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+const schemaTitlesSpec = `openapi: 3.0.3
+info:
+  title: Orders
+  version: "1.0"
+paths:
+  /orders:
+    post:
+      operationId: createOrder
+      requestBody:
+        content:
+          application/json:
+            schema:
+              title: new order
+              type: object
+              properties:
+                address:
+                  title: Address
+                  type: object
+                  properties:
+                    street:
+                      type: string
+      responses:
+        "200":
+          description: ok
+          content:
+            application/json:
+              schema:
+                type: object
+                title: address
+                properties:
+                  city:
+                    type: string
+components:
+  schemas:
+    Order:
+      type: object
+      properties:
+        status:
+          title: order status
+          type: string
+          enum: [open, closed]
+        shipping:
+          title: Address
+          type: object
+          properties:
+            street:
+              type: string
+        tags:
+          type: array
+          items:
+            title: untyped
+            type: string
+`
+
+func TestUseSchemaTitleAsName(t *testing.T) {
+	doc, err := openapi3.NewLoader().LoadFromData([]byte(schemaTitlesSpec))
+	if err != nil {
+		t.Fatalf("load spec: %v", err)
+	}
+	in, err := BuildIRWithOptions(doc, BuildIROptions{UseSchemaTitleAsName: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var names []string
+	for _, md := range in.ModelDefs {
+		names = append(names, md.Name)
+	}
+	// The identical Address in the request body reuses the component model; the different
+	// response one is numbered. Titled scalars stay inline.
+	if fmt.Sprint(names) != "[Address Address2 NewOrder Order OrderStatus]" {
+		t.Errorf("models = %v", names)
+	}
+
+	op := in.Services[0].Operations[0]
+	if op.RequestBody == nil || op.RequestBody.Schema.Kind != ir.IRKindRef || op.RequestBody.Schema.Ref != "NewOrder" {
+		t.Errorf("expected the request body to reference NewOrder, got %+v", op.RequestBody)
+	}
+	if op.Response.Schema.Kind != ir.IRKindRef || op.Response.Schema.Ref != "Address2" {
+		t.Errorf("expected the response to reference Address2, got %+v", op.Response.Schema)
+	}
+}
@@ -0,0 +1,183 @@
+package swift
+
+import (
+	"embed"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+
+	"github.com/Masterminds/sprig/v3"
+
+	"github.com/blimu-dev/sdk-gen/pkg/config"
+	"github.com/blimu-dev/sdk-gen/pkg/ir"
+	"github.com/blimu-dev/sdk-gen/pkg/utils"
+)
+
+//go:embed templates/*
+var templatesFS embed.FS
+
+// SwiftGenerator implements the Generator interface for Swift
+type SwiftGenerator struct {
+	// funcs are the template functions set with SetTemplateFuncs
+	funcs template.FuncMap
+	// logger receives warnings, see SetLogger
+	logger *slog.Logger
+}
+
+// NewSwiftGenerator creates a new Swift generator
+func NewSwiftGenerator() *SwiftGenerator {
+	return &SwiftGenerator{}
+}
+
+// GetType returns the generator type identifier
+func (g *SwiftGenerator) GetType() string {
+	return "swift"
+}
+
+// SetTemplateFuncs adds funcs to the functions available to the templates. A function named
+// like a built-in helper replaces it.
+func (g *SwiftGenerator) SetTemplateFuncs(funcs template.FuncMap) {
+	g.funcs = funcs
+}
+
+// SetLogger sets the logger warnings found while generating, such as renamed methods, are
+// reported to
+func (g *SwiftGenerator) SetLogger(logger *slog.Logger) {
+	g.logger = logger
+}
+
+// Generate creates a Swift package (Package.swift, Codable models and URLSession services
+// with async methods) from the given configuration and IR
+func (g *SwiftGenerator) Generate(client config.Client, in ir.IR) error {
+	module := moduleName(client.PackageName)
+	srcDir := filepath.Join(client.OutDir, "Sources", module)
+	if err := os.MkdirAll(srcDir, 0o755); err != nil {
+		return err
+	}
+
+	clientName := toPascalCase(client.Name)
+	if !strings.HasSuffix(clientName, "Client") {
+		clientName += "Client"
+	}
+	recursive := recursiveModels(in.ModelDefs)
+
+	methodNames, warnings := utils.NewMethodNames(in.Services, func(op ir.IROperation) string { return ResolveMethodName(client, op) })
+	utils.LogWarnings(g.logger, client.Name, warnings)
+	methodName := methodNames.Get
+
+	funcMap := template.FuncMap{
+		"pascal":          toPascalCase,
+		"modelName":       modelTypeName,
+		"camel":           toCamelCase,
+		"moduleName":      func() string { return module },
+		"packageVersion":  client.PackageVersion,
+		"clientName":      func() string { return clientName },
+		"serviceName":     func(tag string) string { return toPascalCase(tag) + "Service" },
+		"serviceField":    func(tag string) string { return swiftIdent(toCamelCase(tag)) },
+		"methodName":      methodName,
+		"methodParams":    buildMethodParams,
+		"queryStatements": queryStatements,
+		"pathTemplate":    buildPathTemplate,
+		"returnType":      returnType,
+		"swiftType":       schemaToSwiftType,
+		"swiftString":     swiftString,
+		"fieldType":       fieldType,
+		"fieldName":       func(name string) string { return fieldName(name, client.PreservePropertyNames) },
+		"codingKey":       func(name string) string { return codingKey(name, client.PreservePropertyNames) },
+		"needsCodingKeys": func(fields []ir.IRField) bool { return needsCodingKeys(fields, client.PreservePropertyNames) },
+		"initParams":      func(fields []ir.IRField) string { return initParams(fields, client.PreservePropertyNames) },
+		"enumRawType":     enumRawType,
+		"enumCases":       enumCases,
+		"isStruct":        isStruct,
+		"isRecursive":     func(name string) bool { return recursive[name] },
+		"modelFields":     func(s ir.IRSchema) []ir.IRField { return modelFields(in.ModelDefs, s) },
+		"docComment":      formatDocComment,
+	}
+
+	// Merge sprig functions
+	for k, v := range sprig.FuncMap() {
+		funcMap[k] = v
+	}
+	// User functions come last and replace helpers of the same name
+	for k, v := range g.funcs {
+		funcMap[k] = v
+	}
+
+	data := map[string]any{"Client": client, "IR": in}
+
+	// Generate the package manifest
+	if err := renderFile(client, "Package.swift.gotmpl", filepath.Join(client.OutDir, "Package.swift"), funcMap, data); err != nil {
+		return err
+	}
+
+	// Generate the client and models
+	if err := renderFile(client, "Client.swift.gotmpl", filepath.Join(srcDir, clientName+".swift"), funcMap, data); err != nil {
+		return err
+	}
+	if err := renderFile(client, "Models.swift.gotmpl", filepath.Join(srcDir, "Models.swift"), funcMap, data); err != nil {
+		return err
+	}
+	if err := renderFile(client, "AnyCodable.swift.gotmpl", filepath.Join(srcDir, "AnyCodable.swift"), funcMap, data); err != nil {
+		return err
+	}
+
+	// Generate services
+	for _, service := range in.Services {
+		// Skip services with no operations
+		if len(service.Operations) == 0 {
+			continue
+		}
+		fileName := client.FileName(toPascalCase(service.Tag)+"Service", service.Tag, "service") + ".swift"
+		if err := renderFile(client, "Service.swift.gotmpl", filepath.Join(srcDir, fileName), funcMap, map[string]any{"Client": client, "Service": service}); err != nil {
+			return err
+		}
+	}
+
+	// Generate README.md
+	if err := renderFile(client, "README.md.gotmpl", filepath.Join(client.OutDir, "README.md"), funcMap, data); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// renderFile renders a template file to the target path
+func renderFile(client config.Client, templateName, targetPath string, funcMap template.FuncMap, data map[string]any) error {
+	// Check if file should be excluded
+	if client.ShouldExcludeFile(targetPath) {
+		return nil // Skip this file silently
+	}
+
+	tmplContent, err := templatesFS.ReadFile("templates/" + templateName)
+	if err != nil {
+		return fmt.Errorf("failed to read template %s: %w", templateName, err)
+	}
+
+	tmpl, err := template.New(templateName).Funcs(funcMap).Parse(string(tmplContent))
+	if err != nil {
+		return fmt.Errorf("failed to parse template %s: %w", templateName, err)
+	}
+
+	file, err := os.Create(targetPath)
+	if err != nil {
+		return fmt.Errorf("failed to create file %s: %w", targetPath, err)
+	}
+	defer file.Close()
+
+	// Package.swift must start with its swift-tools-version line, so it gets no header
+	if filepath.Base(targetPath) != "Package.swift" {
+		if _, err := io.WriteString(file, utils.FileHeader(client.FileHeader, targetPath)); err != nil {
+			return fmt.Errorf("failed to write file %s: %w", targetPath, err)
+		}
+	}
+
+	if err := tmpl.Execute(file, data); err != nil {
+		return fmt.Errorf("failed to execute template %s: %w", templateName, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,509 @@
+package swift
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/blimu-dev/sdk-gen/pkg/config"
+	"github.com/blimu-dev/sdk-gen/pkg/ir"
+	"github.com/blimu-dev/sdk-gen/pkg/utils"
+)
+
+// Alias functions to use centralized utilities (advanced versions for better camelCase handling)
+var toPascalCase = utils.ToPascalCaseAdvanced
+var toCamelCase = utils.ToCamelCaseAdvanced
+
+// unknownType is used where the schema does not pin down a concrete type. AnyCodable is
+// generated alongside the models and round-trips any JSON value.
+const unknownType = "AnyCodable"
+
+// swiftKeywords lists keywords that must be escaped with backticks when used as identifiers
+var swiftKeywords = map[string]bool{
+	"associatedtype": true, "class": true, "deinit": true, "enum": true, "extension": true,
+	"fileprivate": true, "func": true, "import": true, "init": true, "inout": true,
+	"internal": true, "let": true, "open": true, "operator": true, "private": true,
+	"precedencegroup": true, "protocol": true, "public": true, "rethrows": true, "static": true,
+	"struct": true, "subscript": true, "typealias": true, "var": true, "break": true,
+	"case": true, "catch": true, "continue": true, "default": true, "defer": true, "do": true,
+	"else": true, "fallthrough": true, "for": true, "guard": true, "if": true, "in": true,
+	"repeat": true, "return": true, "throw": true, "switch": true, "where": true, "while": true,
+	"Any": true, "as": true, "await": true, "false": true, "is": true, "nil": true, "self": true,
+	"Self": true, "super": true, "throws": true, "true": true, "try": true,
+}
+
+// reservedTypeNames lists the standard library, Foundation and generated support types the
+// package uses; a model of the same name would shadow them in the module
+var reservedTypeNames = map[string]bool{
+	"Error": true, "Data": true, "URL": true, "Date": true, "String": true, "Int": true,
+	"Double": true, "Float": true, "Bool": true, "Array": true, "Dictionary": true, "Set": true,
+	"Optional": true, "Result": true, "Decimal": true, "UUID": true, "Character": true,
+	"Void": true, "Never": true, "Codable": true, "Encodable": true, "Decodable": true,
+	"JSONEncoder": true, "JSONDecoder": true, "URLRequest": true, "URLSession": true,
+	"URLComponents": true, "URLQueryItem": true, "URLError": true, "HTTPURLResponse": true,
+	"AnyCodable": true, "APIError": true,
+}
+
+var identPattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// schemaToSwiftType converts an IR schema to a Swift type string
+func schemaToSwiftType(x any) string {
+	switch v := x.(type) {
+	case ir.IRSchema:
+		return schemaToSwiftTypeImpl(v)
+	case *ir.IRSchema:
+		if v != nil {
+			return schemaToSwiftTypeImpl(*v)
+		}
+		return unknownType
+	default:
+		return unknownType
+	}
+}
+
+func schemaToSwiftTypeImpl(s ir.IRSchema) string {
+	var t string
+	switch s.Kind {
+	case ir.IRKindString:
+		if s.Format == "binary" || s.Format == "byte" {
+			// Codable carries Data as a base64 string
+			t = "Data"
+		} else {
+			t = "String"
+		}
+	case ir.IRKindNumber:
+		t = "Double"
+	case ir.IRKindInteger:
+		t = "Int"
+	case ir.IRKindBoolean:
+		t = "Bool"
+	case ir.IRKindRef:
+		if s.Ref == "" {
+			t = unknownType
+		} else {
+			t = modelTypeName(s.Ref)
+		}
+	case ir.IRKindArray:
+		if s.Items != nil {
+			t = "[" + schemaToSwiftTypeImpl(*s.Items) + "]"
+		} else {
+			t = "[" + unknownType + "]"
+		}
+	case ir.IRKindEnum:
+		// Inline enums have no type of their own; named enums arrive as refs
+		t = enumRawType(s)
+	case ir.IRKindObject:
+		if s.AdditionalProperties != nil {
+			t = "[String: " + schemaToSwiftTypeImpl(*s.AdditionalProperties) + "]"
+		} else {
+			t = "[String: " + unknownType + "]"
+		}
+	default:
+		// null, oneOf/anyOf/allOf without a named model, not and unknown
+		t = unknownType
+	}
+
+	if s.Nullable && !strings.HasSuffix(t, "?") {
+		t += "?"
+	}
+	return t
+}
+
+// modelTypeName returns the Swift type name of a model, with a Model suffix when it would
+// shadow a type in reservedTypeNames, e.g. ErrorModel for a schema named Error
+func modelTypeName(name string) string {
+	t := toPascalCase(name)
+	if reservedTypeNames[t] {
+		return t + "Model"
+	}
+	return t
+}
+
+// enumRawType returns the Swift raw type of an enum's values
+func enumRawType(s ir.IRSchema) string {
+	switch s.EnumBase {
+	case ir.IRKindInteger:
+		return "Int"
+	case ir.IRKindNumber:
+		return "Double"
+	case ir.IRKindBoolean:
+		return "Bool"
+	}
+	return "String"
+}
+
+// swiftIdent escapes a name that collides with a Swift keyword
+func swiftIdent(name string) string {
+	if swiftKeywords[name] {
+		return "`" + name + "`"
+	}
+	return name
+}
+
+// propertyName returns the Swift property name for a JSON field
+func propertyName(jsonName string) string {
+	name := toCamelCase(jsonName)
+	if name == "" {
+		name = "value"
+	}
+	if name[0] >= '0' && name[0] <= '9' {
+		name = "_" + name
+	}
+	return swiftIdent(name)
+}
+
+// fieldName returns the Swift property name of a model field: its JSON name when property
+// names are preserved and the JSON name is a valid identifier, else the camelCase propertyName
+func fieldName(jsonName string, preserve bool) string {
+	if preserve && identPattern.MatchString(jsonName) {
+		return swiftIdent(jsonName)
+	}
+	return propertyName(jsonName)
+}
+
+// codingKey returns the CodingKeys case of a field: the bare property name when it matches the
+// JSON name, else the property name mapped to the JSON name (case createdAt = "created_at")
+func codingKey(jsonName string, preserve bool) string {
+	name := fieldName(jsonName, preserve)
+	if strings.Trim(name, "`") == jsonName {
+		return name
+	}
+	return name + " = " + swiftString(jsonName)
+}
+
+// needsCodingKeys reports whether any field's JSON name differs from its Swift property name,
+// in which case the model declares CodingKeys
+func needsCodingKeys(fields []ir.IRField, preserve bool) bool {
+	for _, f := range fields {
+		if strings.Contains(codingKey(f.Name, preserve), "=") {
+			return true
+		}
+	}
+	return false
+}
+
+// fieldType returns the Swift type of a model field. Optional fields are optionals so they
+// can be left out, both when decoding and in the generated initializer.
+func fieldType(f ir.IRField) string {
+	t := schemaToSwiftType(f.Type)
+	if !f.Required && !strings.HasSuffix(t, "?") {
+		t += "?"
+	}
+	return t
+}
+
+// initParams renders the parameter list of a model's memberwise initializer; optional
+// fields default to nil
+func initParams(fields []ir.IRField, preserve bool) string {
+	params := make([]string, 0, len(fields))
+	for _, f := range fields {
+		param := fmt.Sprintf("%s: %s", fieldName(f.Name, preserve), fieldType(f))
+		if strings.HasSuffix(fieldType(f), "?") {
+			param += " = nil"
+		}
+		params = append(params, param)
+	}
+	return strings.Join(params, ", ")
+}
+
+// enumCase is one case of a generated Swift enum
+type enumCase struct {
+	Name  string
+	Value string
+}
+
+// enumCases returns the cases of a named enum with their raw value literals. String cases
+// are named after their values; numeric ones after x-enum-varnames when present.
+func enumCases(s ir.IRSchema) []enumCase {
+	literals := s.EnumLiterals()
+	names := s.EnumValues
+	numeric := s.EnumBase == ir.IRKindInteger || s.EnumBase == ir.IRKindNumber
+	if numeric {
+		names = literals
+		if len(s.EnumNames) == len(literals) {
+			names = s.EnumNames
+		}
+	}
+	members := utils.EnumMemberNames(names, utils.EnumNamePascal)
+	cases := make([]enumCase, 0, len(literals))
+	for i, v := range literals {
+		if !numeric {
+			v = swiftString(v)
+		}
+		cases = append(cases, enumCase{Name: swiftIdent(strings.ToLower(members[i][:1]) + members[i][1:]), Value: v})
+	}
+	return cases
+}
+
+// swiftString renders s as a Swift string literal
+func swiftString(s string) string {
+	var b strings.Builder
+	b.WriteByte('"')
+	for _, r := range s {
+		switch r {
+		case '"':
+			b.WriteString(`\"`)
+		case '\\':
+			b.WriteString(`\\`)
+		case '\n':
+			b.WriteString(`\n`)
+		case '\r':
+			b.WriteString(`\r`)
+		case '\t':
+			b.WriteString(`\t`)
+		default:
+			if r < 0x20 {
+				fmt.Fprintf(&b, `\u{%x}`, r)
+			} else {
+				b.WriteRune(r)
+			}
+		}
+	}
+	b.WriteByte('"')
+	return b.String()
+}
+
+// modelFields returns the fields rendered for an object or allOf model. allOf members are
+// flattened: referenced object models contribute their properties, later members win.
+func modelFields(defs []ir.IRModelDef, s ir.IRSchema) []ir.IRField {
+	switch s.Kind {
+	case ir.IRKindObject:
+		return s.Properties
+	case ir.IRKindAllOf:
+		var fields []ir.IRField
+		index := map[string]int{}
+		add := func(props []ir.IRField) {
+			for _, f := range props {
+				if i, ok := index[f.Name]; ok {
+					fields[i] = f
+					continue
+				}
+				index[f.Name] = len(fields)
+				fields = append(fields, f)
+			}
+		}
+		for _, part := range s.AllOf {
+			if part == nil {
+				continue
+			}
+			if part.Kind == ir.IRKindRef {
+				for _, md := range defs {
+					if md.Name == part.Ref {
+						add(modelFields(defs, md.Schema))
+					}
+				}
+				continue
+			}
+			add(modelFields(defs, *part))
+		}
+		return fields
+	}
+	return nil
+}
+
+// isStruct reports whether a model renders as a struct (or class) with stored properties
+func isStruct(s ir.IRSchema) bool {
+	return s.Kind == ir.IRKindObject && s.AdditionalProperties == nil || s.Kind == ir.IRKindAllOf
+}
+
+// recursiveModels returns the models that contain themselves through model-typed fields (a
+// User with a manager: User?). Swift structs cannot, so those models are generated as final
+// classes. Arrays and dictionaries already box their elements and do not count.
+func recursiveModels(defs []ir.IRModelDef) map[string]bool {
+	edges := map[string][]string{}
+	for _, md := range defs {
+		if !isStruct(md.Schema) {
+			continue
+		}
+		for _, f := range modelFields(defs, md.Schema) {
+			if f.Type != nil && f.Type.Kind == ir.IRKindRef && f.Type.Ref != "" {
+				edges[md.Name] = append(edges[md.Name], f.Type.Ref)
+			}
+		}
+	}
+
+	out := map[string]bool{}
+	for _, md := range defs {
+		seen := map[string]bool{}
+		stack := append([]string(nil), edges[md.Name]...)
+		for len(stack) > 0 {
+			name := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			if name == md.Name {
+				out[md.Name] = true
+				break
+			}
+			if !seen[name] {
+				seen[name] = true
+				stack = append(stack, edges[name]...)
+			}
+		}
+	}
+	return out
+}
+
+// ResolveMethodName chooses final method name using operationId, then heuristic
+func ResolveMethodName(client config.Client, op ir.IROperation) string {
+	if parsed := defaultParseOperationID(op.OperationID); parsed != "" {
+		return swiftIdent(toCamelCase(parsed))
+	}
+	return deriveMethodName(op)
+}
+
+// defaultParseOperationID strips any prefix up to and including "Controller_"
+func defaultParseOperationID(opID string) string {
+	if idx := strings.Index(opID, "Controller_"); idx >= 0 {
+		return opID[idx+len("Controller_"):]
+	}
+	return opID
+}
+
+// deriveMethodName creates method names using basic REST-style heuristics
+func deriveMethodName(op ir.IROperation) string {
+	hasID := strings.Contains(op.Path, "{") && strings.Contains(op.Path, "}")
+	switch strings.ToUpper(op.Method) {
+	case "GET":
+		if hasID {
+			return "get"
+		}
+		return "list"
+	case "POST":
+		return "create"
+	case "PUT", "PATCH":
+		return "update"
+	case "DELETE":
+		return "delete"
+	default:
+		return toCamelCase(op.Method)
+	}
+}
+
+// orderPathParams returns path parameters in the order they appear in the path
+func orderPathParams(op ir.IROperation) []ir.IRParam {
+	byName := make(map[string]ir.IRParam, len(op.PathParams))
+	for _, p := range op.PathParams {
+		byName[p.Name] = p
+	}
+	var ordered []ir.IRParam
+	for _, m := range regexp.MustCompile(`\{([^}]+)\}`).FindAllStringSubmatch(op.Path, -1) {
+		if p, ok := byName[m[1]]; ok {
+			ordered = append(ordered, p)
+		}
+	}
+	return ordered
+}
+
+// buildPathTemplate builds a Swift interpolated string for the operation path with each path
+// parameter percent-encoded as a single segment; the values of array parameters are encoded
+// one by one and joined with the separator of their style
+func buildPathTemplate(op ir.IROperation) string {
+	path := strings.Trim(swiftString(op.Path), `"`)
+	for _, p := range op.PathParams {
+		expr := fmt.Sprintf(`%s\(client.pathSegment(%s))`, p.PathPrefix(), propertyName(p.Name))
+		if p.Schema.Kind == ir.IRKindArray {
+			expr = fmt.Sprintf(`%s\(%s.map { client.pathSegment($0) }.joined(separator: %s))`, p.PathPrefix(), propertyName(p.Name), swiftString(p.PathSeparator()))
+		}
+		path = strings.ReplaceAll(path, "{"+p.Name+"}", expr)
+	}
+	return `"` + path + `"`
+}
+
+// buildMethodParams renders the parameter list of a service method: path parameters, the
+// request body, then query parameters (optional ones default to nil)
+func buildMethodParams(op ir.IROperation) string {
+	var params []string
+	for _, p := range orderPathParams(op) {
+		params = append(params, fmt.Sprintf("%s: %s", propertyName(p.Name), schemaToSwiftType(p.Schema)))
+	}
+	if op.RequestBody != nil {
+		t := schemaToSwiftType(op.RequestBody.Schema)
+		if !op.RequestBody.Required && !strings.HasSuffix(t, "?") {
+			params = append(params, fmt.Sprintf("body: %s? = nil", t))
+		} else {
+			params = append(params, "body: "+t)
+		}
+	}
+	required, optional := []string{}, []string{}
+	for _, p := range op.QueryParams {
+		t := schemaToSwiftType(p.Schema)
+		if p.Required {
+			required = append(required, fmt.Sprintf("%s: %s", propertyName(p.Name), t))
+			continue
+		}
+		if !strings.HasSuffix(t, "?") {
+			t += "?"
+		}
+		optional = append(optional, fmt.Sprintf("%s: %s = nil", propertyName(p.Name), t))
+	}
+	params = append(params, required...)
+	params = append(params, optional...)
+	return strings.Join(params, ", ")
+}
+
+// queryStatements renders the statements that append the operation's query parameters to a
+// [URLQueryItem] named query. Arrays repeat the parameter, JSON parameters are sent encoded
+// and unset optional parameters are left out.
+func queryStatements(op ir.IROperation) []string {
+	var out []string
+	for _, p := range op.QueryParams {
+		value := propertyName(p.Name)
+		optional := !p.Required || strings.HasSuffix(schemaToSwiftType(p.Schema), "?")
+		if optional {
+			out = append(out, fmt.Sprintf("if let value = %s {", value))
+			value = "value"
+		}
+		var stmt string
+		switch {
+		case p.IsJSON():
+			stmt = fmt.Sprintf("query.append(URLQueryItem(name: %s, value: try client.jsonString(%s)))", swiftString(p.Name), value)
+		case p.Schema.Kind == ir.IRKindArray:
+			stmt = fmt.Sprintf("query += %s.map { URLQueryItem(name: %s, value: client.queryString($0)) }", value, swiftString(p.Name))
+		default:
+			stmt = fmt.Sprintf("query.append(URLQueryItem(name: %s, value: client.queryString(%s)))", swiftString(p.Name), value)
+		}
+		if optional {
+			out = append(out, "    "+stmt, "}")
+		} else {
+			out = append(out, stmt)
+		}
+	}
+	return out
+}
+
+// returnType returns the Swift return type of an operation, empty when it returns nothing
+func returnType(op ir.IROperation) string {
+	if op.Response.TypeTS == "void" {
+		return ""
+	}
+	return schemaToSwiftType(op.Response.Schema)
+}
+
+// moduleName turns the configured package name into a Swift module name (@acme/billing-sdk
+// becomes AcmeBillingSdk)
+func moduleName(name string) string {
+	module := toPascalCase(name)
+	if module == "" {
+		return "Client"
+	}
+	if module[0] >= '0' && module[0] <= '9' {
+		module = "_" + module
+	}
+	return module
+}
+
+// formatDocComment formats a string as /// doc comment lines at the given indentation
+func formatDocComment(s, indent string) string {
+	if strings.TrimSpace(s) == "" {
+		return ""
+	}
+	var lines []string
+	for _, line := range strings.Split(s, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			lines = append(lines, indent+"///")
+		} else {
+			lines = append(lines, indent+"/// "+line)
+		}
+	}
+	return strings.Join(lines, "\n")
+}
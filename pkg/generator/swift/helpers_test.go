@@ -0,0 +1,135 @@
+package swift
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/blimu-dev/sdk-gen/pkg/ir"
+)
+
+func TestSchemaToSwiftType(t *testing.T) {
+	str := ir.IRSchema{Kind: ir.IRKindString}
+	tests := []struct {
+		name     string
+		schema   ir.IRSchema
+		expected string
+	}{
+		{"string", str, "String"},
+		{"binary", ir.IRSchema{Kind: ir.IRKindString, Format: "binary"}, "Data"},
+		{"integer", ir.IRSchema{Kind: ir.IRKindInteger}, "Int"},
+		{"number", ir.IRSchema{Kind: ir.IRKindNumber}, "Double"},
+		{"boolean", ir.IRSchema{Kind: ir.IRKindBoolean}, "Bool"},
+		{"nullable", ir.IRSchema{Kind: ir.IRKindString, Nullable: true}, "String?"},
+		{"array", ir.IRSchema{Kind: ir.IRKindArray, Items: &str}, "[String]"},
+		{"map", ir.IRSchema{Kind: ir.IRKindObject, AdditionalProperties: &str}, "[String: String]"},
+		{"ref", ir.IRSchema{Kind: ir.IRKindRef, Ref: "user_profile"}, "UserProfile"},
+		{"ref to a model shadowing Swift.Error", ir.IRSchema{Kind: ir.IRKindRef, Ref: "Error"}, "ErrorModel"},
+		{"ref to a model shadowing Foundation.Data", ir.IRSchema{Kind: ir.IRKindRef, Ref: "data"}, "DataModel"},
+		{"inline enum", ir.IRSchema{Kind: ir.IRKindEnum, EnumBase: ir.IRKindInteger}, "Int"},
+		{"unknown", ir.IRSchema{Kind: ir.IRKindUnknown}, "AnyCodable"},
+		{"nullable unknown", ir.IRSchema{Kind: ir.IRKindUnknown, Nullable: true}, "AnyCodable?"},
+	}
+
+	for _, test := range tests {
+		if result := schemaToSwiftType(test.schema); result != test.expected {
+			t.Errorf("%s: schemaToSwiftType() = %q, expected %q", test.name, result, test.expected)
+		}
+	}
+}
+
+func TestCodingKey(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+		// preserved is the coding key when property names are preserved
+		preserved string
+	}{
+		{"name", "name", "name"},
+		{"createdAt", "createdAt", "createdAt"},
+		{"created_at", `createdAt = "created_at"`, "created_at"},
+		{"x-request-id", `xRequestId = "x-request-id"`, `xRequestId = "x-request-id"`},
+		{"2fa", `_2fa = "2fa"`, `_2fa = "2fa"`},
+		{"default", "`default`", "`default`"},
+	}
+
+	for _, test := range tests {
+		if result := codingKey(test.input, false); result != test.expected {
+			t.Errorf("codingKey(%q, false) = %q, expected %q", test.input, result, test.expected)
+		}
+		if result := codingKey(test.input, true); result != test.preserved {
+			t.Errorf("codingKey(%q, true) = %q, expected %q", test.input, result, test.preserved)
+		}
+	}
+
+	str := ir.IRSchema{Kind: ir.IRKindString}
+	if needsCodingKeys([]ir.IRField{{Name: "id", Type: &str}, {Name: "default", Type: &str}}, false) {
+		t.Error("expected no CodingKeys when every JSON name is the property name")
+	}
+	if !needsCodingKeys([]ir.IRField{{Name: "id", Type: &str}, {Name: "created_at", Type: &str}}, false) {
+		t.Error("expected CodingKeys for created_at")
+	}
+}
+
+func TestInitParams(t *testing.T) {
+	str := ir.IRSchema{Kind: ir.IRKindString}
+	nullable := ir.IRSchema{Kind: ir.IRKindString, Nullable: true}
+	fields := []ir.IRField{
+		{Name: "id", Type: &str, Required: true},
+		{Name: "nickname", Type: &str},
+		{Name: "deleted_at", Type: &nullable, Required: true},
+	}
+	expected := "id: String, nickname: String? = nil, deletedAt: String? = nil"
+	if result := initParams(fields, false); result != expected {
+		t.Errorf("initParams() = %q, expected %q", result, expected)
+	}
+}
+
+func TestEnumCases(t *testing.T) {
+	str := ir.IRSchema{Kind: ir.IRKindEnum, EnumBase: ir.IRKindString, EnumValues: []string{"active", "ACTIVE", "in-review", "default", `say "hi"`}}
+	expected := []enumCase{{"active", `"active"`}, {"active2", `"ACTIVE"`}, {"inReview", `"in-review"`}, {"`default`", `"default"`}, {"sayHi", `"say \"hi\""`}}
+	if result := enumCases(str); !reflect.DeepEqual(result, expected) {
+		t.Errorf("enumCases() = %v, expected %v", result, expected)
+	}
+
+	numeric := ir.IRSchema{Kind: ir.IRKindEnum, EnumBase: ir.IRKindInteger, EnumValues: []string{"1", "2"}, EnumRaw: []any{float64(1), float64(2)}, EnumNames: []string{"Low", "High"}}
+	expected = []enumCase{{"low", "1"}, {"high", "2"}}
+	if result := enumCases(numeric); !reflect.DeepEqual(result, expected) {
+		t.Errorf("enumCases() = %v, expected %v", result, expected)
+	}
+}
+
+func TestRecursiveModels(t *testing.T) {
+	ref := func(name string) *ir.IRSchema { return &ir.IRSchema{Kind: ir.IRKindRef, Ref: name} }
+	defs := []ir.IRModelDef{
+		{Name: "User", Schema: ir.IRSchema{Kind: ir.IRKindObject, Properties: []ir.IRField{{Name: "manager", Type: ref("User")}}}},
+		{Name: "A", Schema: ir.IRSchema{Kind: ir.IRKindObject, Properties: []ir.IRField{{Name: "b", Type: ref("B")}}}},
+		{Name: "B", Schema: ir.IRSchema{Kind: ir.IRKindObject, Properties: []ir.IRField{{Name: "a", Type: ref("A")}}}},
+		// Arrays box their elements, so Team does not need to be a class
+		{Name: "Team", Schema: ir.IRSchema{Kind: ir.IRKindObject, Properties: []ir.IRField{
+			{Name: "teams", Type: &ir.IRSchema{Kind: ir.IRKindArray, Items: ref("Team")}},
+			{Name: "owner", Type: ref("User")},
+		}}},
+	}
+
+	expected := map[string]bool{"User": true, "A": true, "B": true}
+	if result := recursiveModels(defs); !reflect.DeepEqual(result, expected) {
+		t.Errorf("recursiveModels() = %v, expected %v", result, expected)
+	}
+}
+
+func TestQueryStatements(t *testing.T) {
+	op := ir.IROperation{QueryParams: []ir.IRParam{
+		{Name: "ids", Schema: ir.IRSchema{Kind: ir.IRKindArray, Items: &ir.IRSchema{Kind: ir.IRKindInteger}}},
+		{Name: "page_size", Required: true, Schema: ir.IRSchema{Kind: ir.IRKindInteger}},
+	}}
+	expected := strings.Join([]string{
+		"if let value = ids {",
+		`    query += value.map { URLQueryItem(name: "ids", value: client.queryString($0)) }`,
+		"}",
+		`query.append(URLQueryItem(name: "page_size", value: client.queryString(pageSize)))`,
+	}, "\n")
+	if result := strings.Join(queryStatements(op), "\n"); result != expected {
+		t.Errorf("queryStatements() =\n%s\nexpected\n%s", result, expected)
+	}
+}
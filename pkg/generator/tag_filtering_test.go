@@ -1,8 +1,14 @@
 package generator
 
 import (
+	"fmt"
 	"regexp"
+	"strings"
 	"testing"
+
+	"github.com/blimu-dev/sdk-gen/pkg/config"
+	"github.com/blimu-dev/sdk-gen/pkg/ir"
+	"github.com/getkin/kin-openapi/openapi3"
 )
 
 func TestShouldIncludeOperation(t *testing.T) {
@@ -133,3 +139,97 @@ func TestShouldIncludeOperation(t *testing.T) {
 		})
 	}
 }
+
+func TestUntaggedBehavior(t *testing.T) {
+	doc := &openapi3.T{Paths: openapi3.NewPaths(
+		openapi3.WithPath("/health", &openapi3.PathItem{Get: &openapi3.Operation{OperationID: "health"}}),
+		openapi3.WithPath("/users", &openapi3.PathItem{
+			Get:  &openapi3.Operation{OperationID: "listUsers", Tags: []string{"users"}},
+			Post: &openapi3.Operation{OperationID: "createUser"},
+		}),
+	)}
+	serviceTags := func(in ir.IR) []string {
+		var tags []string
+		for _, s := range in.Services {
+			tags = append(tags, fmt.Sprintf("%s:%d", s.Tag, len(s.Operations)))
+		}
+		return tags
+	}
+
+	tests := []struct {
+		name     string
+		opts     BuildIROptions
+		expected []string
+	}{
+		{"default bucket", BuildIROptions{}, []string{"misc:2", "users:1"}},
+		{"renamed bucket", BuildIROptions{UntaggedTag: "common"}, []string{"common:2", "users:1"}},
+		{"skip", BuildIROptions{UntaggedBehavior: config.UntaggedSkip}, []string{"users:1"}},
+	}
+	for _, test := range tests {
+		in, err := BuildIRWithOptions(doc, test.opts)
+		if err != nil {
+			t.Fatalf("%s: %v", test.name, err)
+		}
+		if got := serviceTags(in); fmt.Sprint(got) != fmt.Sprint(test.expected) {
+			t.Errorf("%s: services = %v, expected %v", test.name, got, test.expected)
+		}
+	}
+
+	in, _ := BuildIRWithOptions(doc, BuildIROptions{UntaggedTag: "common"})
+	if tags := in.Services[0].Operations[0].OriginalTags; len(tags) != 1 || tags[0] != "common" {
+		t.Errorf("expected untagged operations to carry the bucket tag, got %v", tags)
+	}
+
+	_, err := BuildIRWithOptions(doc, BuildIROptions{UntaggedBehavior: config.UntaggedError})
+	if err == nil || !strings.Contains(err.Error(), "GET /health, POST /users") {
+		t.Errorf("expected an error listing the untagged operations, got %v", err)
+	}
+}
+
+func TestOperationFilters(t *testing.T) {
+	fullIR := ir.IR{Services: []ir.IRService{
+		{Tag: "admin", Operations: []ir.IROperation{
+			{Method: "GET", Path: "/admin/stats", OriginalTags: []string{"admin"}},
+		}},
+		{Tag: "users", Operations: []ir.IROperation{
+			{OperationID: "listUsers", Method: "GET", Path: "/users", OriginalTags: []string{"users"}},
+			{OperationID: "getUser", Method: "GET", Path: "/users/{id}", OriginalTags: []string{"users"}},
+			{OperationID: "deleteUser", Method: "DELETE", Path: "/users/{id}", OriginalTags: []string{"users"}},
+		}},
+	}}
+	operations := func(in ir.IR) string {
+		var keys []string
+		for _, s := range in.Services {
+			for _, op := range s.Operations {
+				keys = append(keys, operationFilterKey(op))
+			}
+		}
+		return strings.Join(keys, ", ")
+	}
+
+	tests := []struct {
+		name     string
+		client   config.Client
+		expected string
+	}{
+		{"allowlist", config.Client{IncludeOperations: []string{"^(list|get)User"}}, "listUsers, getUser"},
+		{"exclude wins", config.Client{IncludeOperations: []string{"User"}, ExcludeOperations: []string{"^delete"}}, "listUsers, getUser"},
+		{"on top of tags", config.Client{IncludeTags: []string{"admin"}, IncludeOperations: []string{"^listUsers$"}}, ""},
+		{"without operationId", config.Client{IncludeOperations: []string{"^GET /admin/"}}, "GET /admin/stats"},
+	}
+	s := &Service{}
+	for _, test := range tests {
+		in, err := s.filterIR(fullIR, test.client)
+		if err != nil {
+			t.Fatalf("%s: %v", test.name, err)
+		}
+		if got := operations(in); got != test.expected {
+			t.Errorf("%s: operations = %q, expected %q", test.name, got, test.expected)
+		}
+	}
+
+	_, err := s.filterIR(fullIR, config.Client{ExcludeOperations: []string{"("}})
+	if err == nil || !strings.Contains(err.Error(), "invalid excludeOperations pattern") {
+		t.Errorf("expected an invalid pattern error, got %v", err)
+	}
+}
@@ -0,0 +1,62 @@
+package generator
+
+import (
+	"github.com/blimu-dev/sdk-gen/pkg/config"
+	"github.com/blimu-dev/sdk-gen/pkg/ir"
+)
+
+// typeOverrideLanguages maps client types to the language key of IRSchema.TypeOverrides
+var typeOverrideLanguages = map[string]string{
+	"go":               "go",
+	"typescript":       "ts",
+	"typescript-types": "ts",
+	"python":           "python",
+}
+
+// applyTypeMappings returns a copy of the IR where every primitive schema matching one of the
+// client's typeMappings carries the mapped native type as a type override, as if it declared
+// x-go-type, x-ts-type or x-python-type. Schemas with their own override keep it.
+func applyTypeMappings(in ir.IR, client config.Client) ir.IR {
+	lang := typeOverrideLanguages[client.Type]
+	if lang == "" || len(client.TypeMappings) == 0 {
+		return in
+	}
+
+	byKey := make(map[[2]string]config.TypeMapping, len(client.TypeMappings))
+	for _, m := range client.TypeMappings {
+		byKey[[2]string{m.Type, m.Format}] = m
+	}
+
+	return mapSchemas(in, func(s ir.IRSchema) ir.IRSchema {
+		switch s.Kind {
+		case ir.IRKindString, ir.IRKindInteger, ir.IRKindNumber, ir.IRKindBoolean:
+		default:
+			return s
+		}
+		if s.TypeOverrides[lang] != "" {
+			return s
+		}
+		m, ok := byKey[[2]string{string(s.Kind), s.Format}]
+		if !ok {
+			// A mapping without a format covers the remaining schemas of its type
+			if m, ok = byKey[[2]string{string(s.Kind), ""}]; !ok {
+				return s
+			}
+		}
+		s.TypeOverrides = withEntry(s.TypeOverrides, lang, m.Native)
+		if m.Import != "" {
+			s.TypeImports = withEntry(s.TypeImports, lang, m.Import)
+		}
+		return s
+	})
+}
+
+// withEntry returns a copy of m with key set to value, leaving the shared original untouched
+func withEntry(m map[string]string, key, value string) map[string]string {
+	out := make(map[string]string, len(m)+1)
+	for k, v := range m {
+		out[k] = v
+	}
+	out[key] = value
+	return out
+}
@@ -0,0 +1,121 @@
+package generator
+
+import (
+	"testing"
+
+	"github.com/blimu-dev/sdk-gen/pkg/config"
+	"github.com/blimu-dev/sdk-gen/pkg/ir"
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+const typeMappingsSpec = `openapi: 3.0.3
+info:
+  title: Accounts
+  version: "1.0"
+paths:
+  /accounts/{id}:
+    get:
+      operationId: getAccount
+      parameters:
+        - name: id
+          in: path
+          required: true
+          schema:
+            type: string
+            format: uuid
+      responses:
+        "200":
+          description: ok
+          content:
+            application/json:
+              schema:
+                $ref: '#/components/schemas/Account'
+components:
+  schemas:
+    Account:
+      type: object
+      properties:
+        id:
+          type: string
+          format: uuid
+        email:
+          type: string
+          format: email
+        name:
+          type: string
+        balance:
+          type: number
+          format: decimal
+        owners:
+          type: array
+          items:
+            type: string
+            format: uuid
+        legacyId:
+          type: string
+          format: uuid
+          x-python-type: str
+`
+
+func TestApplyTypeMappings(t *testing.T) {
+	doc, err := openapi3.NewLoader().LoadFromData([]byte(typeMappingsSpec))
+	if err != nil {
+		t.Fatalf("load spec: %v", err)
+	}
+	fullIR, err := BuildIR(doc)
+	if err != nil {
+		t.Fatalf("BuildIR: %v", err)
+	}
+
+	client := config.Client{Type: "python", TypeMappings: []config.TypeMapping{
+		{Type: "string", Format: "uuid", Native: "uuid.UUID", Import: "uuid"},
+		{Type: "number", Format: "decimal", Native: "decimal.Decimal", Import: "decimal"},
+		{Type: "string", Native: "str"},
+	}}
+	out, err := (&Service{}).filterIR(fullIR, client)
+	if err != nil {
+		t.Fatalf("filterIR: %v", err)
+	}
+
+	fields := map[string]ir.IRSchema{}
+	for _, md := range out.ModelDefs {
+		for _, f := range md.Schema.Properties {
+			fields[f.Name] = *f.Type
+		}
+	}
+	tests := []struct {
+		name       string
+		schema     ir.IRSchema
+		override   string
+		typeImport string
+	}{
+		{"uuid field", fields["id"], "uuid.UUID", "uuid"},
+		{"decimal field", fields["balance"], "decimal.Decimal", "decimal"},
+		{"array items", *fields["owners"].Items, "uuid.UUID", "uuid"},
+		{"string without a format-specific mapping", fields["email"], "str", ""},
+		{"plain string", fields["name"], "str", ""},
+		{"x-python-type wins", fields["legacyId"], "str", ""},
+		{"path parameter", out.Services[0].Operations[0].PathParams[0].Schema, "uuid.UUID", "uuid"},
+	}
+	for _, test := range tests {
+		if got := test.schema.TypeOverrides["python"]; got != test.override {
+			t.Errorf("%s: override = %q, expected %q", test.name, got, test.override)
+		}
+		if got := test.schema.TypeImports["python"]; got != test.typeImport {
+			t.Errorf("%s: import = %q, expected %q", test.name, got, test.typeImport)
+		}
+	}
+	if imports := out.TypeImports("python"); len(imports) != 2 || imports[0] != "decimal" || imports[1] != "uuid" {
+		t.Errorf("TypeImports() = %v", imports)
+	}
+
+	// Other languages and the shared IR are left alone
+	if got := fields["id"].TypeOverrides["go"]; got != "" {
+		t.Errorf("unexpected go override %q", got)
+	}
+	fullIR.WalkSchemas(func(s ir.IRSchema) {
+		if s.TypeOverrides["python"] == "uuid.UUID" {
+			t.Error("full IR was modified")
+		}
+	})
+}
@@ -3,9 +3,12 @@ package typescripttypes
 import (
 	"embed"
 	"fmt"
+	"io"
+	"log/slog"
 	"os"
 	"path/filepath"
 	"regexp"
+	"strconv"
 	"strings"
 	"text/template"
 
@@ -20,7 +23,12 @@ import (
 var templatesFS embed.FS
 
 // TypeScriptTypesGenerator implements the Generator interface for TypeScript type augmentation
-type TypeScriptTypesGenerator struct{}
+type TypeScriptTypesGenerator struct {
+	// funcs are the template functions set with SetTemplateFuncs
+	funcs template.FuncMap
+	// logger receives warnings, see SetLogger
+	logger *slog.Logger
+}
 
 // NewTypeScriptTypesGenerator creates a new TypeScript types generator
 func NewTypeScriptTypesGenerator() *TypeScriptTypesGenerator {
@@ -32,6 +40,18 @@ func (g *TypeScriptTypesGenerator) GetType() string {
 	return "typescript-types"
 }
 
+// SetTemplateFuncs adds funcs to the functions available to the templates. A function named
+// like a built-in helper replaces it.
+func (g *TypeScriptTypesGenerator) SetTemplateFuncs(funcs template.FuncMap) {
+	g.funcs = funcs
+}
+
+// SetLogger sets the logger warnings found while generating, such as renamed methods, are
+// reported to
+func (g *TypeScriptTypesGenerator) SetLogger(logger *slog.Logger) {
+	g.logger = logger
+}
+
 // Generate creates a TypeScript type augmentation file from the given configuration and IR
 func (g *TypeScriptTypesGenerator) Generate(client config.Client, in ir.IR) error {
 	// Ensure output directory exists
@@ -51,23 +71,30 @@ func (g *TypeScriptTypesGenerator) Generate(client config.Client, in ir.IR) erro
 		opts.OutputFileName = client.PackageName + ".d.ts"
 	}
 
+	typeOpts := newTypeOptions(client)
+	methodNames, warnings := utils.NewMethodNames(in.Services, func(op ir.IROperation) string { return resolveMethodName(client, op) })
+	utils.LogWarnings(g.logger, client.Name, warnings)
+	methodName := methodNames.Get
+
 	funcMap := template.FuncMap{
 		"pascal":      toPascalCase,
 		"camel":       toCamelCase,
 		"kebab":       toKebabCase,
 		"serviceName": func(tag string) string { return toPascalCase(tag) + "Service" },
 		"serviceProp": func(tag string) string { return toCamelCase(tag) },
-		"methodName":  func(op ir.IROperation) string { return resolveMethodName(client, op) },
+		"methodName":  methodName,
 		"pathTemplate":      func(op ir.IROperation) string { return buildPathTemplate(op) },
 		"pathParamsInOrder": func(op ir.IROperation) []ir.IRParam { return orderPathParams(op) },
-		"methodSignature":   func(op ir.IROperation) []string { return buildMethodSignature(op, resolveMethodName(client, op)) },
+		"enumLiterals":      enumTSLiterals,
+		"typeImports":       func() []string { return in.TypeImports("ts") },
+		"methodSignature":   func(op ir.IROperation) []string { return buildMethodSignature(op, methodName(op), typeOpts) },
 		"tsType": func(x any) string {
 			switch v := x.(type) {
 			case ir.IRSchema:
-				return schemaToTSType(v)
+				return schemaToTSType(v, typeOpts)
 			case *ir.IRSchema:
 				if v != nil {
-					return schemaToTSType(*v)
+					return schemaToTSType(*v, typeOpts)
 				}
 				return "unknown"
 			default:
@@ -115,10 +142,14 @@ func (g *TypeScriptTypesGenerator) Generate(client config.Client, in ir.IR) erro
 	for k, v := range sprig.FuncMap() {
 		funcMap[k] = v
 	}
+	// User functions come last and replace helpers of the same name
+	for k, v := range g.funcs {
+		funcMap[k] = v
+	}
 
 	// Generate the type augmentation file
 	outputFile := filepath.Join(client.OutDir, opts.OutputFileName)
-	if err := renderFile("types.d.ts.gotmpl", outputFile, funcMap, map[string]any{
+	if err := renderFile(client, "types.d.ts.gotmpl", outputFile, funcMap, map[string]any{
 		"Client":  client,
 		"IR":      in,
 		"Options": opts,
@@ -130,7 +161,12 @@ func (g *TypeScriptTypesGenerator) Generate(client config.Client, in ir.IR) erro
 }
 
 // renderFile renders a template file to the target path
-func renderFile(templateName, targetPath string, funcMap template.FuncMap, data map[string]any) error {
+func renderFile(client config.Client, templateName, targetPath string, funcMap template.FuncMap, data map[string]any) error {
+	// Check if file should be excluded
+	if client.ShouldExcludeFile(targetPath) {
+		return nil // Skip this file silently
+	}
+
 	tmplContent, err := templatesFS.ReadFile("templates/" + templateName)
 	if err != nil {
 		return fmt.Errorf("failed to read template %s: %w", templateName, err)
@@ -147,6 +183,10 @@ func renderFile(templateName, targetPath string, funcMap template.FuncMap, data
 	}
 	defer file.Close()
 
+	if _, err := io.WriteString(file, utils.FileHeader(client.FileHeader, targetPath)); err != nil {
+		return fmt.Errorf("failed to write file %s: %w", targetPath, err)
+	}
+
 	if err := tmpl.Execute(file, data); err != nil {
 		return fmt.Errorf("failed to execute template %s: %w", templateName, err)
 	}
@@ -159,14 +199,37 @@ var toPascalCase = utils.ToPascalCase
 var toCamelCase = utils.ToCamelCase
 var toKebabCase = utils.ToKebabCase
 
+// typeOptions carries client settings that influence how IR schemas map to TypeScript types
+type typeOptions struct {
+	// DateAsNativeType maps date/date-time strings to Date
+	DateAsNativeType bool
+}
+
+// newTypeOptions derives type mapping options from the client configuration
+func newTypeOptions(client config.Client) typeOptions {
+	return typeOptions{DateAsNativeType: client.DateAsNativeType}
+}
+
 // schemaToTSType converts an IR schema to TypeScript type string
-func schemaToTSType(s ir.IRSchema) string {
+func schemaToTSType(s ir.IRSchema, opts typeOptions) string {
+	// x-ts-type replaces the inferred type
+	if override := s.TypeOverrides["ts"]; override != "" {
+		if s.Nullable {
+			return override + " | null"
+		}
+		return override
+	}
 	// Base type string without nullability; append null later
 	var t string
 	switch s.Kind {
 	case ir.IRKindString:
 		if s.Format == "binary" {
 			t = "Blob"
+		} else if s.Format == "byte" {
+			// base64-encoded content is typed as the string sent on the wire
+			t = "string"
+		} else if opts.DateAsNativeType && (s.Format == "date" || s.Format == "date-time") {
+			t = "Date"
 		} else {
 			t = "string"
 		}
@@ -184,55 +247,33 @@ func schemaToTSType(s ir.IRSchema) string {
 		}
 	case ir.IRKindArray:
 		if s.Items != nil {
-			inner := schemaToTSType(*s.Items)
-			// Wrap unions/intersections in parentheses inside Array<>
-			if strings.Contains(inner, " | ") || strings.Contains(inner, " & ") {
-				inner = "(" + inner + ")"
-			}
-			t = "Array<" + inner + ">"
+			// Unions need no parentheses inside Array<>, so nullable items render as
+			// Array<T | null> while a nullable array renders as Array<T> | null
+			t = "Array<" + schemaToTSType(*s.Items, opts) + ">"
 		} else {
 			t = "Array<unknown>"
 		}
 	case ir.IRKindOneOf:
 		parts := make([]string, 0, len(s.OneOf))
 		for _, sub := range s.OneOf {
-			parts = append(parts, schemaToTSType(*sub))
+			parts = append(parts, schemaToTSType(*sub, opts))
 		}
 		t = strings.Join(parts, " | ")
 	case ir.IRKindAnyOf:
 		parts := make([]string, 0, len(s.AnyOf))
 		for _, sub := range s.AnyOf {
-			parts = append(parts, schemaToTSType(*sub))
+			parts = append(parts, schemaToTSType(*sub, opts))
 		}
 		t = strings.Join(parts, " | ")
 	case ir.IRKindAllOf:
 		parts := make([]string, 0, len(s.AllOf))
 		for _, sub := range s.AllOf {
-			parts = append(parts, schemaToTSType(*sub))
+			parts = append(parts, schemaToTSType(*sub, opts))
 		}
 		t = strings.Join(parts, " & ")
 	case ir.IRKindEnum:
 		// Prefer using name via Ref in properties; for safety, inline a union here
-		if len(s.EnumValues) > 0 {
-			vals := make([]string, 0, len(s.EnumValues))
-			switch s.EnumBase {
-			case ir.IRKindNumber, ir.IRKindInteger:
-				for _, v := range s.EnumValues {
-					vals = append(vals, v)
-				}
-			case ir.IRKindBoolean:
-				for _, v := range s.EnumValues {
-					if v == "true" || v == "false" {
-						vals = append(vals, v)
-					} else {
-						vals = append(vals, "\""+v+"\"")
-					}
-				}
-			default:
-				for _, v := range s.EnumValues {
-					vals = append(vals, "\""+v+"\"")
-				}
-			}
+		if vals := enumTSLiterals(s); len(vals) > 0 {
 			t = strings.Join(vals, " | ")
 		} else {
 			t = "unknown"
@@ -244,7 +285,7 @@ func schemaToTSType(s ir.IRSchema) string {
 			// Inline object shape for rare cases; nested ones should be refs
 			parts := make([]string, 0, len(s.Properties))
 			for _, f := range s.Properties {
-				ft := schemaToTSType(*f.Type)
+				ft := schemaToTSType(*f.Type, opts)
 				if f.Required {
 					parts = append(parts, f.Name+": "+ft)
 				} else {
@@ -324,7 +365,8 @@ func defaultParseOperationID(opID string) string {
 	return opID
 }
 
-// buildPathTemplate converts OpenAPI path to TypeScript template literal
+// buildPathTemplate converts OpenAPI path to TypeScript template literal; array parameters
+// are joined with the separator of their style
 func buildPathTemplate(op ir.IROperation) string {
 	// Convert /foo/{id}/bar/{slug} -> `/foo/${path.id}/bar/${path.slug}`
 	path := op.Path
@@ -340,9 +382,15 @@ func buildPathTemplate(op ir.IROperation) string {
 			}
 			if j < len(path) {
 				name := path[i+1 : j]
-				b.WriteString("${encodeURIComponent(")
-				b.WriteString(name)
-				b.WriteString(")}")
+				b.WriteString(op.PathPrefix(name))
+				if p, ok := op.PathParam(name); ok && p.Schema.Kind == ir.IRKindArray {
+					// Each value is encoded on its own, the separator is not
+					fmt.Fprintf(&b, "${%s.map((v) => encodeURIComponent(String(v))).join(%s)}", name, strconv.Quote(p.PathSeparator()))
+				} else {
+					b.WriteString("${encodeURIComponent(")
+					b.WriteString(name)
+					b.WriteString(")}")
+				}
 				i = j
 				continue
 			}
@@ -381,11 +429,11 @@ func orderPathParams(op ir.IROperation) []ir.IRParam {
 }
 
 // buildMethodSignature constructs the TS parameter list, using the provided methodName for query type name
-func buildMethodSignature(op ir.IROperation, methodName string) []string {
+func buildMethodSignature(op ir.IROperation, methodName string, opts typeOptions) []string {
 	parts := []string{}
 	// path params as positional args
 	for _, p := range orderPathParams(op) {
-		parts = append(parts, fmt.Sprintf("%s: %s", p.Name, schemaToTSType(p.Schema)))
+		parts = append(parts, fmt.Sprintf("%s: %s", p.Name, schemaToTSType(p.Schema, opts)))
 	}
 	// query object
 	if len(op.QueryParams) > 0 {
@@ -399,7 +447,7 @@ func buildMethodSignature(op ir.IROperation, methodName string) []string {
 		if !op.RequestBody.Required {
 			opt = "?"
 		}
-		parts = append(parts, fmt.Sprintf("body%s: %s", opt, schemaToTSType(op.RequestBody.Schema)))
+		parts = append(parts, fmt.Sprintf("body%s: %s", opt, schemaToTSType(op.RequestBody.Schema, opts)))
 	}
 	// init
 	parts = append(parts, "init?: Omit<RequestInit, \"method\" | \"body\">")
@@ -428,3 +476,26 @@ func quoteTSPropertyName(name string) string {
 	}
 	return name
 }
+
+// enumTSLiterals returns the TypeScript literal for each enum value. Numeric and boolean
+// enums are rendered from the raw values so numbers stay exact (1000000 rather than 1e+06).
+func enumTSLiterals(s ir.IRSchema) []string {
+	vals := make([]string, 0, len(s.EnumValues))
+	switch s.EnumBase {
+	case ir.IRKindNumber, ir.IRKindInteger:
+		vals = append(vals, s.EnumLiterals()...)
+	case ir.IRKindBoolean:
+		for _, v := range s.EnumLiterals() {
+			if v == "true" || v == "false" {
+				vals = append(vals, v)
+			} else {
+				vals = append(vals, "\""+v+"\"")
+			}
+		}
+	default:
+		for _, v := range s.EnumValues {
+			vals = append(vals, "\""+v+"\"")
+		}
+	}
+	return vals
+}
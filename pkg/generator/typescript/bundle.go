@@ -0,0 +1,109 @@
+package typescript
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/blimu-dev/sdk-gen/pkg/utils"
+)
+
+var (
+	// importLine matches a single-line import statement, capturing its module specifier
+	importLine = regexp.MustCompile(`^import .* from ["']([^"']+)["'];$`)
+	// reexportLine matches a re-export from another module of the SDK
+	reexportLine = regexp.MustCompile(`^export .* from ["']\.\.?/[^"']+["'];$`)
+	// localExportLine matches an export list of names declared elsewhere, e.g. export { FetchError };
+	localExportLine = regexp.MustCompile(`^export (type )?\{[^}]*\};$`)
+	// declarationLine matches exported declarations at any indentation and unexported ones at
+	// the top level, capturing the declared name
+	declarationLine = regexp.MustCompile(`^(?:\s*export\s+(?:declare\s+)?|)(?:async\s+)?(?:function\*?|class|interface|type|const|let|var|enum)\s+([A-Za-z_$][\w$]*)`)
+	// schemaRef matches a reference into the Schema namespace, e.g. Schema.User
+	schemaRef = regexp.MustCompile(`\bSchema\.([A-Za-z_$])`)
+	// blankLines matches runs of empty lines left behind by dropped statements
+	blankLines = regexp.MustCompile(`\n(?:[ \t]*\n){2,}`)
+)
+
+// bundleSingleFile merges the rendered sources under srcDir into src/index.ts and removes the
+// other files. Imports between the SDK's modules and re-exports are dropped, Schema.X
+// references become the in-file model X, and external imports move to the top. Two modules
+// declaring the same name cannot share one file, so that is an error.
+func bundleSingleFile(srcDir, fileHeader string) error {
+	// Declarations come before their uses where that matters (classes extending or
+	// instantiating others), so models and the core client go first and index.ts last
+	files := []string{"schema.ts", "client.ts", "utils.ts", "meta.ts"}
+	services, err := filepath.Glob(filepath.Join(srcDir, "services", "*.ts"))
+	if err != nil {
+		return err
+	}
+	sort.Strings(services)
+	for _, path := range services {
+		rel, _ := filepath.Rel(srcDir, path)
+		files = append(files, filepath.ToSlash(rel))
+	}
+	files = append(files, "index.ts")
+
+	var imports, body, bundled []string
+	seenImports := map[string]bool{}
+	declaredBy := map[string]string{}
+	for _, name := range files {
+		path := filepath.Join(srcDir, filepath.FromSlash(name))
+		data, err := os.ReadFile(path)
+		if os.IsNotExist(err) {
+			continue
+		} else if err != nil {
+			return err
+		}
+		src := strings.TrimPrefix(string(data), utils.FileHeader(fileHeader, path))
+
+		var kept []string
+		for _, line := range strings.Split(src, "\n") {
+			if m := importLine.FindStringSubmatch(line); m != nil {
+				if !strings.HasPrefix(m[1], ".") && !seenImports[line] {
+					seenImports[line] = true
+					imports = append(imports, line)
+				}
+				continue
+			}
+			if reexportLine.MatchString(line) || localExportLine.MatchString(line) {
+				// A comment introducing the dropped export goes with it
+				for len(kept) > 0 && strings.HasPrefix(strings.TrimSpace(kept[len(kept)-1]), "//") {
+					kept = kept[:len(kept)-1]
+				}
+				continue
+			}
+			if m := declarationLine.FindStringSubmatch(line); m != nil {
+				if other, ok := declaredBy[m[1]]; ok && other != name {
+					return fmt.Errorf("bundleSingleFile: %q is declared by both %s and %s", m[1], other, name)
+				}
+				declaredBy[m[1]] = name
+			}
+			kept = append(kept, schemaRef.ReplaceAllString(line, "$1"))
+		}
+		merged := blankLines.ReplaceAllString(strings.Join(kept, "\n"), "\n\n")
+		body = append(body, "// "+name, strings.TrimSpace(merged), "")
+		if name != "index.ts" {
+			bundled = append(bundled, path)
+		}
+	}
+
+	for _, path := range bundled {
+		if err := os.Remove(path); err != nil {
+			return err
+		}
+	}
+	if err := os.RemoveAll(filepath.Join(srcDir, "services")); err != nil {
+		return err
+	}
+
+	var out strings.Builder
+	out.WriteString(utils.FileHeader(fileHeader, filepath.Join(srcDir, "index.ts")))
+	if len(imports) > 0 {
+		out.WriteString(strings.Join(imports, "\n") + "\n\n")
+	}
+	out.WriteString(strings.Join(body, "\n"))
+	return os.WriteFile(filepath.Join(srcDir, "index.ts"), []byte(out.String()), 0o644)
+}
@@ -0,0 +1,79 @@
+package typescript
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeSources(t *testing.T, files map[string]string) string {
+	t.Helper()
+	dir := t.TempDir()
+	for name, content := range files {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+func TestBundleSingleFile(t *testing.T) {
+	dir := writeSources(t, map[string]string{
+		"schema.ts": "// Header\n\nimport { Decimal } from \"decimal.js\";\n\n  export interface User {\n    balance: Decimal;\n  }\n",
+		"client.ts": "// Header\n\nexport class CoreClient {}\n",
+		"services/users.ts": "// Header\n\nimport { CoreClient } from \"../client\";\nimport * as Schema from \"../schema\";\nimport { Decimal } from \"decimal.js\";\n\n" +
+			"export class UsersService {\n  constructor(private core: CoreClient) {}\n  get(): Promise<Schema.User> { return Promise.resolve({} as Schema.User); }\n}\n",
+		"index.ts": "// Header\n\nimport { CoreClient } from \"./client\";\nimport { UsersService } from \"./services/users\";\n\n" +
+			"export class API {}\n\n// Re-exports\nexport { CoreClient };\nexport * as Schema from \"./schema\";\nexport { UsersService } from \"./services/users\";\n",
+	})
+
+	if err := bundleSingleFile(dir, "Header"); err != nil {
+		t.Fatalf("bundleSingleFile: %v", err)
+	}
+	data, err := os.ReadFile(filepath.Join(dir, "index.ts"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := string(data)
+
+	if !strings.HasPrefix(got, "// Header\n\nimport { Decimal } from \"decimal.js\";\n\n// schema.ts\n") {
+		t.Errorf("expected the header, then the external import once, then the models:\n%s", got)
+	}
+	for _, unexpected := range []string{"Schema.", "from \"./", "from \"../", "export { CoreClient }", "// Re-exports"} {
+		if strings.Contains(got, unexpected) {
+			t.Errorf("bundle contains %q:\n%s", unexpected, got)
+		}
+	}
+	if !strings.Contains(got, "get(): Promise<User> { return Promise.resolve({} as User); }") {
+		t.Errorf("expected Schema.User to become User:\n%s", got)
+	}
+	if strings.Count(got, "import { Decimal }") != 1 || strings.Count(got, "// Header") != 1 {
+		t.Errorf("expected a single import and header:\n%s", got)
+	}
+	for _, name := range []string{"schema.ts", "client.ts", "services"} {
+		if _, err := os.Stat(filepath.Join(dir, name)); !os.IsNotExist(err) {
+			t.Errorf("expected %s to be removed", name)
+		}
+	}
+}
+
+func TestBundleSingleFileConflict(t *testing.T) {
+	dir := writeSources(t, map[string]string{
+		"schema.ts": "  export interface CoreClient {\n    id: string;\n  }\n",
+		"client.ts": "export class CoreClient {}\n",
+		"index.ts":  "export * as Schema from \"./schema\";\n",
+	})
+
+	err := bundleSingleFile(dir, "")
+	if err == nil || !strings.Contains(err.Error(), `"CoreClient" is declared by both schema.ts and client.ts`) {
+		t.Fatalf("expected a conflict error, got %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "schema.ts")); err != nil {
+		t.Errorf("expected sources to be left in place on error: %v", err)
+	}
+}
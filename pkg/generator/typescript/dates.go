@@ -0,0 +1,166 @@
+package typescript
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/blimu-dev/sdk-gen/pkg/ir"
+)
+
+// dateShapes renders where JSON values hold the date and date-time strings typed as Date with
+// dateAsNativeType, as DateShape literals for the reviver and encoder of client.ts. Only those
+// strings are revived, so plain strings that look like dates stay strings, and dates are sent
+// back as YYYY-MM-DD.
+type dateShapes struct {
+	defs []ir.IRModelDef
+	// dated holds the models whose values hold dates, directly or through refs
+	dated map[string]bool
+}
+
+// namedDateShape is the shape of a model with dates, declared once in DATE_SHAPES and
+// referenced by name so recursive models are revived at any depth
+type namedDateShape struct {
+	Name  string
+	Shape string
+}
+
+// newDateShapes finds the models holding dates, following refs until no more are found
+func newDateShapes(defs []ir.IRModelDef) dateShapes {
+	d := dateShapes{defs: defs, dated: map[string]bool{}}
+	for changed := true; changed; {
+		changed = false
+		for _, md := range defs {
+			if !d.dated[md.Name] && d.shape(md.Schema) != "" {
+				d.dated[md.Name] = true
+				changed = true
+			}
+		}
+	}
+	return d
+}
+
+// models returns the shapes of the models holding dates in declaration order
+func (d dateShapes) models() []namedDateShape {
+	var out []namedDateShape
+	seen := map[string]bool{}
+	for _, md := range d.defs {
+		if d.dated[md.Name] && !seen[md.Name] {
+			seen[md.Name] = true
+			out = append(out, namedDateShape{Name: strconv.Quote(md.Name), Shape: d.shape(md.Schema)})
+		}
+	}
+	return out
+}
+
+// response renders the shape of what an operation resolves to: its response, or every JSON
+// response it may negotiate. It is empty when the response holds no dates.
+func (d dateShapes) response(op ir.IROperation) string {
+	if !op.Response.Negotiated() {
+		return d.shape(op.Response.Schema)
+	}
+	var shapes []string
+	for _, content := range op.Response.Contents {
+		if shape := d.shape(content.Schema); shape != "" {
+			shapes = append(shapes, shape)
+		}
+	}
+	return allShapes(shapes)
+}
+
+// body renders the shape of an operation's JSON or form request body, whose dates are
+// formatted before it is serialized. It is empty when the body holds no dates.
+func (d dateShapes) body(op ir.IROperation) string {
+	if acceptsContents(op) || op.RequestBody == nil {
+		return ""
+	}
+	if op.RequestBody.ContentType != "application/json" && !op.RequestBody.IsFormURLEncoded() {
+		return ""
+	}
+	return d.shape(op.RequestBody.Schema)
+}
+
+// query renders the shape of an operation's query object. Parameters sent as JSON are
+// serialized by the service and left out. It is empty when no parameter holds dates.
+func (d dateShapes) query(op ir.IROperation) string {
+	var fields []string
+	for _, p := range op.QueryParams {
+		if p.IsJSON() {
+			continue
+		}
+		if shape := d.shape(p.Schema); shape != "" {
+			fields = append(fields, strconv.Quote(p.Name)+": "+shape)
+		}
+	}
+	if len(fields) == 0 {
+		return ""
+	}
+	return "{ fields: { " + strings.Join(fields, ", ") + " } }"
+}
+
+// shape renders the DateShape of s, or "" when its values hold no dates
+func (d dateShapes) shape(s ir.IRSchema) string {
+	if s.TypeOverrides["ts"] != "" {
+		return ""
+	}
+	switch s.Kind {
+	case ir.IRKindString:
+		if s.Format == "date" || s.Format == "date-time" {
+			return strconv.Quote(s.Format)
+		}
+	case ir.IRKindRef:
+		if d.dated[s.Ref] {
+			return "{ ref: " + strconv.Quote(s.Ref) + " }"
+		}
+	case ir.IRKindArray:
+		if s.Items != nil {
+			if items := d.shape(*s.Items); items != "" {
+				return "{ array: " + items + " }"
+			}
+		}
+	case ir.IRKindObject:
+		// Like the TypeScript types, only maps type their additional properties
+		if isMapSchema(s) {
+			if values := d.shape(*s.AdditionalProperties); values != "" {
+				return "{ values: " + values + " }"
+			}
+			return ""
+		}
+		var fields []string
+		for _, f := range s.Properties {
+			if f.Type == nil {
+				continue
+			}
+			if shape := d.shape(*f.Type); shape != "" {
+				fields = append(fields, strconv.Quote(f.Name)+": "+shape)
+			}
+		}
+		if len(fields) > 0 {
+			return "{ fields: { " + strings.Join(fields, ", ") + " } }"
+		}
+	case ir.IRKindOneOf, ir.IRKindAnyOf, ir.IRKindAllOf:
+		var shapes []string
+		for _, list := range [][]*ir.IRSchema{s.OneOf, s.AnyOf, s.AllOf} {
+			for _, sub := range list {
+				if sub == nil {
+					continue
+				}
+				if shape := d.shape(*sub); shape != "" {
+					shapes = append(shapes, shape)
+				}
+			}
+		}
+		return allShapes(shapes)
+	}
+	return ""
+}
+
+// allShapes combines shapes that all apply to the same value
+func allShapes(shapes []string) string {
+	switch len(shapes) {
+	case 0:
+		return ""
+	case 1:
+		return shapes[0]
+	}
+	return "{ all: [" + strings.Join(shapes, ", ") + "] }"
+}
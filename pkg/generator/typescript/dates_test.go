@@ -0,0 +1,145 @@
+package typescript
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/blimu-dev/sdk-gen/pkg/config"
+	"github.com/blimu-dev/sdk-gen/pkg/ir"
+)
+
+func TestDateShapes(t *testing.T) {
+	str := &ir.IRSchema{Kind: ir.IRKindString}
+	dateTime := &ir.IRSchema{Kind: ir.IRKindString, Format: "date-time"}
+	ref := func(name string) *ir.IRSchema { return &ir.IRSchema{Kind: ir.IRKindRef, Ref: name} }
+	object := func(fields ...ir.IRField) ir.IRSchema { return ir.IRSchema{Kind: ir.IRKindObject, Properties: fields} }
+	d := newDateShapes([]ir.IRModelDef{
+		// Only Event reaches a date, through its recursive children
+		{Name: "Node", Schema: object(ir.IRField{Name: "children", Type: &ir.IRSchema{Kind: ir.IRKindArray, Items: ref("Node")}}, ir.IRField{Name: "event", Type: ref("Event")})},
+		{Name: "Event", Schema: object(ir.IRField{Name: "at", Type: dateTime}, ir.IRField{Name: "label", Type: str})},
+		{Name: "Note", Schema: object(ir.IRField{Name: "text", Type: str})},
+	})
+
+	tests := []struct {
+		name     string
+		schema   ir.IRSchema
+		expected string
+	}{
+		{"plain string", *str, ""},
+		{"date", ir.IRSchema{Kind: ir.IRKindString, Format: "date"}, `"date"`},
+		{"overridden date", ir.IRSchema{Kind: ir.IRKindString, Format: "date", TypeOverrides: map[string]string{"ts": "string"}}, ""},
+		{"model without dates", *ref("Note"), ""},
+		{"model with dates", *ref("Event"), `{ ref: "Event" }`},
+		{"model reaching dates", *ref("Node"), `{ ref: "Node" }`},
+		{"array", ir.IRSchema{Kind: ir.IRKindArray, Items: dateTime}, `{ array: "date-time" }`},
+		{"map", ir.IRSchema{Kind: ir.IRKindObject, AdditionalProperties: dateTime}, `{ values: "date-time" }`},
+		{"map of strings", ir.IRSchema{Kind: ir.IRKindObject, AdditionalProperties: str}, ""},
+		{"object", object(ir.IRField{Name: "label", Type: str}, ir.IRField{Name: "at", Type: dateTime}), `{ fields: { "at": "date-time" } }`},
+		{"union", ir.IRSchema{Kind: ir.IRKindOneOf, OneOf: []*ir.IRSchema{ref("Event"), ref("Note"), ref("Node")}}, `{ all: [{ ref: "Event" }, { ref: "Node" }] }`},
+	}
+	for _, test := range tests {
+		if got := d.shape(test.schema); got != test.expected {
+			t.Errorf("%s: shape() = %s, expected %s", test.name, got, test.expected)
+		}
+	}
+
+	models := d.models()
+	if len(models) != 2 || models[0].Name != `"Node"` || models[1].Shape != `{ fields: { "at": "date-time" } }` {
+		t.Errorf("models() = %+v", models)
+	}
+}
+
+func TestRequestDateShapes(t *testing.T) {
+	date := ir.IRSchema{Kind: ir.IRKindString, Format: "date"}
+	dated := ir.IRSchema{Kind: ir.IRKindRef, Ref: "Booking"}
+	d := newDateShapes([]ir.IRModelDef{
+		{Name: "Booking", Schema: ir.IRSchema{Kind: ir.IRKindObject, Properties: []ir.IRField{{Name: "day", Type: &date}}}},
+	})
+	// A booking read from a response is sent back with its day as YYYY-MM-DD
+	if models := d.models(); len(models) != 1 || models[0].Shape != `{ fields: { "day": "date" } }` {
+		t.Errorf("models() = %+v", models)
+	}
+
+	tests := []struct {
+		name     string
+		body     *ir.IRRequestBody
+		expected string
+	}{
+		{"json", &ir.IRRequestBody{ContentType: "application/json", Schema: dated}, `{ ref: "Booking" }`},
+		{"form", &ir.IRRequestBody{ContentType: ir.ContentTypeFormURLEncoded, Schema: dated}, `{ ref: "Booking" }`},
+		{"multipart", &ir.IRRequestBody{ContentType: "multipart/form-data", Schema: dated}, ""},
+		{"no body", nil, ""},
+	}
+	for _, test := range tests {
+		if got := d.body(ir.IROperation{RequestBody: test.body}); got != test.expected {
+			t.Errorf("%s: body() = %s, expected %s", test.name, got, test.expected)
+		}
+	}
+
+	op := ir.IROperation{QueryParams: []ir.IRParam{
+		{Name: "from", Schema: date},
+		{Name: "days", Schema: ir.IRSchema{Kind: ir.IRKindArray, Items: &date}},
+		{Name: "filter", Schema: dated, ContentType: "application/json"},
+		{Name: "q", Schema: ir.IRSchema{Kind: ir.IRKindString}},
+	}}
+	expected := `{ fields: { "from": "date", "days": { array: "date" } } }`
+	if got := d.query(op); got != expected {
+		t.Errorf("query() = %s, expected %s", got, expected)
+	}
+}
+
+func TestDateOnlyRoundTrip(t *testing.T) {
+	date := ir.IRSchema{Kind: ir.IRKindString, Format: "date"}
+	booking := ir.IRSchema{Kind: ir.IRKindRef, Ref: "Booking"}
+	in := ir.IR{
+		ModelDefs: []ir.IRModelDef{
+			{Name: "Booking", Schema: ir.IRSchema{Kind: ir.IRKindObject, Properties: []ir.IRField{{Name: "day", Type: &date}}}},
+		},
+		Services: []ir.IRService{{Tag: "bookings", Operations: []ir.IROperation{
+			{OperationID: "getBooking", Method: "GET", Path: "/booking", Tag: "bookings", Response: ir.IRResponse{TypeTS: "Booking", Schema: booking, StatusCode: "200", ContentType: "application/json"}},
+			{
+				OperationID: "putBooking", Method: "PUT", Path: "/booking", Tag: "bookings",
+				QueryParams: []ir.IRParam{{Name: "from", Schema: date}},
+				RequestBody: &ir.IRRequestBody{ContentType: "application/json", Schema: booking, Required: true},
+				Response:    ir.IRResponse{TypeTS: "void"},
+			},
+		}}},
+	}
+	dir := t.TempDir()
+	client := config.Client{Type: "typescript", OutDir: dir, PackageName: "bookings", Name: "Bookings", DateAsNativeType: true}
+	if err := NewTypeScriptGenerator().Generate(client, in); err != nil {
+		t.Fatal(err)
+	}
+	read := func(name string) string {
+		data, err := os.ReadFile(filepath.Join(dir, "src", name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return string(data)
+	}
+
+	// The day revived from getBooking is sent back to putBooking as YYYY-MM-DD, not as a
+	// date-time, and so is the date query parameter
+	for name, expected := range map[string][]string{
+		"client.ts": {
+			`"Booking": { fields: { "day": "date" } },`,
+			`if (shape === "date") return value instanceof Date ? value.toISOString().slice(0, 10) : value;`,
+			`const query = encodeDates(init.query, init.queryDates) as Record<string, any>;`,
+		},
+		filepath.Join("services", "bookings.ts"): {
+			`import { CoreClient, encodeDates } from "../client";`,
+			`dates: { ref: "Booking" },`,
+			`queryDates: { fields: { "from": "date" } },`,
+			`body: JSON.stringify(encodeDates(body, { ref: "Booking" })),`,
+		},
+	} {
+		content := read(name)
+		for _, want := range expected {
+			if !strings.Contains(content, want) {
+				t.Errorf("%s does not contain %q", name, want)
+			}
+		}
+	}
+}
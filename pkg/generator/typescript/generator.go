@@ -1,8 +1,11 @@
 package typescript
 
 import (
+	"bytes"
 	"embed"
 	"fmt"
+	"io"
+	"log/slog"
 	"os"
 	"path/filepath"
 	"regexp"
@@ -13,13 +16,19 @@ import (
 
 	"github.com/blimu-dev/sdk-gen/pkg/config"
 	"github.com/blimu-dev/sdk-gen/pkg/ir"
+	"github.com/blimu-dev/sdk-gen/pkg/utils"
 )
 
 //go:embed templates/*
 var templatesFS embed.FS
 
 // TypeScriptGenerator implements the Generator interface for TypeScript
-type TypeScriptGenerator struct{}
+type TypeScriptGenerator struct {
+	// funcs are the template functions set with SetTemplateFuncs
+	funcs template.FuncMap
+	// logger receives warnings, see SetLogger
+	logger *slog.Logger
+}
 
 // NewTypeScriptGenerator creates a new TypeScript generator
 func NewTypeScriptGenerator() *TypeScriptGenerator {
@@ -31,57 +40,171 @@ func (g *TypeScriptGenerator) GetType() string {
 	return "typescript"
 }
 
+// SetTemplateFuncs adds funcs to the functions available to the templates. A function named
+// like a built-in helper replaces it.
+func (g *TypeScriptGenerator) SetTemplateFuncs(funcs template.FuncMap) {
+	g.funcs = funcs
+}
+
+// SetLogger sets the logger warnings found while generating, such as renamed methods, are
+// reported to
+func (g *TypeScriptGenerator) SetLogger(logger *slog.Logger) {
+	g.logger = logger
+}
+
 // Generate creates a TypeScript SDK from the given configuration and IR
 func (g *TypeScriptGenerator) Generate(client config.Client, in ir.IR) error {
+	if client.BundleSingleFile && client.EmitZod {
+		return fmt.Errorf("bundleSingleFile cannot be combined with emitZod")
+	}
+
 	// Ensure directories
 	srcDir := filepath.Join(client.OutDir, "src")
 	servicesDir := filepath.Join(srcDir, "services")
-	if err := os.MkdirAll(servicesDir, 0o755); err != nil {
+	emitClient := client.ShouldEmitClient()
+	if err := os.MkdirAll(srcDir, 0o755); err != nil {
 		return err
 	}
 
+	typeOpts := newTypeOptions(client)
+	typeOpts.Variants = collectModelVariants(in.ModelDefs)
+	typeOpts.NullableModels = collectNullableModels(in.ModelDefs)
+	// Deduplicate model definitions to prevent duplicate enum/type generation
+	deduplicatedIR := deduplicateModelDefs(in)
+	zod := newZodRenderer(deduplicatedIR.ModelDefs, typeOpts)
+	dates := newDateShapes(deduplicatedIR.ModelDefs)
+	defs := make(map[string]ir.IRModelDef, len(deduplicatedIR.ModelDefs))
+	for _, md := range deduplicatedIR.ModelDefs {
+		defs[md.Name] = md
+	}
+	bodyDefaults := func(op ir.IROperation) string { return buildBodyDefaults(op, defs) }
+	methodNames, warnings := utils.NewMethodNames(in.Services, func(op ir.IROperation) string { return resolveMethodName(client, op) })
+	utils.LogWarnings(g.logger, client.Name, warnings)
+	methodName := methodNames.Get
+
 	funcMap := template.FuncMap{
-		"pascal":      toPascalCase,
-		"camel":       toCamelCase,
-		"kebab":       toKebabCase,
-		"serviceName": func(tag string) string { return toPascalCase(tag) + "Service" },
-		"serviceProp": func(tag string) string { return toCamelCase(tag) },
-		"fileBase":    func(tag string) string { return strings.ToLower(toSnakeCase(tag)) },
-		"methodName":  func(op ir.IROperation) string { return resolveMethodName(client, op) },
-		"queryTypeName": func(op ir.IROperation) string {
-			return toPascalCase(op.Tag) + toPascalCase(resolveMethodName(client, op)) + "Query"
-		},
+		"pascal":            toPascalCase,
+		"camel":             toCamelCase,
+		"kebab":             toKebabCase,
+		"serviceName":       func(tag string) string { return toPascalCase(tag) + "Service" },
+		"serviceProp":       func(tag string) string { return toCamelCase(tag) },
+		"fileBase":          func(tag string) string { return serviceFileBase(client, tag) },
+		"methodName":        methodName,
+		"queryTypeName":     func(op ir.IROperation) string { return queryTypeName(op, methodName(op)) },
 		"pathTemplate":      func(op ir.IROperation) string { return buildPathTemplate(op) },
 		"queryKeyBase":      func(op ir.IROperation) string { return buildQueryKeyBase(op) },
 		"pathParamsInOrder": func(op ir.IROperation) []ir.IRParam { return orderPathParams(op) },
-		"methodSignature":   func(op ir.IROperation) []string { return buildMethodSignature(op, resolveMethodName(client, op)) },
+		"environments":      func() []utils.Environment { return utils.Environments(client.Environments, in.Servers) },
+		"apiKeyAlias": func() string {
+			if s, ok := apiKeyAlias(in.SecuritySchemes); ok {
+				return s.Key
+			}
+			return ""
+		},
+		"sensitiveFields": func() []string { return sensitiveFieldNames(in) },
+		"methodSignature": func(op ir.IROperation) []string {
+			if client.SingleOptionsArg {
+				return buildOptionsSignature(op, methodName(op), true)
+			}
+			return buildMethodSignature(op, methodName(op), typeOpts)
+		},
 		"methodSignatureNoInit": func(op ir.IROperation) []string {
-			parts := buildMethodSignature(op, resolveMethodName(client, op))
+			if client.SingleOptionsArg {
+				return buildOptionsSignature(op, methodName(op), false)
+			}
+			parts := buildMethodSignature(op, methodName(op), typeOpts)
 			if len(parts) > 0 {
 				return parts[:len(parts)-1]
 			}
 			return parts
 		},
+		"optionsTypeName": func(op ir.IROperation) string { return optionsTypeName(op, methodName(op)) },
+		"optionsMembers":  func(op ir.IROperation) []string { return buildOptionsMembers(op, methodName(op), typeOpts) },
+		"unpackOptions": func(op ir.IROperation, withInit bool) string {
+			if !client.SingleOptionsArg {
+				return ""
+			}
+			return buildOptionsDestructure(op, withInit)
+		},
 		"queryKeyArgs": func(op ir.IROperation) []string { return queryKeyArgs(op) },
+		"operationKey": operationKey,
+		"exampleLines": func(v any) []string { return utils.ExampleLines(v, "  ") },
+		"exampleJSON":  utils.ExampleJSON,
+		"withHeaders": func(op ir.IROperation) bool {
+			return client.ResponseWithHeaders && len(op.Response.Headers) > 0
+		},
+		"resultType":           func(op ir.IROperation) string { return operationResponseTSType(op, typeOpts) },
+		"responseHeadersType":  responseHeadersType,
+		"responseHeadersValue": responseHeadersValue,
+		"acceptsContents":      acceptsContents,
+		"acceptTypeParam":      acceptTypeParam,
+		"acceptStream":         acceptStream,
+		"acceptEvents":         acceptEvents,
+		"eventDecoding":        eventDecoding,
+		"usesEventStreams":     usesEventStreams,
+		"serviceUtilsImports":  func(service ir.IRService) string { return serviceUtilsImports(service, bodyDefaults) },
+		"bodyDefaults":         bodyDefaults,
+		"typeImports":          func() []string { return in.TypeImports("ts") },
+		"zodPropertyCount":     zodPropertyCount,
+		"isMapSchema":          isMapSchema,
+		"dateShapes":           dates.models,
+		"queryDefaults":        func(op ir.IROperation) string { return buildQueryDefaults(op) },
+		"jsonQueryParams":      func(op ir.IROperation) string { return buildJSONQueryParams(op) },
+		"tsDefault":            func(v any) string { return tsLiteral(v) },
 		"tsType": func(x any) string {
 			switch v := x.(type) {
 			case ir.IRSchema:
-				return schemaToTSType(v)
+				return schemaToTSType(v, typeOpts)
 			case *ir.IRSchema:
 				if v != nil {
-					return schemaToTSType(*v)
+					return schemaToTSType(*v, typeOpts)
 				}
 				return "unknown"
 			default:
 				return "unknown"
 			}
 		},
-		"stripSchemaNs": func(s string) string { return strings.ReplaceAll(s, "Schema.", "") },
-		"reMatch":       func(pattern, s string) bool { r := regexp.MustCompile(pattern); return r.MatchString(s) },
-		"dict":          func() map[string]interface{} { return make(map[string]interface{}) },
-		"hasKey":        func(dict map[string]interface{}, key string) bool { _, exists := dict[key]; return exists },
-		"set":           func(dict map[string]interface{}, key string, value interface{}) string { dict[key] = value; return "" },
-		"quotePropName": quoteTSPropertyName,
+		"responseDates": func(op ir.IROperation) string {
+			if !client.DateAsNativeType {
+				return ""
+			}
+			return dates.response(op)
+		},
+		"bodyDates": func(op ir.IROperation) string {
+			if !client.DateAsNativeType {
+				return ""
+			}
+			return dates.body(op)
+		},
+		"queryDates": func(op ir.IROperation) string {
+			if !client.DateAsNativeType {
+				return ""
+			}
+			return dates.query(op)
+		},
+		"usesEncodeDates": func(service ir.IRService) bool {
+			for _, op := range service.Operations {
+				if client.DateAsNativeType && dates.body(op) != "" {
+					return true
+				}
+			}
+			return false
+		},
+		"responseType":   func(s ir.IRSchema) string { return responseTSType(s, typeOpts) },
+		"zodType":        zod.schema,
+		"zodField":       zod.field,
+		"createOmit":     func(name string) string { return omitKeys(typeOpts.Variants.Create[name]) },
+		"readOmit":       func(name string) string { return omitKeys(typeOpts.Variants.Read[name]) },
+		"stripSchemaNs":  func(s string) string { return strings.ReplaceAll(s, "Schema.", "") },
+		"reMatch":        func(pattern, s string) bool { r := regexp.MustCompile(pattern); return r.MatchString(s) },
+		"enumLiterals":   enumTSLiterals,
+		"enumKeys":       enumTSKeys,
+		"dict":           func() map[string]interface{} { return make(map[string]interface{}) },
+		"hasKey":         func(dict map[string]interface{}, key string) bool { _, exists := dict[key]; return exists },
+		"set":            func(dict map[string]interface{}, key string, value interface{}) string { dict[key] = value; return "" },
+		"quotePropName":  quoteTSPropertyName,
+		"emitClient":     func() bool { return emitClient },
+		"packageVersion": client.PackageVersion,
 		// Namespace helper functions
 		"groupByNamespace": func(services []ir.IRService) map[string][]ir.IRService {
 			namespaces := make(map[string][]ir.IRService)
@@ -117,51 +240,87 @@ func (g *TypeScriptGenerator) Generate(client config.Client, in ir.IR) error {
 	for k, v := range sprig.FuncMap() {
 		funcMap[k] = v
 	}
+	// User functions come last and replace helpers of the same name
+	for k, v := range g.funcs {
+		funcMap[k] = v
+	}
 
-	// client.ts
-	if err := renderFile(client, "client.ts.gotmpl", filepath.Join(srcDir, "client.ts"), funcMap, map[string]any{"Client": client, "IR": in}); err != nil {
-		return err
+	// client.ts, utils.ts and the services are left out of types-only packages
+	if emitClient {
+		if err := renderFile(client, "client.ts.gotmpl", filepath.Join(srcDir, "client.ts"), funcMap, map[string]any{"Client": client, "IR": in}); err != nil {
+			return err
+		}
+		if err := renderFile(client, "utils.ts.gotmpl", filepath.Join(srcDir, "utils.ts"), funcMap, map[string]any{"Client": client, "IR": in}); err != nil {
+			return err
+		}
+		if err := os.MkdirAll(servicesDir, 0o755); err != nil {
+			return err
+		}
+		for _, s := range in.Services {
+			target := filepath.Join(servicesDir, serviceFileBase(client, s.Tag)+".ts")
+			if err := renderFile(client, "service.ts.gotmpl", target, funcMap, map[string]any{"Client": client, "Service": s}); err != nil {
+				return err
+			}
+		}
 	}
 	// index.ts
 	if err := renderFile(client, "index.ts.gotmpl", filepath.Join(srcDir, "index.ts"), funcMap, map[string]any{"Client": client, "IR": in}); err != nil {
 		return err
 	}
-	// utils.ts
-	if err := renderFile(client, "utils.ts.gotmpl", filepath.Join(srcDir, "utils.ts"), funcMap, map[string]any{"Client": client, "IR": in}); err != nil {
-		return err
-	}
-	// services per tag
-	for _, s := range in.Services {
-		target := filepath.Join(servicesDir, fmt.Sprintf("%s.ts", strings.ToLower(toSnakeCase(s.Tag))))
-		if err := renderFile(client, "service.ts.gotmpl", target, funcMap, map[string]any{"Client": client, "Service": s}); err != nil {
+	// meta.ts
+	if client.EmitOperationMetadata {
+		if err := renderFile(client, "meta.ts.gotmpl", filepath.Join(srcDir, "meta.ts"), funcMap, map[string]any{"Client": client, "IR": in}); err != nil {
 			return err
 		}
 	}
 	// schemas (always render; may hold operation query interfaces even without models)
-	// Deduplicate model definitions to prevent duplicate enum/type generation
-	deduplicatedIR := deduplicateModelDefs(in)
 	if err := renderFile(client, "schema.ts.gotmpl", filepath.Join(srcDir, "schema.ts"), funcMap, map[string]any{"IR": deduplicatedIR}); err != nil {
 		return err
 	}
+	// schemas.zod.ts
+	if client.EmitZod {
+		if err := renderFile(client, "schemas.zod.ts.gotmpl", filepath.Join(srcDir, "schemas.zod.ts"), funcMap, map[string]any{"IR": deduplicatedIR}); err != nil {
+			return err
+		}
+	}
+	if client.BundleSingleFile {
+		if err := bundleSingleFile(srcDir, client.FileHeader); err != nil {
+			return err
+		}
+	}
 	// package.json
 	if err := renderFile(client, "package.json.gotmpl", filepath.Join(client.OutDir, "package.json"), funcMap, map[string]any{"Client": client}); err != nil {
 		return err
 	}
 
 	// .prettierrc.json
-	if err := renderFile(client, ".prettierrc.json.gotmpl", filepath.Join(client.OutDir, ".prettierrc.json"), funcMap, map[string]any{"Client": client}); err != nil {
+	if err := renderFile(client, ".prettierrc.json.gotmpl", filepath.Join(client.OutDir, ".prettierrc.json"), funcMap, map[string]any{"Client": client, "Style": newCodeStyle(client)}); err != nil {
 		return err
 	}
 	// .prettierignore
 	if err := renderFile(client, ".prettierignore.gotmpl", filepath.Join(client.OutDir, ".prettierignore"), funcMap, map[string]any{"Client": client}); err != nil {
 		return err
 	}
+	// .gitignore
+	if err := renderFile(client, ".gitignore.gotmpl", filepath.Join(client.OutDir, ".gitignore"), funcMap, map[string]any{"Client": client}); err != nil {
+		return err
+	}
+	// .npmignore keeps sources and tooling config out of the published package
+	if client.ForPublishing {
+		if err := renderFile(client, ".npmignore.gotmpl", filepath.Join(client.OutDir, ".npmignore"), funcMap, map[string]any{"Client": client}); err != nil {
+			return err
+		}
+	}
 	// tsconfig.json
 	if err := renderFile(client, "tsconfig.json.gotmpl", filepath.Join(client.OutDir, "tsconfig.json"), funcMap, map[string]any{"Client": client}); err != nil {
 		return err
 	}
 	// README.md
-	if err := renderFile(client, "README.md.gotmpl", filepath.Join(client.OutDir, "README.md"), funcMap, map[string]any{"Client": client, "IR": in}); err != nil {
+	readme := "README.md.gotmpl"
+	if !emitClient {
+		readme = "README.types.md.gotmpl"
+	}
+	if err := renderFile(client, readme, filepath.Join(client.OutDir, "README.md"), funcMap, map[string]any{"Client": client, "IR": in}); err != nil {
 		return err
 	}
 	return nil
@@ -190,9 +349,21 @@ func renderFile(client config.Client, templateName, targetPath string, funcMap t
 	}
 	defer file.Close()
 
-	if err := tmpl.Execute(file, data); err != nil {
+	if _, err := io.WriteString(file, utils.FileHeader(client.FileHeader, targetPath)); err != nil {
+		return fmt.Errorf("failed to write file %s: %w", targetPath, err)
+	}
+
+	var out bytes.Buffer
+	if err := tmpl.Execute(&out, data); err != nil {
 		return fmt.Errorf("failed to execute template %s: %w", templateName, err)
 	}
+	src := out.String()
+	if strings.HasSuffix(targetPath, ".ts") {
+		src = newCodeStyle(client).apply(src)
+	}
+	if _, err := io.WriteString(file, src); err != nil {
+		return fmt.Errorf("failed to write file %s: %w", targetPath, err)
+	}
 
 	return nil
 }
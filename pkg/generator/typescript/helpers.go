@@ -1,8 +1,11 @@
 package typescript
 
 import (
+	"encoding/json"
 	"fmt"
 	"os/exec"
+	"sort"
+	"strconv"
 	"strings"
 
 	"github.com/blimu-dev/sdk-gen/pkg/config"
@@ -10,14 +13,255 @@ import (
 	"github.com/blimu-dev/sdk-gen/pkg/utils"
 )
 
+// typeOptions carries client settings that influence how IR schemas map to TypeScript types
+type typeOptions struct {
+	// DateAsNativeType maps date/date-time strings to Date
+	DateAsNativeType bool
+	// Variants holds the request/response aliases derived from readOnly/writeOnly fields
+	Variants modelVariants
+	// NullableModels holds the nullable models whose declaration cannot include null
+	NullableModels map[string]bool
+}
+
+// nullable reports whether the type of s includes null, which for refs also holds when the
+// referenced model is nullable but declared as an interface or enum
+func (o typeOptions) nullable(s ir.IRSchema) bool {
+	return s.Nullable || s.Kind == ir.IRKindRef && o.NullableModels[s.Ref]
+}
+
+// isMapSchema reports whether s is an object with no properties whose values are described by
+// additionalProperties, rendered as a Record
+func isMapSchema(s ir.IRSchema) bool {
+	return s.Kind == ir.IRKindObject && len(s.Properties) == 0 && s.AdditionalProperties != nil
+}
+
+// collectNullableModels finds the nullable models that schema.ts declares as an interface or
+// an enum, so refs to them add null themselves. Other models are type aliases including null.
+func collectNullableModels(defs []ir.IRModelDef) map[string]bool {
+	nullable := map[string]bool{}
+	for _, md := range defs {
+		if md.Schema.Nullable && (md.Schema.Kind == ir.IRKindEnum || md.Schema.Kind == ir.IRKindObject && !isMapSchema(md.Schema)) {
+			nullable[md.Name] = true
+		}
+	}
+	return nullable
+}
+
+// modelVariants records, per model name, the fields omitted from its derived aliases.
+// Create variants drop readOnly fields (request bodies); Read variants drop writeOnly
+// fields (responses).
+type modelVariants struct {
+	Create map[string][]string
+	Read   map[string][]string
+}
+
+// collectModelVariants finds object models with readOnly or writeOnly fields. A variant is
+// skipped when its derived name would collide with an existing model.
+func collectModelVariants(defs []ir.IRModelDef) modelVariants {
+	v := modelVariants{Create: map[string][]string{}, Read: map[string][]string{}}
+	names := make(map[string]bool, len(defs))
+	for _, md := range defs {
+		names[md.Name] = true
+	}
+	for _, md := range defs {
+		if md.Schema.Kind != ir.IRKindObject {
+			continue
+		}
+		var readOnly, writeOnly []string
+		for _, f := range md.Schema.Properties {
+			if f.Annotations.ReadOnly {
+				readOnly = append(readOnly, f.Name)
+			}
+			if f.Annotations.WriteOnly {
+				writeOnly = append(writeOnly, f.Name)
+			}
+		}
+		if len(readOnly) > 0 && !names[md.Name+"Create"] {
+			v.Create[md.Name] = readOnly
+		}
+		if len(writeOnly) > 0 && !names[md.Name+"Read"] {
+			v.Read[md.Name] = writeOnly
+		}
+	}
+	return v
+}
+
+// variantTSType renders s like schemaToTSType, but swaps direct refs (and array items) to
+// models that have a variant in the given set for the suffixed alias name
+func variantTSType(s ir.IRSchema, opts typeOptions, variants map[string][]string, suffix string) string {
+	switch {
+	case s.TypeOverrides["ts"] != "":
+		return schemaToTSType(s, opts)
+	case s.Kind == ir.IRKindRef && variants[s.Ref] != nil:
+		t := "Schema." + s.Ref + suffix
+		if opts.nullable(s) {
+			t += " | null"
+		}
+		return t
+	case s.Kind == ir.IRKindArray && s.Items != nil && s.Items.Kind == ir.IRKindRef && variants[s.Items.Ref] != nil:
+		t := "Array<" + variantTSType(*s.Items, opts, variants, suffix) + ">"
+		if s.Nullable {
+			t += " | null"
+		}
+		return t
+	}
+	return schemaToTSType(s, opts)
+}
+
+// requestTSType renders the type of a request body, preferring Create variants
+func requestTSType(s ir.IRSchema, opts typeOptions) string {
+	return variantTSType(s, opts, opts.Variants.Create, "Create")
+}
+
+// responseTSType renders the type of a response body, preferring Read variants
+func responseTSType(s ir.IRSchema, opts typeOptions) string {
+	return variantTSType(s, opts, opts.Variants.Read, "Read")
+}
+
+// operationResponseTSType is the type an operation resolves to: the response model, the
+// events of event streams, the unread body stream for other streamed responses, void when the response has no content, or the
+// type selected by the accept type parameter when the response is negotiated
+func operationResponseTSType(op ir.IROperation, opts typeOptions) string {
+	if op.Response.Negotiated() {
+		return acceptResponseTSType(op, opts)
+	}
+	if op.Response.EventStream() {
+		return eventStreamTSType(op.Response.Schema, opts)
+	}
+	if op.Response.Stream {
+		return "ReadableStream<Uint8Array>"
+	}
+	if op.Response.TypeTS == "void" {
+		return "void"
+	}
+	return responseTSType(op.Response.Schema, opts)
+}
+
+// acceptResponseTSType renders the result of an operation whose response is negotiated as a
+// map from media type to type indexed by the method's accept type parameter A, so the result
+// narrows to the media type requested
+func acceptResponseTSType(op ir.IROperation, opts typeOptions) string {
+	parts := make([]string, 0, len(op.Response.Contents))
+	for _, content := range op.Response.Contents {
+		t := responseTSType(content.Schema, opts)
+		if content.EventStream() {
+			t = eventStreamTSType(content.Schema, opts)
+		} else if content.Stream {
+			t = "ReadableStream<Uint8Array>"
+		}
+		parts = append(parts, fmt.Sprintf("%q: %s", content.ContentType, t))
+	}
+	return "{ " + strings.Join(parts, "; ") + " }[A]"
+}
+
+// acceptTypeParam declares the accept type parameter A of a method whose response is
+// negotiated, constrained to the response media types and defaulting to the preferred one.
+// It is empty for other operations.
+func acceptTypeParam(op ir.IROperation) string {
+	if !op.Response.Negotiated() {
+		return ""
+	}
+	types := make([]string, 0, len(op.Response.Contents))
+	for _, content := range op.Response.Contents {
+		types = append(types, strconv.Quote(content.ContentType))
+	}
+	return fmt.Sprintf("A extends %s = %s", strings.Join(types, " | "), types[0])
+}
+
+// acceptStream is the stream option of a method whose response is negotiated: an expression
+// checking whether the requested media type is streamed, or empty when none is. Event streams
+// are read by the events option instead.
+func acceptStream(op ir.IROperation) string {
+	var streamed []string
+	for _, content := range op.Response.Contents {
+		if content.Stream && !content.EventStream() {
+			streamed = append(streamed, strconv.Quote(content.ContentType))
+		}
+	}
+	if len(streamed) == 0 {
+		return ""
+	}
+	return fmt.Sprintf("[%s].includes(init?.accept ?? %q)", strings.Join(streamed, ", "), op.Response.ContentType)
+}
+
+// acceptEvents is the events option of a method whose response is negotiated: an expression
+// choosing how event data is decoded when the requested media type is an event stream, or
+// empty when none is
+func acceptEvents(op ir.IROperation) string {
+	for _, content := range op.Response.Contents {
+		if content.EventStream() {
+			return fmt.Sprintf("(init?.accept ?? %q) === %q ? %q : undefined", op.Response.ContentType, content.ContentType, eventDecoding(content.Schema))
+		}
+	}
+	return ""
+}
+
+// eventDecoding is how the data of server-sent events with the given schema is decoded:
+// "text" for string or undeclared data, else "json"
+func eventDecoding(s ir.IRSchema) string {
+	if s.Kind == ir.IRKindString || s.Kind == ir.IRKindUnknown {
+		return "text"
+	}
+	return "json"
+}
+
+// eventStreamTSType is what a text/event-stream response resolves to: an async iterable of its
+// events, typed by the schema of their data
+func eventStreamTSType(s ir.IRSchema, opts typeOptions) string {
+	data := "string"
+	if eventDecoding(s) == "json" {
+		data = responseTSType(s, opts)
+	}
+	return fmt.Sprintf("AsyncIterable<ServerSentEvent<%s>>", data)
+}
+
+// usesEventStreams reports whether a method of the service resolves to server-sent events, so
+// its file imports ServerSentEvent
+func usesEventStreams(service ir.IRService) bool {
+	for _, op := range service.Operations {
+		for _, variant := range op.AcceptVariants() {
+			if variant.Response.EventStream() {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// omitKeys renders field names as a TypeScript union of string literals for Omit<>
+func omitKeys(fields []string) string {
+	quoted := make([]string, 0, len(fields))
+	for _, f := range fields {
+		quoted = append(quoted, fmt.Sprintf("%q", f))
+	}
+	return strings.Join(quoted, " | ")
+}
+
+// newTypeOptions derives type mapping options from the client configuration
+func newTypeOptions(client config.Client) typeOptions {
+	return typeOptions{DateAsNativeType: client.DateAsNativeType}
+}
+
 // schemaToTSType converts an IR schema to TypeScript type string
-func schemaToTSType(s ir.IRSchema) string {
+func schemaToTSType(s ir.IRSchema, opts typeOptions) string {
+	// x-ts-type replaces the inferred type
+	if override := s.TypeOverrides["ts"]; override != "" {
+		if s.Nullable {
+			return override + " | null"
+		}
+		return override
+	}
 	// Base type string without nullability; append null later
 	var t string
 	switch s.Kind {
 	case "string":
 		if s.Format == "binary" {
 			t = "Blob"
+		} else if s.Format == "byte" {
+			// base64 stays a string on the wire; see decodeBase64/encodeBase64
+			t = "string"
+		} else if opts.DateAsNativeType && (s.Format == "date" || s.Format == "date-time") {
+			t = "Date"
 		} else {
 			t = "string"
 		}
@@ -35,67 +279,52 @@ func schemaToTSType(s ir.IRSchema) string {
 		}
 	case "array":
 		if s.Items != nil {
-			inner := schemaToTSType(*s.Items)
-			// Wrap unions/intersections in parentheses inside Array<>
-			if strings.Contains(inner, " | ") || strings.Contains(inner, " & ") {
-				inner = "(" + inner + ")"
-			}
-			t = "Array<" + inner + ">"
+			// Unions need no parentheses inside Array<>, so nullable items render as
+			// Array<T | null> while a nullable array renders as Array<T> | null
+			t = "Array<" + schemaToTSType(*s.Items, opts) + ">"
 		} else {
 			t = "Array<unknown>"
 		}
 	case "oneOf":
 		parts := make([]string, 0, len(s.OneOf))
 		for _, sub := range s.OneOf {
-			parts = append(parts, schemaToTSType(*sub))
+			parts = append(parts, schemaToTSType(*sub, opts))
 		}
 		t = strings.Join(parts, " | ")
 	case "anyOf":
 		parts := make([]string, 0, len(s.AnyOf))
 		for _, sub := range s.AnyOf {
-			parts = append(parts, schemaToTSType(*sub))
+			parts = append(parts, schemaToTSType(*sub, opts))
 		}
 		t = strings.Join(parts, " | ")
 	case "allOf":
 		parts := make([]string, 0, len(s.AllOf))
 		for _, sub := range s.AllOf {
-			parts = append(parts, schemaToTSType(*sub))
+			// & binds tighter than |, so unions (nullable members included) need parentheses
+			part := schemaToTSType(*sub, opts)
+			if isTopLevelUnion(part) {
+				part = "(" + part + ")"
+			}
+			parts = append(parts, part)
 		}
 		t = strings.Join(parts, " & ")
 	case "enum":
 		// Prefer using name via Ref in properties; for safety, inline a union here
-		if len(s.EnumValues) > 0 {
-			vals := make([]string, 0, len(s.EnumValues))
-			switch s.EnumBase {
-			case "number", "integer":
-				for _, v := range s.EnumValues {
-					vals = append(vals, v)
-				}
-			case "boolean":
-				for _, v := range s.EnumValues {
-					if v == "true" || v == "false" {
-						vals = append(vals, v)
-					} else {
-						vals = append(vals, "\""+v+"\"")
-					}
-				}
-			default:
-				for _, v := range s.EnumValues {
-					vals = append(vals, "\""+v+"\"")
-				}
-			}
+		if vals := enumTSLiterals(s); len(vals) > 0 {
 			t = strings.Join(vals, " | ")
 		} else {
 			t = "unknown"
 		}
 	case "object":
-		if len(s.Properties) == 0 {
+		if isMapSchema(s) {
+			t = "Record<string, " + schemaToTSType(*s.AdditionalProperties, opts) + ">"
+		} else if len(s.Properties) == 0 {
 			t = "Record<string, unknown>"
 		} else {
 			// Inline object shape for rare cases; nested ones should be refs
 			parts := make([]string, 0, len(s.Properties))
 			for _, f := range s.Properties {
-				ft := schemaToTSType(*f.Type)
+				ft := schemaToTSType(*f.Type, opts)
 				if f.Required {
 					parts = append(parts, f.Name+": "+ft)
 				} else {
@@ -107,12 +336,35 @@ func schemaToTSType(s ir.IRSchema) string {
 	default:
 		t = "unknown"
 	}
-	if s.Nullable && t != "null" {
+	if opts.nullable(s) && t != "null" {
 		t += " | null"
 	}
 	return t
 }
 
+// isTopLevelUnion reports whether the TypeScript type t is a union outside of any brackets
+func isTopLevelUnion(t string) bool {
+	depth := 0
+	for i := 0; i < len(t); i++ {
+		switch t[i] {
+		case '<', '(', '{', '[':
+			depth++
+		case '>':
+			// The arrow of a function type from x-ts-type closes nothing
+			if i == 0 || t[i-1] != '=' {
+				depth--
+			}
+		case ')', '}', ']':
+			depth--
+		case '|':
+			if depth == 0 {
+				return true
+			}
+		}
+	}
+	return false
+}
+
 // deriveMethodName creates method names using basic REST-style heuristics
 func deriveMethodName(op ir.IROperation) string {
 	// Basic REST-style heuristics
@@ -186,7 +438,14 @@ var toCamelCase = utils.ToCamelCase
 var toSnakeCase = utils.ToSnakeCase
 var toKebabCase = utils.ToKebabCase
 
-// buildPathTemplate converts OpenAPI path to TypeScript template literal
+// serviceFileBase returns the name of the services/ module of tag, without extension: snake
+// case unless client.FileNaming picks another style
+func serviceFileBase(client config.Client, tag string) string {
+	return client.FileName(strings.ToLower(toSnakeCase(tag)), tag)
+}
+
+// buildPathTemplate converts OpenAPI path to TypeScript template literal; array parameters
+// are joined with the separator of their style
 func buildPathTemplate(op ir.IROperation) string {
 	// Convert /foo/{id}/bar/{slug} -> `/foo/${path.id}/bar/${path.slug}`
 	path := op.Path
@@ -202,9 +461,15 @@ func buildPathTemplate(op ir.IROperation) string {
 			}
 			if j < len(path) {
 				name := path[i+1 : j]
-				b.WriteString("${encodeURIComponent(")
-				b.WriteString(name)
-				b.WriteString(")}")
+				b.WriteString(op.PathPrefix(name))
+				if p, ok := op.PathParam(name); ok && p.Schema.Kind == ir.IRKindArray {
+					// Each value is encoded on its own, the separator is not
+					fmt.Fprintf(&b, "${%s.map((v) => encodeURIComponent(String(v))).join(%s)}", name, strconv.Quote(p.PathSeparator()))
+				} else {
+					b.WriteString("${encodeURIComponent(")
+					b.WriteString(name)
+					b.WriteString(")}")
+				}
 				i = j
 				continue
 			}
@@ -262,18 +527,22 @@ func orderPathParams(op ir.IROperation) []ir.IRParam {
 	return ordered
 }
 
+// queryTypeName names the interface schema.ts declares for an operation's query parameters
+func queryTypeName(op ir.IROperation, methodName string) string {
+	return toPascalCase(op.Tag) + toPascalCase(methodName) + "Query"
+}
+
 // buildMethodSignature constructs the TS parameter list, using the provided methodName for query type name
-func buildMethodSignature(op ir.IROperation, methodName string) []string {
+func buildMethodSignature(op ir.IROperation, methodName string, opts typeOptions) []string {
 	parts := []string{}
 	// path params as positional args
 	for _, p := range orderPathParams(op) {
-		parts = append(parts, fmt.Sprintf("%s: %s", p.Name, schemaToTSType(p.Schema)))
+		parts = append(parts, fmt.Sprintf("%s: %s", p.Name, schemaToTSType(p.Schema, opts)))
 	}
 	// query object
 	if len(op.QueryParams) > 0 {
 		// Reference named interface defined in schema.ts
-		queryType := toPascalCase(op.Tag) + toPascalCase(methodName) + "Query"
-		parts = append(parts, fmt.Sprintf("query?: Schema.%s", queryType))
+		parts = append(parts, fmt.Sprintf("query?: Schema.%s", queryTypeName(op, methodName)))
 	}
 	// body
 	if op.RequestBody != nil {
@@ -281,14 +550,231 @@ func buildMethodSignature(op ir.IROperation, methodName string) []string {
 		if !op.RequestBody.Required {
 			opt = "?"
 		}
-		parts = append(parts, fmt.Sprintf("body%s: %s", opt, schemaToTSType(op.RequestBody.Schema)))
+		bodyType := requestTSType(op.RequestBody.Schema, opts)
+		if acceptsContents(op) {
+			bodyType = requestContentTSType(op, opts)
+		}
+		parts = append(parts, fmt.Sprintf("body%s: %s", opt, bodyType))
+	}
+	// init, with the media type to accept when the response is negotiated
+	init := "init?: Omit<RequestInit, \"method\" | \"body\">"
+	if op.Response.Negotiated() {
+		init += " & { accept?: A }"
 	}
-	// init
-	parts = append(parts, "init?: Omit<RequestInit, \"method\" | \"body\">")
+	parts = append(parts, init)
 
 	return parts
 }
 
+// optionsTypeName names the per-operation options interface used with singleOptionsArg
+func optionsTypeName(op ir.IROperation, methodName string) string {
+	return toPascalCase(op.Tag) + toPascalCase(methodName) + "Options"
+}
+
+// buildOptionsMembers lists the members of an operation's options interface: the path params
+// grouped under pathParams, then query, body and init typed like the positional arguments
+func buildOptionsMembers(op ir.IROperation, methodName string, opts typeOptions) []string {
+	params := orderPathParams(op)
+	parts := []string{}
+	if len(params) > 0 {
+		fields := make([]string, 0, len(params))
+		for _, p := range params {
+			fields = append(fields, fmt.Sprintf("%s: %s", p.Name, schemaToTSType(p.Schema, opts)))
+		}
+		parts = append(parts, "pathParams: { "+strings.Join(fields, "; ")+" }")
+	}
+	return append(parts, buildMethodSignature(op, methodName, opts)[len(params):]...)
+}
+
+// buildOptionsSignature is the singleOptionsArg alternative to buildMethodSignature: a single
+// options argument, defaulting to {} when none of its members is required. Without init the
+// signature is the one of the __queryKeys helper, empty when it has nothing to key on.
+func buildOptionsSignature(op ir.IROperation, methodName string, withInit bool) []string {
+	optionsType := optionsTypeName(op, methodName)
+	if withInit && op.Response.Negotiated() {
+		optionsType += "<A>"
+	}
+	if !withInit {
+		if len(queryKeyArgs(op)) == 0 {
+			return []string{}
+		}
+		optionsType = fmt.Sprintf("Omit<%s, \"init\">", optionsType)
+	}
+	if len(op.PathParams) == 0 && (op.RequestBody == nil || !op.RequestBody.Required) {
+		return []string{fmt.Sprintf("options: %s = {}", optionsType)}
+	}
+	return []string{"options: " + optionsType}
+}
+
+// buildOptionsDestructure unpacks the options argument into the variables the positional
+// signature declares, so both signatures share the method body
+func buildOptionsDestructure(op ir.IROperation, withInit bool) string {
+	names := []string{}
+	if params := queryKeyArgs(op); len(params) > 0 {
+		pathParams := params[:len(orderPathParams(op))]
+		if len(pathParams) > 0 {
+			names = append(names, "pathParams: { "+strings.Join(pathParams, ", ")+" }")
+		}
+		names = append(names, params[len(pathParams):]...)
+	}
+	if withInit {
+		names = append(names, "init")
+	}
+	if len(names) == 0 {
+		return ""
+	}
+	return "const { " + strings.Join(names, ", ") + " } = options;"
+}
+
+// acceptsContents reports whether the request body of op can be sent as more than one media type
+func acceptsContents(op ir.IROperation) bool {
+	return len(op.RequestContents) > 1
+}
+
+// requestContentTSType renders the body of an operation accepting several media types as a
+// union of RequestContent variants discriminated by contentType
+func requestContentTSType(op ir.IROperation, opts typeOptions) string {
+	parts := make([]string, 0, len(op.RequestContents))
+	for _, body := range op.RequestContents {
+		t := requestTSType(body.Schema, opts)
+		if body.ContentType == "multipart/form-data" {
+			t = "FormData"
+		}
+		parts = append(parts, fmt.Sprintf("RequestContent<%q, %s>", body.ContentType, t))
+	}
+	return strings.Join(parts, " | ")
+}
+
+// serviceUtilsImports lists the helpers a service imports from utils.ts, comma separated.
+// bodyDefaults returns the body defaults an operation applies, as in buildBodyDefaults.
+func serviceUtilsImports(service ir.IRService, bodyDefaults func(ir.IROperation) string) string {
+	var form, contents, defaults bool
+	for _, op := range service.Operations {
+		if acceptsContents(op) {
+			contents = true
+		} else if op.RequestBody.IsFormURLEncoded() {
+			form = true
+		}
+		if bodyDefaults(op) != "" {
+			defaults = true
+		}
+	}
+	var names []string
+	if defaults {
+		names = append(names, "applyDefaults")
+	}
+	if form {
+		names = append(names, "toFormUrlEncoded")
+	}
+	if contents {
+		names = append(names, "encodeRequestContent", "RequestContent")
+	}
+	return strings.Join(names, ", ")
+}
+
+// buildBodyDefaults returns the object entries (e.g. "age: 18") for the documented defaults
+// of the top-level fields of an operation's JSON or form request body, applied to the fields
+// the caller leaves undefined. Returns an empty string when no field has a default.
+func buildBodyDefaults(op ir.IROperation, defs map[string]ir.IRModelDef) string {
+	if acceptsContents(op) || op.RequestBody == nil {
+		return ""
+	}
+	if op.RequestBody.ContentType != "application/json" && !op.RequestBody.IsFormURLEncoded() {
+		return ""
+	}
+	var parts []string
+	for _, f := range objectFields(defs, op.RequestBody.Schema, map[string]bool{}) {
+		if lit := tsLiteral(f.Annotations.Default); lit != "" {
+			parts = append(parts, fmt.Sprintf("%s: %s", quoteTSPropertyName(f.Name), lit))
+		}
+	}
+	return strings.Join(parts, ", ")
+}
+
+// objectFields returns the properties of an object schema, following refs and merging allOf
+// members; later members win
+func objectFields(defs map[string]ir.IRModelDef, s ir.IRSchema, seen map[string]bool) []ir.IRField {
+	switch s.Kind {
+	case ir.IRKindObject:
+		return s.Properties
+	case ir.IRKindRef:
+		md, ok := defs[s.Ref]
+		if !ok || seen[s.Ref] {
+			return nil
+		}
+		seen[s.Ref] = true
+		return objectFields(defs, md.Schema, seen)
+	case ir.IRKindAllOf:
+		var fields []ir.IRField
+		for _, part := range s.AllOf {
+			if part == nil {
+				continue
+			}
+			for _, f := range objectFields(defs, *part, seen) {
+				replaced := false
+				for i := range fields {
+					if fields[i].Name == f.Name {
+						fields[i], replaced = f, true
+					}
+				}
+				if !replaced {
+					fields = append(fields, f)
+				}
+			}
+		}
+		return fields
+	}
+	return nil
+}
+
+// operationKey returns the key used for an operation in generated metadata: its operationId,
+// or "METHOD /path" when the operation has none
+func operationKey(op ir.IROperation) string {
+	if op.OperationID != "" {
+		return op.OperationID
+	}
+	return op.Method + " " + op.Path
+}
+
+// headerKind maps a header schema to the primitive kind understood by readHeader
+func headerKind(s ir.IRSchema) string {
+	switch s.Kind {
+	case ir.IRKindNumber, ir.IRKindInteger:
+		return "number"
+	case ir.IRKindBoolean:
+		return "boolean"
+	}
+	return "string"
+}
+
+// responseHeadersType renders the TS object type for an operation's declared response headers;
+// headers the spec marks required are not optional
+func responseHeadersType(op ir.IROperation) string {
+	parts := make([]string, 0, len(op.Response.Headers))
+	for _, h := range op.Response.Headers {
+		if h.Required {
+			parts = append(parts, fmt.Sprintf("%q: %s", h.Name, headerKind(h.Schema)))
+		} else {
+			parts = append(parts, fmt.Sprintf("%q?: %s", h.Name, headerKind(h.Schema)))
+		}
+	}
+	return "{ " + strings.Join(parts, "; ") + " }"
+}
+
+// responseHeadersValue renders the TS object literal reading declared headers from res.headers,
+// asserting that required headers are present
+func responseHeadersValue(op ir.IROperation) string {
+	parts := make([]string, 0, len(op.Response.Headers))
+	for _, h := range op.Response.Headers {
+		read := fmt.Sprintf("readHeader(res.headers, %q, %q)", h.Name, headerKind(h.Schema))
+		if h.Required {
+			read += "!"
+		}
+		parts = append(parts, fmt.Sprintf("%q: %s", h.Name, read))
+	}
+	return "{ " + strings.Join(parts, ", ") + " }"
+}
+
 // queryKeyArgs returns the parameter names (no types) in the same order as the method parameters,
 // excluding the trailing init parameter. Includes:
 // - path params in path order
@@ -307,3 +793,120 @@ func queryKeyArgs(op ir.IROperation) []string {
 	}
 	return out
 }
+
+// tsLiteral renders a schema default as a TypeScript literal, or "" when there is none
+func tsLiteral(v any) string {
+	if v == nil {
+		return ""
+	}
+	b, err := json.Marshal(v)
+	if err != nil {
+		return ""
+	}
+	return string(b)
+}
+
+// buildQueryDefaults returns the object entries (e.g. "limit: 20") for query parameters
+// that declare a default, so the service can merge them under the caller's query.
+// Returns an empty string when no query parameter has a default.
+func buildQueryDefaults(op ir.IROperation) string {
+	parts := []string{}
+	for _, p := range op.QueryParams {
+		if lit := tsLiteral(p.Default); lit != "" {
+			parts = append(parts, fmt.Sprintf("%s: %s", quoteTSPropertyName(p.Name), lit))
+		}
+	}
+	return strings.Join(parts, ", ")
+}
+
+// buildJSONQueryParams returns the object entries (e.g. "filter: JSON.stringify(query?.filter)")
+// that replace query parameters declared with JSON content by their serialized value.
+// Returns an empty string when the operation has none.
+func buildJSONQueryParams(op ir.IROperation) string {
+	parts := []string{}
+	for _, p := range op.QueryParams {
+		if !p.IsJSON() {
+			continue
+		}
+		key := quoteTSPropertyName(p.Name)
+		access := "query?." + key
+		if key != p.Name {
+			access = "query?.[" + key + "]"
+		}
+		parts = append(parts, fmt.Sprintf("%s: %s === undefined ? undefined : JSON.stringify(%s)", key, access, access))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// enumTSKeys returns the key of each enum value in the enum's const object. String enums
+// are keyed by their values; numeric enums use their x-enum-varnames names, made valid and
+// unique identifiers, so consumers can write Status.Active. Numeric enums without names are
+// keyed by their quoted values.
+func enumTSKeys(s ir.IRSchema) []string {
+	literals := s.EnumLiterals()
+	if (s.EnumBase == ir.IRKindNumber || s.EnumBase == ir.IRKindInteger) && len(s.EnumNames) == len(literals) {
+		return utils.EnumMemberNames(s.EnumNames, utils.EnumNameIdentifier)
+	}
+	keys := make([]string, 0, len(literals))
+	for _, v := range literals {
+		keys = append(keys, "\""+v+"\"")
+	}
+	return keys
+}
+
+// enumTSLiterals returns the TypeScript literal for each enum value. Numeric and boolean
+// enums are rendered from the raw values so numbers stay exact (1000000 rather than 1e+06).
+func enumTSLiterals(s ir.IRSchema) []string {
+	vals := make([]string, 0, len(s.EnumValues))
+	switch s.EnumBase {
+	case "number", "integer":
+		vals = append(vals, s.EnumLiterals()...)
+	case "boolean":
+		for _, v := range s.EnumLiterals() {
+			if v == "true" || v == "false" {
+				vals = append(vals, v)
+			} else {
+				vals = append(vals, "\""+v+"\"")
+			}
+		}
+	default:
+		for _, v := range s.EnumValues {
+			vals = append(vals, "\""+v+"\"")
+		}
+	}
+	return vals
+}
+
+// apiKeyAlias returns the apiKey scheme the generic apiKey and apiKeyName client options set:
+// the first one sent in a header or the query. ok is false when there is none, or when a
+// scheme's own options already use those names (a scheme named apiKey has them anyway).
+func apiKeyAlias(schemes []ir.IRSecurityScheme) (scheme ir.IRSecurityScheme, ok bool) {
+	for _, s := range schemes {
+		if name := toCamelCase(s.Key); name == "apiKey" || name == "apiKeyName" {
+			return ir.IRSecurityScheme{}, false
+		}
+	}
+	for _, s := range schemes {
+		if s.Type == "apiKey" && (s.In == "header" || s.In == "query") {
+			return s, true
+		}
+	}
+	return ir.IRSecurityScheme{}, false
+}
+
+// sensitiveFieldNames returns the sorted wire names of the properties anywhere in the IR that
+// are sensitive (format: password or writeOnly), the keys redactSensitive masks
+func sensitiveFieldNames(in ir.IR) []string {
+	seen := map[string]bool{}
+	var names []string
+	in.WalkSchemas(func(s ir.IRSchema) {
+		for _, f := range s.Properties {
+			if f.Sensitive() && !seen[f.Name] {
+				seen[f.Name] = true
+				names = append(names, f.Name)
+			}
+		}
+	})
+	sort.Strings(names)
+	return names
+}
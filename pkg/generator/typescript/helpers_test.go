@@ -0,0 +1,644 @@
+package typescript
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/blimu-dev/sdk-gen/pkg/config"
+	"github.com/blimu-dev/sdk-gen/pkg/ir"
+)
+
+func TestModelVariants(t *testing.T) {
+	str := &ir.IRSchema{Kind: ir.IRKindString}
+	defs := []ir.IRModelDef{
+		{Name: "User", Schema: ir.IRSchema{Kind: ir.IRKindObject, Properties: []ir.IRField{
+			{Name: "createdAt", Type: str, Annotations: ir.IRAnnotations{ReadOnly: true}},
+			{Name: "id", Type: str, Required: true, Annotations: ir.IRAnnotations{ReadOnly: true}},
+			{Name: "name", Type: str},
+			{Name: "password", Type: str, Annotations: ir.IRAnnotations{WriteOnly: true}},
+		}}},
+		{Name: "Plain", Schema: ir.IRSchema{Kind: ir.IRKindObject, Properties: []ir.IRField{{Name: "name", Type: str}}}},
+	}
+
+	opts := typeOptions{Variants: collectModelVariants(defs)}
+	if got := omitKeys(opts.Variants.Create["User"]); got != `"createdAt" | "id"` {
+		t.Errorf("create omit = %s", got)
+	}
+	if got := omitKeys(opts.Variants.Read["User"]); got != `"password"` {
+		t.Errorf("read omit = %s", got)
+	}
+
+	tests := []struct {
+		schema   ir.IRSchema
+		request  string
+		response string
+	}{
+		{ir.IRSchema{Kind: ir.IRKindRef, Ref: "User"}, "Schema.UserCreate", "Schema.UserRead"},
+		{ir.IRSchema{Kind: ir.IRKindArray, Items: &ir.IRSchema{Kind: ir.IRKindRef, Ref: "User"}}, "Array<Schema.UserCreate>", "Array<Schema.UserRead>"},
+		{ir.IRSchema{Kind: ir.IRKindRef, Ref: "Plain"}, "Schema.Plain", "Schema.Plain"},
+	}
+	for _, test := range tests {
+		if got := requestTSType(test.schema, opts); got != test.request {
+			t.Errorf("requestTSType(%+v) = %q, expected %q", test.schema, got, test.request)
+		}
+		if got := responseTSType(test.schema, opts); got != test.response {
+			t.Errorf("responseTSType(%+v) = %q, expected %q", test.schema, got, test.response)
+		}
+	}
+}
+
+func TestEnumTSLiterals(t *testing.T) {
+	tests := []struct {
+		name     string
+		schema   ir.IRSchema
+		expected string
+	}{
+		{
+			name:     "mixed numeric",
+			schema:   ir.IRSchema{Kind: ir.IRKindEnum, EnumBase: ir.IRKindNumber, EnumValues: []string{"1.5", "1e+06"}, EnumRaw: []any{1.5, float64(1000000)}},
+			expected: "1.5 | 1000000",
+		},
+		{
+			name:     "integer",
+			schema:   ir.IRSchema{Kind: ir.IRKindEnum, EnumBase: ir.IRKindInteger, EnumValues: []string{"1", "1e+06"}, EnumRaw: []any{float64(1), float64(1000000)}},
+			expected: "1 | 1000000",
+		},
+		{
+			name:     "boolean",
+			schema:   ir.IRSchema{Kind: ir.IRKindEnum, EnumBase: ir.IRKindBoolean, EnumValues: []string{"true", "false"}, EnumRaw: []any{true, false}},
+			expected: "true | false",
+		},
+		{
+			name:     "numeric-looking strings stay quoted",
+			schema:   ir.IRSchema{Kind: ir.IRKindEnum, EnumBase: ir.IRKindString, EnumValues: []string{"1", "2"}, EnumRaw: []any{"1", "2"}},
+			expected: `"1" | "2"`,
+		},
+	}
+
+	for _, test := range tests {
+		if result := schemaToTSType(test.schema, typeOptions{}); result != test.expected {
+			t.Errorf("%s: schemaToTSType() = %q, expected %q", test.name, result, test.expected)
+		}
+	}
+}
+
+func TestEnumTSKeys(t *testing.T) {
+	status := []any{float64(1), float64(2), float64(3)}
+	tests := []struct {
+		name     string
+		schema   ir.IRSchema
+		expected string
+	}{
+		{
+			name:     "numeric with names",
+			schema:   ir.IRSchema{Kind: ir.IRKindEnum, EnumBase: ir.IRKindInteger, EnumValues: []string{"1", "2", "3"}, EnumRaw: status, EnumNames: []string{"Active", "in-review", "2fa"}},
+			expected: "Active, in_review, _2fa",
+		},
+		{
+			name:     "numeric without names",
+			schema:   ir.IRSchema{Kind: ir.IRKindEnum, EnumBase: ir.IRKindInteger, EnumValues: []string{"1", "2", "3"}, EnumRaw: status},
+			expected: `"1", "2", "3"`,
+		},
+		{
+			name:     "names colliding once sanitized",
+			schema:   ir.IRSchema{Kind: ir.IRKindEnum, EnumBase: ir.IRKindInteger, EnumValues: []string{"1", "2", "3"}, EnumRaw: status, EnumNames: []string{"a-b", "a_b", "c"}},
+			expected: "a_b, a_b_2, c",
+		},
+		{
+			name:     "string enums keep their values",
+			schema:   ir.IRSchema{Kind: ir.IRKindEnum, EnumBase: ir.IRKindString, EnumValues: []string{"active", "banned"}, EnumNames: []string{"Active", "Banned"}},
+			expected: `"active", "banned"`,
+		},
+	}
+
+	for _, test := range tests {
+		if got := strings.Join(enumTSKeys(test.schema), ", "); got != test.expected {
+			t.Errorf("%s: enumTSKeys() = %s, expected %s", test.name, got, test.expected)
+		}
+	}
+	// Values stay unquoted numbers
+	if got := strings.Join(enumTSLiterals(tests[0].schema), ", "); got != "1, 2, 3" {
+		t.Errorf("enumTSLiterals() = %s", got)
+	}
+}
+
+func TestNullableRefTSType(t *testing.T) {
+	s := ir.IRSchema{Kind: ir.IRKindRef, Ref: "User", Nullable: true}
+	if got := schemaToTSType(s, typeOptions{}); got != "Schema.User | null" {
+		t.Errorf("schemaToTSType() = %q, expected %q", got, "Schema.User | null")
+	}
+}
+
+func TestNullableArrayTSType(t *testing.T) {
+	array := func(items ir.IRSchema, nullable bool) ir.IRSchema {
+		return ir.IRSchema{Kind: ir.IRKindArray, Items: &items, Nullable: nullable}
+	}
+	str := ir.IRSchema{Kind: ir.IRKindString}
+	nullableStr := ir.IRSchema{Kind: ir.IRKindString, Nullable: true}
+
+	tests := []struct {
+		name     string
+		schema   ir.IRSchema
+		expected string
+	}{
+		{"nullable array", array(str, true), "Array<string> | null"},
+		{"nullable items", array(nullableStr, false), "Array<string | null>"},
+		{"both", array(nullableStr, true), "Array<string | null> | null"},
+		{"nullable ref items", array(ir.IRSchema{Kind: ir.IRKindRef, Ref: "User", Nullable: true}, false), "Array<Schema.User | null>"},
+		{"nested", array(array(nullableStr, true), false), "Array<Array<string | null> | null>"},
+	}
+
+	for _, test := range tests {
+		if got := schemaToTSType(test.schema, typeOptions{}); got != test.expected {
+			t.Errorf("%s: schemaToTSType() = %q, expected %q", test.name, got, test.expected)
+		}
+	}
+}
+
+func TestNullableMapAndRefTSType(t *testing.T) {
+	ref := func(name string, nullable bool) *ir.IRSchema {
+		return &ir.IRSchema{Kind: ir.IRKindRef, Ref: name, Nullable: nullable}
+	}
+	opts := typeOptions{NullableModels: map[string]bool{"Owner": true}}
+
+	tests := []struct {
+		name     string
+		schema   ir.IRSchema
+		expected string
+	}{
+		{"map", ir.IRSchema{Kind: ir.IRKindObject, AdditionalProperties: &ir.IRSchema{Kind: ir.IRKindInteger}}, "Record<string, number>"},
+		{"nullable map values", ir.IRSchema{Kind: ir.IRKindObject, AdditionalProperties: ref("User", true), Nullable: true}, "Record<string, Schema.User | null> | null"},
+		{"ref to nullable model", *ref("Owner", false), "Schema.Owner | null"},
+		{"map of nullable models", ir.IRSchema{Kind: ir.IRKindObject, AdditionalProperties: ref("Owner", false)}, "Record<string, Schema.Owner | null>"},
+		{"intersection", ir.IRSchema{Kind: ir.IRKindAllOf, AllOf: []*ir.IRSchema{ref("User", true), ref("Audited", false)}}, "(Schema.User | null) & Schema.Audited"},
+		{"intersection of a generic union", ir.IRSchema{Kind: ir.IRKindAllOf, AllOf: []*ir.IRSchema{
+			{Kind: ir.IRKindArray, Items: ref("User", true)}, ref("Audited", false),
+		}}, "Array<Schema.User | null> & Schema.Audited"},
+	}
+
+	for _, test := range tests {
+		if got := schemaToTSType(test.schema, opts); got != test.expected {
+			t.Errorf("%s: schemaToTSType() = %q, expected %q", test.name, got, test.expected)
+		}
+	}
+}
+
+func TestTypeOverrideTSType(t *testing.T) {
+	s := ir.IRSchema{Kind: ir.IRKindRef, Ref: "Money", Nullable: true, TypeOverrides: map[string]string{"ts": "Brand<string, 'Money'>"}}
+	opts := typeOptions{Variants: modelVariants{Read: map[string][]string{"Money": {"id"}}}}
+	if got := responseTSType(s, opts); got != "Brand<string, 'Money'> | null" {
+		t.Errorf("responseTSType() = %q", got)
+	}
+}
+
+func TestByteFormatTSType(t *testing.T) {
+	tests := []struct {
+		format   string
+		expected string
+	}{
+		{"byte", "string"},
+		{"binary", "Blob"},
+	}
+
+	for _, test := range tests {
+		if got := schemaToTSType(ir.IRSchema{Kind: ir.IRKindString, Format: test.format}, typeOptions{}); got != test.expected {
+			t.Errorf("format %s: schemaToTSType() = %q, expected %q", test.format, got, test.expected)
+		}
+	}
+}
+
+func TestStreamResponseTSType(t *testing.T) {
+	op := ir.IROperation{Response: ir.IRResponse{Schema: ir.IRSchema{Kind: ir.IRKindString, Format: "binary"}}}
+	if got := operationResponseTSType(op, typeOptions{}); got != "Blob" {
+		t.Errorf("operationResponseTSType() = %q, expected %q", got, "Blob")
+	}
+	op.Response.Stream = true
+	if got := operationResponseTSType(op, typeOptions{}); got != "ReadableStream<Uint8Array>" {
+		t.Errorf("operationResponseTSType() with a streamed response = %q, expected %q", got, "ReadableStream<Uint8Array>")
+	}
+	empty := ir.IROperation{Response: ir.IRResponse{TypeTS: "void"}}
+	if got := operationResponseTSType(empty, typeOptions{}); got != "void" {
+		t.Errorf("operationResponseTSType() without content = %q, expected %q", got, "void")
+	}
+}
+
+func TestBuildPathTemplate(t *testing.T) {
+	op := ir.IROperation{Path: "/products/{id}/variants/{color}/sizes/{size}", PathParams: []ir.IRParam{
+		{Name: "color", Style: ir.PathStyleLabel},
+		{Name: "id", Style: ir.PathStyleMatrix},
+		{Name: "size"},
+	}}
+	expected := "`/products/;id=${encodeURIComponent(id)}/variants/.${encodeURIComponent(color)}/sizes/${encodeURIComponent(size)}`"
+	if got := buildPathTemplate(op); got != expected {
+		t.Errorf("buildPathTemplate() = %s, expected %s", got, expected)
+	}
+
+	list := ir.IRSchema{Kind: ir.IRKindArray, Items: &ir.IRSchema{Kind: ir.IRKindString}}
+	arrays := ir.IROperation{Path: "/items/{ids}/sizes/{sizes}", PathParams: []ir.IRParam{
+		{Name: "ids", Schema: list},
+		{Name: "sizes", Schema: list, Style: ir.PathStyleMatrix, Explode: true},
+	}}
+	expected = "`/items/${ids.map((v) => encodeURIComponent(String(v))).join(\",\")}/sizes/;sizes=${sizes.map((v) => encodeURIComponent(String(v))).join(\";sizes=\")}`"
+	if got := buildPathTemplate(arrays); got != expected {
+		t.Errorf("buildPathTemplate() with arrays = %s, expected %s", got, expected)
+	}
+}
+
+func TestRequestContents(t *testing.T) {
+	dog := ir.IRSchema{Kind: ir.IRKindRef, Ref: "Dog"}
+	upload := ir.IROperation{
+		RequestBody: &ir.IRRequestBody{ContentType: "application/json", Schema: dog},
+		RequestContents: []ir.IRRequestBody{
+			{ContentType: "application/json", Schema: dog},
+			{ContentType: "multipart/form-data", Schema: ir.IRSchema{Kind: ir.IRKindUnknown}},
+		},
+	}
+	search := ir.IROperation{
+		RequestBody:     &ir.IRRequestBody{ContentType: ir.ContentTypeFormURLEncoded, Schema: dog},
+		RequestContents: []ir.IRRequestBody{{ContentType: ir.ContentTypeFormURLEncoded, Schema: dog}},
+	}
+
+	expected := `RequestContent<"application/json", Schema.Dog> | RequestContent<"multipart/form-data", FormData>`
+	if got := requestContentTSType(upload, typeOptions{}); got != expected {
+		t.Errorf("requestContentTSType() = %s, expected %s", got, expected)
+	}
+
+	tests := []struct {
+		name     string
+		ops      []ir.IROperation
+		expected string
+	}{
+		{"none", []ir.IROperation{{}}, ""},
+		{"form body", []ir.IROperation{search}, "toFormUrlEncoded"},
+		{"several contents", []ir.IROperation{upload}, "encodeRequestContent, RequestContent"},
+		{"both", []ir.IROperation{search, upload}, "toFormUrlEncoded, encodeRequestContent, RequestContent"},
+	}
+	for _, test := range tests {
+		noDefaults := func(ir.IROperation) string { return "" }
+		if got := serviceUtilsImports(ir.IRService{Operations: test.ops}, noDefaults); got != test.expected {
+			t.Errorf("%s: serviceUtilsImports() = %q, expected %q", test.name, got, test.expected)
+		}
+	}
+}
+
+func TestResponseHeaders(t *testing.T) {
+	op := ir.IROperation{Response: ir.IRResponse{Headers: []ir.IRResponseHeader{
+		{Name: "X-RateLimit-Remaining", Schema: ir.IRSchema{Kind: ir.IRKindInteger}},
+		{Name: "X-Total-Count", Schema: ir.IRSchema{Kind: ir.IRKindInteger}, Required: true},
+	}}}
+
+	expected := `{ "X-RateLimit-Remaining"?: number; "X-Total-Count": number }`
+	if got := responseHeadersType(op); got != expected {
+		t.Errorf("responseHeadersType() = %s, expected %s", got, expected)
+	}
+	expected = `{ "X-RateLimit-Remaining": readHeader(res.headers, "X-RateLimit-Remaining", "number"), "X-Total-Count": readHeader(res.headers, "X-Total-Count", "number")! }`
+	if got := responseHeadersValue(op); got != expected {
+		t.Errorf("responseHeadersValue() = %s, expected %s", got, expected)
+	}
+}
+
+func TestBuildBodyDefaults(t *testing.T) {
+	str := ir.IRSchema{Kind: ir.IRKindString}
+	defs := map[string]ir.IRModelDef{
+		"Base": {Name: "Base", Schema: ir.IRSchema{Kind: ir.IRKindObject, Properties: []ir.IRField{
+			{Name: "role", Type: &str, Annotations: ir.IRAnnotations{Default: "member"}},
+		}}},
+		"NewUser": {Name: "NewUser", Schema: ir.IRSchema{Kind: ir.IRKindAllOf, AllOf: []*ir.IRSchema{
+			{Kind: ir.IRKindRef, Ref: "Base"},
+			{Kind: ir.IRKindObject, Properties: []ir.IRField{
+				{Name: "name", Type: &str, Required: true},
+				{Name: "max-age", Type: &ir.IRSchema{Kind: ir.IRKindInteger}, Annotations: ir.IRAnnotations{Default: 18}},
+			}},
+		}}},
+	}
+	newUser := ir.IRSchema{Kind: ir.IRKindRef, Ref: "NewUser"}
+
+	tests := []struct {
+		name     string
+		body     *ir.IRRequestBody
+		expected string
+	}{
+		{"no body", nil, ""},
+		{"json", &ir.IRRequestBody{ContentType: "application/json", Schema: newUser}, `role: "member", "max-age": 18`},
+		{"form", &ir.IRRequestBody{ContentType: ir.ContentTypeFormURLEncoded, Schema: newUser}, `role: "member", "max-age": 18`},
+		{"multipart", &ir.IRRequestBody{ContentType: "multipart/form-data", Schema: newUser}, ""},
+		{"no defaults", &ir.IRRequestBody{ContentType: "application/json", Schema: str}, ""},
+	}
+	for _, test := range tests {
+		if got := buildBodyDefaults(ir.IROperation{RequestBody: test.body}, defs); got != test.expected {
+			t.Errorf("%s: buildBodyDefaults() = %s, expected %s", test.name, got, test.expected)
+		}
+	}
+
+	op := ir.IROperation{RequestBody: &ir.IRRequestBody{ContentType: "application/json", Schema: newUser}}
+	bodyDefaults := func(op ir.IROperation) string { return buildBodyDefaults(op, defs) }
+	if got := serviceUtilsImports(ir.IRService{Operations: []ir.IROperation{op}}, bodyDefaults); got != "applyDefaults" {
+		t.Errorf("serviceUtilsImports() with body defaults = %q, expected %q", got, "applyDefaults")
+	}
+}
+
+func TestBuildJSONQueryParams(t *testing.T) {
+	str := ir.IRSchema{Kind: ir.IRKindString}
+	filter := ir.IRSchema{Kind: ir.IRKindRef, Ref: "Filter"}
+	op := ir.IROperation{QueryParams: []ir.IRParam{
+		{Name: "filter", Schema: filter, ContentType: "application/json"},
+		{Name: "limit", Schema: str},
+		{Name: "sort-by", Schema: filter, ContentType: "application/json"},
+	}}
+	expected := `filter: query?.filter === undefined ? undefined : JSON.stringify(query?.filter), ` +
+		`"sort-by": query?.["sort-by"] === undefined ? undefined : JSON.stringify(query?.["sort-by"])`
+	if got := buildJSONQueryParams(op); got != expected {
+		t.Errorf("buildJSONQueryParams() = %s, expected %s", got, expected)
+	}
+	if got := buildJSONQueryParams(ir.IROperation{QueryParams: []ir.IRParam{{Name: "limit", Schema: str}}}); got != "" {
+		t.Errorf("buildJSONQueryParams() without JSON params = %q", got)
+	}
+}
+
+func TestOptionsSignature(t *testing.T) {
+	str := ir.IRSchema{Kind: ir.IRKindString}
+	update := ir.IROperation{
+		Tag:         "users",
+		Path:        "/orgs/{orgId}/users/{userId}",
+		PathParams:  []ir.IRParam{{Name: "userId", Schema: ir.IRSchema{Kind: ir.IRKindInteger}}, {Name: "orgId", Schema: str}},
+		QueryParams: []ir.IRParam{{Name: "notify", Schema: ir.IRSchema{Kind: ir.IRKindBoolean}}},
+		RequestBody: &ir.IRRequestBody{ContentType: "application/json", Required: true, Schema: ir.IRSchema{Kind: ir.IRKindRef, Ref: "User"}},
+	}
+	list := ir.IROperation{Tag: "users", Path: "/users"}
+
+	expected := []string{
+		"pathParams: { orgId: string; userId: number }",
+		"query?: Schema.UsersUpdateUserQuery",
+		"body: Schema.User",
+		`init?: Omit<RequestInit, "method" | "body">`,
+	}
+	if got := buildOptionsMembers(update, "updateUser", typeOptions{}); strings.Join(got, "\n") != strings.Join(expected, "\n") {
+		t.Errorf("buildOptionsMembers() = %q, expected %q", got, expected)
+	}
+
+	tests := []struct {
+		name      string
+		op        ir.IROperation
+		method    string
+		withInit  bool
+		signature string
+		unpack    string
+	}{
+		{"required", update, "updateUser", true, "options: UsersUpdateUserOptions", "const { pathParams: { orgId, userId }, query, body, init } = options;"},
+		{"query keys", update, "updateUser", false, `options: Omit<UsersUpdateUserOptions, "init">`, "const { pathParams: { orgId, userId }, query, body } = options;"},
+		{"optional", list, "listUsers", true, "options: UsersListUsersOptions = {}", "const { init } = options;"},
+		{"no query keys", list, "listUsers", false, "", ""},
+	}
+	for _, test := range tests {
+		if got := strings.Join(buildOptionsSignature(test.op, test.method, test.withInit), ", "); got != test.signature {
+			t.Errorf("%s: buildOptionsSignature() = %q, expected %q", test.name, got, test.signature)
+		}
+		if got := buildOptionsDestructure(test.op, test.withInit); got != test.unpack {
+			t.Errorf("%s: buildOptionsDestructure() = %q, expected %q", test.name, got, test.unpack)
+		}
+	}
+}
+
+func TestAPIKeyAlias(t *testing.T) {
+	header := ir.IRSecurityScheme{Key: "gatewayKey", Type: "apiKey", In: "header", Name: "X-API-Key"}
+	query := ir.IRSecurityScheme{Key: "queryKey", Type: "apiKey", In: "query", Name: "key"}
+	cookie := ir.IRSecurityScheme{Key: "session", Type: "apiKey", In: "cookie", Name: "sid"}
+	bearer := ir.IRSecurityScheme{Key: "bearerAuth", Type: "http", Scheme: "bearer"}
+	tests := []struct {
+		name     string
+		schemes  []ir.IRSecurityScheme
+		expected string
+	}{
+		{"first header or query scheme", []ir.IRSecurityScheme{bearer, cookie, query, header}, "queryKey"},
+		{"cookie only", []ir.IRSecurityScheme{bearer, cookie}, ""},
+		{"scheme named apiKey", []ir.IRSecurityScheme{header, {Key: "api_key", Type: "apiKey", In: "query", Name: "key"}}, ""},
+	}
+	for _, test := range tests {
+		s, ok := apiKeyAlias(test.schemes)
+		if ok != (test.expected != "") || s.Key != test.expected {
+			t.Errorf("%s: apiKeyAlias() = %q, %v, expected %q", test.name, s.Key, ok, test.expected)
+		}
+	}
+}
+
+func TestSensitiveFieldNames(t *testing.T) {
+	str := &ir.IRSchema{Kind: ir.IRKindString}
+	password := &ir.IRSchema{Kind: ir.IRKindString, Format: "password"}
+	in := ir.IR{
+		ModelDefs: []ir.IRModelDef{{Name: "User", Schema: ir.IRSchema{Kind: ir.IRKindObject, Properties: []ir.IRField{
+			{Name: "name", Type: str},
+			{Name: "password", Type: password},
+			{Name: "credentials", Type: &ir.IRSchema{Kind: ir.IRKindObject, Properties: []ir.IRField{
+				{Name: "clientSecret", Type: str, Annotations: ir.IRAnnotations{WriteOnly: true}},
+			}}},
+		}}}},
+		Services: []ir.IRService{{Tag: "auth", Operations: []ir.IROperation{{
+			RequestBody: &ir.IRRequestBody{Schema: ir.IRSchema{Kind: ir.IRKindObject, Properties: []ir.IRField{
+				{Name: "password", Type: password},
+				{Name: "otp", Type: password},
+			}}},
+		}}}},
+	}
+
+	got := sensitiveFieldNames(in)
+	if expected := []string{"clientSecret", "otp", "password"}; !reflect.DeepEqual(got, expected) {
+		t.Errorf("sensitiveFieldNames() = %v, expected %v", got, expected)
+	}
+}
+
+func TestAcceptResponse(t *testing.T) {
+	str := ir.IRSchema{Kind: ir.IRKindString}
+	op := ir.IROperation{Response: ir.IRResponse{ContentType: "application/json", Schema: ir.IRSchema{Kind: ir.IRKindRef, Ref: "Report"}}}
+	op.Response.Contents = []ir.IRResponseContent{
+		{ContentType: "application/json", Schema: op.Response.Schema},
+		{ContentType: "application/octet-stream", Schema: str, Stream: true},
+		{ContentType: "text/csv", Schema: str},
+		{ContentType: "text/event-stream", Schema: ir.IRSchema{Kind: ir.IRKindRef, Ref: "Event"}, Stream: true},
+	}
+
+	expected := `{ "application/json": Schema.Report; "application/octet-stream": ReadableStream<Uint8Array>; "text/csv": string; "text/event-stream": AsyncIterable<ServerSentEvent<Schema.Event>> }[A]`
+	if got := operationResponseTSType(op, typeOptions{}); got != expected {
+		t.Errorf("operationResponseTSType() = %s, expected %s", got, expected)
+	}
+	expected = `A extends "application/json" | "application/octet-stream" | "text/csv" | "text/event-stream" = "application/json"`
+	if got := acceptTypeParam(op); got != expected {
+		t.Errorf("acceptTypeParam() = %s, expected %s", got, expected)
+	}
+	expected = `["application/octet-stream"].includes(init?.accept ?? "application/json")`
+	if got := acceptStream(op); got != expected {
+		t.Errorf("acceptStream() = %s, expected %s", got, expected)
+	}
+	expected = `(init?.accept ?? "application/json") === "text/event-stream" ? "json" : undefined`
+	if got := acceptEvents(op); got != expected {
+		t.Errorf("acceptEvents() = %s, expected %s", got, expected)
+	}
+	if got := buildMethodSignature(op, "getReport", typeOptions{}); got[len(got)-1] != `init?: Omit<RequestInit, "method" | "body"> & { accept?: A }` {
+		t.Errorf("expected init to take the accept type parameter, got %s", got[len(got)-1])
+	}
+
+	op.Response.Contents = op.Response.Contents[:1]
+	if got := acceptTypeParam(op); got != "" {
+		t.Errorf("acceptTypeParam() = %q for a single media type", got)
+	}
+}
+
+func TestEventStreamTSType(t *testing.T) {
+	tests := []struct {
+		schema   ir.IRSchema
+		decoding string
+		expected string
+	}{
+		{ir.IRSchema{Kind: ir.IRKindRef, Ref: "Event"}, "json", "AsyncIterable<ServerSentEvent<Schema.Event>>"},
+		{ir.IRSchema{Kind: ir.IRKindObject}, "json", "AsyncIterable<ServerSentEvent<Record<string, unknown>>>"},
+		{ir.IRSchema{Kind: ir.IRKindString}, "text", "AsyncIterable<ServerSentEvent<string>>"},
+		{ir.IRSchema{Kind: ir.IRKindUnknown}, "text", "AsyncIterable<ServerSentEvent<string>>"},
+	}
+
+	for _, test := range tests {
+		if got := eventDecoding(test.schema); got != test.decoding {
+			t.Errorf("eventDecoding(%s) = %s, expected %s", test.schema.Kind, got, test.decoding)
+		}
+		if got := eventStreamTSType(test.schema, typeOptions{}); got != test.expected {
+			t.Errorf("eventStreamTSType(%s) = %s, expected %s", test.schema.Kind, got, test.expected)
+		}
+	}
+
+	events := ir.IROperation{Response: ir.IRResponse{ContentType: "text/event-stream; charset=utf-8", Stream: true}}
+	if !usesEventStreams(ir.IRService{Operations: []ir.IROperation{{}, events}}) || usesEventStreams(ir.IRService{Operations: []ir.IROperation{{}}}) {
+		t.Error("expected only the service with an event stream to use ServerSentEvent")
+	}
+}
+
+func TestDefaultHeaders(t *testing.T) {
+	in := ir.IR{Services: []ir.IRService{{Tag: "items", Operations: []ir.IROperation{
+		{OperationID: "listItems", Method: "GET", Path: "/items", Tag: "items", Response: ir.IRResponse{TypeTS: "void"}},
+	}}}}
+	for _, tc := range []struct {
+		name     string
+		headers  map[string]string
+		expected string
+	}{
+		{"none", nil, "const headers = new Headers({\n      ...(this.cfg.headers || {}),"},
+		// Spread before the configured and per-call headers, which take precedence
+		{"configured", map[string]string{"X-Client": "sdk", "User-Agent": "acme/1.0"}, "const headers = new Headers({\n      \"User-Agent\": \"acme/1.0\",\n      \"X-Client\": \"sdk\",\n      ...(this.cfg.headers || {}),\n      ...(init.headers as any),"},
+	} {
+		dir := t.TempDir()
+		client := config.Client{Type: "typescript", OutDir: dir, PackageName: "items", Name: "Items", DefaultHeaders: tc.headers}
+		if err := NewTypeScriptGenerator().Generate(client, in); err != nil {
+			t.Fatal(err)
+		}
+		data, err := os.ReadFile(filepath.Join(dir, "src", "client.ts"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !strings.Contains(string(data), tc.expected) {
+			t.Errorf("%s: client.ts does not contain %q", tc.name, tc.expected)
+		}
+	}
+}
+
+func TestOperationMetadata(t *testing.T) {
+	item := ir.IRSchema{Kind: ir.IRKindRef, Ref: "Item"}
+	in := ir.IR{Services: []ir.IRService{{Tag: "items", Operations: []ir.IROperation{
+		{
+			OperationID: "createItem", Method: "POST", Path: "/items", Tag: "items",
+			RequestBody: &ir.IRRequestBody{ContentType: "application/json", Schema: item, Examples: []any{map[string]any{"name": "pen"}}},
+			Response:    ir.IRResponse{TypeTS: "void"},
+		},
+		// Without an operationId the operation is keyed by method and path
+		{Method: "DELETE", Path: "/items/{id}", Tag: "items", Response: ir.IRResponse{TypeTS: "void"}},
+	}}}}
+	generate := func(emit bool) string {
+		dir := t.TempDir()
+		client := config.Client{Type: "typescript", OutDir: dir, PackageName: "items", Name: "Items", EmitOperationMetadata: emit}
+		if err := NewTypeScriptGenerator().Generate(client, in); err != nil {
+			t.Fatal(err)
+		}
+		return filepath.Join(dir, "src")
+	}
+	read := func(path string) string {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return string(data)
+	}
+
+	src := generate(true)
+	for name, expected := range map[string][]string{
+		"meta.ts": {
+			`"createItem": { method: "POST", path: "/items", tag: "items", exampleBody: {"name":"pen"} },`,
+			`"DELETE /items/{id}": { method: "DELETE", path: "/items/{id}", tag: "items" },`,
+			`} as const satisfies Record<string, OperationMeta>;`,
+			`export type OperationId = keyof typeof operations;`,
+		},
+		"index.ts": {`export * from "./meta";`},
+	} {
+		content := read(filepath.Join(src, name))
+		for _, e := range expected {
+			if !strings.Contains(content, e) {
+				t.Errorf("%s does not contain %q:\n%s", name, e, content)
+			}
+		}
+	}
+
+	src = generate(false)
+	if _, err := os.Stat(filepath.Join(src, "meta.ts")); !os.IsNotExist(err) {
+		t.Errorf("meta.ts generated without emitOperationMetadata")
+	}
+	if strings.Contains(read(filepath.Join(src, "index.ts")), `"./meta"`) {
+		t.Errorf("index.ts exports meta.ts without emitOperationMetadata")
+	}
+}
+
+func TestCookieAPIKey(t *testing.T) {
+	header := ir.IRSecurityScheme{Key: "gatewayKey", Type: "apiKey", In: "header", Name: "X-API-Key"}
+	cookie := ir.IRSecurityScheme{Key: "session", Type: "apiKey", In: "cookie", Name: "sid"}
+	for _, tc := range []struct {
+		name       string
+		schemes    []ir.IRSecurityScheme
+		expected   []string
+		unexpected []string
+	}{
+		{
+			name:    "cookie",
+			schemes: []ir.IRSecurityScheme{header, cookie},
+			expected: []string{
+				// Node sends the cookie header, appended to one passed per call; browsers send
+				// their own cookies with credentials: 'include'
+				"const cookie = `${this.cfg.sessionName || \"sid\"}=${String(this.cfg?.session)}`;",
+				"headers.set(\"Cookie\", existing ? `${existing}; ${cookie}` : cookie);",
+				`const fetchInit: RequestInit = { credentials: this.cfg.credentials ?? "include", ...init, headers };`,
+			},
+		},
+		{
+			name:       "no cookie",
+			schemes:    []ir.IRSecurityScheme{header},
+			expected:   []string{`const fetchInit: RequestInit = { credentials: this.cfg.credentials, ...init, headers };`},
+			unexpected: []string{`"Cookie"`},
+		},
+	} {
+		dir := t.TempDir()
+		client := config.Client{Type: "typescript", OutDir: dir, PackageName: "items", Name: "Items"}
+		if err := NewTypeScriptGenerator().Generate(client, ir.IR{SecuritySchemes: tc.schemes}); err != nil {
+			t.Fatal(err)
+		}
+		data, err := os.ReadFile(filepath.Join(dir, "src", "client.ts"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, e := range tc.expected {
+			if !strings.Contains(string(data), e) {
+				t.Errorf("%s: client.ts does not contain %q", tc.name, e)
+			}
+		}
+		for _, u := range tc.unexpected {
+			if strings.Contains(string(data), u) {
+				t.Errorf("%s: client.ts contains %q", tc.name, u)
+			}
+		}
+	}
+}
@@ -0,0 +1,264 @@
+package typescript
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/blimu-dev/sdk-gen/pkg/config"
+)
+
+// templateIndent is the indentation the templates are written with
+const templateIndent = "  "
+
+// codeStyle is the indentation and string quote style of the generated TypeScript
+type codeStyle struct {
+	// Indent replaces each level of the templates' two-space indentation
+	Indent string
+	// Quote is the quote string literals are converted to, 0 to keep the templates' quotes
+	Quote byte
+}
+
+// newCodeStyle returns the code style configured for the client; config.Load validated it
+func newCodeStyle(client config.Client) codeStyle {
+	style := codeStyle{Indent: templateIndent}
+	if client.Indent == config.IndentTab {
+		style.Indent = "\t"
+	} else if n, err := strconv.Atoi(client.Indent); err == nil && n > 0 {
+		style.Indent = strings.Repeat(" ", n)
+	}
+	switch client.QuoteStyle {
+	case config.QuoteSingle:
+		style.Quote = '\''
+	case config.QuoteDouble:
+		style.Quote = '"'
+	}
+	return style
+}
+
+// UseTabs reports whether the code is indented with tabs, for .prettierrc.json
+func (s codeStyle) UseTabs() bool {
+	return s.Indent == "\t"
+}
+
+// TabWidth is the number of spaces per indentation level, for .prettierrc.json
+func (s codeStyle) TabWidth() int {
+	if s.UseTabs() {
+		return len(templateIndent)
+	}
+	return len(s.Indent)
+}
+
+// SingleQuote reports whether strings are single quoted, for .prettierrc.json. Without a
+// configured style the templates' mix is closest to prettier's single quotes.
+func (s codeStyle) SingleQuote() bool {
+	return s.Quote != '"'
+}
+
+// apply restyles TypeScript source written by the templates. Leading indentation is converted
+// level by level, keeping odd spaces such as those aligning JSDoc asterisks, and string
+// literals are requoted. Comments, regular expressions and template literals are left as is.
+func (s codeStyle) apply(src string) string {
+	if s.Indent == templateIndent && s.Quote == 0 {
+		return src
+	}
+	var out strings.Builder
+	out.Grow(len(src))
+
+	const (
+		code = iota
+		lineComment
+		blockComment
+		template
+	)
+	mode := code
+	// braces counts the open braces of each ${} expression entered from a template literal
+	var braces []int
+	// last is the last significant character of code, to tell regular expressions from division
+	var last byte
+	lineStart := true
+
+	for i := 0; i < len(src); {
+		if lineStart && mode != template {
+			n := 0
+			for i+n < len(src) && src[i+n] == ' ' {
+				n++
+			}
+			out.WriteString(strings.Repeat(s.Indent, n/len(templateIndent)))
+			out.WriteString(strings.Repeat(" ", n%len(templateIndent)))
+			i += n
+			lineStart = false
+			continue
+		}
+		c := src[i]
+		if c == '\n' {
+			out.WriteByte(c)
+			i++
+			lineStart = true
+			if mode == lineComment {
+				mode = code
+			}
+			continue
+		}
+
+		switch mode {
+		case lineComment:
+			out.WriteByte(c)
+			i++
+		case blockComment:
+			if strings.HasPrefix(src[i:], "*/") {
+				out.WriteString("*/")
+				i += 2
+				mode = code
+				continue
+			}
+			out.WriteByte(c)
+			i++
+		case template:
+			switch {
+			case c == '\\' && i+1 < len(src):
+				out.WriteString(src[i : i+2])
+				i += 2
+			case c == '`':
+				out.WriteByte(c)
+				i++
+				mode = code
+				last = c
+			case strings.HasPrefix(src[i:], "${"):
+				out.WriteString("${")
+				i += 2
+				braces = append(braces, 0)
+				mode = code
+				last = '{'
+			default:
+				out.WriteByte(c)
+				i++
+			}
+		default:
+			switch {
+			case strings.HasPrefix(src[i:], "//"):
+				mode = lineComment
+				out.WriteString("//")
+				i += 2
+			case strings.HasPrefix(src[i:], "/*"):
+				mode = blockComment
+				out.WriteString("/*")
+				i += 2
+			case c == '`':
+				mode = template
+				out.WriteByte(c)
+				i++
+			case c == '"' || c == '\'':
+				end := literalEnd(src, i, c, false)
+				out.WriteString(s.requote(src[i:end]))
+				i = end
+				last = c
+			case c == '/' && regexAllowed(last, out.String()):
+				end := literalEnd(src, i, c, true)
+				out.WriteString(src[i:end])
+				i = end
+				last = c
+			case c == '{' && len(braces) > 0:
+				braces[len(braces)-1]++
+				out.WriteByte(c)
+				i++
+				last = c
+			case c == '}' && len(braces) > 0:
+				out.WriteByte(c)
+				i++
+				last = c
+				if braces[len(braces)-1] == 0 {
+					braces = braces[:len(braces)-1]
+					mode = template
+				} else {
+					braces[len(braces)-1]--
+				}
+			default:
+				out.WriteByte(c)
+				i++
+				if c != ' ' && c != '\t' {
+					last = c
+				}
+			}
+		}
+	}
+	return out.String()
+}
+
+// literalEnd returns the index just past the string or regular expression literal opening at
+// start; a literal left open at the end of the line ends there
+func literalEnd(src string, start int, quote byte, regex bool) int {
+	inClass := false
+	for i := start + 1; i < len(src); i++ {
+		switch c := src[i]; {
+		case c == '\n':
+			return i
+		case c == '\\':
+			i++
+		case regex && c == '[':
+			inClass = true
+		case regex && c == ']':
+			inClass = false
+		case c == quote && !inClass:
+			end := i + 1
+			if regex {
+				// flags
+				for end < len(src) && (src[end] >= 'a' && src[end] <= 'z') {
+					end++
+				}
+			}
+			return end
+		}
+	}
+	return len(src)
+}
+
+// regexAllowed reports whether a slash after the last significant character of code, with out
+// written so far, opens a regular expression rather than being a division
+func regexAllowed(last byte, out string) bool {
+	if last == 0 || strings.IndexByte("(,=:[!&|?{};+-*%<>~^", last) >= 0 {
+		return true
+	}
+	trimmed := strings.TrimRight(out, " \t")
+	for _, keyword := range []string{"return", "typeof", "case", "in", "of"} {
+		if strings.HasSuffix(trimmed, keyword) {
+			before := strings.TrimSuffix(trimmed, keyword)
+			if before == "" || !isIdentByte(before[len(before)-1]) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func isIdentByte(c byte) bool {
+	return c == '_' || c == '$' || c >= '0' && c <= '9' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z'
+}
+
+// requote converts a complete string literal to the configured quote, unless its body contains
+// that quote and would need escaping
+func (s codeStyle) requote(literal string) string {
+	from := literal[0]
+	if s.Quote == 0 || from == s.Quote || len(literal) < 2 || literal[len(literal)-1] != from {
+		return literal
+	}
+	body := literal[1 : len(literal)-1]
+	var b strings.Builder
+	b.WriteByte(s.Quote)
+	for i := 0; i < len(body); i++ {
+		switch c := body[i]; {
+		case c == '\\' && i+1 < len(body):
+			if body[i+1] == from {
+				b.WriteByte(from)
+			} else {
+				b.WriteString(body[i : i+2])
+			}
+			i++
+		case c == s.Quote:
+			return literal
+		default:
+			b.WriteByte(c)
+		}
+	}
+	b.WriteByte(s.Quote)
+	return b.String()
+}
@@ -0,0 +1,68 @@
+package typescript
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/blimu-dev/sdk-gen/pkg/config"
+)
+
+func TestCodeStyle(t *testing.T) {
+	src := strings.Join([]string{
+		`import { CoreClient } from "../client";`,
+		`/**`,
+		` * Say "hi" to the user's friends`,
+		` */`,
+		`export function greet(name: string): string {`,
+		`  const re = /"[^/]*"/g; // don't touch "this"`,
+		"  return `Hi ${name === \"\" ? 'you' : name}, \"${name}\"`;",
+		`}`,
+		`const quotes = ["it's", "say \"hi\"", 'plain', 'with "double"'];`,
+		`const half = total / 2; const quarter = half / 2;`,
+	}, "\n")
+	tests := []struct {
+		client   config.Client
+		expected []string
+	}{
+		{config.Client{}, strings.Split(src, "\n")},
+		{config.Client{Indent: "tab", QuoteStyle: config.QuoteSingle}, []string{
+			`import { CoreClient } from '../client';`,
+			`/**`,
+			` * Say "hi" to the user's friends`,
+			` */`,
+			`export function greet(name: string): string {`,
+			"\t" + `const re = /"[^/]*"/g; // don't touch "this"`,
+			"\treturn `Hi ${name === '' ? 'you' : name}, \"${name}\"`;",
+			`}`,
+			`const quotes = ["it's", 'say "hi"', 'plain', 'with "double"'];`,
+			`const half = total / 2; const quarter = half / 2;`,
+		}},
+		{config.Client{Indent: "4", QuoteStyle: config.QuoteDouble}, []string{
+			`import { CoreClient } from "../client";`,
+			`/**`,
+			` * Say "hi" to the user's friends`,
+			` */`,
+			`export function greet(name: string): string {`,
+			`    const re = /"[^/]*"/g; // don't touch "this"`,
+			"    return `Hi ${name === \"\" ? \"you\" : name}, \"${name}\"`;",
+			`}`,
+			`const quotes = ["it's", "say \"hi\"", "plain", 'with "double"'];`,
+			`const half = total / 2; const quarter = half / 2;`,
+		}},
+	}
+
+	for _, test := range tests {
+		got := newCodeStyle(test.client).apply(src)
+		if expected := strings.Join(test.expected, "\n"); got != expected {
+			t.Errorf("indent %q, quoteStyle %q:\n%s\nexpected\n%s", test.client.Indent, test.client.QuoteStyle, got, expected)
+		}
+	}
+}
+
+func TestCodeStyleNestedIndentation(t *testing.T) {
+	src := "class A {\n  m() {\n    if (x) {\n      y();\n    }\n  }\n}"
+	expected := "class A {\n\tm() {\n\t\tif (x) {\n\t\t\ty();\n\t\t}\n\t}\n}"
+	if got := newCodeStyle(config.Client{Indent: config.IndentTab}).apply(src); got != expected {
+		t.Errorf("apply() =\n%s\nexpected\n%s", got, expected)
+	}
+}
@@ -0,0 +1,183 @@
+package typescript
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/blimu-dev/sdk-gen/pkg/ir"
+)
+
+// zodRenderer converts IR schemas to Zod schema expressions for schemas.zod.ts. Every model
+// becomes a const named like its TypeScript type; refs to models declared at or after the
+// current one are wrapped in z.lazy, which covers both forward references and cycles.
+type zodRenderer struct {
+	opts  typeOptions
+	order map[string]int
+}
+
+// newZodRenderer indexes the models in the order they are declared in schemas.zod.ts
+func newZodRenderer(defs []ir.IRModelDef, opts typeOptions) zodRenderer {
+	order := make(map[string]int, len(defs))
+	for i, md := range defs {
+		if _, ok := order[md.Name]; !ok {
+			order[md.Name] = i
+		}
+	}
+	return zodRenderer{opts: opts, order: order}
+}
+
+// schema renders s as a Zod expression used while declaring the model named current
+func (r zodRenderer) schema(s ir.IRSchema, current string) string {
+	t := r.base(s, current)
+	if s.Kind == ir.IRKindObject && s.TypeOverrides["ts"] == "" {
+		t += zodPropertyCount(s)
+	}
+	if (s.Kind == ir.IRKindNumber || s.Kind == ir.IRKindInteger) && s.TypeOverrides["ts"] == "" {
+		t += zodNumericBounds(s)
+	}
+	if s.Nullable && s.Kind != ir.IRKindNull {
+		t += ".nullable()"
+	}
+	return t
+}
+
+func (r zodRenderer) base(s ir.IRSchema, current string) string {
+	// x-ts-type replaces the inferred type, so only the static type can be asserted
+	if override := s.TypeOverrides["ts"]; override != "" {
+		return fmt.Sprintf("z.custom<%s>()", override)
+	}
+	switch s.Kind {
+	case ir.IRKindString:
+		switch {
+		case s.Format == "binary":
+			return "z.instanceof(Blob)"
+		case r.opts.DateAsNativeType && (s.Format == "date" || s.Format == "date-time"):
+			return "z.coerce.date()"
+		}
+		return "z.string()"
+	case ir.IRKindNumber:
+		return "z.number()"
+	case ir.IRKindInteger:
+		return "z.number().int()"
+	case ir.IRKindBoolean:
+		return "z.boolean()"
+	case ir.IRKindNull:
+		return "z.null()"
+	case ir.IRKindRef:
+		if s.Ref == "" {
+			return "z.unknown()"
+		}
+		if idx, ok := r.order[s.Ref]; !ok || idx >= r.order[current] {
+			return fmt.Sprintf("z.lazy(() => %s)", s.Ref)
+		}
+		return s.Ref
+	case ir.IRKindArray:
+		if s.Items == nil {
+			return "z.array(z.unknown())"
+		}
+		return "z.array(" + r.schema(*s.Items, current) + ")"
+	case ir.IRKindOneOf:
+		return r.union(s.OneOf, current)
+	case ir.IRKindAnyOf:
+		return r.union(s.AnyOf, current)
+	case ir.IRKindAllOf:
+		if len(s.AllOf) == 0 {
+			return "z.unknown()"
+		}
+		t := r.schema(*s.AllOf[0], current)
+		for _, sub := range s.AllOf[1:] {
+			t = "z.intersection(" + t + ", " + r.schema(*sub, current) + ")"
+		}
+		return t
+	case ir.IRKindEnum:
+		return zodEnum(s)
+	case ir.IRKindObject:
+		if len(s.Properties) == 0 {
+			if s.AdditionalProperties != nil {
+				return "z.record(z.string(), " + r.schema(*s.AdditionalProperties, current) + ")"
+			}
+			return "z.record(z.string(), z.unknown())"
+		}
+		parts := make([]string, 0, len(s.Properties))
+		for _, f := range s.Properties {
+			parts = append(parts, quoteTSPropertyName(f.Name)+": "+r.field(f, current))
+		}
+		return "z.object({ " + strings.Join(parts, ", ") + " })"
+	}
+	return "z.unknown()"
+}
+
+// field renders an object property, marking fields that are not required as optional
+func (r zodRenderer) field(f ir.IRField, current string) string {
+	t := "z.unknown()"
+	if f.Type != nil {
+		t = r.schema(*f.Type, current)
+	}
+	if !f.Required {
+		t += ".optional()"
+	}
+	return t
+}
+
+func (r zodRenderer) union(subs []*ir.IRSchema, current string) string {
+	switch len(subs) {
+	case 0:
+		return "z.unknown()"
+	case 1:
+		return r.schema(*subs[0], current)
+	}
+	parts := make([]string, 0, len(subs))
+	for _, sub := range subs {
+		parts = append(parts, r.schema(*sub, current))
+	}
+	return "z.union([" + strings.Join(parts, ", ") + "])"
+}
+
+// zodPropertyCount renders refinements checking the minProperties and maxProperties bounds of
+// an object schema, or an empty string when it has none
+func zodPropertyCount(s ir.IRSchema) string {
+	var t string
+	if s.MinProperties != nil {
+		t += fmt.Sprintf(".refine((v) => Object.keys(v).length >= %d, { message: \"must have at least %d properties\" })", *s.MinProperties, *s.MinProperties)
+	}
+	if s.MaxProperties != nil {
+		t += fmt.Sprintf(".refine((v) => Object.keys(v).length <= %d, { message: \"must have at most %d properties\" })", *s.MaxProperties, *s.MaxProperties)
+	}
+	return t
+}
+
+// zodNumericBounds renders the checks of the exclusive bounds and multipleOf of a number
+// schema, or an empty string when it has none
+func zodNumericBounds(s ir.IRSchema) string {
+	var t string
+	if s.ExclusiveMinimum != nil {
+		t += ".gt(" + strconv.FormatFloat(*s.ExclusiveMinimum, 'g', -1, 64) + ")"
+	}
+	if s.ExclusiveMaximum != nil {
+		t += ".lt(" + strconv.FormatFloat(*s.ExclusiveMaximum, 'g', -1, 64) + ")"
+	}
+	if s.MultipleOf != nil {
+		t += ".multipleOf(" + strconv.FormatFloat(*s.MultipleOf, 'g', -1, 64) + ")"
+	}
+	return t
+}
+
+// zodEnum renders string enums with z.enum and other enums as a union of literals
+func zodEnum(s ir.IRSchema) string {
+	vals := enumTSLiterals(s)
+	if len(vals) == 0 {
+		return "z.unknown()"
+	}
+	if s.EnumBase != ir.IRKindNumber && s.EnumBase != ir.IRKindInteger && s.EnumBase != ir.IRKindBoolean {
+		return "z.enum([" + strings.Join(vals, ", ") + "])"
+	}
+	literals := make([]string, 0, len(vals))
+	for _, v := range vals {
+		literals = append(literals, "z.literal("+v+")")
+	}
+	if len(literals) == 1 {
+		return literals[0]
+	}
+	return "z.union([" + strings.Join(literals, ", ") + "])"
+}
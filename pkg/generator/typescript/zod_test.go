@@ -0,0 +1,91 @@
+package typescript
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/blimu-dev/sdk-gen/pkg/config"
+	"github.com/blimu-dev/sdk-gen/pkg/ir"
+)
+
+func TestZodSchema(t *testing.T) {
+	defs := []ir.IRModelDef{{Name: "Address"}, {Name: "Node"}, {Name: "Tree"}}
+	r := newZodRenderer(defs, typeOptions{DateAsNativeType: true})
+
+	str := &ir.IRSchema{Kind: ir.IRKindString}
+	one, ten := uint64(1), uint64(10)
+	zero, cent, hundred := 0.0, 0.01, 100.0
+	tests := []struct {
+		name     string
+		schema   ir.IRSchema
+		expected string
+	}{
+		{"nullable string", ir.IRSchema{Kind: ir.IRKindString, Nullable: true}, "z.string().nullable()"},
+		{"integer", ir.IRSchema{Kind: ir.IRKindInteger}, "z.number().int()"},
+		{
+			"positive amount",
+			ir.IRSchema{Kind: ir.IRKindNumber, ExclusiveMinimum: &zero, ExclusiveMaximum: &hundred, MultipleOf: &cent, Nullable: true},
+			"z.number().gt(0).lt(100).multipleOf(0.01).nullable()",
+		},
+		{"native date", ir.IRSchema{Kind: ir.IRKindString, Format: "date-time"}, "z.coerce.date()"},
+		{"earlier ref", ir.IRSchema{Kind: ir.IRKindRef, Ref: "Address"}, "Address"},
+		{"self ref", ir.IRSchema{Kind: ir.IRKindRef, Ref: "Node"}, "z.lazy(() => Node)"},
+		{"later ref", ir.IRSchema{Kind: ir.IRKindRef, Ref: "Tree"}, "z.lazy(() => Tree)"},
+		{"array of self", ir.IRSchema{Kind: ir.IRKindArray, Items: &ir.IRSchema{Kind: ir.IRKindRef, Ref: "Node"}}, "z.array(z.lazy(() => Node))"},
+		{"string enum", ir.IRSchema{Kind: ir.IRKindEnum, EnumBase: ir.IRKindString, EnumValues: []string{"a", "b"}}, `z.enum(["a", "b"])`},
+		{"integer enum", ir.IRSchema{Kind: ir.IRKindEnum, EnumBase: ir.IRKindInteger, EnumValues: []string{"1", "2"}, EnumRaw: []any{1, 2}}, "z.union([z.literal(1), z.literal(2)])"},
+		{"map", ir.IRSchema{Kind: ir.IRKindObject, AdditionalProperties: str}, "z.record(z.string(), z.string())"},
+		{
+			"bounded map",
+			ir.IRSchema{Kind: ir.IRKindObject, AdditionalProperties: str, MinProperties: &one, MaxProperties: &ten, Nullable: true},
+			`z.record(z.string(), z.string())` +
+				`.refine((v) => Object.keys(v).length >= 1, { message: "must have at least 1 properties" })` +
+				`.refine((v) => Object.keys(v).length <= 10, { message: "must have at most 10 properties" }).nullable()`,
+		},
+		{
+			"inline object",
+			ir.IRSchema{Kind: ir.IRKindObject, Properties: []ir.IRField{{Name: "id", Type: str, Required: true}, {Name: "display-name", Type: str}}},
+			`z.object({ id: z.string(), "display-name": z.string().optional() })`,
+		},
+		{
+			"oneOf",
+			ir.IRSchema{Kind: ir.IRKindOneOf, OneOf: []*ir.IRSchema{str, {Kind: ir.IRKindRef, Ref: "Address"}}},
+			"z.union([z.string(), Address])",
+		},
+		{
+			"allOf",
+			ir.IRSchema{Kind: ir.IRKindAllOf, AllOf: []*ir.IRSchema{{Kind: ir.IRKindRef, Ref: "Address"}, {Kind: ir.IRKindRef, Ref: "Tree"}}},
+			"z.intersection(Address, z.lazy(() => Tree))",
+		},
+		{"ts override", ir.IRSchema{Kind: ir.IRKindString, TypeOverrides: map[string]string{"ts": "Decimal"}}, "z.custom<Decimal>()"},
+	}
+
+	for _, test := range tests {
+		if result := r.schema(test.schema, "Node"); result != test.expected {
+			t.Errorf("%s: schema() = %s, expected %s", test.name, result, test.expected)
+		}
+	}
+}
+
+func TestZodSchemasFile(t *testing.T) {
+	str := &ir.IRSchema{Kind: ir.IRKindString}
+	in := ir.IR{ModelDefs: []ir.IRModelDef{
+		{Name: "User", Schema: ir.IRSchema{Kind: ir.IRKindObject, Properties: []ir.IRField{{Name: "id", Type: str, Required: true}}}},
+	}}
+	dir := t.TempDir()
+	client := config.Client{Type: "typescript", OutDir: dir, PackageName: "users", Name: "Users", EmitZod: true}
+	if err := NewTypeScriptGenerator().Generate(client, in); err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(filepath.Join(dir, "src", "schemas.zod.ts"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := "import { z } from \"zod\";\nimport type * as Schema from \"./schema\";\n\n" +
+		"export const User: z.ZodType<Schema.User> = z.object({\n  id: z.string(),\n});"
+	if !strings.Contains(string(data), expected) {
+		t.Errorf("schemas.zod.ts does not contain %q:\n%s", expected, data)
+	}
+}
@@ -0,0 +1,90 @@
+package generator
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/blimu-dev/sdk-gen/pkg/config"
+	"github.com/blimu-dev/sdk-gen/pkg/ir"
+	"github.com/blimu-dev/sdk-gen/pkg/lint"
+)
+
+// notConstraintNote documents a `not` constraint the generated types cannot express
+const notConstraintNote = "The `not` constraint of this schema is not supported and is not enforced."
+
+// warnUnsupported writes a warning for every schema using a constraint the generators ignore, so
+// the log lists the affected models, fields and parameters
+func warnUnsupported(w io.Writer, in ir.IR) {
+	for _, issue := range lint.Lint(in).Issues {
+		if issue.Code == lint.CodeUnsupportedNot {
+			fmt.Fprintf(w, "warning: %s: %s\n", issue.Location, issue.Message)
+		}
+	}
+}
+
+// noteUnsupportedConstraints returns a copy of the IR whose models, fields and parameters using
+// `not` have notConstraintNote appended to their description, so the generated code comments
+// on the ignored constraint. JSON Schema output keeps `not` and is left as is.
+func noteUnsupportedConstraints(in ir.IR, client config.Client) ir.IR {
+	if client.Type == "jsonschema" {
+		return in
+	}
+
+	out := in
+	out.ModelDefs = make([]ir.IRModelDef, len(in.ModelDefs))
+	for i, md := range in.ModelDefs {
+		if md.Schema.Kind == ir.IRKindObject && len(md.Schema.Properties) > 0 {
+			// Objects get the note on the fields using `not` rather than on the model
+			md.Schema.Properties = noteFields(md.Schema.Properties)
+		} else if md.Schema.Uses(ir.IRKindNot) {
+			md.Annotations.Description = withNote(md.Annotations.Description, notConstraintNote)
+		}
+		out.ModelDefs[i] = md
+	}
+
+	out.Services = make([]ir.IRService, len(in.Services))
+	for i, service := range in.Services {
+		ops := make([]ir.IROperation, len(service.Operations))
+		for j, op := range service.Operations {
+			op.PathParams = noteParams(op.PathParams)
+			op.QueryParams = noteParams(op.QueryParams)
+			ops[j] = op
+		}
+		service.Operations = ops
+		out.Services[i] = service
+	}
+	return out
+}
+
+func noteFields(fields []ir.IRField) []ir.IRField {
+	out := make([]ir.IRField, len(fields))
+	for i, f := range fields {
+		if f.Type != nil && f.Type.Uses(ir.IRKindNot) {
+			f.Annotations.Description = withNote(f.Annotations.Description, notConstraintNote)
+		}
+		out[i] = f
+	}
+	return out
+}
+
+func noteParams(params []ir.IRParam) []ir.IRParam {
+	if params == nil {
+		return nil
+	}
+	out := make([]ir.IRParam, len(params))
+	for i, p := range params {
+		if p.Schema.Uses(ir.IRKindNot) {
+			p.Description = withNote(p.Description, notConstraintNote)
+		}
+		out[i] = p
+	}
+	return out
+}
+
+// withNote appends note to a description as its own paragraph
+func withNote(description, note string) string {
+	if description == "" {
+		return note
+	}
+	return description + "\n\n" + note
+}
@@ -0,0 +1,66 @@
+package generator
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/blimu-dev/sdk-gen/pkg/config"
+	"github.com/blimu-dev/sdk-gen/pkg/ir"
+)
+
+func TestNoteUnsupportedConstraints(t *testing.T) {
+	str := ir.IRSchema{Kind: ir.IRKindString}
+	not := ir.IRSchema{Kind: ir.IRKindNot, Not: &ir.IRSchema{Kind: ir.IRKindEnum, EnumBase: ir.IRKindString, EnumValues: []string{"root"}}}
+	in := ir.IR{
+		ModelDefs: []ir.IRModelDef{
+			{Name: "User", Schema: ir.IRSchema{Kind: ir.IRKindObject, Properties: []ir.IRField{
+				{Name: "id", Type: &str},
+				{Name: "login", Type: &not, Annotations: ir.IRAnnotations{Description: "Login name"}},
+				{Name: "aliases", Type: &ir.IRSchema{Kind: ir.IRKindArray, Items: &not}},
+			}}},
+			{Name: "NotRoot", Schema: not},
+		},
+		Services: []ir.IRService{{Tag: "users", Operations: []ir.IROperation{{
+			OperationID: "listUsers", Method: "GET", Path: "/users",
+			QueryParams: []ir.IRParam{{Name: "login", Schema: not}, {Name: "limit", Schema: str}},
+		}}}},
+	}
+
+	out := noteUnsupportedConstraints(in, config.Client{Type: "go"})
+	fields := out.ModelDefs[0].Schema.Properties
+	expected := []string{"", "Login name\n\n" + notConstraintNote, notConstraintNote}
+	for i, f := range fields {
+		if f.Annotations.Description != expected[i] {
+			t.Errorf("User.%s: description %q, expected %q", f.Name, f.Annotations.Description, expected[i])
+		}
+	}
+	if out.ModelDefs[0].Annotations.Description != "" {
+		t.Errorf("User: expected no model note, got %q", out.ModelDefs[0].Annotations.Description)
+	}
+	if got := out.ModelDefs[1].Annotations.Description; got != notConstraintNote {
+		t.Errorf("NotRoot: description %q, expected the note", got)
+	}
+	params := out.Services[0].Operations[0].QueryParams
+	if params[0].Description != notConstraintNote || params[1].Description != "" {
+		t.Errorf("unexpected parameter descriptions %q, %q", params[0].Description, params[1].Description)
+	}
+
+	// The shared input IR is left untouched
+	if in.ModelDefs[0].Schema.Properties[1].Annotations.Description != "Login name" || in.ModelDefs[1].Annotations.Description != "" {
+		t.Errorf("input IR was modified")
+	}
+
+	if got := noteUnsupportedConstraints(in, config.Client{Type: "jsonschema"}); got.ModelDefs[1].Annotations.Description != "" {
+		t.Errorf("jsonschema: expected no note, got %q", got.ModelDefs[1].Annotations.Description)
+	}
+
+	var buf bytes.Buffer
+	warnUnsupported(&buf, in)
+	expectedLog := "warning: GET /users query parameter login: schema uses not, which the generated types cannot express; the constraint is ignored\n" +
+		"warning: NotRoot: schema uses not, which the generated types cannot express; the constraint is ignored\n" +
+		"warning: User.aliases[]: schema uses not, which the generated types cannot express; the constraint is ignored\n" +
+		"warning: User.login: schema uses not, which the generated types cannot express; the constraint is ignored\n"
+	if buf.String() != expectedLog {
+		t.Errorf("warnUnsupported() =\n%s\nexpected:\n%s", buf.String(), expectedLog)
+	}
+}
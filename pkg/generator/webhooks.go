@@ -0,0 +1,155 @@
+package generator
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/blimu-dev/sdk-gen/pkg/ir"
+	"github.com/blimu-dev/sdk-gen/pkg/utils"
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+// webhooksExtension is where OpenAPI 3.1 webhooks end up, as the 3.0 document model has no
+// field for them
+const webhooksExtension = "webhooks"
+
+// webhookMethods are the operations of a webhook or callback path item, in output order
+var webhookMethods = []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS", "HEAD", "TRACE"}
+
+// hoistedPayloadKinds are the payload schema kinds that get a model of their own, since
+// generators render them as named types
+var hoistedPayloadKinds = map[ir.IRSchemaKind]bool{
+	ir.IRKindObject: true, ir.IRKindEnum: true,
+	ir.IRKindOneOf: true, ir.IRKindAnyOf: true, ir.IRKindAllOf: true,
+}
+
+// extractWebhooks collects the webhooks of the document, sorted by name, followed by the
+// callbacks of its operations in path order. Inline payload schemas become models named after
+// the webhook, returned to be appended to modelDefs.
+func extractWebhooks(doc *openapi3.T, modelDefs []ir.IRModelDef) ([]ir.IRWebhook, []ir.IRModelDef, error) {
+	taken := map[string]bool{}
+	for _, md := range modelDefs {
+		taken[utils.ToPascalCaseAdvanced(md.Name)] = true
+	}
+	var out []ir.IRWebhook
+	var payloads []ir.IRModelDef
+
+	add := func(name, operation, expression string, item *openapi3.PathItem) {
+		if item == nil {
+			return
+		}
+		for _, method := range webhookMethods {
+			op := item.GetOperation(method)
+			if op == nil || sdkIgnored(op.Extensions) {
+				continue
+			}
+			wh := ir.IRWebhook{
+				Name:        name,
+				Operation:   operation,
+				Expression:  expression,
+				Method:      method,
+				Summary:     op.Summary,
+				Description: op.Description,
+				Deprecated:  op.Deprecated,
+			}
+			if contents := extractRequestContents(doc, withRequestBody(doc, op)); len(contents) > 0 {
+				payload := contents[0]
+				if hoistedPayloadKinds[payload.Schema.Kind] {
+					model := payloadModelName(name, operation, taken)
+					payloads = append(payloads, ir.IRModelDef{Name: model, Schema: payload.Schema})
+					payload.Schema = ir.IRSchema{Kind: ir.IRKindRef, Ref: model}
+				}
+				wh.Payload = &payload
+			}
+			out = append(out, wh)
+		}
+	}
+
+	webhooks, err := documentWebhooks(doc)
+	if err != nil {
+		return nil, nil, err
+	}
+	for _, name := range slices.Sorted(maps.Keys(webhooks)) {
+		add(name, "", "", webhooks[name])
+	}
+
+	if doc.Paths != nil {
+		paths := doc.Paths.Map()
+		for _, path := range slices.Sorted(maps.Keys(paths)) {
+			item := paths[path]
+			for _, method := range webhookMethods {
+				op := item.GetOperation(method)
+				if op == nil || sdkIgnored(op.Extensions) {
+					continue
+				}
+				key := operationFilterKey(ir.IROperation{OperationID: op.OperationID, Method: method, Path: path})
+				for _, name := range slices.Sorted(maps.Keys(op.Callbacks)) {
+					cb := op.Callbacks[name]
+					if cb == nil || cb.Value == nil {
+						continue
+					}
+					items := cb.Value.Map()
+					for _, expression := range slices.Sorted(maps.Keys(items)) {
+						add(name, key, expression, items[expression])
+					}
+				}
+			}
+		}
+	}
+	return out, payloads, nil
+}
+
+// documentWebhooks decodes the OpenAPI 3.1 webhooks object of the document, if any
+func documentWebhooks(doc *openapi3.T) (map[string]*openapi3.PathItem, error) {
+	raw, ok := doc.Extensions[webhooksExtension]
+	if !ok {
+		return nil, nil
+	}
+	data, err := json.Marshal(raw)
+	if err != nil {
+		return nil, fmt.Errorf("invalid webhooks: %w", err)
+	}
+	var webhooks map[string]*openapi3.PathItem
+	if err := json.Unmarshal(data, &webhooks); err != nil {
+		return nil, fmt.Errorf("invalid webhooks: %w", err)
+	}
+	return webhooks, nil
+}
+
+// withRequestBody returns op with a request body reference to the components resolved. The
+// loader resolves those for paths but not for webhooks, which it does not know about.
+func withRequestBody(doc *openapi3.T, op *openapi3.Operation) *openapi3.Operation {
+	rb := op.RequestBody
+	if rb == nil || rb.Value != nil || doc.Components == nil {
+		return op
+	}
+	name := strings.TrimPrefix(rb.Ref, "#/components/requestBodies/")
+	if resolved, ok := doc.Components.RequestBodies[name]; ok && resolved != nil {
+		resolvedOp := *op
+		resolvedOp.RequestBody = resolved
+		return &resolvedOp
+	}
+	return op
+}
+
+// payloadModelName names the model of an inline webhook payload <Name>Payload, prefixed with
+// the operation of a callback, then numbered, when that is taken
+func payloadModelName(name, operation string, taken map[string]bool) string {
+	base := utils.ToPascalCaseAdvanced(name) + "Payload"
+	candidates := []string{base}
+	if operation != "" {
+		candidates = append(candidates, utils.ToPascalCaseAdvanced(operation)+base)
+	}
+	for i := 2; ; i++ {
+		for _, candidate := range candidates {
+			if !taken[candidate] {
+				taken[candidate] = true
+				return candidate
+			}
+		}
+		candidates = []string{fmt.Sprintf("%s%d", base, i)}
+	}
+}
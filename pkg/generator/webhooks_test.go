@@ -0,0 +1,90 @@
+package generator
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/blimu-dev/sdk-gen/pkg/config"
+	"github.com/blimu-dev/sdk-gen/pkg/ir"
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+func TestWebhooks(t *testing.T) {
+	doc, err := openapi3.NewLoader().LoadFromFile("testdata/webhooks.yaml")
+	if err != nil {
+		t.Fatalf("load fixture: %v", err)
+	}
+	fullIR, err := BuildIR(doc)
+	if err != nil {
+		t.Fatalf("BuildIR: %v", err)
+	}
+
+	type webhook struct{ name, operation, expression, payload string }
+	var got []webhook
+	for _, wh := range fullIR.Webhooks {
+		payload := ""
+		if wh.Payload != nil {
+			payload = wh.Payload.Schema.Ref
+		}
+		got = append(got, webhook{wh.Name, wh.Operation, wh.Expression, payload})
+	}
+	expected := []webhook{
+		{"newPet", "", "", "NewPetPayload"},
+		{"petRemoved", "", "", "Pet"},
+		{"onEvent", "createSubscription", "{$request.body#/callbackUrl}", "OnEventPayload"},
+	}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("webhooks = %+v, expected %+v", got, expected)
+	}
+	if fullIR.Webhooks[0].Summary != "A pet was added" || fullIR.Webhooks[0].Method != "POST" {
+		t.Errorf("newPet: unexpected webhook %+v", fullIR.Webhooks[0])
+	}
+
+	models := func(in ir.IR) map[string]ir.IRModelDef {
+		out := map[string]ir.IRModelDef{}
+		for _, md := range in.ModelDefs {
+			out[md.Name] = md
+		}
+		return out
+	}
+	newPet, ok := models(fullIR)["NewPetPayload"]
+	if !ok || newPet.Schema.Kind != ir.IRKindObject || len(newPet.Schema.Properties) != 2 {
+		t.Fatalf("expected an object model for the newPet payload, got %+v", newPet)
+	}
+
+	// Models only webhooks use survive per-client pruning; callbacks go with their operation
+	out, err := (&Service{}).filterIR(fullIR, config.Client{ExcludeTags: []string{"subscriptions"}})
+	if err != nil {
+		t.Fatalf("filterIR: %v", err)
+	}
+	if len(out.Webhooks) != 2 {
+		t.Errorf("expected the callback of the excluded operation to be dropped, got %+v", out.Webhooks)
+	}
+	kept := models(out)
+	for _, name := range []string{"NewPetPayload", "Tag", "Pet"} {
+		if _, ok := kept[name]; !ok {
+			t.Errorf("expected %s to be kept for the webhooks", name)
+		}
+	}
+	if _, ok := kept["OnEventPayload"]; ok {
+		t.Error("expected the payload of the dropped callback to be pruned")
+	}
+}
+
+func TestPayloadModelName(t *testing.T) {
+	taken := map[string]bool{"NewPetPayload": true}
+	tests := []struct {
+		name, operation, expected string
+	}{
+		{"order.created", "", "OrderCreatedPayload"},
+		{"newPet", "", "NewPetPayload2"},
+		{"onEvent", "createSubscription", "OnEventPayload"},
+		{"onEvent", "createAlert", "CreateAlertOnEventPayload"},
+	}
+
+	for _, test := range tests {
+		if got := payloadModelName(test.name, test.operation, taken); got != test.expected {
+			t.Errorf("payloadModelName(%q, %q) = %q, expected %q", test.name, test.operation, got, test.expected)
+		}
+	}
+}
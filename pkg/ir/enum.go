@@ -0,0 +1,41 @@
+package ir
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+)
+
+// FormatEnumValue renders a raw enum value without losing precision: floats keep their
+// exact digits (1000000 rather than 1e+06) and booleans render as true/false.
+func FormatEnumValue(v any) string {
+	switch x := v.(type) {
+	case float64:
+		return strconv.FormatFloat(x, 'f', -1, 64)
+	case float32:
+		return strconv.FormatFloat(float64(x), 'f', -1, 32)
+	case json.Number:
+		return x.String()
+	case bool:
+		return strconv.FormatBool(x)
+	default:
+		return fmt.Sprint(x)
+	}
+}
+
+// EnumLiterals returns the enum values of s as unquoted literals. Numeric and boolean
+// enums are rendered from EnumRaw so the original values are preserved; string enums
+// use EnumValues.
+func (s IRSchema) EnumLiterals() []string {
+	switch s.EnumBase {
+	case IRKindNumber, IRKindInteger, IRKindBoolean:
+		if len(s.EnumRaw) > 0 {
+			out := make([]string, 0, len(s.EnumRaw))
+			for _, v := range s.EnumRaw {
+				out = append(out, FormatEnumValue(v))
+			}
+			return out
+		}
+	}
+	return s.EnumValues
+}
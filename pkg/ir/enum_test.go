@@ -0,0 +1,67 @@
+package ir
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestFormatEnumValue(t *testing.T) {
+	tests := []struct {
+		input    any
+		expected string
+	}{
+		{1.5, "1.5"},
+		{float64(1000000), "1000000"},
+		{float64(-3), "-3"},
+		{1e21, "1000000000000000000000"},
+		{int64(9007199254740993), "9007199254740993"},
+		{true, "true"},
+		{"active", "active"},
+	}
+
+	for _, test := range tests {
+		if result := FormatEnumValue(test.input); result != test.expected {
+			t.Errorf("FormatEnumValue(%v) = %q, expected %q", test.input, result, test.expected)
+		}
+	}
+}
+
+func TestEnumLiterals(t *testing.T) {
+	tests := []struct {
+		name     string
+		schema   IRSchema
+		expected []string
+	}{
+		{
+			name:     "mixed numeric",
+			schema:   IRSchema{Kind: IRKindEnum, EnumBase: IRKindNumber, EnumValues: []string{"1.5", "1e+06", "0"}, EnumRaw: []any{1.5, float64(1000000), float64(0)}},
+			expected: []string{"1.5", "1000000", "0"},
+		},
+		{
+			name:     "integer",
+			schema:   IRSchema{Kind: IRKindEnum, EnumBase: IRKindInteger, EnumValues: []string{"1e+06"}, EnumRaw: []any{float64(1000000)}},
+			expected: []string{"1000000"},
+		},
+		{
+			name:     "boolean",
+			schema:   IRSchema{Kind: IRKindEnum, EnumBase: IRKindBoolean, EnumValues: []string{"true", "false"}, EnumRaw: []any{true, false}},
+			expected: []string{"true", "false"},
+		},
+		{
+			name:     "string uses EnumValues",
+			schema:   IRSchema{Kind: IRKindEnum, EnumBase: IRKindString, EnumValues: []string{"1", "a"}, EnumRaw: []any{"1", "a"}},
+			expected: []string{"1", "a"},
+		},
+		{
+			name:     "numeric without raw values",
+			schema:   IRSchema{Kind: IRKindEnum, EnumBase: IRKindInteger, EnumValues: []string{"2"}},
+			expected: []string{"2"},
+		},
+	}
+
+	for _, test := range tests {
+		if result := test.schema.EnumLiterals(); !reflect.DeepEqual(result, test.expected) {
+			t.Errorf("%s: EnumLiterals() = %v, expected %v", test.name, result, test.expected)
+		}
+	}
+}
@@ -1,5 +1,7 @@
 package ir
 
+import "strings"
+
 // IROperation represents a single API operation (endpoint + method)
 type IROperation struct {
 	OperationID  string
@@ -12,8 +14,56 @@ type IROperation struct {
 	Deprecated   bool
 	PathParams   []IRParam
 	QueryParams  []IRParam
-	RequestBody  *IRRequestBody
-	Response     IRResponse
+	RequestBody  *IRRequestBody // The preferred request content, RequestContents[0]
+	// RequestContents holds every media type the request body accepts, preferred first
+	RequestContents []IRRequestBody
+	Response        IRResponse
+	// ErrorResponses lists the documented 4xx/5xx and default responses, ordered as exact
+	// codes, then ranges (4XX), then default
+	ErrorResponses []IRErrorResponse
+	// Servers lists the server URLs declared on the operation, or else on its path, with
+	// variables set to their defaults. Empty when the client base URL applies.
+	Servers []string
+}
+
+// ServerURL returns the server the operation's requests go to instead of the client base URL,
+// or an empty string when there is none. A relative URL ("/v2") is relative to the base URL.
+func (op IROperation) ServerURL() string {
+	if len(op.Servers) == 0 {
+		return ""
+	}
+	return op.Servers[0]
+}
+
+// DeprecatedParams returns the deprecated path and query parameters of the operation, path
+// parameters first, for method docs to call out
+func (op IROperation) DeprecatedParams() []IRParam {
+	var out []IRParam
+	for _, p := range append(append([]IRParam{}, op.PathParams...), op.QueryParams...) {
+		if p.Deprecated {
+			out = append(out, p)
+		}
+	}
+	return out
+}
+
+// PathPrefix returns the IRParam.PathPrefix of the path parameter with the given name, or an
+// empty string when the operation declares no such parameter
+func (op IROperation) PathPrefix(name string) string {
+	if p, ok := op.PathParam(name); ok {
+		return p.PathPrefix()
+	}
+	return ""
+}
+
+// PathParam returns the path parameter with the given name
+func (op IROperation) PathParam(name string) (IRParam, bool) {
+	for _, p := range op.PathParams {
+		if p.Name == name {
+			return p, true
+		}
+	}
+	return IRParam{}, false
 }
 
 // IRService represents a group of operations, typically grouped by tag
@@ -22,6 +72,29 @@ type IRService struct {
 	Operations []IROperation
 }
 
+// UsesFormBody reports whether any operation of the service sends a form-encoded body
+func (s IRService) UsesFormBody() bool {
+	for _, op := range s.Operations {
+		if op.RequestBody.IsFormURLEncoded() {
+			return true
+		}
+	}
+	return false
+}
+
+// UsesJSONQueryParams reports whether any operation of the service has a query parameter
+// sent as JSON
+func (s IRService) UsesJSONQueryParams() bool {
+	for _, op := range s.Operations {
+		for _, p := range op.QueryParams {
+			if p.IsJSON() {
+				return true
+			}
+		}
+	}
+	return false
+}
+
 // IR represents the complete intermediate representation of an OpenAPI spec
 type IR struct {
 	Services        []IRService
@@ -29,6 +102,37 @@ type IR struct {
 	SecuritySchemes []IRSecurityScheme
 	// ModelDefs holds a language-agnostic structured representation of components schemas
 	ModelDefs []IRModelDef
+	// Webhooks lists the requests the API sends to its consumers: OpenAPI 3.1 webhooks, then
+	// operation callbacks. Their payload models are part of ModelDefs.
+	Webhooks []IRWebhook
+	// Servers lists the document's servers in spec order
+	Servers []IRServer
+}
+
+// IRServer is a server of the document, with its variables set to their defaults
+type IRServer struct {
+	URL         string
+	Description string
+}
+
+// IRWebhook represents an inbound request the API makes to the consumer, declared as an
+// OpenAPI 3.1 webhook or as a callback of an operation
+type IRWebhook struct {
+	// Name is the key of the webhook, or of the callback in its operation's callbacks
+	Name string
+	// Operation identifies the operation declaring the callback by its operationId, or by
+	// "METHOD /path" without one; empty for webhooks
+	Operation string
+	// Expression is the callback URL expression, e.g. {$request.body#/callbackUrl}; empty for
+	// webhooks, whose URL is registered out of band
+	Expression  string
+	Method      string
+	Summary     string
+	Description string
+	Deprecated  bool
+	// Payload is the preferred request body content, nil when the request has none. Inline
+	// object, enum and composed schemas are hoisted into a <Name>Payload model it refers to.
+	Payload *IRRequestBody
 }
 
 // IRParam represents a parameter (path or query)
@@ -38,6 +142,60 @@ type IRParam struct {
 	Schema   IRSchema
 	// Description from the OpenAPI parameter
 	Description string
+	// Default is the schema default value, if any (e.g. 20 for ?limit=20)
+	Default any
+	// Examples of the value from the parameter's example/examples, or the schema example
+	Examples []any
+	// ContentType is the media type of a parameter declared with content instead of a schema
+	// (e.g. application/json); empty for schema parameters
+	ContentType string
+	// Deprecated is set from the OpenAPI parameter's deprecated flag
+	Deprecated bool
+	// Style is the serialization style of a path parameter: "simple" (the default, also when
+	// empty), "label" or "matrix"
+	Style string
+	// Explode is the explode flag of a path parameter, which changes how the label and matrix
+	// styles separate the values of an array
+	Explode bool
+}
+
+// Path parameter styles other than the default "simple"
+const (
+	PathStyleLabel  = "label"
+	PathStyleMatrix = "matrix"
+)
+
+// PathPrefix returns what precedes a path parameter's value in the URL for its style: "." for
+// label (/files/.5), ";name=" for matrix (/files/;id=5) and nothing for simple (/files/5)
+func (p IRParam) PathPrefix() string {
+	switch p.Style {
+	case PathStyleLabel:
+		return "."
+	case PathStyleMatrix:
+		return ";" + p.Name + "="
+	}
+	return ""
+}
+
+// PathSeparator returns what separates the values of an array path parameter for its style:
+// "," (/files/1,2), except "." for exploded label (/files/.1.2) and ";name=" for exploded
+// matrix parameters (/files/;id=1;id=2)
+func (p IRParam) PathSeparator() string {
+	if p.Explode {
+		switch p.Style {
+		case PathStyleLabel:
+			return "."
+		case PathStyleMatrix:
+			return ";" + p.Name + "="
+		}
+	}
+	return ","
+}
+
+// IsJSON reports whether the parameter is declared with JSON content, so its value is sent
+// JSON-serialized (e.g. ?filter={"status":"active"})
+func (p IRParam) IsJSON() bool {
+	return p.ContentType == "application/json" || strings.HasSuffix(p.ContentType, "+json")
 }
 
 // IRRequestBody represents a request body
@@ -46,14 +204,104 @@ type IRRequestBody struct {
 	TypeTS      string
 	Required    bool
 	Schema      IRSchema
+	// Examples of the body from the media type's example/examples, or the schema example
+	Examples []any
+}
+
+// ContentTypeFormURLEncoded is the media type of form-encoded request bodies
+const ContentTypeFormURLEncoded = "application/x-www-form-urlencoded"
+
+// IsFormURLEncoded reports whether the body is sent as application/x-www-form-urlencoded
+func (b *IRRequestBody) IsFormURLEncoded() bool {
+	return b != nil && b.ContentType == ContentTypeFormURLEncoded
 }
 
 // IRResponse represents a response
 type IRResponse struct {
 	TypeTS string
 	Schema IRSchema
+	// StatusCode is the success status the response was modeled from ("200", "201", "204", ...),
+	// or "default" when the operation declares no 2xx response and the default one stands in.
+	// It is empty when neither is declared and any 2xx is accepted.
+	StatusCode string
+	// ContentType is the media type the Schema was taken from; empty for void responses
+	ContentType string
+	// Stream is set for binary and event-stream content types (application/octet-stream,
+	// text/event-stream); clients hand such bodies to the caller unread instead of parsing them
+	Stream bool
 	// Description contains the response description chosen for this operation
 	Description string
+	// Headers declared on the chosen response, sorted by name
+	Headers []IRResponseHeader
+	// Examples of the body from the media type's example/examples, or the schema example
+	Examples []any
+	// Contents holds every media type the chosen response can be returned as, preferred
+	// (ContentType) first; clients select one with the Accept header when there are several
+	Contents []IRResponseContent
+}
+
+// IRResponseContent is one media type a response can be returned as
+type IRResponseContent struct {
+	ContentType string
+	Schema      IRSchema
+	// Stream is set like IRResponse.Stream
+	Stream bool
+}
+
+// EventStream reports whether the response is a text/event-stream of server-sent events
+func (r IRResponse) EventStream() bool {
+	return isEventStream(r.ContentType)
+}
+
+// EventStream reports whether the content is a text/event-stream of server-sent events
+func (c IRResponseContent) EventStream() bool {
+	return isEventStream(c.ContentType)
+}
+
+func isEventStream(contentType string) bool {
+	mediaType, _, _ := strings.Cut(contentType, ";")
+	return strings.EqualFold(strings.TrimSpace(mediaType), "text/event-stream")
+}
+
+// Negotiated reports whether the response can be returned as more than one media type, so
+// clients send an Accept header choosing one
+func (r IRResponse) Negotiated() bool {
+	return len(r.Contents) > 1
+}
+
+// AcceptVariants returns the operation once per media type its response can be returned as,
+// preferred first, each with the Response narrowed to that media type. An operation whose
+// response is not negotiated is its only variant.
+func (op IROperation) AcceptVariants() []IROperation {
+	if !op.Response.Negotiated() {
+		return []IROperation{op}
+	}
+	out := make([]IROperation, len(op.Response.Contents))
+	for i, content := range op.Response.Contents {
+		variant := op
+		variant.Response.ContentType = content.ContentType
+		variant.Response.Schema = content.Schema
+		variant.Response.Stream = content.Stream
+		out[i] = variant
+	}
+	return out
+}
+
+// IRErrorResponse represents a documented error response of an operation
+type IRErrorResponse struct {
+	// StatusCode as written in the spec: an exact code ("404"), a range ("4XX") or "default"
+	StatusCode  string
+	Description string
+	// Schema of the JSON body; Kind is unknown when the response documents no JSON body
+	Schema IRSchema
+}
+
+// IRResponseHeader represents a header declared on a response (e.g. X-Total-Count)
+type IRResponseHeader struct {
+	Name        string
+	Schema      IRSchema
+	Description string
+	Required    bool
 }
 
 // IRModel represents a generated model (legacy, kept for compatibility)
@@ -110,6 +358,17 @@ type IRSchema struct {
 	// Object
 	Properties           []IRField
 	AdditionalProperties *IRSchema // typed maps; nil when absent
+	// MinProperties and MaxProperties bound the number of properties an object may hold;
+	// nil when absent
+	MinProperties *uint64
+	MaxProperties *uint64
+
+	// Number and integer
+	// ExclusiveMinimum and ExclusiveMaximum are bounds a value must be strictly greater or
+	// less than, and a value must be a multiple of MultipleOf; nil when absent
+	ExclusiveMinimum *float64
+	ExclusiveMaximum *float64
+	MultipleOf       *float64
 
 	// Array
 	Items *IRSchema
@@ -118,6 +377,9 @@ type IRSchema struct {
 	EnumValues []string     // stringified values for portability
 	EnumRaw    []any        // original values preserving type where possible
 	EnumBase   IRSchemaKind // underlying base kind: string, number, integer, boolean, unknown
+	// EnumNames are member names from x-enum-varnames (or x-enumNames), one per value; nil
+	// when absent or when their count does not match the values
+	EnumNames []string
 
 	// Ref (component name or canonical name)
 	Ref string
@@ -130,6 +392,13 @@ type IRSchema struct {
 
 	// Polymorphism
 	Discriminator *IRDiscriminator
+
+	// TypeOverrides maps a language ("go", "ts", "python") to a native type from the
+	// x-go-type, x-ts-type or x-python-type extension, emitted instead of the inferred type
+	TypeOverrides map[string]string
+	// TypeImports maps a language ("go", "python") to the import its override needs, from
+	// the x-go-import or x-python-import extension
+	TypeImports map[string]string
 }
 
 // IRField represents a field in an object schema
@@ -141,6 +410,12 @@ type IRField struct {
 	Annotations IRAnnotations
 }
 
+// Sensitive reports whether the field holds a secret to keep out of logs and debug output: a
+// password (format: password) or a writeOnly value
+func (f IRField) Sensitive() bool {
+	return f.Annotations.WriteOnly || (f.Type != nil && f.Type.Format == "password")
+}
+
 // IRDiscriminator represents polymorphism discriminator information
 type IRDiscriminator struct {
 	PropertyName string
@@ -0,0 +1,45 @@
+package ir
+
+import "testing"
+
+func TestUsesFormBody(t *testing.T) {
+	form := &IRRequestBody{ContentType: ContentTypeFormURLEncoded}
+	json := &IRRequestBody{ContentType: "application/json"}
+	tests := []struct {
+		name     string
+		ops      []IROperation
+		expected bool
+	}{
+		{"no body", []IROperation{{}}, false},
+		{"json body", []IROperation{{RequestBody: json}}, false},
+		{"form body", []IROperation{{RequestBody: json}, {RequestBody: form}}, true},
+	}
+
+	for _, test := range tests {
+		if got := (IRService{Operations: test.ops}).UsesFormBody(); got != test.expected {
+			t.Errorf("%s: UsesFormBody() = %v, expected %v", test.name, got, test.expected)
+		}
+	}
+}
+
+func TestUsesJSONQueryParams(t *testing.T) {
+	str := IRSchema{Kind: IRKindString}
+	tests := []struct {
+		name     string
+		params   []IRParam
+		expected bool
+	}{
+		{"no params", nil, false},
+		{"schema param", []IRParam{{Name: "q", Schema: str}}, false},
+		{"json param", []IRParam{{Name: "q", Schema: str}, {Name: "filter", ContentType: "application/json"}}, true},
+		{"json suffix", []IRParam{{Name: "filter", ContentType: "application/vnd.api+json"}}, true},
+		{"other content", []IRParam{{Name: "filter", ContentType: "text/plain"}}, false},
+	}
+
+	for _, test := range tests {
+		svc := IRService{Operations: []IROperation{{QueryParams: test.params}}}
+		if got := svc.UsesJSONQueryParams(); got != test.expected {
+			t.Errorf("%s: UsesJSONQueryParams() = %v, expected %v", test.name, got, test.expected)
+		}
+	}
+}
@@ -0,0 +1,91 @@
+package ir
+
+import "sort"
+
+// WalkSchemas calls fn for every schema in the IR, nested schemas included: model definitions,
+// parameters, request bodies, responses with their headers, error responses and webhook payloads
+func (in IR) WalkSchemas(fn func(IRSchema)) {
+	for _, md := range in.ModelDefs {
+		walkSchema(md.Schema, fn)
+	}
+	for _, service := range in.Services {
+		for _, op := range service.Operations {
+			for _, p := range op.PathParams {
+				walkSchema(p.Schema, fn)
+			}
+			for _, p := range op.QueryParams {
+				walkSchema(p.Schema, fn)
+			}
+			if op.RequestBody != nil {
+				walkSchema(op.RequestBody.Schema, fn)
+			}
+			for _, body := range op.RequestContents {
+				walkSchema(body.Schema, fn)
+			}
+			walkSchema(op.Response.Schema, fn)
+			for _, content := range op.Response.Contents {
+				walkSchema(content.Schema, fn)
+			}
+			for _, h := range op.Response.Headers {
+				walkSchema(h.Schema, fn)
+			}
+			for _, er := range op.ErrorResponses {
+				walkSchema(er.Schema, fn)
+			}
+		}
+	}
+	for _, wh := range in.Webhooks {
+		if wh.Payload != nil {
+			walkSchema(wh.Payload.Schema, fn)
+		}
+	}
+}
+
+func walkSchema(s IRSchema, fn func(IRSchema)) {
+	fn(s)
+	for _, sub := range []*IRSchema{s.Items, s.AdditionalProperties, s.Not} {
+		if sub != nil {
+			walkSchema(*sub, fn)
+		}
+	}
+	for _, list := range [][]*IRSchema{s.OneOf, s.AnyOf, s.AllOf} {
+		for _, sub := range list {
+			if sub != nil {
+				walkSchema(*sub, fn)
+			}
+		}
+	}
+	for _, f := range s.Properties {
+		if f.Type != nil {
+			walkSchema(*f.Type, fn)
+		}
+	}
+}
+
+// Uses reports whether s or any schema nested in it has the given kind. Refs are not followed.
+func (s IRSchema) Uses(kind IRSchemaKind) bool {
+	found := false
+	walkSchema(s, func(sub IRSchema) {
+		if sub.Kind == kind {
+			found = true
+		}
+	})
+	return found
+}
+
+// TypeImports returns the imports needed by the type overrides for the given language ("go",
+// "ts", "python") used anywhere in the IR, sorted and without duplicates
+func (in IR) TypeImports(lang string) []string {
+	seen := map[string]bool{}
+	in.WalkSchemas(func(s IRSchema) {
+		if imp := s.TypeImports[lang]; imp != "" {
+			seen[imp] = true
+		}
+	})
+	out := make([]string, 0, len(seen))
+	for imp := range seen {
+		out = append(out, imp)
+	}
+	sort.Strings(out)
+	return out
+}
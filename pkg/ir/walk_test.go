@@ -0,0 +1,57 @@
+package ir
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestTypeImports(t *testing.T) {
+	uuid := IRSchema{Kind: IRKindString, TypeOverrides: map[string]string{"go": "uuid.UUID"}, TypeImports: map[string]string{"go": "github.com/google/uuid"}}
+	decimal := IRSchema{Kind: IRKindString, TypeOverrides: map[string]string{"go": "decimal.Decimal"}, TypeImports: map[string]string{"go": "github.com/shopspring/decimal"}}
+	luxon := IRSchema{Kind: IRKindString, TypeOverrides: map[string]string{"ts": "DateTime"}, TypeImports: map[string]string{"ts": `import type { DateTime } from "luxon";`}}
+	in := IR{
+		ModelDefs: []IRModelDef{{Name: "Account", Schema: IRSchema{Kind: IRKindObject, Properties: []IRField{
+			{Name: "owners", Type: &IRSchema{Kind: IRKindArray, Items: &uuid}},
+		}}}},
+		Services: []IRService{{Operations: []IROperation{{
+			QueryParams:    []IRParam{{Name: "id", Schema: uuid}},
+			Response:       IRResponse{Headers: []IRResponseHeader{{Name: "X-Updated", Schema: luxon}}},
+			ErrorResponses: []IRErrorResponse{{StatusCode: "400", Schema: IRSchema{Kind: IRKindOneOf, OneOf: []*IRSchema{&decimal}}}},
+		}}}},
+	}
+
+	tests := []struct {
+		lang     string
+		expected []string
+	}{
+		{"go", []string{"github.com/google/uuid", "github.com/shopspring/decimal"}},
+		{"ts", []string{`import type { DateTime } from "luxon";`}},
+		{"python", []string{}},
+	}
+	for _, test := range tests {
+		if got := in.TypeImports(test.lang); strings.Join(got, ",") != strings.Join(test.expected, ",") {
+			t.Errorf("TypeImports(%q) = %v, expected %v", test.lang, got, test.expected)
+		}
+	}
+}
+
+func TestUses(t *testing.T) {
+	not := IRSchema{Kind: IRKindNot, Not: &IRSchema{Kind: IRKindString}}
+	tests := []struct {
+		name     string
+		schema   IRSchema
+		expected bool
+	}{
+		{"itself", not, true},
+		{"array items", IRSchema{Kind: IRKindArray, Items: &not}, true},
+		{"object field", IRSchema{Kind: IRKindObject, Properties: []IRField{{Name: "a", Type: &not}}}, true},
+		{"oneOf member", IRSchema{Kind: IRKindOneOf, OneOf: []*IRSchema{{Kind: IRKindString}, &not}}, true},
+		{"ref", IRSchema{Kind: IRKindRef, Ref: "Forbidden"}, false},
+		{"plain object", IRSchema{Kind: IRKindObject, Properties: []IRField{{Name: "a", Type: &IRSchema{Kind: IRKindString}}}}, false},
+	}
+	for _, test := range tests {
+		if got := test.schema.Uses(IRKindNot); got != test.expected {
+			t.Errorf("%s: Uses(not) = %v, expected %v", test.name, got, test.expected)
+		}
+	}
+}
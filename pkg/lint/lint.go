@@ -0,0 +1,309 @@
+// Package lint reports OpenAPI spec problems that affect the generated SDKs, such as
+// operations without an operationId or schemas that cannot be typed. It works on the IR,
+// so it sees the spec the way the generators do.
+package lint
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/blimu-dev/sdk-gen/pkg/config"
+	"github.com/blimu-dev/sdk-gen/pkg/ir"
+	"github.com/blimu-dev/sdk-gen/pkg/utils"
+)
+
+// Severity ranks how badly an issue affects the generated SDKs
+type Severity string
+
+const (
+	// SeverityError marks issues that produce broken or colliding code
+	SeverityError Severity = "error"
+	// SeverityWarning marks issues that produce valid but poorly typed or poorly named code
+	SeverityWarning Severity = "warning"
+)
+
+// Issue codes reported by Lint
+const (
+	CodeMissingOperationID   = "missing-operation-id"
+	CodeDuplicateOperationID = "duplicate-operation-id"
+	CodeUntaggedOperation    = "untagged-operation"
+	CodeEnumCollision        = "enum-collision"
+	CodeUntypedSchema        = "untyped-schema"
+	CodeUnsupportedNot       = "unsupported-not"
+	// CodeInvalidSpec is not reported by Lint but by the validate command, for specs that fail
+	// to load or to validate
+	CodeInvalidSpec = "invalid-spec"
+)
+
+// Issue is a single lint finding
+type Issue struct {
+	Severity Severity `json:"severity"`
+	Code     string   `json:"code"`
+	// Location points at the offending operation ("GET /users") or schema ("User.status")
+	Location string `json:"location"`
+	Message  string `json:"message"`
+	// File and Line point at the spec source, when known (see Report.Locate)
+	File string `json:"file,omitempty"`
+	Line int    `json:"line,omitempty"`
+}
+
+// position returns where the issue is for text output: file:line, then the location
+func (i Issue) position() string {
+	var parts []string
+	if i.File != "" {
+		if i.Line > 0 {
+			parts = append(parts, fmt.Sprintf("%s:%d", i.File, i.Line))
+		} else {
+			parts = append(parts, i.File)
+		}
+	}
+	if i.Location != "" {
+		parts = append(parts, i.Location)
+	}
+	return strings.Join(parts, ": ")
+}
+
+// Report holds the issues found in a spec, errors first
+type Report struct {
+	Issues []Issue `json:"issues"`
+}
+
+// Count returns the number of issues with the given severity
+func (r Report) Count(severity Severity) int {
+	n := 0
+	for _, issue := range r.Issues {
+		if issue.Severity == severity {
+			n++
+		}
+	}
+	return n
+}
+
+// HasErrors reports whether the report contains any error-level issue
+func (r Report) HasErrors() bool {
+	return r.Count(SeverityError) > 0
+}
+
+// WriteText writes the report as one line per issue followed by a summary
+func (r Report) WriteText(w io.Writer) error {
+	for _, issue := range r.Issues {
+		if _, err := fmt.Fprintf(w, "%s\t%s\t%s: %s\n", issue.Severity, issue.Code, issue.position(), issue.Message); err != nil {
+			return err
+		}
+	}
+	_, err := fmt.Fprintf(w, "%d error(s), %d warning(s)\n", r.Count(SeverityError), r.Count(SeverityWarning))
+	return err
+}
+
+// WriteJSON writes the report as an indented JSON document
+func (r Report) WriteJSON(w io.Writer) error {
+	if r.Issues == nil {
+		r.Issues = []Issue{}
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(r)
+}
+
+// Locate returns the report with the File of every issue set to file and, where lines finds
+// the operation or schema the issue's Location names, its Line. lines looks up the line of a
+// key path in the spec, see openapi.SourceLines.
+func (r Report) Locate(file string, lines func(path ...string) int) Report {
+	issues := make([]Issue, len(r.Issues))
+	for i, issue := range r.Issues {
+		issue.File = file
+		for _, path := range sourcePaths(issue.Location) {
+			if issue.Line = lines(path...); issue.Line > 0 {
+				break
+			}
+		}
+		issues[i] = issue
+	}
+	return Report{Issues: issues}
+}
+
+// sourcePaths returns the spec key paths a location may point at, most specific first: the
+// operation of "GET /users query parameter page", or the schema and nested properties of
+// "User.address.city" under OpenAPI 3 components or Swagger 2.0 definitions
+func sourcePaths(location string) [][]string {
+	if method, rest, ok := strings.Cut(location, " "); ok && strings.ToUpper(method) == method && strings.HasPrefix(rest, "/") {
+		path, _, _ := strings.Cut(rest, " ")
+		return [][]string{{"paths", strings.TrimSuffix(path, ","), strings.ToLower(method)}}
+	}
+	const separators = ".[{( "
+	end := strings.IndexAny(location, separators)
+	if end < 0 {
+		end = len(location)
+	}
+	var properties []string
+	for rest := location[end:]; strings.HasPrefix(rest, "."); {
+		rest = rest[1:]
+		n := strings.IndexAny(rest, separators)
+		if n < 0 {
+			n = len(rest)
+		}
+		properties = append(properties, rest[:n])
+		rest = rest[n:]
+	}
+
+	var paths [][]string
+	for _, base := range [][]string{{"components", "schemas", location[:end]}, {"definitions", location[:end]}} {
+		for n := len(properties); n >= 0; n-- {
+			path := append([]string{}, base...)
+			for _, p := range properties[:n] {
+				path = append(path, "properties", p)
+			}
+			paths = append(paths, path)
+		}
+	}
+	return paths
+}
+
+// Lint checks an IR (as built by generator.BuildIR) for problems that affect SDK generation
+func Lint(in ir.IR) Report {
+	l := &linter{}
+	l.operations(in.Services)
+	for _, md := range in.ModelDefs {
+		l.schema(md.Name, md.Schema)
+	}
+
+	sort.SliceStable(l.issues, func(i, j int) bool {
+		a, b := l.issues[i], l.issues[j]
+		if a.Severity != b.Severity {
+			return a.Severity == SeverityError
+		}
+		if a.Code != b.Code {
+			return a.Code < b.Code
+		}
+		return a.Location < b.Location
+	})
+	return Report{Issues: l.issues}
+}
+
+type linter struct {
+	issues []Issue
+}
+
+func (l *linter) report(severity Severity, code, location, format string, args ...any) {
+	l.issues = append(l.issues, Issue{Severity: severity, Code: code, Location: location, Message: fmt.Sprintf(format, args...)})
+}
+
+func (l *linter) operations(services []ir.IRService) {
+	byID := map[string][]string{}
+	for _, service := range services {
+		for _, op := range service.Operations {
+			location := op.Method + " " + op.Path
+			if op.OperationID == "" {
+				l.report(SeverityWarning, CodeMissingOperationID, location,
+					"operation has no operationId; its method name is derived from the path and may change")
+			} else {
+				byID[op.OperationID] = append(byID[op.OperationID], location)
+			}
+			// Untagged operations get the default untagged tag when the IR is built
+			if len(op.OriginalTags) == 1 && op.OriginalTags[0] == config.DefaultUntaggedTag {
+				l.report(SeverityWarning, CodeUntaggedOperation, location,
+					"operation has no tags and is generated into the %s service", config.DefaultUntaggedTag)
+			}
+
+			for _, p := range op.PathParams {
+				l.schema(location+" path parameter "+p.Name, p.Schema)
+			}
+			for _, p := range op.QueryParams {
+				l.schema(location+" query parameter "+p.Name, p.Schema)
+			}
+			// Multipart bodies are intentionally untyped
+			if op.RequestBody != nil && op.RequestBody.ContentType != "multipart/form-data" {
+				l.schema(location+" request body", op.RequestBody.Schema)
+			}
+			// The first content is RequestBody; alternatives are named by media type
+			for i, body := range op.RequestContents {
+				if i > 0 && body.ContentType != "multipart/form-data" {
+					l.schema(location+" request body ("+body.ContentType+")", body.Schema)
+				}
+			}
+			if op.Response.TypeTS == "" {
+				l.schema(location+" response", op.Response.Schema)
+			}
+			for i, content := range op.Response.Contents {
+				if i > 0 {
+					l.schema(location+" response ("+content.ContentType+")", content.Schema)
+				}
+			}
+		}
+	}
+
+	ids := make([]string, 0, len(byID))
+	for id := range byID {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	for _, id := range ids {
+		if locations := byID[id]; len(locations) > 1 {
+			l.report(SeverityError, CodeDuplicateOperationID, strings.Join(locations, ", "),
+				"operationId %q is used by %d operations", id, len(locations))
+		}
+	}
+}
+
+// schema checks a schema and its nested schemas for untyped parts, ignored not constraints
+// and colliding enum values
+func (l *linter) schema(location string, s ir.IRSchema) {
+	switch s.Kind {
+	case ir.IRKindUnknown, "":
+		l.report(SeverityWarning, CodeUntypedSchema, location,
+			"schema has no type and is generated as unknown/any/interface{}")
+		return
+	case ir.IRKindNot:
+		l.report(SeverityWarning, CodeUnsupportedNot, location,
+			"schema uses not, which the generated types cannot express; the constraint is ignored")
+		return
+	case ir.IRKindEnum:
+		l.enum(location, s)
+	case ir.IRKindArray:
+		if s.Items != nil {
+			l.schema(location+"[]", *s.Items)
+		}
+	}
+	for _, f := range s.Properties {
+		if f.Type != nil {
+			l.schema(location+"."+f.Name, *f.Type)
+		}
+	}
+	if s.AdditionalProperties != nil {
+		l.schema(location+"{}", *s.AdditionalProperties)
+	}
+	for _, variants := range [][]*ir.IRSchema{s.OneOf, s.AnyOf, s.AllOf} {
+		for i, sub := range variants {
+			if sub != nil {
+				l.schema(fmt.Sprintf("%s(%s %d)", location, s.Kind, i), *sub)
+			}
+		}
+	}
+}
+
+// enum reports string enum values that map to the same member name once sanitized
+// (e.g. "in-review" and "in_review" both become IN_REVIEW). Generators keep them apart with a
+// numeric suffix, which is easy to mix up, so it is a warning.
+func (l *linter) enum(location string, s ir.IRSchema) {
+	if s.EnumBase != ir.IRKindString {
+		return
+	}
+	byName := map[string][]string{}
+	var names []string
+	for _, v := range s.EnumValues {
+		name := utils.EnumMemberNames([]string{v}, utils.EnumNameUpperSnake)[0]
+		if _, ok := byName[name]; !ok {
+			names = append(names, name)
+		}
+		byName[name] = append(byName[name], v)
+	}
+	for _, name := range names {
+		if values := byName[name]; len(values) > 1 {
+			l.report(SeverityWarning, CodeEnumCollision, location,
+				"enum values %q all map to the member name %s; all but the first get a numeric suffix", values, name)
+		}
+	}
+}
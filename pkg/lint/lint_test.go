@@ -0,0 +1,135 @@
+package lint
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/blimu-dev/sdk-gen/pkg/ir"
+)
+
+func TestLint(t *testing.T) {
+	str := ir.IRSchema{Kind: ir.IRKindString}
+	in := ir.IR{
+		Services: []ir.IRService{
+			{Tag: "misc", Operations: []ir.IROperation{
+				{Method: "GET", Path: "/health", OriginalTags: []string{"misc"}, Response: ir.IRResponse{Schema: str}},
+			}},
+			{Tag: "users", Operations: []ir.IROperation{
+				{OperationID: "getUser", Method: "GET", Path: "/users/{id}", OriginalTags: []string{"users"},
+					PathParams: []ir.IRParam{{Name: "id", Schema: str}}, Response: ir.IRResponse{Schema: ir.IRSchema{Kind: ir.IRKindRef, Ref: "User"}}},
+				{OperationID: "getUser", Method: "GET", Path: "/users/me", OriginalTags: []string{"users"},
+					Response: ir.IRResponse{Schema: ir.IRSchema{Kind: ir.IRKindUnknown}}},
+				{OperationID: "upload", Method: "POST", Path: "/users/avatar", OriginalTags: []string{"users"},
+					RequestBody: &ir.IRRequestBody{ContentType: "multipart/form-data", Schema: ir.IRSchema{Kind: ir.IRKindUnknown}}, Response: ir.IRResponse{TypeTS: "void"}},
+			}},
+		},
+		ModelDefs: []ir.IRModelDef{
+			{Name: "User", Schema: ir.IRSchema{Kind: ir.IRKindObject, Properties: []ir.IRField{
+				{Name: "name", Type: &str},
+				{Name: "meta", Type: &ir.IRSchema{Kind: ir.IRKindUnknown}},
+				{Name: "nickname", Type: &ir.IRSchema{Kind: ir.IRKindNot, Not: &ir.IRSchema{Kind: ir.IRKindUnknown}}},
+			}}},
+			{Name: "Status", Schema: ir.IRSchema{Kind: ir.IRKindEnum, EnumBase: ir.IRKindString, EnumValues: []string{"in-review", "in_review", "done"}}},
+		},
+	}
+
+	report := Lint(in)
+	expected := []struct {
+		severity Severity
+		code     string
+		location string
+	}{
+		{SeverityError, CodeDuplicateOperationID, "GET /users/{id}, GET /users/me"},
+		{SeverityWarning, CodeEnumCollision, "Status"},
+		{SeverityWarning, CodeMissingOperationID, "GET /health"},
+		{SeverityWarning, CodeUnsupportedNot, "User.nickname"},
+		{SeverityWarning, CodeUntaggedOperation, "GET /health"},
+		{SeverityWarning, CodeUntypedSchema, "GET /users/me response"},
+		{SeverityWarning, CodeUntypedSchema, "User.meta"},
+	}
+	if len(report.Issues) != len(expected) {
+		t.Fatalf("expected %d issues, got %+v", len(expected), report.Issues)
+	}
+	for i, e := range expected {
+		issue := report.Issues[i]
+		if issue.Severity != e.severity || issue.Code != e.code || issue.Location != e.location {
+			t.Errorf("issue %d = %+v, expected %s %s at %q", i, issue, e.severity, e.code, e.location)
+		}
+	}
+	if !report.HasErrors() || report.Count(SeverityWarning) != 6 {
+		t.Errorf("unexpected counts: %d errors, %d warnings", report.Count(SeverityError), report.Count(SeverityWarning))
+	}
+}
+
+func TestReportWriters(t *testing.T) {
+	report := Report{Issues: []Issue{{Severity: SeverityWarning, Code: CodeUntypedSchema, Location: "User.meta", Message: "untyped"}}}
+
+	var text bytes.Buffer
+	if err := report.WriteText(&text); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(text.String(), "warning\tuntyped-schema\tUser.meta: untyped\n") || !strings.HasSuffix(text.String(), "0 error(s), 1 warning(s)\n") {
+		t.Errorf("unexpected text report:\n%s", text.String())
+	}
+
+	var out bytes.Buffer
+	if err := (Report{}).WriteJSON(&out); err != nil {
+		t.Fatal(err)
+	}
+	var decoded map[string][]Issue
+	if err := json.Unmarshal(out.Bytes(), &decoded); err != nil || decoded["issues"] == nil {
+		t.Errorf("expected an empty issues array, got %s (%v)", out.String(), err)
+	}
+}
+
+func TestLocate(t *testing.T) {
+	lines := map[string]int{
+		"paths /users get":                           4,
+		"components schemas User":                    10,
+		"components schemas User properties address": 12,
+		"definitions Pet":                            20,
+	}
+	report := Report{Issues: []Issue{
+		{Location: "GET /users query parameter page"},
+		{Location: "GET /users, POST /users"},
+		{Location: "User.address.city[]"},
+		{Location: "User(oneOf 1)"},
+		{Location: "Pet.tags"},
+		{Location: "Order"},
+	}}.Locate("spec.yaml", func(path ...string) int { return lines[strings.Join(path, " ")] })
+
+	expected := []int{4, 4, 12, 10, 20, 0}
+	for i, issue := range report.Issues {
+		if issue.File != "spec.yaml" || issue.Line != expected[i] {
+			t.Errorf("%s: located at %s:%d, expected spec.yaml:%d", issue.Location, issue.File, issue.Line, expected[i])
+		}
+	}
+}
+
+func TestWriteSARIF(t *testing.T) {
+	report := Report{Issues: []Issue{
+		{Severity: SeverityError, Code: CodeInvalidSpec, File: "spec.yaml", Message: "invalid"},
+		{Severity: SeverityWarning, Code: CodeUntypedSchema, Location: "User.meta", Message: "untyped", File: "spec.yaml", Line: 12},
+	}}
+	var out bytes.Buffer
+	if err := report.WriteSARIF(&out); err != nil {
+		t.Fatal(err)
+	}
+	var log sarifLog
+	if err := json.Unmarshal(out.Bytes(), &log); err != nil || log.Version != "2.1.0" || len(log.Runs) != 1 {
+		t.Fatalf("unexpected SARIF log %s (%v)", out.String(), err)
+	}
+	run := log.Runs[0]
+	if len(run.Tool.Driver.Rules) != 2 || run.Tool.Driver.Rules[0].ID != CodeInvalidSpec {
+		t.Errorf("unexpected rules %+v", run.Tool.Driver.Rules)
+	}
+	if len(run.Results) != 2 || run.Results[0].Level != "error" || run.Results[0].Locations[0].PhysicalLocation.Region != nil {
+		t.Fatalf("unexpected results %+v", run.Results)
+	}
+	untyped := run.Results[1].Locations[0]
+	if untyped.PhysicalLocation.Region.StartLine != 12 || untyped.LogicalLocations[0].FullyQualifiedName != "User.meta" {
+		t.Errorf("unexpected location %+v", untyped)
+	}
+}
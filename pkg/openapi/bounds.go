@@ -0,0 +1,59 @@
+package openapi
+
+import (
+	"bytes"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Extensions the numeric OpenAPI 3.1 exclusiveMinimum and exclusiveMaximum are moved to.
+// kin-openapi only knows the 3.0 boolean form and fails to parse a number there.
+const (
+	ExclusiveMinimumExtension = "x-sdk-exclusive-minimum"
+	ExclusiveMaximumExtension = "x-sdk-exclusive-maximum"
+)
+
+// exclusiveBoundExtensions maps the exclusive bound keywords to their extension
+var exclusiveBoundExtensions = map[string]string{
+	"exclusiveMinimum": ExclusiveMinimumExtension,
+	"exclusiveMaximum": ExclusiveMaximumExtension,
+}
+
+// normalizeExclusiveBounds renames numeric exclusiveMinimum and exclusiveMaximum keywords of
+// a raw JSON or YAML spec to their extension, so the document can be parsed. The 3.0 boolean
+// form is left alone, and data is returned unchanged when it has no numeric bound.
+func normalizeExclusiveBounds(data []byte) ([]byte, error) {
+	if !bytes.Contains(data, []byte("exclusiveM")) {
+		return data, nil
+	}
+	var root yaml.Node
+	if err := yaml.Unmarshal(data, &root); err != nil {
+		// Left for the loader to report
+		return data, nil
+	}
+	if !renameNumericBounds(&root) {
+		return data, nil
+	}
+	return yaml.Marshal(&root)
+}
+
+// renameNumericBounds renames the numeric exclusive bounds under n, reporting whether it
+// found any
+func renameNumericBounds(n *yaml.Node) bool {
+	renamed := false
+	if n.Kind == yaml.MappingNode {
+		for i := 0; i+1 < len(n.Content); i += 2 {
+			key, value := n.Content[i], n.Content[i+1]
+			if ext, ok := exclusiveBoundExtensions[key.Value]; ok && value.Kind == yaml.ScalarNode && (value.Tag == "!!int" || value.Tag == "!!float") {
+				key.Value = ext
+				renamed = true
+			}
+		}
+	}
+	for _, child := range n.Content {
+		if renameNumericBounds(child) {
+			renamed = true
+		}
+	}
+	return renamed
+}
@@ -0,0 +1,26 @@
+package openapi
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestNormalizeExclusiveBounds(t *testing.T) {
+	spec := `{"openapi": "3.1.0", "components": {"schemas": {"Payment": {"type": "object", "properties": {
+  "amount": {"type": "number", "exclusiveMinimum": 0, "exclusiveMaximum": 1e6},
+  "discount": {"type": "number", "maximum": 100, "exclusiveMaximum": true}}}}}}`
+	out, err := normalizeExclusiveBounds([]byte(spec))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{ExclusiveMinimumExtension + `": 0`, ExclusiveMaximumExtension + `": 1e6`, `"exclusiveMaximum": true`} {
+		if !strings.Contains(string(out), want) {
+			t.Errorf("expected %s in\n%s", want, out)
+		}
+	}
+
+	boolean := `{"openapi": "3.0.3", "components": {"schemas": {"N": {"type": "number", "minimum": 0, "exclusiveMinimum": true}}}}`
+	if out, err := normalizeExclusiveBounds([]byte(boolean)); err != nil || string(out) != boolean {
+		t.Errorf("expected the 3.0 form to be left alone, got %s (%v)", out, err)
+	}
+}
@@ -0,0 +1,289 @@
+package openapi
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+// CacheOptions configures the on-disk spec cache
+type CacheOptions struct {
+	// Dir is the cache directory; defaults to <user cache dir>/sdk-gen
+	Dir string
+	// Disabled bypasses the cache entirely (no reads or writes)
+	Disabled bool
+	// Headers are sent when fetching an HTTP(S) spec, only to the spec's own host
+	Headers map[string]string
+	// Context bounds the HTTP(S) requests; defaults to context.Background()
+	Context context.Context
+}
+
+// cacheEntry is the metadata stored next to a cached document
+type cacheEntry struct {
+	// Input is the spec path or URL the entry was created for
+	Input string `json:"input"`
+	// ContentHash is the sha256 of the raw spec bytes
+	ContentHash string `json:"contentHash"`
+	// ETag is the HTTP entity tag returned when the spec was fetched
+	ETag string `json:"etag,omitempty"`
+	// ModTime (in Unix nanoseconds) and Size describe a local spec file when it was read, so
+	// an unchanged file is served from the cache without reading it
+	ModTime int64 `json:"modTime,omitempty"`
+	Size    int64 `json:"size,omitempty"`
+	// Validated is true when the document passed validation
+	Validated bool `json:"validated,omitempty"`
+	// ValidationError holds the validation failure message, if validation failed
+	ValidationError string `json:"validationError,omitempty"`
+}
+
+// specCache stores parsed documents keyed by the spec location
+type specCache struct {
+	dir string
+}
+
+// newSpecCache returns a cache for the given options, or nil when caching is disabled
+func newSpecCache(opts CacheOptions) *specCache {
+	if opts.Disabled || os.Getenv("SDKGEN_NO_CACHE") != "" {
+		return nil
+	}
+	dir := opts.Dir
+	if dir == "" {
+		base, err := os.UserCacheDir()
+		if err != nil {
+			return nil
+		}
+		dir = filepath.Join(base, "sdk-gen")
+	}
+	return &specCache{dir: dir}
+}
+
+// paths returns the metadata and document file paths for an input
+func (c *specCache) paths(input string) (meta, doc string) {
+	sum := sha256.Sum256([]byte(input))
+	key := hex.EncodeToString(sum[:])
+	return filepath.Join(c.dir, key+".meta.json"), filepath.Join(c.dir, key+".spec.json")
+}
+
+// read returns the cached entry and serialized document for an input, if present
+func (c *specCache) read(input string) (*cacheEntry, []byte) {
+	metaPath, docPath := c.paths(input)
+	metaData, err := os.ReadFile(metaPath)
+	if err != nil {
+		return nil, nil
+	}
+	var entry cacheEntry
+	if err := json.Unmarshal(metaData, &entry); err != nil || entry.Input != input {
+		return nil, nil
+	}
+	docData, err := os.ReadFile(docPath)
+	if err != nil {
+		return nil, nil
+	}
+	return &entry, docData
+}
+
+// write stores the entry and the serialized document. Failures are ignored since the
+// cache is only an optimization.
+func (c *specCache) write(entry cacheEntry, doc *openapi3.T) {
+	docData, err := json.Marshal(doc)
+	if err != nil {
+		return
+	}
+	c.writeRaw(entry, docData)
+}
+
+// writeRaw stores the entry and an already serialized document
+func (c *specCache) writeRaw(entry cacheEntry, docData []byte) {
+	if err := os.MkdirAll(c.dir, 0o755); err != nil {
+		return
+	}
+	metaData, err := json.Marshal(entry)
+	if err != nil {
+		return
+	}
+	metaPath, docPath := c.paths(entry.Input)
+	if err := os.WriteFile(docPath, docData, 0o644); err != nil {
+		return
+	}
+	_ = os.WriteFile(metaPath, metaData, 0o644)
+}
+
+// LoadDocumentCached loads an OpenAPI document like LoadDocument, reusing a cached parse
+// when the spec is unchanged. Local files whose modification time and size match the entry
+// are not read at all, and otherwise keyed by content hash; HTTP(S) specs are revalidated
+// with If-None-Match when an ETag was previously returned.
+func LoadDocumentCached(input string, opts CacheOptions) (*openapi3.T, error) {
+	doc, _, err := loadCached(input, opts)
+	return doc, err
+}
+
+// ValidateDocumentCached validates an OpenAPI document, reusing a cached validation
+// result when the spec is unchanged
+func ValidateDocumentCached(input string, opts CacheOptions) error {
+	doc, entry, err := loadCached(input, opts)
+	if err != nil {
+		return err
+	}
+	if entry != nil && entry.Validated {
+		return nil
+	}
+	if entry != nil && entry.ValidationError != "" {
+		return errors.New(entry.ValidationError)
+	}
+	ctx := opts.Context
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	verr := doc.Validate(ctx)
+	if cache := newSpecCache(opts); cache != nil && entry != nil {
+		entry.Validated = verr == nil
+		if verr != nil {
+			entry.ValidationError = verr.Error()
+		}
+		cache.write(*entry, doc)
+	}
+	return verr
+}
+
+// loadCached loads the document and returns the cache entry describing it (nil when caching is disabled)
+func loadCached(input string, opts CacheOptions) (*openapi3.T, *cacheEntry, error) {
+	ctx := opts.Context
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	client := HeaderClient(input, opts.Headers)
+	loader := NewLoader(ctx, client)
+	cache := newSpecCache(opts)
+	if cache == nil {
+		doc, err := LoadDocumentWithLoader(loader, input)
+		return doc, nil, err
+	}
+
+	cached, cachedDoc := cache.read(input)
+	// Stat a local spec before reading it, so a change made meanwhile is seen next time
+	info, statErr := os.Stat(input)
+	local := !isRemote(input) && statErr == nil
+	if local && cached != nil && cached.Size == info.Size() && cached.ModTime == info.ModTime().UnixNano() {
+		if doc, err := decodeCached(loader, cachedDoc, localLocation(input)); err == nil {
+			return doc, cached, nil
+		}
+	}
+
+	raw, etag, notModified, location, err := fetchSpec(ctx, client, input, cached)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if notModified || (cached != nil && cached.ContentHash == hashBytes(raw)) {
+		doc, err := decodeCached(loader, cachedDoc, location)
+		if err == nil {
+			if etag != "" {
+				cached.ETag = etag
+			}
+			if local && (cached.Size != info.Size() || cached.ModTime != info.ModTime().UnixNano()) {
+				// Touched but unchanged: record the new stat for the fast path
+				cached.Size, cached.ModTime = info.Size(), info.ModTime().UnixNano()
+				cache.writeRaw(*cached, cachedDoc)
+			}
+			return doc, cached, nil
+		}
+		// Corrupt cache entry; fall through to a fresh parse
+		if notModified {
+			raw, etag, _, location, err = fetchSpec(ctx, client, input, nil)
+			if err != nil {
+				return nil, nil, err
+			}
+		}
+	}
+
+	doc, err := loadData(loader, raw, location)
+	if err != nil {
+		return nil, nil, err
+	}
+	entry := cacheEntry{Input: input, ContentHash: hashBytes(raw), ETag: etag}
+	if local {
+		entry.Size, entry.ModTime = info.Size(), info.ModTime().UnixNano()
+	}
+	cache.write(entry, doc)
+	return doc, &entry, nil
+}
+
+// decodeCached decodes a cached document, already converted to OpenAPI 3 JSON, and resolves
+// its refs against the spec location. It skips the Swagger and bounds checks of loadData.
+func decodeCached(loader *openapi3.Loader, data []byte, location *url.URL) (*openapi3.T, error) {
+	doc := &openapi3.T{}
+	if err := json.Unmarshal(data, doc); err != nil {
+		return nil, err
+	}
+	if err := loader.ResolveRefsIn(doc, location); err != nil {
+		return nil, err
+	}
+	return doc, nil
+}
+
+// isRemote reports whether input is an HTTP(S) URL rather than a local path
+func isRemote(input string) bool {
+	u, err := url.Parse(input)
+	return err == nil && (u.Scheme == "http" || u.Scheme == "https")
+}
+
+// localLocation returns the location refs of a local spec resolve against
+func localLocation(input string) *url.URL {
+	abs, err := filepath.Abs(input)
+	if err != nil {
+		abs = input
+	}
+	return &url.URL{Path: filepath.ToSlash(abs)}
+}
+
+// fetchSpec reads the raw spec bytes from disk or over HTTP(S). For HTTP it sends the
+// cached ETag and reports notModified on a 304 response.
+func fetchSpec(ctx context.Context, client *http.Client, input string, cached *cacheEntry) (raw []byte, etag string, notModified bool, location *url.URL, err error) {
+	if u, perr := url.Parse(input); perr == nil && (u.Scheme == "http" || u.Scheme == "https") {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, input, nil)
+		if err != nil {
+			return nil, "", false, nil, err
+		}
+		if cached != nil && cached.ETag != "" {
+			req.Header.Set("If-None-Match", cached.ETag)
+		}
+		resp, err := client.Do(req)
+		if err != nil {
+			return nil, "", false, nil, err
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode == http.StatusNotModified && cached != nil {
+			return nil, resp.Header.Get("ETag"), true, u, nil
+		}
+		if resp.StatusCode < 200 || resp.StatusCode > 299 {
+			return nil, "", false, nil, fmt.Errorf("failed to fetch spec %s: HTTP %d", input, resp.StatusCode)
+		}
+		raw, err = io.ReadAll(resp.Body)
+		if err != nil {
+			return nil, "", false, nil, err
+		}
+		return raw, resp.Header.Get("ETag"), false, u, nil
+	}
+
+	raw, err = os.ReadFile(input)
+	if err != nil {
+		return nil, "", false, nil, err
+	}
+	return raw, "", false, localLocation(input), nil
+}
+
+// hashBytes returns the hex-encoded sha256 of data
+func hashBytes(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,133 @@
+package openapi
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+const cacheTestSpec = `openapi: 3.0.3
+info:
+  title: Original
+  version: "1.0"
+paths: {}
+`
+
+func TestLoadDocumentCachedReusesUnchangedSpec(t *testing.T) {
+	dir := t.TempDir()
+	specPath := filepath.Join(dir, "openapi.yaml")
+	if err := os.WriteFile(specPath, []byte(cacheTestSpec), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	opts := CacheOptions{Dir: filepath.Join(dir, "cache")}
+
+	doc, err := LoadDocumentCached(specPath, opts)
+	if err != nil {
+		t.Fatalf("first load: %v", err)
+	}
+	if doc.Info.Title != "Original" {
+		t.Fatalf("title = %q, expected Original", doc.Info.Title)
+	}
+
+	// Tamper with the cached document; an unchanged spec must be served from the cache
+	cache := newSpecCache(opts)
+	_, docPath := cache.paths(specPath)
+	if err := os.WriteFile(docPath, []byte(`{"openapi":"3.0.3","info":{"title":"Cached","version":"1.0"},"paths":{}}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	doc, err = LoadDocumentCached(specPath, opts)
+	if err != nil {
+		t.Fatalf("second load: %v", err)
+	}
+	if doc.Info.Title != "Cached" {
+		t.Errorf("title = %q, expected the cached document", doc.Info.Title)
+	}
+
+	// Changing the spec invalidates the entry
+	if err := os.WriteFile(specPath, []byte(cacheTestSpec+"# changed\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	doc, err = LoadDocumentCached(specPath, opts)
+	if err != nil {
+		t.Fatalf("third load: %v", err)
+	}
+	if doc.Info.Title != "Original" {
+		t.Errorf("title = %q, expected a fresh parse", doc.Info.Title)
+	}
+
+	// Disabled bypasses the cache
+	if _, err := LoadDocumentCached(specPath, CacheOptions{Dir: opts.Dir, Disabled: true}); err != nil {
+		t.Fatalf("uncached load: %v", err)
+	}
+}
+
+func TestLoadDocumentCachedSkipsReadingUnchangedFile(t *testing.T) {
+	dir := t.TempDir()
+	specPath := filepath.Join(dir, "openapi.yaml")
+	if err := os.WriteFile(specPath, []byte(cacheTestSpec), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	opts := CacheOptions{Dir: filepath.Join(dir, "cache")}
+	if _, err := LoadDocumentCached(specPath, opts); err != nil {
+		t.Fatalf("first load: %v", err)
+	}
+	info, err := os.Stat(specPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Same size and modification time: the entry is trusted without reading the file, so
+	// an edit that keeps both is not seen
+	edited := strings.Replace(cacheTestSpec, "Original", "Modified", 1)
+	if err := os.WriteFile(specPath, []byte(edited), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chtimes(specPath, info.ModTime(), info.ModTime()); err != nil {
+		t.Fatal(err)
+	}
+	doc, err := LoadDocumentCached(specPath, opts)
+	if err != nil {
+		t.Fatalf("second load: %v", err)
+	}
+	if doc.Info.Title != "Original" {
+		t.Errorf("title = %q, expected the cached document without reading the spec", doc.Info.Title)
+	}
+
+	// A new modification time makes the file be read and hashed again
+	later := info.ModTime().Add(time.Second)
+	if err := os.Chtimes(specPath, later, later); err != nil {
+		t.Fatal(err)
+	}
+	doc, err = LoadDocumentCached(specPath, opts)
+	if err != nil {
+		t.Fatalf("third load: %v", err)
+	}
+	if doc.Info.Title != "Modified" {
+		t.Errorf("title = %q, expected the edited spec", doc.Info.Title)
+	}
+}
+
+func TestLoadDocumentCachedSendsHeaders(t *testing.T) {
+	var auth []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		auth = append(auth, r.Header.Get("Authorization"))
+		_, _ = w.Write([]byte(cacheTestSpec))
+	}))
+	defer server.Close()
+
+	opts := CacheOptions{Dir: filepath.Join(t.TempDir(), "cache"), Headers: map[string]string{"Authorization": "Bearer secret"}}
+	if _, err := LoadDocumentCached(server.URL+"/openapi.yaml", opts); err != nil {
+		t.Fatalf("cached load: %v", err)
+	}
+	opts.Disabled = true
+	if _, err := LoadDocumentCached(server.URL+"/openapi.yaml", opts); err != nil {
+		t.Fatalf("uncached load: %v", err)
+	}
+	if len(auth) != 2 || auth[0] != "Bearer secret" || auth[1] != "Bearer secret" {
+		t.Errorf("Authorization headers = %q, expected the token on both requests", auth)
+	}
+}
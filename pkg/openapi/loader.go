@@ -1,12 +1,22 @@
 package openapi
 
 import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
 	"net/url"
+	"path/filepath"
 
+	"github.com/getkin/kin-openapi/openapi2"
+	"github.com/getkin/kin-openapi/openapi2conv"
 	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/oasdiff/yaml"
+	yamlv3 "gopkg.in/yaml.v3"
 )
 
-// LoadDocument loads an OpenAPI document from a local file path or an HTTP(S) URL
+// LoadDocument loads an OpenAPI document from a local file path or an HTTP(S) URL. Swagger 2.0
+// documents are converted to OpenAPI 3.0.
 func LoadDocument(input string) (*openapi3.T, error) {
 	loader := &openapi3.Loader{IsExternalRefsAllowed: true}
 	return LoadDocumentWithLoader(loader, input)
@@ -15,11 +25,117 @@ func LoadDocument(input string) (*openapi3.T, error) {
 // LoadDocumentWithLoader loads an OpenAPI document using a custom loader
 func LoadDocumentWithLoader(loader *openapi3.Loader, input string) (*openapi3.T, error) {
 	// Try to parse as URL; if it looks like http(s), fetch via URL
+	location := &url.URL{Path: filepath.ToSlash(input)}
 	if u, err := url.Parse(input); err == nil && (u.Scheme == "http" || u.Scheme == "https") {
-		return loader.LoadFromURI(u)
+		location = u
 	}
-	// Fallback to reading from filesystem path
-	return loader.LoadFromFile(input)
+	read := loader.ReadFromURIFunc
+	if read == nil {
+		read = openapi3.DefaultReadFromURI
+	}
+	data, err := read(loader, location)
+	if err != nil {
+		return nil, err
+	}
+	return loadData(loader, data, location)
+}
+
+// loadData parses the document read from location, converting a Swagger 2.0 document to
+// OpenAPI 3.0 first
+func loadData(loader *openapi3.Loader, data []byte, location *url.URL) (*openapi3.T, error) {
+	if !isSwagger2(data) {
+		data, err := normalizeExclusiveBounds(data)
+		if err != nil {
+			return nil, err
+		}
+		return loader.LoadFromDataWithPath(data, location)
+	}
+	var doc2 openapi2.T
+	if err := yaml.Unmarshal(data, &doc2); err != nil {
+		return nil, fmt.Errorf("failed to parse Swagger 2.0 document: %w", err)
+	}
+	doc, err := openapi2conv.ToV3WithLoader(&doc2, loader, location)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert Swagger 2.0 document: %w", err)
+	}
+	return doc, nil
+}
+
+// isSwagger2 reports whether data is a Swagger 2.0 document, i.e. declares swagger: "2.0"
+func isSwagger2(data []byte) bool {
+	var version struct {
+		Swagger string `yaml:"swagger"`
+	}
+	return yamlv3.Unmarshal(data, &version) == nil && version.Swagger == "2.0"
+}
+
+// NewLoader returns a loader resolving external refs that fetches HTTP(S) documents with
+// client (http.DefaultClient when nil) and cancels the requests when ctx is done. Pair it
+// with HeaderClient to load a spec behind authentication.
+func NewLoader(ctx context.Context, client *http.Client) *openapi3.Loader {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	return &openapi3.Loader{
+		Context:               ctx,
+		IsExternalRefsAllowed: true,
+		ReadFromURIFunc:       openapi3.URIMapCache(openapi3.ReadFromURIs(readFromHTTP(client), openapi3.ReadFromFile)),
+	}
+}
+
+// readFromHTTP is openapi3.ReadFromHTTP with requests bound to the loader's context
+func readFromHTTP(client *http.Client) openapi3.ReadFromURIFunc {
+	return func(loader *openapi3.Loader, location *url.URL) ([]byte, error) {
+		if location.Scheme == "" || location.Host == "" {
+			return nil, openapi3.ErrURINotSupported
+		}
+		ctx := loader.Context
+		if ctx == nil {
+			ctx = context.Background()
+		}
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, location.String(), nil)
+		if err != nil {
+			return nil, err
+		}
+		resp, err := client.Do(req)
+		if err != nil {
+			return nil, err
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode > 399 {
+			return nil, fmt.Errorf("error loading %q: request returned status code %d", location.String(), resp.StatusCode)
+		}
+		return io.ReadAll(resp.Body)
+	}
+}
+
+// HeaderClient returns the client fetching the spec at input: http.DefaultClient, or one
+// adding headers to requests for the spec's host. Other hosts (external refs) never get
+// them, so credentials are not leaked.
+func HeaderClient(input string, headers map[string]string) *http.Client {
+	u, err := url.Parse(input)
+	if len(headers) == 0 || err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+		return http.DefaultClient
+	}
+	return &http.Client{Transport: headerTransport{host: u.Host, headers: headers, next: http.DefaultTransport}}
+}
+
+// headerTransport adds headers to requests for a single host
+type headerTransport struct {
+	host    string
+	headers map[string]string
+	next    http.RoundTripper
+}
+
+func (t headerTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.URL.Host != t.host {
+		return t.next.RoundTrip(req)
+	}
+	req = req.Clone(req.Context())
+	for k, v := range t.headers {
+		req.Header.Set(k, v)
+	}
+	return t.next.RoundTrip(req)
 }
 
 // ValidateDocument validates an OpenAPI document
@@ -0,0 +1,103 @@
+package openapi
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestHeaderClientOnlyAuthenticatesSpecHost(t *testing.T) {
+	var refAuth string
+	refs := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		refAuth = r.Header.Get("Authorization")
+		_, _ = w.Write([]byte(`{"type": "object", "properties": {"id": {"type": "string"}}}`))
+	}))
+	defer refs.Close()
+
+	spec := `openapi: 3.0.3
+info:
+  title: Private
+  version: "1.0"
+paths: {}
+components:
+  schemas:
+    User:
+      $ref: "` + refs.URL + `/user.json"
+`
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		_, _ = w.Write([]byte(spec))
+	}))
+	defer api.Close()
+
+	if _, err := LoadDocument(api.URL + "/openapi.yaml"); err == nil {
+		t.Error("expected an error loading the spec without the token")
+	}
+
+	input := api.URL + "/openapi.yaml"
+	loader := NewLoader(context.Background(), HeaderClient(input, map[string]string{"Authorization": "Bearer secret"}))
+	doc, err := LoadDocumentWithLoader(loader, input)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if user := doc.Components.Schemas["User"]; user == nil || user.Value == nil || user.Value.Properties["id"] == nil {
+		t.Errorf("expected the external User schema to be resolved")
+	}
+	if refAuth != "" {
+		t.Errorf("external ref host received Authorization %q", refAuth)
+	}
+}
+
+func TestNewLoaderFollowsContext(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(cacheTestSpec))
+	}))
+	defer server.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err := LoadDocumentWithLoader(NewLoader(ctx, nil), server.URL+"/openapi.yaml")
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("expected a context.Canceled error, got %v", err)
+	}
+	_, err = LoadDocumentCached(server.URL+"/openapi.yaml", CacheOptions{Disabled: true, Context: ctx})
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("expected a context.Canceled error from the cached loader, got %v", err)
+	}
+}
+
+func TestLoadDocumentConvertsSwagger2(t *testing.T) {
+	doc, err := LoadDocument("testdata/swagger2.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(doc.OpenAPI, "3.") {
+		t.Errorf("expected an OpenAPI 3 document, got version %q", doc.OpenAPI)
+	}
+	if len(doc.Servers) != 1 || doc.Servers[0].URL != "https://api.example.com/v1" {
+		t.Errorf("expected the host and base path as server, got %+v", doc.Servers)
+	}
+	create := doc.Paths.Find("/pets").Post
+	if create == nil || create.RequestBody == nil || create.RequestBody.Value.Content.Get("application/json") == nil {
+		t.Fatalf("expected the body parameter as a JSON request body, got %+v", create)
+	}
+	if pet := doc.Components.Schemas["Pet"]; pet == nil || pet.Value == nil || pet.Value.Properties["name"] == nil {
+		t.Errorf("expected the Pet definition as a component schema")
+	}
+	if key := doc.Components.SecuritySchemes["apiKey"]; key == nil || key.Value.In != "header" || key.Value.Name != "X-API-Key" {
+		t.Errorf("expected the apiKey security definition as a security scheme")
+	}
+
+	if err := ValidateDocument("testdata/swagger2.yaml"); err != nil {
+		t.Errorf("ValidateDocument: %v", err)
+	}
+	if _, err := LoadDocumentCached("testdata/swagger2.yaml", CacheOptions{Dir: t.TempDir()}); err != nil {
+		t.Errorf("LoadDocumentCached: %v", err)
+	}
+}
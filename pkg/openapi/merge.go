@@ -0,0 +1,285 @@
+package openapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+const schemaRefPrefix = "#/components/schemas/"
+
+// MergeSource is one document taking part in a merge
+type MergeSource struct {
+	// Name identifies the source in error messages (typically its path or URL)
+	Name string
+	Doc  *openapi3.T
+	// Prefix is prepended to every component schema name of this source, and to every
+	// reference to those schemas, so models from different specs cannot collide
+	Prefix string
+}
+
+// MergeDocuments unions the paths, tags and components of several documents into a new
+// document. Info, servers and the OpenAPI version are taken from the first source.
+// Component schemas with the same name from different sources are an error unless a
+// prefix disambiguates them; other components may repeat only with identical definitions.
+func MergeDocuments(sources []MergeSource) (*openapi3.T, error) {
+	if len(sources) == 0 {
+		return nil, fmt.Errorf("no specs to merge")
+	}
+
+	first := sources[0].Doc
+	merged := &openapi3.T{
+		OpenAPI:    first.OpenAPI,
+		Info:       first.Info,
+		Servers:    first.Servers,
+		Security:   first.Security,
+		Paths:      openapi3.NewPaths(),
+		Components: &openapi3.Components{},
+	}
+
+	schemaOrigin := map[string]string{}
+	opOrigin := map[string]string{}
+	tagSeen := map[string]bool{}
+
+	for _, src := range sources {
+		doc := src.Doc
+		if src.Prefix != "" {
+			prefixSchemas(doc, src.Prefix)
+		}
+
+		for _, tag := range doc.Tags {
+			if tag == nil || tagSeen[tag.Name] {
+				continue
+			}
+			tagSeen[tag.Name] = true
+			merged.Tags = append(merged.Tags, tag)
+		}
+
+		if doc.Paths != nil {
+			for path, item := range doc.Paths.Map() {
+				target := merged.Paths.Value(path)
+				if target == nil {
+					target = &openapi3.PathItem{}
+					merged.Paths.Set(path, target)
+				}
+				if len(item.Parameters) > 0 {
+					target.Parameters = append(target.Parameters, item.Parameters...)
+				}
+				for method, op := range item.Operations() {
+					key := method + " " + path
+					if origin, ok := opOrigin[key]; ok {
+						return nil, fmt.Errorf("operation %s is defined in both %s and %s", key, origin, src.Name)
+					}
+					opOrigin[key] = src.Name
+					target.SetOperation(method, op)
+				}
+			}
+		}
+
+		if doc.Components == nil {
+			continue
+		}
+		c := doc.Components
+		for name, schema := range c.Schemas {
+			if origin, ok := schemaOrigin[name]; ok {
+				return nil, fmt.Errorf("component schema %q is defined in both %s and %s; set a prefix on one of them", name, origin, src.Name)
+			}
+			schemaOrigin[name] = src.Name
+			if merged.Components.Schemas == nil {
+				merged.Components.Schemas = openapi3.Schemas{}
+			}
+			merged.Components.Schemas[name] = schema
+		}
+		if err := mergeComponentMap(&merged.Components.Parameters, c.Parameters, "parameter", src.Name); err != nil {
+			return nil, err
+		}
+		if err := mergeComponentMap(&merged.Components.Headers, c.Headers, "header", src.Name); err != nil {
+			return nil, err
+		}
+		if err := mergeComponentMap(&merged.Components.RequestBodies, c.RequestBodies, "request body", src.Name); err != nil {
+			return nil, err
+		}
+		if err := mergeComponentMap(&merged.Components.Responses, c.Responses, "response", src.Name); err != nil {
+			return nil, err
+		}
+		if err := mergeComponentMap(&merged.Components.SecuritySchemes, c.SecuritySchemes, "security scheme", src.Name); err != nil {
+			return nil, err
+		}
+	}
+
+	return merged, nil
+}
+
+// mergeComponentMap copies src into *dst. A name present in both is accepted only when
+// both definitions are identical.
+func mergeComponentMap[M ~map[string]V, V any](dst *M, src M, kind, origin string) error {
+	for name, v := range src {
+		if *dst == nil {
+			*dst = M{}
+		}
+		if existing, ok := (*dst)[name]; ok {
+			if !sameDefinition(existing, v) {
+				return fmt.Errorf("component %s %q from %s conflicts with an existing definition", kind, name, origin)
+			}
+			continue
+		}
+		(*dst)[name] = v
+	}
+	return nil
+}
+
+// sameDefinition compares two component definitions by their JSON form
+func sameDefinition(a, b any) bool {
+	ja, errA := json.Marshal(a)
+	jb, errB := json.Marshal(b)
+	if errA != nil || errB != nil {
+		return reflect.DeepEqual(a, b)
+	}
+	return string(ja) == string(jb)
+}
+
+// prefixSchemas renames every component schema of doc to prefix+name and rewrites all
+// references to them, including discriminator mappings
+func prefixSchemas(doc *openapi3.T, prefix string) {
+	if doc.Components == nil || len(doc.Components.Schemas) == 0 {
+		return
+	}
+	renamed := make(openapi3.Schemas, len(doc.Components.Schemas))
+	for name, schema := range doc.Components.Schemas {
+		renamed[prefix+name] = schema
+	}
+
+	w := &refRewriter{prefix: prefix, seen: map[*openapi3.Schema]bool{}, refs: map[*openapi3.SchemaRef]bool{}}
+	for _, schema := range doc.Components.Schemas {
+		w.schemaRef(schema)
+	}
+	doc.Components.Schemas = renamed
+
+	for _, p := range doc.Components.Parameters {
+		if p != nil && p.Value != nil {
+			w.parameter(p.Value)
+		}
+	}
+	for _, h := range doc.Components.Headers {
+		if h != nil && h.Value != nil {
+			w.schemaRef(h.Value.Schema)
+			w.content(h.Value.Content)
+		}
+	}
+	for _, rb := range doc.Components.RequestBodies {
+		if rb != nil && rb.Value != nil {
+			w.content(rb.Value.Content)
+		}
+	}
+	for _, r := range doc.Components.Responses {
+		if r != nil && r.Value != nil {
+			w.response(r.Value)
+		}
+	}
+	if doc.Paths == nil {
+		return
+	}
+	for _, item := range doc.Paths.Map() {
+		for _, p := range item.Parameters {
+			if p != nil && p.Value != nil {
+				w.parameter(p.Value)
+			}
+		}
+		for _, op := range item.Operations() {
+			for _, p := range op.Parameters {
+				if p != nil && p.Value != nil {
+					w.parameter(p.Value)
+				}
+			}
+			if op.RequestBody != nil && op.RequestBody.Value != nil {
+				w.content(op.RequestBody.Value.Content)
+			}
+			if op.Responses != nil {
+				for _, r := range op.Responses.Map() {
+					if r != nil && r.Value != nil {
+						w.response(r.Value)
+					}
+				}
+			}
+		}
+	}
+}
+
+// refRewriter walks schemas and prefixes local component schema references
+type refRewriter struct {
+	prefix string
+	seen   map[*openapi3.Schema]bool
+	// refs guards against prefixing a shared SchemaRef twice
+	refs map[*openapi3.SchemaRef]bool
+}
+
+func (w *refRewriter) rewrite(ref string) string {
+	if strings.HasPrefix(ref, schemaRefPrefix) {
+		return schemaRefPrefix + w.prefix + strings.TrimPrefix(ref, schemaRefPrefix)
+	}
+	return ref
+}
+
+func (w *refRewriter) schemaRef(sr *openapi3.SchemaRef) {
+	if sr == nil || w.refs[sr] {
+		return
+	}
+	w.refs[sr] = true
+	sr.Ref = w.rewrite(sr.Ref)
+	s := sr.Value
+	if s == nil || w.seen[s] {
+		return
+	}
+	w.seen[s] = true
+
+	for _, sub := range s.OneOf {
+		w.schemaRef(sub)
+	}
+	for _, sub := range s.AnyOf {
+		w.schemaRef(sub)
+	}
+	for _, sub := range s.AllOf {
+		w.schemaRef(sub)
+	}
+	w.schemaRef(s.Not)
+	w.schemaRef(s.Items)
+	for _, prop := range s.Properties {
+		w.schemaRef(prop)
+	}
+	w.schemaRef(s.AdditionalProperties.Schema)
+	if s.Discriminator != nil {
+		for value, ref := range s.Discriminator.Mapping {
+			// Mapping targets may be refs or bare schema names
+			if !strings.Contains(ref, "/") {
+				ref = schemaRefPrefix + ref
+			}
+			s.Discriminator.Mapping[value] = w.rewrite(ref)
+		}
+	}
+}
+
+func (w *refRewriter) parameter(p *openapi3.Parameter) {
+	w.schemaRef(p.Schema)
+	w.content(p.Content)
+}
+
+func (w *refRewriter) response(r *openapi3.Response) {
+	w.content(r.Content)
+	for _, h := range r.Headers {
+		if h != nil && h.Value != nil {
+			w.schemaRef(h.Value.Schema)
+			w.content(h.Value.Content)
+		}
+	}
+}
+
+func (w *refRewriter) content(c openapi3.Content) {
+	for _, media := range c {
+		if media != nil {
+			w.schemaRef(media.Schema)
+		}
+	}
+}
@@ -0,0 +1,211 @@
+package openapi
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+const mergeUsersSpec = `openapi: 3.0.3
+info:
+  title: Users
+  version: "1.0"
+tags:
+  - name: users
+paths:
+  /users:
+    get:
+      tags: [users]
+      operationId: listUsers
+      responses:
+        "200":
+          description: ok
+          content:
+            application/json:
+              schema:
+                type: array
+                items:
+                  $ref: '#/components/schemas/User'
+components:
+  securitySchemes:
+    bearer:
+      type: http
+      scheme: bearer
+  schemas:
+    User:
+      type: object
+      properties:
+        pet:
+          $ref: '#/components/schemas/Pet'
+    Pet:
+      oneOf:
+        - $ref: '#/components/schemas/Cat'
+      discriminator:
+        propertyName: kind
+        mapping:
+          cat: Cat
+    Cat:
+      type: object
+      properties:
+        kind:
+          type: string
+`
+
+const mergeBillingSpec = `openapi: 3.0.3
+info:
+  title: Billing
+  version: "2.0"
+tags:
+  - name: billing
+  - name: users
+paths:
+  /invoices:
+    get:
+      tags: [billing]
+      operationId: listInvoices
+      responses:
+        "200":
+          description: ok
+          content:
+            application/json:
+              schema:
+                $ref: '#/components/schemas/User'
+components:
+  securitySchemes:
+    bearer:
+      type: http
+      scheme: bearer
+  schemas:
+    User:
+      type: object
+      properties:
+        email:
+          type: string
+`
+
+func loadMergeSpec(t *testing.T, spec string) *openapi3.T {
+	t.Helper()
+	doc, err := openapi3.NewLoader().LoadFromData([]byte(spec))
+	if err != nil {
+		t.Fatalf("load spec: %v", err)
+	}
+	return doc
+}
+
+func TestMergeDocumentsWithPrefix(t *testing.T) {
+	merged, err := MergeDocuments([]MergeSource{
+		{Name: "users.yaml", Doc: loadMergeSpec(t, mergeUsersSpec)},
+		{Name: "billing.yaml", Doc: loadMergeSpec(t, mergeBillingSpec), Prefix: "Billing"},
+	})
+	if err != nil {
+		t.Fatalf("merge: %v", err)
+	}
+
+	if merged.Info.Title != "Users" {
+		t.Errorf("info title = %q, expected the first source's", merged.Info.Title)
+	}
+	if len(merged.Tags) != 2 {
+		t.Errorf("tags = %d, expected users and billing once each", len(merged.Tags))
+	}
+	for _, path := range []string{"/users", "/invoices"} {
+		if merged.Paths.Value(path) == nil {
+			t.Errorf("path %s missing from merged document", path)
+		}
+	}
+	for _, name := range []string{"User", "Pet", "Cat", "BillingUser"} {
+		if merged.Components.Schemas[name] == nil {
+			t.Errorf("schema %s missing from merged document", name)
+		}
+	}
+	if merged.Components.SecuritySchemes["bearer"] == nil {
+		t.Error("identical security scheme should be kept")
+	}
+
+	ref := merged.Paths.Value("/invoices").Get.Responses.Value("200").Value.Content["application/json"].Schema.Ref
+	if ref != "#/components/schemas/BillingUser" {
+		t.Errorf("prefixed response ref = %q", ref)
+	}
+	// The unprefixed source keeps its refs and mappings untouched
+	pet := merged.Components.Schemas["Pet"].Value
+	if got := pet.Discriminator.Mapping["cat"]; got != "Cat" {
+		t.Errorf("unprefixed mapping = %q, expected Cat", got)
+	}
+}
+
+func TestMergeDocumentsPrefixRewritesMappings(t *testing.T) {
+	merged, err := MergeDocuments([]MergeSource{
+		{Name: "users.yaml", Doc: loadMergeSpec(t, mergeUsersSpec), Prefix: "Users"},
+	})
+	if err != nil {
+		t.Fatalf("merge: %v", err)
+	}
+
+	user := merged.Components.Schemas["UsersUser"]
+	if user == nil {
+		t.Fatal("UsersUser missing")
+	}
+	if ref := user.Value.Properties["pet"].Ref; ref != "#/components/schemas/UsersPet" {
+		t.Errorf("property ref = %q", ref)
+	}
+	pet := merged.Components.Schemas["UsersPet"].Value
+	if ref := pet.OneOf[0].Ref; ref != "#/components/schemas/UsersCat" {
+		t.Errorf("oneOf ref = %q", ref)
+	}
+	if got := pet.Discriminator.Mapping["cat"]; got != "#/components/schemas/UsersCat" {
+		t.Errorf("mapping = %q", got)
+	}
+	items := merged.Paths.Value("/users").Get.Responses.Value("200").Value.Content["application/json"].Schema.Value.Items
+	if items.Ref != "#/components/schemas/UsersUser" {
+		t.Errorf("items ref = %q", items.Ref)
+	}
+}
+
+func TestMergeDocumentsConflicts(t *testing.T) {
+	tests := []struct {
+		name   string
+		second string
+		// keepUser leaves the colliding User schema in place
+		keepUser bool
+		wantErr  string
+	}{
+		{
+			name:     "schema collision without prefix",
+			second:   mergeBillingSpec,
+			keepUser: true,
+			wantErr:  `component schema "User"`,
+		},
+		{
+			name:    "duplicate operation",
+			second:  strings.Replace(mergeBillingSpec, "/invoices", "/users", 1),
+			wantErr: "operation GET /users",
+		},
+		{
+			name:    "conflicting security scheme",
+			second:  strings.Replace(mergeBillingSpec, "scheme: bearer", "scheme: basic", 1),
+			wantErr: `security scheme "bearer"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			second := loadMergeSpec(t, tt.second)
+			if !tt.keepUser {
+				// Isolate the conflict under test from the schema collision
+				delete(second.Components.Schemas, "User")
+				for _, item := range second.Paths.Map() {
+					for _, op := range item.Operations() {
+						op.Responses.Value("200").Value.Content = nil
+					}
+				}
+			}
+			_, err := MergeDocuments([]MergeSource{
+				{Name: "users.yaml", Doc: loadMergeSpec(t, mergeUsersSpec)},
+				{Name: "billing.yaml", Doc: second},
+			})
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("error = %v, expected it to mention %s", err, tt.wantErr)
+			}
+		})
+	}
+}
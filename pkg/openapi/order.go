@@ -0,0 +1,119 @@
+package openapi
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// httpMethods are the path item keys that declare operations
+var httpMethods = map[string]bool{
+	"get": true, "put": true, "post": true, "delete": true,
+	"options": true, "head": true, "patch": true, "trace": true,
+}
+
+// OperationKey identifies an operation in SourceOrder results
+func OperationKey(method, path string) string {
+	return strings.ToUpper(method) + " " + path
+}
+
+// SourceOrder lists the operations of a raw JSON or YAML spec as OperationKey values in the
+// order they are written. Parsed documents keep paths in a map, so this is the only way to
+// recover that order.
+func SourceOrder(data []byte) ([]string, error) {
+	var root yaml.Node
+	if err := yaml.Unmarshal(data, &root); err != nil {
+		return nil, err
+	}
+	if len(root.Content) == 0 {
+		return nil, nil
+	}
+	paths := mappingValue(root.Content[0], "paths")
+	if paths == nil || paths.Kind != yaml.MappingNode {
+		return nil, nil
+	}
+	var out []string
+	for i := 0; i+1 < len(paths.Content); i += 2 {
+		path, item := paths.Content[i].Value, paths.Content[i+1]
+		if item.Kind != yaml.MappingNode {
+			continue
+		}
+		for j := 0; j+1 < len(item.Content); j += 2 {
+			if method := item.Content[j].Value; httpMethods[strings.ToLower(method)] {
+				out = append(out, OperationKey(method, path))
+			}
+		}
+	}
+	return out, nil
+}
+
+// ReadSpec returns the raw bytes of the spec at input (a file path or HTTP(S) URL), bypassing
+// the cache
+func ReadSpec(input string, opts CacheOptions) ([]byte, error) {
+	ctx := opts.Context
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	raw, _, _, _, err := fetchSpec(ctx, HeaderClient(input, opts.Headers), input, nil)
+	return raw, err
+}
+
+// SourceLines parses a raw JSON or YAML spec and returns a function looking up the line of
+// the key at a path, e.g. ("paths", "/users", "get"); it returns 0 when there is no such key
+func SourceLines(data []byte) (func(path ...string) int, error) {
+	var root yaml.Node
+	if err := yaml.Unmarshal(data, &root); err != nil {
+		return nil, err
+	}
+	return func(path ...string) int {
+		if len(root.Content) == 0 || len(path) == 0 {
+			return 0
+		}
+		node, line := root.Content[0], 0
+		for _, key := range path {
+			if node.Kind != yaml.MappingNode {
+				return 0
+			}
+			found := false
+			for i := 0; i+1 < len(node.Content); i += 2 {
+				if node.Content[i].Value == key {
+					node, line, found = node.Content[i+1], node.Content[i].Line, true
+					break
+				}
+			}
+			if !found {
+				return 0
+			}
+		}
+		return line
+	}, nil
+}
+
+// LoadSourceOrder reads the spec at input (a file path or HTTP(S) URL) and returns its
+// SourceOrder. The spec is always read fresh since the cache only stores parsed documents.
+func LoadSourceOrder(input string, opts CacheOptions) ([]string, error) {
+	raw, err := ReadSpec(input, opts)
+	if err != nil {
+		return nil, err
+	}
+	order, err := SourceOrder(raw)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read operation order of %s: %w", input, err)
+	}
+	return order, nil
+}
+
+// mappingValue returns the value node for key in a mapping node, or nil
+func mappingValue(node *yaml.Node, key string) *yaml.Node {
+	if node.Kind != yaml.MappingNode {
+		return nil
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return node.Content[i+1]
+		}
+	}
+	return nil
+}
@@ -0,0 +1,65 @@
+package openapi
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSourceOrder(t *testing.T) {
+	yamlSpec := `openapi: 3.0.3
+paths:
+  /users:
+    post: {}
+    get: {}
+    parameters: []
+  /health:
+    get: {}
+`
+	jsonSpec := `{"paths": {"/users": {"post": {}, "get": {}, "parameters": []}, "/health": {"get": {}}}}`
+	expected := []string{"POST /users", "GET /users", "GET /health"}
+
+	for name, spec := range map[string]string{"yaml": yamlSpec, "json": jsonSpec} {
+		order, err := SourceOrder([]byte(spec))
+		if err != nil {
+			t.Fatalf("%s: SourceOrder: %v", name, err)
+		}
+		if !reflect.DeepEqual(order, expected) {
+			t.Errorf("%s: SourceOrder() = %v, expected %v", name, order, expected)
+		}
+	}
+
+	if order, err := SourceOrder([]byte("openapi: 3.0.3\n")); err != nil || order != nil {
+		t.Errorf("expected no operations without paths, got %v, %v", order, err)
+	}
+}
+
+func TestSourceLines(t *testing.T) {
+	spec := `openapi: 3.0.3
+paths:
+  /users:
+    get: {}
+components:
+  schemas:
+    User:
+      properties:
+        name: {}
+`
+	lines, err := SourceLines([]byte(spec))
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		path     []string
+		expected int
+	}{
+		{[]string{"paths", "/users", "get"}, 4},
+		{[]string{"components", "schemas", "User", "properties", "name"}, 9},
+		{[]string{"paths", "/users", "post"}, 0},
+		{[]string{"paths", "/users", "get", "x"}, 0},
+	}
+	for _, test := range tests {
+		if got := lines(test.path...); got != test.expected {
+			t.Errorf("line of %v = %d, expected %d", test.path, got, test.expected)
+		}
+	}
+}
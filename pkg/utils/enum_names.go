@@ -0,0 +1,90 @@
+package utils
+
+import (
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// EnumNameStyle selects how EnumMemberNames spells a member name
+type EnumNameStyle int
+
+const (
+	// EnumNameUpperSnake spells members as UPPER_SNAKE_CASE (Python, Kotlin)
+	EnumNameUpperSnake EnumNameStyle = iota
+	// EnumNamePascal spells members as PascalCase (Go, after the type name)
+	EnumNamePascal
+	// EnumNameIdentifier keeps the value as written and only replaces characters that are
+	// not valid in an identifier with underscores (TypeScript object keys)
+	EnumNameIdentifier
+)
+
+// EnumMemberNames returns a valid identifier for each enum value, in the given style and in
+// the same order. Values that are empty become EMPTY (Empty, _empty), values with nothing
+// usable left after sanitizing become VALUE, and names starting with a digit are prefixed
+// (VALUE_1, Value1, _1). Values that sanitize to the same name are disambiguated like method
+// names: the first keeps the name and later ones get a numeric suffix (ACTIVE, ACTIVE_2, ...)
+// that never collides with another member. The result depends only on the input, so
+// regenerating yields the same names.
+func EnumMemberNames(values []string, style EnumNameStyle) []string {
+	resolved := make([]string, len(values))
+	taken := map[string]bool{}
+	for i, v := range values {
+		resolved[i] = enumMemberName(v, style)
+		taken[resolved[i]] = true
+	}
+
+	sep := ""
+	if style == EnumNameUpperSnake || style == EnumNameIdentifier {
+		sep = "_"
+	}
+	used := map[string]bool{}
+	for i, name := range resolved {
+		if used[name] {
+			n := 2
+			for taken[name+sep+strconv.Itoa(n)] || used[name+sep+strconv.Itoa(n)] {
+				n++
+			}
+			name = name + sep + strconv.Itoa(n)
+			resolved[i] = name
+		}
+		used[name] = true
+	}
+	return resolved
+}
+
+// enumMemberName sanitizes a single enum value without regard to the other members
+func enumMemberName(value string, style EnumNameStyle) string {
+	var name, empty, fallback, digitPrefix string
+	switch style {
+	case EnumNamePascal:
+		name = ToPascalCaseAdvanced(value)
+		empty, fallback, digitPrefix = "Empty", "Value", "Value"
+	case EnumNameIdentifier:
+		var b strings.Builder
+		for _, r := range value {
+			if r == '_' || r == '$' || unicode.IsLetter(r) || unicode.IsDigit(r) {
+				b.WriteRune(r)
+			} else {
+				b.WriteRune('_')
+			}
+		}
+		name = b.String()
+		empty, fallback, digitPrefix = "_empty", "_value", "_"
+	default:
+		name = strings.ToUpper(ToSnakeCaseAdvanced(value))
+		empty, fallback, digitPrefix = "EMPTY", "VALUE", "VALUE_"
+	}
+
+	first, _ := utf8.DecodeRuneInString(name)
+	switch {
+	case value == "":
+		return empty
+	case name == "":
+		return fallback
+	case unicode.IsDigit(first):
+		return digitPrefix + name
+	}
+	return name
+}
@@ -0,0 +1,42 @@
+package utils
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestEnumMemberNames(t *testing.T) {
+	tests := []struct {
+		name     string
+		values   []string
+		style    EnumNameStyle
+		expected string
+	}{
+		{"plain values", []string{"active", "in-review", "onHold"}, EnumNameUpperSnake, "ACTIVE, IN_REVIEW, ON_HOLD"},
+		{"case collisions", []string{"ACTIVE", "active", "Active"}, EnumNameUpperSnake, "ACTIVE, ACTIVE_2, ACTIVE_3"},
+		// The suffix skips names another value already sanitizes to
+		{"suffix taken by another value", []string{"a", "A", "a_2"}, EnumNameUpperSnake, "A, A_3, A_2"},
+		{"numeric leading", []string{"1day", "2", "v1"}, EnumNameUpperSnake, "VALUE_1DAY, VALUE_2, V1"},
+		{"unicode", []string{"café", "日本", "naïve"}, EnumNameUpperSnake, "CAFE, VALUE, NAIVE"},
+		{"empty values", []string{"", "-", ""}, EnumNameUpperSnake, "EMPTY, VALUE, EMPTY_2"},
+		{"pascal", []string{"in_progress", "IN-PROGRESS", "3d", ""}, EnumNamePascal, "InProgress, InProgress2, Value3d, Empty"},
+		{"identifier", []string{"a-b", "a_b", "2fa", "日本", ""}, EnumNameIdentifier, "a_b, a_b_2, _2fa, 日本, _empty"},
+	}
+
+	for _, test := range tests {
+		got := strings.Join(EnumMemberNames(test.values, test.style), ", ")
+		if got != test.expected {
+			t.Errorf("%s: EnumMemberNames(%q) = %s, expected %s", test.name, test.values, got, test.expected)
+		}
+	}
+}
+
+func TestEnumMemberNamesDeterministic(t *testing.T) {
+	values := []string{"b", "B", "a", "A", "", ""}
+	first := strings.Join(EnumMemberNames(values, EnumNameUpperSnake), ",")
+	for i := 0; i < 10; i++ {
+		if got := strings.Join(EnumMemberNames(values, EnumNameUpperSnake), ","); got != first {
+			t.Fatalf("EnumMemberNames() = %s, expected %s", got, first)
+		}
+	}
+}
@@ -0,0 +1,52 @@
+package utils
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/blimu-dev/sdk-gen/pkg/ir"
+)
+
+// Environment is a named base URL generated clients offer as a preset
+type Environment struct {
+	Name string
+	URL  string
+}
+
+// Environments returns the environment presets of a client: the configured ones sorted by
+// name, or else one per absolute server of the spec in spec order. A server is named after its
+// description in camelCase ("Sandbox server" -> sandboxServer), or server1, server2, ... by
+// position without one; repeated names get a numeric suffix.
+func Environments(configured map[string]string, servers []ir.IRServer) []Environment {
+	var out []Environment
+	if len(configured) > 0 {
+		names := make([]string, 0, len(configured))
+		for name := range configured {
+			names = append(names, name)
+		}
+		slices.Sort(names)
+		for _, name := range names {
+			out = append(out, Environment{Name: name, URL: strings.TrimSuffix(configured[name], "/")})
+		}
+		return out
+	}
+
+	taken := map[string]bool{}
+	for i, server := range servers {
+		if !strings.HasPrefix(server.URL, "http://") && !strings.HasPrefix(server.URL, "https://") {
+			continue
+		}
+		name := ToCamelCaseAdvanced(server.Description)
+		if name == "" {
+			name = fmt.Sprintf("server%d", i+1)
+		}
+		unique := name
+		for n := 2; taken[unique]; n++ {
+			unique = fmt.Sprintf("%s%d", name, n)
+		}
+		taken[unique] = true
+		out = append(out, Environment{Name: unique, URL: server.URL})
+	}
+	return out
+}
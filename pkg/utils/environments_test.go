@@ -0,0 +1,43 @@
+package utils
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/blimu-dev/sdk-gen/pkg/ir"
+)
+
+func TestEnvironments(t *testing.T) {
+	servers := []ir.IRServer{
+		{URL: "https://api.example.com", Description: "Production"},
+		{URL: "/v2", Description: "Relative"},
+		{URL: "https://sandbox.example.com", Description: "Sandbox server"},
+		{URL: "https://eu.sandbox.example.com", Description: "Sandbox server"},
+		{URL: "http://localhost:8080"},
+	}
+	tests := []struct {
+		name       string
+		configured map[string]string
+		expected   []Environment
+	}{
+		{"spec servers", nil, []Environment{
+			{"production", "https://api.example.com"},
+			{"sandboxServer", "https://sandbox.example.com"},
+			{"sandboxServer2", "https://eu.sandbox.example.com"},
+			{"server5", "http://localhost:8080"},
+		}},
+		{"configured", map[string]string{"staging": "https://staging.example.com/", "production": "https://api.example.com"}, []Environment{
+			{"production", "https://api.example.com"},
+			{"staging", "https://staging.example.com"},
+		}},
+	}
+
+	for _, test := range tests {
+		if got := Environments(test.configured, servers); !reflect.DeepEqual(got, test.expected) {
+			t.Errorf("%s: Environments() = %v, expected %v", test.name, got, test.expected)
+		}
+	}
+	if got := Environments(nil, nil); got != nil {
+		t.Errorf("Environments() without servers = %v, expected none", got)
+	}
+}
@@ -0,0 +1,39 @@
+package utils
+
+import (
+	"path/filepath"
+	"strings"
+)
+
+// headerCommentPrefixes maps source file extensions to their line comment syntax
+var headerCommentPrefixes = map[string]string{
+	".go":    "//",
+	".ts":    "//",
+	".js":    "//",
+	".kt":    "//",
+	".kts":   "//",
+	".py":    "#",
+	".swift": "//",
+}
+
+// FileHeader renders header as a comment block for the file at path, followed by a blank
+// line. It returns an empty string when header is empty or the file type has no known
+// comment syntax (JSON, Markdown, ...), so those files are left untouched.
+func FileHeader(header, path string) string {
+	header = strings.TrimRight(header, " \t\r\n")
+	prefix, ok := headerCommentPrefixes[filepath.Ext(path)]
+	if header == "" || !ok {
+		return ""
+	}
+	var b strings.Builder
+	for _, line := range strings.Split(header, "\n") {
+		line = strings.TrimRight(line, " \t\r")
+		if line == "" {
+			b.WriteString(prefix + "\n")
+			continue
+		}
+		b.WriteString(prefix + " " + line + "\n")
+	}
+	b.WriteString("\n")
+	return b.String()
+}
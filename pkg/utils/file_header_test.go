@@ -0,0 +1,25 @@
+package utils
+
+import "testing"
+
+func TestFileHeader(t *testing.T) {
+	header := "SPDX-License-Identifier: MIT\n\nCopyright Acme\n"
+	tests := []struct {
+		header   string
+		path     string
+		expected string
+	}{
+		{header, "/out/client.go", "// SPDX-License-Identifier: MIT\n//\n// Copyright Acme\n\n"},
+		{header, "/out/src/index.ts", "// SPDX-License-Identifier: MIT\n//\n// Copyright Acme\n\n"},
+		{header, "/out/pkg/models.py", "# SPDX-License-Identifier: MIT\n#\n# Copyright Acme\n\n"},
+		{header, "/out/package.json", ""},
+		{header, "/out/README.md", ""},
+		{"", "/out/client.go", ""},
+	}
+
+	for _, test := range tests {
+		if got := FileHeader(test.header, test.path); got != test.expected {
+			t.Errorf("FileHeader(%q, %q) = %q, expected %q", test.header, test.path, got, test.expected)
+		}
+	}
+}
@@ -0,0 +1,84 @@
+package utils
+
+import (
+	"fmt"
+	"log/slog"
+	"strconv"
+	"strings"
+
+	"github.com/blimu-dev/sdk-gen/pkg/ir"
+)
+
+// MethodNames maps every operation to a method name that is unique within its service
+type MethodNames struct {
+	names   map[string]string
+	resolve func(ir.IROperation) string
+}
+
+// NewMethodNames resolves a method name for every operation and disambiguates names that
+// collide within a service: the first operation (in service order) keeps the name and later
+// ones get a numeric suffix (retrieve, retrieve2, ...). It also returns a warning for every
+// collision found. Operations are expected in their sorted IR order, so the result is
+// deterministic.
+func NewMethodNames(services []ir.IRService, resolve func(ir.IROperation) string) (*MethodNames, []string) {
+	m := &MethodNames{names: map[string]string{}, resolve: resolve}
+	var warnings []string
+	for _, service := range services {
+		resolved := make([]string, len(service.Operations))
+		taken := map[string]bool{}
+		for i, op := range service.Operations {
+			resolved[i] = resolve(op)
+			taken[resolved[i]] = true
+		}
+
+		used := map[string]bool{}
+		collisions := map[string][]string{}
+		var order []string
+		for i, op := range service.Operations {
+			name := resolved[i]
+			if used[name] {
+				if len(collisions[name]) == 0 {
+					order = append(order, name)
+				}
+				n := 2
+				for taken[name+strconv.Itoa(n)] || used[name+strconv.Itoa(n)] {
+					n++
+				}
+				unique := name + strconv.Itoa(n)
+				collisions[name] = append(collisions[name], fmt.Sprintf("%s %s -> %s", op.Method, op.Path, unique))
+				name = unique
+			}
+			used[name] = true
+			m.names[operationKey(op)] = name
+		}
+
+		for _, name := range order {
+			warnings = append(warnings, fmt.Sprintf("service %q: method name %q is used by more than one operation; renamed %s",
+				service.Tag, name, strings.Join(collisions[name], ", ")))
+		}
+	}
+	return m, warnings
+}
+
+// Get returns the unique method name of an operation
+func (m *MethodNames) Get(op ir.IROperation) string {
+	if name, ok := m.names[operationKey(op)]; ok {
+		return name
+	}
+	return m.resolve(op)
+}
+
+func operationKey(op ir.IROperation) string {
+	return op.Tag + " " + op.Method + " " + op.Path
+}
+
+// LogWarnings reports the warnings found while generating client, such as those of
+// NewMethodNames, at warn level. A nil logger drops them.
+func LogWarnings(logger *slog.Logger, client string, warnings []string) {
+	if logger == nil {
+		return
+	}
+	for _, w := range warnings {
+		logger.Warn(w, "client", client)
+	}
+}
@@ -0,0 +1,60 @@
+package utils
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/blimu-dev/sdk-gen/pkg/ir"
+)
+
+func TestNewMethodNames(t *testing.T) {
+	op := func(tag, method, path, id string) ir.IROperation {
+		return ir.IROperation{Tag: tag, Method: method, Path: path, OperationID: id}
+	}
+	services := []ir.IRService{
+		{Tag: "users", Operations: []ir.IROperation{
+			op("users", "GET", "/users/{id}", "retrieve"),
+			op("users", "GET", "/users/{id}/profile", "retrieve"),
+			op("users", "GET", "/users/{id}/settings", "retrieve"),
+			op("users", "GET", "/users/me", "retrieve2"),
+			op("users", "POST", "/users", "create"),
+		}},
+		// The same name in another service is not a collision
+		{Tag: "teams", Operations: []ir.IROperation{
+			op("teams", "GET", "/teams/{id}", "retrieve"),
+		}},
+	}
+
+	names, warnings := NewMethodNames(services, func(op ir.IROperation) string { return op.OperationID })
+
+	tests := []struct {
+		op       ir.IROperation
+		expected string
+	}{
+		{services[0].Operations[0], "retrieve"},
+		{services[0].Operations[1], "retrieve3"},
+		{services[0].Operations[2], "retrieve4"},
+		{services[0].Operations[3], "retrieve2"},
+		{services[0].Operations[4], "create"},
+		{services[1].Operations[0], "retrieve"},
+	}
+	for _, test := range tests {
+		if got := names.Get(test.op); got != test.expected {
+			t.Errorf("Get(%s %s) = %q, expected %q", test.op.Method, test.op.Path, got, test.expected)
+		}
+	}
+
+	if len(warnings) != 1 {
+		t.Fatalf("expected one warning, got %v", warnings)
+	}
+	for _, part := range []string{`"users"`, `"retrieve"`, "GET /users/{id}/profile -> retrieve3", "GET /users/{id}/settings -> retrieve4"} {
+		if !strings.Contains(warnings[0], part) {
+			t.Errorf("warning %q should mention %s", warnings[0], part)
+		}
+	}
+
+	// Operations outside the given services fall back to the resolver
+	if got := names.Get(op("misc", "GET", "/health", "health")); got != "health" {
+		t.Errorf("Get(unknown) = %q, expected health", got)
+	}
+}
@@ -1,9 +1,13 @@
 package utils
 
 import (
+	"bytes"
+	"encoding/json"
+	"fmt"
 	"regexp"
 	"strings"
 	"unicode"
+	"unicode/utf8"
 
 	"golang.org/x/text/runes"
 	"golang.org/x/text/transform"
@@ -17,11 +21,25 @@ var (
 
 // RemoveAccents removes accents from a string, converting accented characters to their base forms
 func RemoveAccents(s string) string {
+	// Nearly every name in a spec is ASCII, which has nothing to remove
+	if isASCII(s) {
+		return s
+	}
 	t := transform.Chain(norm.NFD, runes.Remove(runes.In(unicode.Mn)), norm.NFC)
 	result, _, _ := transform.String(t, s)
 	return result
 }
 
+// isASCII reports whether s consists of ASCII characters only
+func isASCII(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] >= utf8.RuneSelf {
+			return false
+		}
+	}
+	return true
+}
+
 // SplitWords splits a string into words, handling camelCase, PascalCase, snake_case, and kebab-case
 func SplitWords(s string) []string {
 	s = strings.TrimSpace(s)
@@ -36,7 +54,7 @@ func SplitWords(s string) []string {
 	s = camelSplit.ReplaceAllString(s, "$1 $2")
 
 	// Then split on non-alphanumeric characters and spaces
-	parts := regexp.MustCompile(`[^A-Za-z0-9]+`).Split(s, -1)
+	parts := nonAlnum.Split(s, -1)
 
 	// Filter out empty parts
 	result := make([]string, 0, len(parts))
@@ -268,3 +286,21 @@ func ToKebabCaseAdvanced(s string) string {
 	}
 	return strings.Join(allParts, "-")
 }
+
+// ExampleJSON renders an example value as compact JSON
+func ExampleJSON(v any) string {
+	return strings.Join(ExampleLines(v, ""), "")
+}
+
+// ExampleLines renders an example value as JSON split into lines, for embedding in docs and
+// doc comments. With an empty indent the JSON is compact and fits on a single line.
+func ExampleLines(v any, indent string) []string {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", indent)
+	if err := enc.Encode(v); err != nil {
+		return strings.Split(fmt.Sprint(v), "\n")
+	}
+	return strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+}
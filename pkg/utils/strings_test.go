@@ -1,6 +1,7 @@
 package utils
 
 import (
+	"strings"
 	"testing"
 )
 
@@ -285,3 +286,17 @@ func TestSplitCamelCase(t *testing.T) {
 		}
 	}
 }
+
+func TestExampleLines(t *testing.T) {
+	example := map[string]any{"name": "Ada", "tags": []any{"a<b"}}
+
+	if got := ExampleJSON(example); got != `{"name":"Ada","tags":["a<b"]}` {
+		t.Errorf("ExampleJSON() = %q", got)
+	}
+
+	expected := []string{`{`, `  "name": "Ada",`, `  "tags": [`, `    "a<b"`, `  ]`, `}`}
+	result := ExampleLines(example, "  ")
+	if strings.Join(result, "\n") != strings.Join(expected, "\n") {
+		t.Errorf("ExampleLines() = %q, expected %q", result, expected)
+	}
+}
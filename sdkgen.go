@@ -20,6 +20,7 @@ package sdkgen
 
 import (
 	"github.com/blimu-dev/sdk-gen/pkg/generator"
+	"github.com/blimu-dev/sdk-gen/pkg/ir"
 )
 
 // GenerateTypeScriptSDK is a convenience function for generating a TypeScript SDK with minimal configuration.
@@ -82,6 +83,7 @@ func GenerateSDK(opts GenerateSDKOptions) error {
 	genOpts := generator.GenerateSDKOptions{
 		ConfigPath:   opts.ConfigPath,
 		SingleClient: opts.SingleClient,
+		NoCache:      opts.NoCache,
 		Spec:         opts.Spec,
 		Type:         opts.Type,
 		OutDir:       opts.OutDir,
@@ -120,6 +122,22 @@ func ValidateSpec(specPath string) error {
 	return generator.ValidateSpec(specPath)
 }
 
+// BuildIR loads an OpenAPI specification and returns its complete intermediate representation.
+// The IR is not filtered by tags, which makes it suitable for custom generators.
+//
+// Example:
+//
+//	spec, err := sdkgen.BuildIR("./openapi.yaml")
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//	for _, svc := range spec.Services {
+//		fmt.Println(svc.Tag, len(svc.Operations))
+//	}
+func BuildIR(specPath string) (ir.IR, error) {
+	return generator.BuildIRFromSpec(specPath)
+}
+
 // GenerateSDKOptions contains options for SDK generation
 type GenerateSDKOptions struct {
 	// ConfigPath is the path to the configuration file (optional)
@@ -128,6 +146,9 @@ type GenerateSDKOptions struct {
 	// SingleClient generates only the named client from config (optional)
 	SingleClient string
 
+	// NoCache bypasses the spec cache configured in the config file (optional)
+	NoCache bool
+
 	// Fallback options when no config file is provided
 	Spec        string   // OpenAPI spec file or URL
 	Type        string   // Generator type (e.g., "typescript")